@@ -74,6 +74,8 @@ type ClientService interface {
 
 	GetMapName(params *GetMapNameParams, opts ...ClientOption) (*GetMapNameOK, error)
 
+	GetMapNameDump(params *GetMapNameDumpParams, writer io.Writer, opts ...ClientOption) (*GetMapNameDumpOK, error)
+
 	GetMapNameEvents(params *GetMapNameEventsParams, writer io.Writer, opts ...ClientOption) (*GetMapNameEventsOK, error)
 
 	GetNodeIds(params *GetNodeIdsParams, opts ...ClientOption) (*GetNodeIdsOK, error)
@@ -356,6 +358,50 @@ func (a *Client) GetMapName(params *GetMapNameParams, opts ...ClientOption) (*Ge
 	panic(msg)
 }
 
+/*
+	GetMapNameDump retrieves the entries of a b p f map as a stream
+
+	Streams the current entries of the BPF map as newline-delimited JSON,
+
+one BPFMapEntry object per line. The entries are read through the
+batch lookup API and emitted in pages, so that arbitrarily large maps
+can be dumped with bounded memory usage on both sides.
+*/
+func (a *Client) GetMapNameDump(params *GetMapNameDumpParams, writer io.Writer, opts ...ClientOption) (*GetMapNameDumpOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetMapNameDumpParams()
+	}
+	op := &runtime.ClientOperation{
+		ID:                 "GetMapNameDump",
+		Method:             "GET",
+		PathPattern:        "/map/{name}/dump",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &GetMapNameDumpReader{formats: a.formats, writer: writer},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	}
+	for _, opt := range opts {
+		opt(op)
+	}
+
+	result, err := a.transport.Submit(op)
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*GetMapNameDumpOK)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	// safeguard: normally, absent a default response, unknown success responses return an error above: so this is a codegen issue
+	msg := fmt.Sprintf("unexpected success response for GetMapNameDump: API contract not enforced by server. Client expected to get an error, but got: %T", result)
+	panic(msg)
+}
+
 /*
 GetMapNameEvents retrieves the recent event logs associated with this endpoint
 */
@@ -0,0 +1,189 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Authors of Cilium
+// SPDX-License-Identifier: Apache-2.0
+
+package daemon
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime"
+	cr "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+)
+
+// NewGetMapNameDumpParams creates a new GetMapNameDumpParams object,
+// with the default timeout for this client.
+//
+// Default values are not hydrated, since defaults are normally applied by the API server side.
+//
+// To enforce default values in parameter, use SetDefaults or WithDefaults.
+func NewGetMapNameDumpParams() *GetMapNameDumpParams {
+	return &GetMapNameDumpParams{
+		timeout: cr.DefaultTimeout,
+	}
+}
+
+// NewGetMapNameDumpParamsWithTimeout creates a new GetMapNameDumpParams object
+// with the ability to set a timeout on a request.
+func NewGetMapNameDumpParamsWithTimeout(timeout time.Duration) *GetMapNameDumpParams {
+	return &GetMapNameDumpParams{
+		timeout: timeout,
+	}
+}
+
+// NewGetMapNameDumpParamsWithContext creates a new GetMapNameDumpParams object
+// with the ability to set a context for a request.
+func NewGetMapNameDumpParamsWithContext(ctx context.Context) *GetMapNameDumpParams {
+	return &GetMapNameDumpParams{
+		Context: ctx,
+	}
+}
+
+// NewGetMapNameDumpParamsWithHTTPClient creates a new GetMapNameDumpParams object
+// with the ability to set a custom HTTPClient for a request.
+func NewGetMapNameDumpParamsWithHTTPClient(client *http.Client) *GetMapNameDumpParams {
+	return &GetMapNameDumpParams{
+		HTTPClient: client,
+	}
+}
+
+/*
+GetMapNameDumpParams contains all the parameters to send to the API endpoint
+
+	for the get map name dump operation.
+
+	Typically these are written to a http.Request.
+*/
+type GetMapNameDumpParams struct {
+
+	/* Name.
+
+	   Name of map
+	*/
+	Name string
+
+	/* PageSize.
+
+	   Number of entries per flushed page
+	*/
+	PageSize *int64
+
+	timeout    time.Duration
+	Context    context.Context
+	HTTPClient *http.Client
+}
+
+// WithDefaults hydrates default values in the get map name dump params (not the query body).
+//
+// All values with no default are reset to their zero value.
+func (o *GetMapNameDumpParams) WithDefaults() *GetMapNameDumpParams {
+	o.SetDefaults()
+	return o
+}
+
+// SetDefaults hydrates default values in the get map name dump params (not the query body).
+//
+// All values with no default are reset to their zero value.
+func (o *GetMapNameDumpParams) SetDefaults() {
+	// no default values defined for this parameter
+}
+
+// WithTimeout adds the timeout to the get map name dump params
+func (o *GetMapNameDumpParams) WithTimeout(timeout time.Duration) *GetMapNameDumpParams {
+	o.SetTimeout(timeout)
+	return o
+}
+
+// SetTimeout adds the timeout to the get map name dump params
+func (o *GetMapNameDumpParams) SetTimeout(timeout time.Duration) {
+	o.timeout = timeout
+}
+
+// WithContext adds the context to the get map name dump params
+func (o *GetMapNameDumpParams) WithContext(ctx context.Context) *GetMapNameDumpParams {
+	o.SetContext(ctx)
+	return o
+}
+
+// SetContext adds the context to the get map name dump params
+func (o *GetMapNameDumpParams) SetContext(ctx context.Context) {
+	o.Context = ctx
+}
+
+// WithHTTPClient adds the HTTPClient to the get map name dump params
+func (o *GetMapNameDumpParams) WithHTTPClient(client *http.Client) *GetMapNameDumpParams {
+	o.SetHTTPClient(client)
+	return o
+}
+
+// SetHTTPClient adds the HTTPClient to the get map name dump params
+func (o *GetMapNameDumpParams) SetHTTPClient(client *http.Client) {
+	o.HTTPClient = client
+}
+
+// WithName adds the name to the get map name dump params
+func (o *GetMapNameDumpParams) WithName(name string) *GetMapNameDumpParams {
+	o.SetName(name)
+	return o
+}
+
+// SetName adds the name to the get map name dump params
+func (o *GetMapNameDumpParams) SetName(name string) {
+	o.Name = name
+}
+
+// WithPageSize adds the pageSize to the get map name dump params
+func (o *GetMapNameDumpParams) WithPageSize(pageSize *int64) *GetMapNameDumpParams {
+	o.SetPageSize(pageSize)
+	return o
+}
+
+// SetPageSize adds the pageSize to the get map name dump params
+func (o *GetMapNameDumpParams) SetPageSize(pageSize *int64) {
+	o.PageSize = pageSize
+}
+
+// WriteToRequest writes these params to a swagger request
+func (o *GetMapNameDumpParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.Registry) error {
+
+	if err := r.SetTimeout(o.timeout); err != nil {
+		return err
+	}
+	var res []error
+
+	// path param name
+	if err := r.SetPathParam("name", o.Name); err != nil {
+		return err
+	}
+
+	if o.PageSize != nil {
+
+		// query param page-size
+		var qrPageSize int64
+
+		if o.PageSize != nil {
+			qrPageSize = *o.PageSize
+		}
+		qPageSize := swag.FormatInt64(qrPageSize)
+		if qPageSize != "" {
+
+			if err := r.SetQueryParam("page-size", qPageSize); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
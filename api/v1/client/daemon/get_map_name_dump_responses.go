@@ -0,0 +1,230 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Authors of Cilium
+// SPDX-License-Identifier: Apache-2.0
+
+package daemon
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+)
+
+// GetMapNameDumpReader is a Reader for the GetMapNameDump structure.
+type GetMapNameDumpReader struct {
+	formats strfmt.Registry
+	writer  io.Writer
+}
+
+// ReadResponse reads a server response into the received o.
+func (o *GetMapNameDumpReader) ReadResponse(response runtime.ClientResponse, consumer runtime.Consumer) (interface{}, error) {
+	switch response.Code() {
+	case 200:
+		result := NewGetMapNameDumpOK(o.writer)
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return result, nil
+	case 404:
+		result := NewGetMapNameDumpNotFound()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return nil, result
+	case 501:
+		result := NewGetMapNameDumpNotImplemented()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return nil, result
+	default:
+		return nil, runtime.NewAPIError("[GET /map/{name}/dump] GetMapNameDump", response, response.Code())
+	}
+}
+
+// NewGetMapNameDumpOK creates a GetMapNameDumpOK with default headers values
+func NewGetMapNameDumpOK(writer io.Writer) *GetMapNameDumpOK {
+	return &GetMapNameDumpOK{
+
+		Payload: writer,
+	}
+}
+
+/*
+GetMapNameDumpOK describes a response with status code 200, with default header values.
+
+Success
+*/
+type GetMapNameDumpOK struct {
+	Payload io.Writer
+}
+
+// IsSuccess returns true when this get map name dump o k response has a 2xx status code
+func (o *GetMapNameDumpOK) IsSuccess() bool {
+	return true
+}
+
+// IsRedirect returns true when this get map name dump o k response has a 3xx status code
+func (o *GetMapNameDumpOK) IsRedirect() bool {
+	return false
+}
+
+// IsClientError returns true when this get map name dump o k response has a 4xx status code
+func (o *GetMapNameDumpOK) IsClientError() bool {
+	return false
+}
+
+// IsServerError returns true when this get map name dump o k response has a 5xx status code
+func (o *GetMapNameDumpOK) IsServerError() bool {
+	return false
+}
+
+// IsCode returns true when this get map name dump o k response a status code equal to that given
+func (o *GetMapNameDumpOK) IsCode(code int) bool {
+	return code == 200
+}
+
+// Code gets the status code for the get map name dump o k response
+func (o *GetMapNameDumpOK) Code() int {
+	return 200
+}
+
+func (o *GetMapNameDumpOK) Error() string {
+	return fmt.Sprintf("[GET /map/{name}/dump][%d] getMapNameDumpOK", 200)
+}
+
+func (o *GetMapNameDumpOK) String() string {
+	return fmt.Sprintf("[GET /map/{name}/dump][%d] getMapNameDumpOK", 200)
+}
+
+func (o *GetMapNameDumpOK) GetPayload() io.Writer {
+	return o.Payload
+}
+
+func (o *GetMapNameDumpOK) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	// response payload
+	if err := consumer.Consume(response.Body(), o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
+// NewGetMapNameDumpNotFound creates a GetMapNameDumpNotFound with default headers values
+func NewGetMapNameDumpNotFound() *GetMapNameDumpNotFound {
+	return &GetMapNameDumpNotFound{}
+}
+
+/*
+GetMapNameDumpNotFound describes a response with status code 404, with default header values.
+
+Map not found
+*/
+type GetMapNameDumpNotFound struct {
+}
+
+// IsSuccess returns true when this get map name dump not found response has a 2xx status code
+func (o *GetMapNameDumpNotFound) IsSuccess() bool {
+	return false
+}
+
+// IsRedirect returns true when this get map name dump not found response has a 3xx status code
+func (o *GetMapNameDumpNotFound) IsRedirect() bool {
+	return false
+}
+
+// IsClientError returns true when this get map name dump not found response has a 4xx status code
+func (o *GetMapNameDumpNotFound) IsClientError() bool {
+	return true
+}
+
+// IsServerError returns true when this get map name dump not found response has a 5xx status code
+func (o *GetMapNameDumpNotFound) IsServerError() bool {
+	return false
+}
+
+// IsCode returns true when this get map name dump not found response a status code equal to that given
+func (o *GetMapNameDumpNotFound) IsCode(code int) bool {
+	return code == 404
+}
+
+// Code gets the status code for the get map name dump not found response
+func (o *GetMapNameDumpNotFound) Code() int {
+	return 404
+}
+
+func (o *GetMapNameDumpNotFound) Error() string {
+	return fmt.Sprintf("[GET /map/{name}/dump][%d] getMapNameDumpNotFound", 404)
+}
+
+func (o *GetMapNameDumpNotFound) String() string {
+	return fmt.Sprintf("[GET /map/{name}/dump][%d] getMapNameDumpNotFound", 404)
+}
+
+func (o *GetMapNameDumpNotFound) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	return nil
+}
+
+// NewGetMapNameDumpNotImplemented creates a GetMapNameDumpNotImplemented with default headers values
+func NewGetMapNameDumpNotImplemented() *GetMapNameDumpNotImplemented {
+	return &GetMapNameDumpNotImplemented{}
+}
+
+/*
+GetMapNameDumpNotImplemented describes a response with status code 501, with default header values.
+
+Streaming dump not supported for this map
+*/
+type GetMapNameDumpNotImplemented struct {
+}
+
+// IsSuccess returns true when this get map name dump not implemented response has a 2xx status code
+func (o *GetMapNameDumpNotImplemented) IsSuccess() bool {
+	return false
+}
+
+// IsRedirect returns true when this get map name dump not implemented response has a 3xx status code
+func (o *GetMapNameDumpNotImplemented) IsRedirect() bool {
+	return false
+}
+
+// IsClientError returns true when this get map name dump not implemented response has a 4xx status code
+func (o *GetMapNameDumpNotImplemented) IsClientError() bool {
+	return false
+}
+
+// IsServerError returns true when this get map name dump not implemented response has a 5xx status code
+func (o *GetMapNameDumpNotImplemented) IsServerError() bool {
+	return true
+}
+
+// IsCode returns true when this get map name dump not implemented response a status code equal to that given
+func (o *GetMapNameDumpNotImplemented) IsCode(code int) bool {
+	return code == 501
+}
+
+// Code gets the status code for the get map name dump not implemented response
+func (o *GetMapNameDumpNotImplemented) Code() int {
+	return 501
+}
+
+func (o *GetMapNameDumpNotImplemented) Error() string {
+	return fmt.Sprintf("[GET /map/{name}/dump][%d] getMapNameDumpNotImplemented", 501)
+}
+
+func (o *GetMapNameDumpNotImplemented) String() string {
+	return fmt.Sprintf("[GET /map/{name}/dump][%d] getMapNameDumpNotImplemented", 501)
+}
+
+func (o *GetMapNameDumpNotImplemented) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	return nil
+}
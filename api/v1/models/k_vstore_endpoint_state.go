@@ -0,0 +1,64 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Authors of Cilium
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"context"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+)
+
+// KVstoreEndpointState Status of an individual kvstore endpoint
+//
+// +k8s:deepcopy-gen=true
+//
+// swagger:model KVstoreEndpointState
+type KVstoreEndpointState struct {
+
+	// Address of the endpoint
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Error encountered while checking the endpoint status
+	Error string `json:"error,omitempty"`
+
+	// Whether the endpoint is the current cluster leader
+	Leader bool `json:"leader,omitempty"`
+
+	// Server version reported by the endpoint
+	Version string `json:"version,omitempty"`
+}
+
+// Validate validates this k vstore endpoint state
+func (m *KVstoreEndpointState) Validate(formats strfmt.Registry) error {
+	return nil
+}
+
+// ContextValidate validates this k vstore endpoint state based on context it is used
+func (m *KVstoreEndpointState) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *KVstoreEndpointState) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *KVstoreEndpointState) UnmarshalBinary(b []byte) error {
+	var res KVstoreEndpointState
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
@@ -0,0 +1,145 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Authors of Cilium
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+)
+
+// KVstoreStatus Structured status of the key/value datastore, complementing the
+// human-readable summary
+//
+// +k8s:deepcopy-gen=true
+//
+// swagger:model KVstoreStatus
+type KVstoreStatus struct {
+
+	// Number of consecutive quorum check failures
+	ConsecutiveQuorumErrors int64 `json:"consecutive-quorum-errors,omitempty"`
+
+	// Status of the individual kvstore endpoints
+	Endpoints []*KVstoreEndpointState `json:"endpoints"`
+
+	// Whether the kvstore cluster currently has quorum
+	HasQuorum bool `json:"has-quorum,omitempty"`
+
+	// Seconds elapsed since the last heartbeat update was received
+	LastHeartbeatAgeSeconds float64 `json:"last-heartbeat-age-seconds,omitempty"`
+
+	// Number of currently acquired generic leases
+	Leases int64 `json:"leases,omitempty"`
+
+	// Number of currently acquired lock leases
+	LockLeases int64 `json:"lock-leases,omitempty"`
+
+	// Last quorum check failure, set when quorum is not established
+	QuorumError string `json:"quorum-error,omitempty"`
+}
+
+// Validate validates this k vstore status
+func (m *KVstoreStatus) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateEndpoints(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *KVstoreStatus) validateEndpoints(formats strfmt.Registry) error {
+	if swag.IsZero(m.Endpoints) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.Endpoints); i++ {
+		if swag.IsZero(m.Endpoints[i]) { // not required
+			continue
+		}
+
+		if m.Endpoints[i] != nil {
+			if err := m.Endpoints[i].Validate(formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("endpoints" + "." + strconv.Itoa(i))
+				} else if ce, ok := err.(*errors.CompositeError); ok {
+					return ce.ValidateName("endpoints" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
+// ContextValidate validate this k vstore status based on the context it is used
+func (m *KVstoreStatus) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.contextValidateEndpoints(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *KVstoreStatus) contextValidateEndpoints(ctx context.Context, formats strfmt.Registry) error {
+
+	for i := 0; i < len(m.Endpoints); i++ {
+
+		if m.Endpoints[i] != nil {
+
+			if swag.IsZero(m.Endpoints[i]) { // not required
+				return nil
+			}
+
+			if err := m.Endpoints[i].ContextValidate(ctx, formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("endpoints" + "." + strconv.Itoa(i))
+				} else if ce, ok := err.(*errors.CompositeError); ok {
+					return ce.ValidateName("endpoints" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *KVstoreStatus) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *KVstoreStatus) UnmarshalBinary(b []byte) error {
+	var res KVstoreStatus
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
@@ -102,6 +102,9 @@ type StatusResponse struct {
 	// Status of key/value datastore
 	Kvstore *Status `json:"kvstore,omitempty"`
 
+	// Structured status of key/value datastore
+	KvstoreDetail *KVstoreStatus `json:"kvstore-detail,omitempty"`
+
 	// Status of masquerading
 	Masquerading *Masquerading `json:"masquerading,omitempty"`
 
@@ -221,6 +224,10 @@ func (m *StatusResponse) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateKvstoreDetail(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.validateMasquerading(formats); err != nil {
 		res = append(res, err)
 	}
@@ -701,6 +708,25 @@ func (m *StatusResponse) validateKvstore(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *StatusResponse) validateKvstoreDetail(formats strfmt.Registry) error {
+	if swag.IsZero(m.KvstoreDetail) { // not required
+		return nil
+	}
+
+	if m.KvstoreDetail != nil {
+		if err := m.KvstoreDetail.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("kvstore-detail")
+			} else if ce, ok := err.(*errors.CompositeError); ok {
+				return ce.ValidateName("kvstore-detail")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *StatusResponse) validateMasquerading(formats strfmt.Registry) error {
 	if swag.IsZero(m.Masquerading) { // not required
 		return nil
@@ -912,6 +938,10 @@ func (m *StatusResponse) ContextValidate(ctx context.Context, formats strfmt.Reg
 		res = append(res, err)
 	}
 
+	if err := m.contextValidateKvstoreDetail(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.contextValidateMasquerading(ctx, formats); err != nil {
 		res = append(res, err)
 	}
@@ -1429,6 +1459,27 @@ func (m *StatusResponse) contextValidateKvstore(ctx context.Context, formats str
 	return nil
 }
 
+func (m *StatusResponse) contextValidateKvstoreDetail(ctx context.Context, formats strfmt.Registry) error {
+
+	if m.KvstoreDetail != nil {
+
+		if swag.IsZero(m.KvstoreDetail) { // not required
+			return nil
+		}
+
+		if err := m.KvstoreDetail.ContextValidate(ctx, formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("kvstore-detail")
+			} else if ce, ok := err.(*errors.CompositeError); ok {
+				return ce.ValidateName("kvstore-detail")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *StatusResponse) contextValidateMasquerading(ctx context.Context, formats strfmt.Registry) error {
 
 	if m.Masquerading != nil {
@@ -397,6 +397,49 @@ func (in *K8sStatus) DeepCopy() *K8sStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KVstoreEndpointState) DeepCopyInto(out *KVstoreEndpointState) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KVstoreEndpointState.
+func (in *KVstoreEndpointState) DeepCopy() *KVstoreEndpointState {
+	if in == nil {
+		return nil
+	}
+	out := new(KVstoreEndpointState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KVstoreStatus) DeepCopyInto(out *KVstoreStatus) {
+	*out = *in
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]*KVstoreEndpointState, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(KVstoreEndpointState)
+				**out = **in
+			}
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KVstoreStatus.
+func (in *KVstoreStatus) DeepCopy() *KVstoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KVstoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KubeProxyReplacement) DeepCopyInto(out *KubeProxyReplacement) {
 	*out = *in
@@ -966,6 +1009,11 @@ func (in *StatusResponse) DeepCopyInto(out *StatusResponse) {
 		*out = new(Status)
 		**out = **in
 	}
+	if in.KvstoreDetail != nil {
+		in, out := &in.KvstoreDetail, &out.KvstoreDetail
+		*out = new(KVstoreStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Masquerading != nil {
 		in, out := &in.Masquerading, &out.Masquerading
 		*out = new(Masquerading)
@@ -1206,6 +1206,41 @@ func init() {
         }
       }
     },
+    "/map/{name}/dump": {
+      "get": {
+        "description": "Streams the current entries of the BPF map as newline-delimited JSON,\none BPFMapEntry object per line. The entries are read through the\nbatch lookup API and emitted in pages, so that arbitrarily large maps\ncan be dumped with bounded memory usage on both sides.\n",
+        "tags": [
+          "daemon"
+        ],
+        "summary": "Retrieves the entries of a BPF map as a stream",
+        "parameters": [
+          {
+            "$ref": "#/parameters/map-name"
+          },
+          {
+            "type": "integer",
+            "description": "Number of entries per flushed page",
+            "name": "page-size",
+            "in": "query"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "type": "string",
+              "format": "binary"
+            }
+          },
+          "404": {
+            "description": "Map not found"
+          },
+          "501": {
+            "description": "Streaming dump not supported for this map"
+          }
+        }
+      }
+    },
     "/map/{name}/events": {
       "get": {
         "tags": [
@@ -3610,6 +3645,65 @@ func init() {
         }
       }
     },
+    "KVstoreEndpointState": {
+      "description": "Status of an individual kvstore endpoint\n\n+k8s:deepcopy-gen=true",
+      "type": "object",
+      "properties": {
+        "endpoint": {
+          "description": "Address of the endpoint",
+          "type": "string"
+        },
+        "error": {
+          "description": "Error encountered while checking the endpoint status",
+          "type": "string"
+        },
+        "leader": {
+          "description": "Whether the endpoint is the current cluster leader",
+          "type": "boolean"
+        },
+        "version": {
+          "description": "Server version reported by the endpoint",
+          "type": "string"
+        }
+      }
+    },
+    "KVstoreStatus": {
+      "description": "Structured status of the key/value datastore, complementing the\nhuman-readable summary\n\n+k8s:deepcopy-gen=true",
+      "type": "object",
+      "properties": {
+        "consecutive-quorum-errors": {
+          "description": "Number of consecutive quorum check failures",
+          "type": "integer"
+        },
+        "endpoints": {
+          "description": "Status of the individual kvstore endpoints",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/KVstoreEndpointState"
+          }
+        },
+        "has-quorum": {
+          "description": "Whether the kvstore cluster currently has quorum",
+          "type": "boolean"
+        },
+        "last-heartbeat-age-seconds": {
+          "description": "Seconds elapsed since the last heartbeat update was received",
+          "type": "number"
+        },
+        "leases": {
+          "description": "Number of currently acquired generic leases",
+          "type": "integer"
+        },
+        "lock-leases": {
+          "description": "Number of currently acquired lock leases",
+          "type": "integer"
+        },
+        "quorum-error": {
+          "description": "Last quorum check failure, set when quorum is not established",
+          "type": "string"
+        }
+      }
+    },
     "KubeProxyReplacement": {
       "description": "Status of kube-proxy replacement\n\n+k8s:deepcopy-gen=true",
       "type": "object",
@@ -5040,6 +5134,10 @@ func init() {
           "description": "Status of key/value datastore",
           "$ref": "#/definitions/Status"
         },
+        "kvstore-detail": {
+          "description": "Structured status of key/value datastore",
+          "$ref": "#/definitions/KVstoreStatus"
+        },
         "masquerading": {
           "description": "Status of masquerading",
           "$ref": "#/definitions/Masquerading"
@@ -6764,6 +6862,45 @@ func init() {
         }
       }
     },
+    "/map/{name}/dump": {
+      "get": {
+        "description": "Streams the current entries of the BPF map as newline-delimited JSON,\none BPFMapEntry object per line. The entries are read through the\nbatch lookup API and emitted in pages, so that arbitrarily large maps\ncan be dumped with bounded memory usage on both sides.\n",
+        "tags": [
+          "daemon"
+        ],
+        "summary": "Retrieves the entries of a BPF map as a stream",
+        "parameters": [
+          {
+            "type": "string",
+            "description": "Name of map",
+            "name": "name",
+            "in": "path",
+            "required": true
+          },
+          {
+            "type": "integer",
+            "description": "Number of entries per flushed page",
+            "name": "page-size",
+            "in": "query"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "type": "string",
+              "format": "binary"
+            }
+          },
+          "404": {
+            "description": "Map not found"
+          },
+          "501": {
+            "description": "Streaming dump not supported for this map"
+          }
+        }
+      }
+    },
     "/map/{name}/events": {
       "get": {
         "tags": [
@@ -9318,6 +9455,65 @@ func init() {
         }
       }
     },
+    "KVstoreEndpointState": {
+      "description": "Status of an individual kvstore endpoint\n\n+k8s:deepcopy-gen=true",
+      "type": "object",
+      "properties": {
+        "endpoint": {
+          "description": "Address of the endpoint",
+          "type": "string"
+        },
+        "error": {
+          "description": "Error encountered while checking the endpoint status",
+          "type": "string"
+        },
+        "leader": {
+          "description": "Whether the endpoint is the current cluster leader",
+          "type": "boolean"
+        },
+        "version": {
+          "description": "Server version reported by the endpoint",
+          "type": "string"
+        }
+      }
+    },
+    "KVstoreStatus": {
+      "description": "Structured status of the key/value datastore, complementing the\nhuman-readable summary\n\n+k8s:deepcopy-gen=true",
+      "type": "object",
+      "properties": {
+        "consecutive-quorum-errors": {
+          "description": "Number of consecutive quorum check failures",
+          "type": "integer"
+        },
+        "endpoints": {
+          "description": "Status of the individual kvstore endpoints",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/KVstoreEndpointState"
+          }
+        },
+        "has-quorum": {
+          "description": "Whether the kvstore cluster currently has quorum",
+          "type": "boolean"
+        },
+        "last-heartbeat-age-seconds": {
+          "description": "Seconds elapsed since the last heartbeat update was received",
+          "type": "number"
+        },
+        "leases": {
+          "description": "Number of currently acquired generic leases",
+          "type": "integer"
+        },
+        "lock-leases": {
+          "description": "Number of currently acquired lock leases",
+          "type": "integer"
+        },
+        "quorum-error": {
+          "description": "Last quorum check failure, set when quorum is not established",
+          "type": "string"
+        }
+      }
+    },
     "KubeProxyReplacement": {
       "description": "Status of kube-proxy replacement\n\n+k8s:deepcopy-gen=true",
       "type": "object",
@@ -11162,6 +11358,10 @@ func init() {
           "description": "Status of key/value datastore",
           "$ref": "#/definitions/Status"
         },
+        "kvstore-detail": {
+          "description": "Structured status of key/value datastore",
+          "$ref": "#/definitions/KVstoreStatus"
+        },
         "masquerading": {
           "description": "Status of masquerading",
           "$ref": "#/definitions/Masquerading"
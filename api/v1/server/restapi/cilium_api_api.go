@@ -147,6 +147,9 @@ func NewCiliumAPIAPI(spec *loads.Document) *CiliumAPIAPI {
 		DaemonGetMapNameHandler: daemon.GetMapNameHandlerFunc(func(params daemon.GetMapNameParams) middleware.Responder {
 			return middleware.NotImplemented("operation daemon.GetMapName has not yet been implemented")
 		}),
+		DaemonGetMapNameDumpHandler: daemon.GetMapNameDumpHandlerFunc(func(params daemon.GetMapNameDumpParams) middleware.Responder {
+			return middleware.NotImplemented("operation daemon.GetMapNameDump has not yet been implemented")
+		}),
 		DaemonGetMapNameEventsHandler: daemon.GetMapNameEventsHandlerFunc(func(params daemon.GetMapNameEventsParams) middleware.Responder {
 			return middleware.NotImplemented("operation daemon.GetMapNameEvents has not yet been implemented")
 		}),
@@ -302,6 +305,8 @@ type CiliumAPIAPI struct {
 	DaemonGetMapHandler daemon.GetMapHandler
 	// DaemonGetMapNameHandler sets the operation handler for the get map name operation
 	DaemonGetMapNameHandler daemon.GetMapNameHandler
+	// DaemonGetMapNameDumpHandler sets the operation handler for the get map name dump operation
+	DaemonGetMapNameDumpHandler daemon.GetMapNameDumpHandler
 	// DaemonGetMapNameEventsHandler sets the operation handler for the get map name events operation
 	DaemonGetMapNameEventsHandler daemon.GetMapNameEventsHandler
 	// DaemonGetNodeIdsHandler sets the operation handler for the get node ids operation
@@ -510,6 +515,9 @@ func (o *CiliumAPIAPI) Validate() error {
 	if o.DaemonGetMapNameHandler == nil {
 		unregistered = append(unregistered, "daemon.GetMapNameHandler")
 	}
+	if o.DaemonGetMapNameDumpHandler == nil {
+		unregistered = append(unregistered, "daemon.GetMapNameDumpHandler")
+	}
 	if o.DaemonGetMapNameEventsHandler == nil {
 		unregistered = append(unregistered, "daemon.GetMapNameEventsHandler")
 	}
@@ -782,6 +790,10 @@ func (o *CiliumAPIAPI) initHandlerCache() {
 	if o.handlers["GET"] == nil {
 		o.handlers["GET"] = make(map[string]http.Handler)
 	}
+	o.handlers["GET"]["/map/{name}/dump"] = daemon.NewGetMapNameDump(o.context, o.DaemonGetMapNameDumpHandler)
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
 	o.handlers["GET"]["/map/{name}/events"] = daemon.NewGetMapNameEvents(o.context, o.DaemonGetMapNameEventsHandler)
 	if o.handlers["GET"] == nil {
 		o.handlers["GET"] = make(map[string]http.Handler)
@@ -0,0 +1,64 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Authors of Cilium
+// SPDX-License-Identifier: Apache-2.0
+
+package daemon
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime/middleware"
+)
+
+// GetMapNameDumpHandlerFunc turns a function with the right signature into a get map name dump handler
+type GetMapNameDumpHandlerFunc func(GetMapNameDumpParams) middleware.Responder
+
+// Handle executing the request and returning a response
+func (fn GetMapNameDumpHandlerFunc) Handle(params GetMapNameDumpParams) middleware.Responder {
+	return fn(params)
+}
+
+// GetMapNameDumpHandler interface for that can handle valid get map name dump params
+type GetMapNameDumpHandler interface {
+	Handle(GetMapNameDumpParams) middleware.Responder
+}
+
+// NewGetMapNameDump creates a new http.Handler for the get map name dump operation
+func NewGetMapNameDump(ctx *middleware.Context, handler GetMapNameDumpHandler) *GetMapNameDump {
+	return &GetMapNameDump{Context: ctx, Handler: handler}
+}
+
+/*
+	GetMapNameDump swagger:route GET /map/{name}/dump daemon getMapNameDump
+
+# Retrieves the entries of a BPF map as a stream
+
+Streams the current entries of the BPF map as newline-delimited JSON,
+one BPFMapEntry object per line. The entries are read through the
+batch lookup API and emitted in pages, so that arbitrarily large maps
+can be dumped with bounded memory usage on both sides.
+*/
+type GetMapNameDump struct {
+	Context *middleware.Context
+	Handler GetMapNameDumpHandler
+}
+
+func (o *GetMapNameDump) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	route, rCtx, _ := o.Context.RouteInfo(r)
+	if rCtx != nil {
+		*r = *rCtx
+	}
+	var Params = NewGetMapNameDumpParams()
+	if err := o.Context.BindValidRequest(r, route, &Params); err != nil { // bind params
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+
+	res := o.Handler.Handle(Params) // actually handle the request
+	o.Context.Respond(rw, r, route.Produces, route, res)
+
+}
@@ -0,0 +1,109 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+// Copyright Authors of Cilium
+// SPDX-License-Identifier: Apache-2.0
+
+package daemon
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+)
+
+// GetMapNameDumpOKCode is the HTTP code returned for type GetMapNameDumpOK
+const GetMapNameDumpOKCode int = 200
+
+/*
+GetMapNameDumpOK Success
+
+swagger:response getMapNameDumpOK
+*/
+type GetMapNameDumpOK struct {
+
+	/*
+	  In: Body
+	*/
+	Payload io.ReadCloser `json:"body,omitempty"`
+}
+
+// NewGetMapNameDumpOK creates GetMapNameDumpOK with default headers values
+func NewGetMapNameDumpOK() *GetMapNameDumpOK {
+
+	return &GetMapNameDumpOK{}
+}
+
+// WithPayload adds the payload to the get map name dump o k response
+func (o *GetMapNameDumpOK) WithPayload(payload io.ReadCloser) *GetMapNameDumpOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get map name dump o k response
+func (o *GetMapNameDumpOK) SetPayload(payload io.ReadCloser) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetMapNameDumpOK) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(200)
+	payload := o.Payload
+	if err := producer.Produce(rw, payload); err != nil {
+		panic(err) // let the recovery middleware deal with this
+	}
+}
+
+// GetMapNameDumpNotFoundCode is the HTTP code returned for type GetMapNameDumpNotFound
+const GetMapNameDumpNotFoundCode int = 404
+
+/*
+GetMapNameDumpNotFound Map not found
+
+swagger:response getMapNameDumpNotFound
+*/
+type GetMapNameDumpNotFound struct {
+}
+
+// NewGetMapNameDumpNotFound creates GetMapNameDumpNotFound with default headers values
+func NewGetMapNameDumpNotFound() *GetMapNameDumpNotFound {
+
+	return &GetMapNameDumpNotFound{}
+}
+
+// WriteResponse to the client
+func (o *GetMapNameDumpNotFound) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.Header().Del(runtime.HeaderContentType) //Remove Content-Type on empty responses
+
+	rw.WriteHeader(404)
+}
+
+// GetMapNameDumpNotImplementedCode is the HTTP code returned for type GetMapNameDumpNotImplemented
+const GetMapNameDumpNotImplementedCode int = 501
+
+/*
+GetMapNameDumpNotImplemented Streaming dump not supported for this map
+
+swagger:response getMapNameDumpNotImplemented
+*/
+type GetMapNameDumpNotImplemented struct {
+}
+
+// NewGetMapNameDumpNotImplemented creates GetMapNameDumpNotImplemented with default headers values
+func NewGetMapNameDumpNotImplemented() *GetMapNameDumpNotImplemented {
+
+	return &GetMapNameDumpNotImplemented{}
+}
+
+// WriteResponse to the client
+func (o *GetMapNameDumpNotImplemented) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.Header().Del(runtime.HeaderContentType) //Remove Content-Type on empty responses
+
+	rw.WriteHeader(501)
+}
@@ -96,6 +96,7 @@ type apiParams struct {
 	ServiceGetLrpHandler                 service.GetLrpHandler
 	DaemonGetMapHandler                  daemon.GetMapHandler
 	DaemonGetMapNameHandler              daemon.GetMapNameHandler
+	DaemonGetMapNameDumpHandler          daemon.GetMapNameDumpHandler
 	DaemonGetMapNameEventsHandler        daemon.GetMapNameEventsHandler
 	DaemonGetNodeIdsHandler              daemon.GetNodeIdsHandler
 	PolicyGetPolicyHandler               policy.GetPolicyHandler
@@ -153,6 +154,7 @@ func newAPI(p apiParams) *restapi.CiliumAPIAPI {
 	api.ServiceGetLrpHandler = p.ServiceGetLrpHandler
 	api.DaemonGetMapHandler = p.DaemonGetMapHandler
 	api.DaemonGetMapNameHandler = p.DaemonGetMapNameHandler
+	api.DaemonGetMapNameDumpHandler = p.DaemonGetMapNameDumpHandler
 	api.DaemonGetMapNameEventsHandler = p.DaemonGetMapNameEventsHandler
 	api.DaemonGetNodeIdsHandler = p.DaemonGetNodeIdsHandler
 	api.PolicyGetPolicyHandler = p.PolicyGetPolicyHandler
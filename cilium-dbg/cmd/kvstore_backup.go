@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cilium/cilium/pkg/kvstore"
+)
+
+var kvstoreBackupOutput string
+
+var kvstoreBackupCmd = &cobra.Command{
+	Use:     "backup [options] <prefix>",
+	Short:   "Snapshot all keys under a prefix to a restorable backup stream",
+	Example: "cilium kvstore backup --output backup.json cilium/",
+	Run: func(cmd *cobra.Command, args []string) {
+		prefix := ""
+		if len(args) > 0 {
+			prefix = args[0]
+		}
+
+		out := io.Writer(os.Stdout)
+		if kvstoreBackupOutput != "" {
+			f, err := os.Create(kvstoreBackupOutput)
+			if err != nil {
+				Fatalf("Unable to create output file: %s", err)
+			}
+			defer f.Close()
+			out = f
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+
+		client := setupKvstore(ctx, log)
+
+		count, err := kvstore.Backup(ctx, client, prefix, out)
+		if err != nil {
+			Fatalf("Unable to back up prefix %s: %s", prefix, err)
+		}
+		fmt.Fprintf(os.Stderr, "Backed up %d keys under %s\n", count, prefix)
+	},
+}
+
+var kvstoreRestoreCmd = &cobra.Command{
+	Use:     "restore [options] <file>",
+	Short:   "Restore keys from a backup stream created with backup",
+	Example: "cilium kvstore restore backup.json",
+	Run: func(cmd *cobra.Command, args []string) {
+		in := io.Reader(os.Stdin)
+		if len(args) > 0 && args[0] != "-" {
+			f, err := os.Open(args[0])
+			if err != nil {
+				Fatalf("Unable to open backup file: %s", err)
+			}
+			defer f.Close()
+			in = f
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+
+		client := setupKvstore(ctx, log)
+
+		restored, err := kvstore.Restore(ctx, client, in)
+		if err != nil {
+			Fatalf("Unable to restore backup: %s", err)
+		}
+		fmt.Printf("Restored %d keys\n", restored)
+	},
+}
+
+func init() {
+	kvstoreCmd.AddCommand(kvstoreBackupCmd)
+	kvstoreBackupCmd.Flags().StringVar(&kvstoreBackupOutput, "output", "", "Write the backup to the given file instead of stdout")
+	kvstoreCmd.AddCommand(kvstoreRestoreCmd)
+}
@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/go-openapi/runtime"
+	runtime_client "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+	"github.com/spf13/cobra"
+
+	clientapi "github.com/cilium/cilium/api/v1/client"
+	daemonAPI "github.com/cilium/cilium/api/v1/client/daemon"
+	"github.com/cilium/cilium/api/v1/models"
+	clientPkg "github.com/cilium/cilium/pkg/client"
+	"github.com/cilium/cilium/pkg/command"
+)
+
+var dumpMapPageSize int64
+
+// mapDumpCmd represents the map dump command
+var mapDumpCmd = &cobra.Command{
+	Use:     "dump <name>",
+	Short:   "Display the content of given BPF map as a stream",
+	Long: `Streams the current entries of the given BPF map from the kernel,
+without loading the full dump into memory on either side. Only maps
+supporting batched lookups can be dumped this way; use "cilium map get"
+to inspect the cached content of the other maps.`,
+	Example: "cilium map dump cilium_ct4_global",
+	Run: func(_ *cobra.Command, args []string) {
+		if len(args) == 0 || args[0] == "" {
+			Fatalf("map name must be specified")
+		}
+
+		var rt *runtime_client.Runtime
+		if r, err := clientPkg.NewRuntime(clientPkg.WithHost(vp.GetString("host"))); err != nil {
+			Fatalf("Error while creating client: %s\n", err)
+		} else {
+			rt = r
+		}
+
+		rt.Consumers[runtime.JSONMime] = runtime.ByteStreamConsumer()
+		c := &clientPkg.Client{CiliumAPI: *clientapi.New(rt, strfmt.Default)}
+
+		reader, writer := io.Pipe()
+		dec := json.NewDecoder(reader)
+
+		wg := &sync.WaitGroup{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				entry := &models.BPFMapEntry{}
+				err := dec.Decode(entry)
+				if errors.Is(err, io.EOF) {
+					return
+				}
+				if err != nil {
+					Fatalf("error while reading stream: %s", err)
+				}
+				if command.OutputOption() {
+					if err := command.PrintOutput(entry); err != nil {
+						Fatalf("could not dump data to specified output format: %s", err.Error())
+					}
+				} else {
+					fmt.Fprintf(os.Stdout, "key=%s value=%s\n", entry.Key, entry.Value)
+				}
+			}
+		}()
+
+		params := daemonAPI.NewGetMapNameDumpParamsWithContext(context.Background()).WithName(args[0])
+		if dumpMapPageSize > 0 {
+			params = params.WithPageSize(&dumpMapPageSize)
+		}
+
+		_, err := c.Daemon.GetMapNameDump(params, writer)
+		if err != nil {
+			Fatalf("could not dump map: %s", err)
+		}
+
+		_ = writer.Close()
+		wg.Wait()
+	},
+}
+
+func init() {
+	MAPCmd.AddCommand(mapDumpCmd)
+	mapDumpCmd.Flags().Int64Var(&dumpMapPageSize, "page-size", 0, "Number of entries per flushed page (0 selects the server default)")
+	command.AddOutputOption(mapDumpCmd)
+}
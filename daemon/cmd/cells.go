@@ -25,6 +25,7 @@ import (
 	cgroup "github.com/cilium/cilium/pkg/cgroups/manager"
 	"github.com/cilium/cilium/pkg/ciliumenvoyconfig"
 	"github.com/cilium/cilium/pkg/clustermesh"
+	"github.com/cilium/cilium/pkg/clustermesh/clustercfg"
 	cmtypes "github.com/cilium/cilium/pkg/clustermesh/types"
 	"github.com/cilium/cilium/pkg/controller"
 	"github.com/cilium/cilium/pkg/crypto/certificatemanager"
@@ -117,6 +118,13 @@ var (
 		kvstore.Cell(kvstore.DisabledBackendName),
 		cell.Invoke(kvstoreLocksGC),
 
+		// Guard all kvstore write paths against overwriting the keys of
+		// another cluster in case of cluster identity misconfiguration.
+		cell.DecorateAll(func(client kvstore.Client, cinfo cmtypes.ClusterInfo) kvstore.Client {
+			return clustercfg.GuardWrites(client, cinfo,
+				kvstore.NodesPrefix, kvstore.ClusterConfigPrefix)
+		}),
+
 		cni.Cell,
 
 		// Provide the modular metrics registry, metric HTTP server and legacy metrics cell.
@@ -285,7 +293,14 @@ var (
 
 		// The node discovery cell provides the local node configuration and node discovery
 		// which communicate changes in local node information to the API server or KVStore.
-		nodediscovery.Cell,
+		// Its kvstore client is restricted to the nodes keyspace, so that a programming
+		// error cannot corrupt the keys of another subsystem.
+		cell.Decorate(
+			func(client kvstore.Client) kvstore.Client {
+				return kvstore.RestrictClient(client, "nodediscovery", kvstore.NodesPrefix)
+			},
+			nodediscovery.Cell,
+		),
 
 		// Cgroup manager maintains Kubernetes and low-level metadata (cgroup path and
 		// cgroup id) for local pods and their containers.
@@ -415,9 +430,14 @@ func kvstoreExtraOptions(in struct {
 	NodeManager nodeManager.NodeManager
 	ClientSet   k8sClient.Clientset
 	Resolver    *dial.ServiceResolver
+	Warmup      *kvstore.WatchWarmup
 }) (kvstore.ExtraOptions, kvstore.BootstrapStat) {
 	goopts := kvstore.ExtraOptions{
 		ClusterSizeDependantInterval: in.NodeManager.ClusterSizeDependantInterval,
+
+		// Lift the bootstrap rate limit once all the prefixes registered
+		// with the watch warm-up completed their initial listing.
+		BootstrapComplete: in.Warmup.SyncedChannel(),
 	}
 
 	// If K8s is enabled we can do the service translation automagically by
@@ -208,7 +208,8 @@ const (
 	LBIPAMSharingAcrossNamespace      = LBIPAMPrefix + "/sharing-cross-namespace"
 	LBIPAMSharingAcrossNamespaceAlias = Prefix + "/lb-ipam-sharing-cross-namespace"
 
-	CECInjectCiliumFilters = CECPrefix + "/inject-cilium-filters"
+	CECInjectCiliumFilters          = CECPrefix + "/inject-cilium-filters"
+	CECDefaultListenerSocketOptions = CECPrefix + "/default-listener-socket-options"
 )
 
 // CiliumPrefixRegex is a regex matching Cilium specific annotations.
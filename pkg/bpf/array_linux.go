@@ -0,0 +1,177 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+//go:build linux
+
+package bpf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+
+	"github.com/cilium/ebpf"
+)
+
+// Len returns the number of slots of this map, i.e. its max_entries. For
+// array maps every index below Len is always allocated, so Len also equals
+// the number of entries yielded by an ordered iteration.
+func (m *Map) Len() int {
+	return int(m.MaxEntries())
+}
+
+// ArrayIterator provides a typed iterator over Array maps, yielding the
+// entries ordered by index. Unlike the generic dump functions, which walk
+// the map via NextKey and thus impose no ordering, it looks up each index
+// from 0 up to Len() in turn. Indices which unexpectedly fail the lookup
+// are skipped and reported via Gaps() once iteration has finished.
+//
+// The pointer type of VT must implement MapValue.
+//
+// Example usage:
+//
+//	iter := NewArrayIterator[TestValue](m)
+//	for idx, v := range iter.IterateAll(context.TODO()) {
+//		// ...
+//	}
+type ArrayIterator[VT any, VP ValuePointer[VT]] struct {
+	m    *Map
+	gaps []uint32
+	err  error
+}
+
+// NewArrayIterator returns an ordered typed iterator over the given Array
+// map. Any error encountered during iteration can be accessed via the Err()
+// function once iteration has finished.
+//
+// Subsequent iterations via IterateAll reset all internal state and begin
+// iteration over.
+func NewArrayIterator[VT any, VP ValuePointer[VT]](m *Map) *ArrayIterator[VT, VP] {
+	return &ArrayIterator[VT, VP]{m: m}
+}
+
+// Err returns errors encountered during the previous iteration when
+// IterateAll(...) is called.
+//
+// If the iterator is reused, the error will be reset.
+func (ai *ArrayIterator[VT, VP]) Err() error {
+	return ai.err
+}
+
+// Gaps returns the indices skipped during the previous iteration because
+// their lookup reported a missing key, which is unexpected for array maps
+// as all slots are pre-allocated.
+func (ai *ArrayIterator[VT, VP]) Gaps() []uint32 {
+	return ai.gaps
+}
+
+// IterateAll returns an iterator over all slots of the map, ordered by
+// index.
+func (ai *ArrayIterator[VT, VP]) IterateAll(ctx context.Context) iter.Seq2[uint32, VP] {
+	ai.err = nil
+	ai.gaps = nil
+
+	return func(yield func(uint32, VP) bool) {
+		if t := ai.m.Type(); t != ebpf.Array {
+			ai.err = fmt.Errorf("map %s is of type %s, expected %s", ai.m.name, t, ebpf.Array)
+			return
+		}
+
+		if err := ai.m.Open(); err != nil {
+			ai.err = err
+			return
+		}
+
+		for idx := uint32(0); idx < uint32(ai.m.Len()); idx++ {
+			if ctx.Err() != nil {
+				ai.err = ctx.Err()
+				return
+			}
+
+			var value VT
+			if err := ai.m.m.Lookup(&idx, VP(&value)); err != nil {
+				if errors.Is(err, ebpf.ErrKeyNotExist) {
+					ai.gaps = append(ai.gaps, idx)
+					continue
+				}
+				ai.err = fmt.Errorf("failed to look up index %d: %w", idx, err)
+				return
+			}
+
+			if !yield(idx, &value) {
+				return
+			}
+		}
+	}
+}
+
+// PerCPUArrayIterator provides a typed iterator over PerCPUArray maps,
+// yielding for each index, in order, the slice with the values of all CPUs.
+// It follows the same conventions as ArrayIterator.
+type PerCPUArrayIterator[VT any, VP ValuePointer[VT]] struct {
+	m    *Map
+	gaps []uint32
+	err  error
+}
+
+// NewPerCPUArrayIterator returns an ordered typed iterator over the given
+// PerCPUArray map. Any error encountered during iteration can be accessed
+// via the Err() function once iteration has finished.
+func NewPerCPUArrayIterator[VT any, VP ValuePointer[VT]](m *Map) *PerCPUArrayIterator[VT, VP] {
+	return &PerCPUArrayIterator[VT, VP]{m: m}
+}
+
+// Err returns errors encountered during the previous iteration when
+// IterateAll(...) is called.
+//
+// If the iterator is reused, the error will be reset.
+func (ai *PerCPUArrayIterator[VT, VP]) Err() error {
+	return ai.err
+}
+
+// Gaps returns the indices skipped during the previous iteration because
+// their lookup reported a missing key.
+func (ai *PerCPUArrayIterator[VT, VP]) Gaps() []uint32 {
+	return ai.gaps
+}
+
+// IterateAll returns an iterator over all slots of the map, ordered by
+// index, yielding the values of all CPUs for each slot.
+func (ai *PerCPUArrayIterator[VT, VP]) IterateAll(ctx context.Context) iter.Seq2[uint32, []VT] {
+	ai.err = nil
+	ai.gaps = nil
+
+	return func(yield func(uint32, []VT) bool) {
+		if t := ai.m.Type(); t != ebpf.PerCPUArray {
+			ai.err = fmt.Errorf("map %s is of type %s, expected %s", ai.m.name, t, ebpf.PerCPUArray)
+			return
+		}
+
+		if err := ai.m.Open(); err != nil {
+			ai.err = err
+			return
+		}
+
+		for idx := uint32(0); idx < uint32(ai.m.Len()); idx++ {
+			if ctx.Err() != nil {
+				ai.err = ctx.Err()
+				return
+			}
+
+			var values []VT
+			if err := ai.m.m.Lookup(&idx, &values); err != nil {
+				if errors.Is(err, ebpf.ErrKeyNotExist) {
+					ai.gaps = append(ai.gaps, idx)
+					continue
+				}
+				ai.err = fmt.Errorf("failed to look up index %d: %w", idx, err)
+				return
+			}
+
+			if !yield(idx, values) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+//go:build linux
+
+package bpf
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/rlimit"
+	"github.com/cilium/hive/hivetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cilium/cilium/pkg/testutils"
+)
+
+func TestArrayIteratorTypes(t *testing.T) {
+	m := NewMap("cilium_test",
+		ebpf.Hash,
+		&TestKey{},
+		&TestValue{}, 1, 0)
+	iter := NewArrayIterator[TestValue](m)
+	for range iter.IterateAll(context.TODO()) {
+	}
+	assert.Error(t, iter.Err())
+
+	perCPUIter := NewPerCPUArrayIterator[TestValue](m)
+	for range perCPUIter.IterateAll(context.TODO()) {
+	}
+	assert.Error(t, perCPUIter.Err())
+}
+
+func TestPrivilegedArrayIterator(t *testing.T) {
+	testutils.PrivilegedTest(t)
+
+	CheckOrMountFS(hivetest.Logger(t), "")
+
+	err := rlimit.RemoveMemlock()
+	require.NoError(t, err)
+
+	const mapSize = 8
+
+	m := NewMap("cilium_test_array",
+		ebpf.Array,
+		&TestKey{},
+		&TestValue{},
+		mapSize,
+		0,
+	)
+	require.NoError(t, m.OpenOrCreate())
+	t.Cleanup(func() {
+		require.NoError(t, m.Close())
+	})
+
+	require.Equal(t, mapSize, m.Len())
+
+	for i := range uint32(mapSize) {
+		require.NoError(t, m.Update(&TestKey{Key: i}, &TestValue{Value: 100 + i}))
+	}
+
+	// All slots are yielded in index order.
+	iter := NewArrayIterator[TestValue](m)
+	var indices []uint32
+	for idx, v := range iter.IterateAll(context.TODO()) {
+		indices = append(indices, idx)
+		require.Equal(t, 100+idx, v.Value)
+	}
+	require.NoError(t, iter.Err())
+	require.Empty(t, iter.Gaps())
+	require.Equal(t, []uint32{0, 1, 2, 3, 4, 5, 6, 7}, indices)
+
+	// Breaking out of the iteration early is supported.
+	count := 0
+	for range iter.IterateAll(context.TODO()) {
+		count++
+		if count == 3 {
+			break
+		}
+	}
+	require.NoError(t, iter.Err())
+	require.Equal(t, 3, count)
+}
+
+func TestPrivilegedPerCPUArrayIterator(t *testing.T) {
+	testutils.PrivilegedTest(t)
+
+	CheckOrMountFS(hivetest.Logger(t), "")
+
+	err := rlimit.RemoveMemlock()
+	require.NoError(t, err)
+
+	const mapSize = 4
+
+	m := NewMap("cilium_test_percpu_array",
+		ebpf.PerCPUArray,
+		&TestKey{},
+		&TestValue{},
+		mapSize,
+		0,
+	)
+	require.NoError(t, m.OpenOrCreate())
+	t.Cleanup(func() {
+		require.NoError(t, m.Close())
+	})
+
+	require.Equal(t, mapSize, m.Len())
+
+	iter := NewPerCPUArrayIterator[TestValue](m)
+	var indices []uint32
+	for idx, values := range iter.IterateAll(context.TODO()) {
+		indices = append(indices, idx)
+		require.NotEmpty(t, values)
+	}
+	require.NoError(t, iter.Err())
+	require.Empty(t, iter.Gaps())
+	require.Equal(t, []uint32{0, 1, 2, 3}, indices)
+}
@@ -156,7 +156,15 @@ func OpenOrCreateMap(logger *slog.Logger, spec *ebpf.MapSpec, pinDir string) (*e
 			return nil, err
 		}
 
-		return createMap(spec, &opts)
+		em, err := createMap(spec, &opts)
+		if err == nil {
+			recordPinMetadata(logger, spec)
+		}
+		return em, err
+	}
+
+	if err == nil && spec.Pinning != 0 {
+		recordPinMetadata(logger, spec)
 	}
 
 	return m, err
@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+//go:build linux
+
+package bpf
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/cilium/cilium/pkg/mountinfo"
+)
+
+// ValidatePinRoot verifies that the given path is usable as the root for
+// map pins: it must be a directory on a bpffs instance, owned by root and
+// not writable by other users. This guards against pinning maps below a
+// directory another (possibly unprivileged) entity controls.
+func ValidatePinRoot(path string) error {
+	var fst unix.Statfs_t
+	if err := unix.Statfs(path, &fst); err != nil {
+		return &os.PathError{Op: "statfs", Path: path, Err: err}
+	}
+	if fst.Type != unix.BPF_FS_MAGIC {
+		return fmt.Errorf("%s is not on a BPF filesystem", path)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", path)
+	}
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok && stat.Uid != 0 {
+		return fmt.Errorf("%s is not owned by root (uid %d)", path, stat.Uid)
+	}
+	if info.Mode().Perm()&0o002 != 0 {
+		return fmt.Errorf("%s is world-writable", path)
+	}
+
+	return nil
+}
+
+// BPFFSRootForMountNS returns the bpffs root mounted in the mount namespace
+// of the given process, resolved through /proc/<pid>/root so that it is
+// reachable from the mount namespace of the caller. The returned path has
+// been validated via ValidatePinRoot.
+func BPFFSRootForMountNS(pid int) (string, error) {
+	mountInfos, err := mountinfo.GetMountInfoFromPID(pid)
+	if err != nil {
+		return "", err
+	}
+
+	for _, mountInfo := range mountInfos {
+		if mountInfo.FilesystemType != "bpf" {
+			continue
+		}
+
+		root := filepath.Join("/proc", strconv.Itoa(pid), "root", mountInfo.MountPoint)
+		if err := ValidatePinRoot(root); err != nil {
+			return "", fmt.Errorf("invalid bpffs mount in mount namespace of pid %d: %w", pid, err)
+		}
+		return root, nil
+	}
+
+	return "", fmt.Errorf("no bpffs mount found in mount namespace of pid %d", pid)
+}
+
+// UseBPFFSForMountNS points map open/create operations at the bpffs instance
+// mounted in the mount namespace of the given process. This allows the agent
+// logic to target the correct bpffs even when running in an alternative
+// mount namespace (e.g., certain CNI chaining modes). It must be called
+// before any map paths have been computed.
+func UseBPFFSForMountNS(pid int) error {
+	root, err := BPFFSRootForMountNS(pid)
+	if err != nil {
+		return err
+	}
+	setBPFFSRoot(root)
+	return nil
+}
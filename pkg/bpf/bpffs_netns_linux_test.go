@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+//go:build linux
+
+package bpf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePinRoot(t *testing.T) {
+	// A regular directory is not a bpffs instance.
+	err := ValidatePinRoot(t.TempDir())
+	assert.ErrorContains(t, err, "not on a BPF filesystem")
+
+	// Non-existing paths are rejected.
+	assert.Error(t, ValidatePinRoot(filepath.Join(t.TempDir(), "missing")))
+}
+
+func TestBPFFSRootForMountNS(t *testing.T) {
+	// The current process serves as a stand-in for a process in another
+	// mount namespace: its mountinfo is parsed the same way.
+	root, err := BPFFSRootForMountNS(os.Getpid())
+	if err != nil {
+		// No (valid) bpffs mount available in this environment.
+		t.Skipf("no bpffs mount: %s", err)
+	}
+	require.NoError(t, ValidatePinRoot(root))
+}
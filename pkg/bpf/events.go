@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"slices"
 	"sync"
 	"sync/atomic"
 
@@ -51,6 +52,10 @@ type Event struct {
 	Timestamp time.Time
 	action    Action
 	cacheEntry
+
+	// valueRedactor is inherited from the originating map, so that value
+	// redaction also covers the events output paths.
+	valueRedactor ValueRedactor
 }
 
 // GetAction returns the event action string.
@@ -73,6 +78,9 @@ func (e Event) GetValue() string {
 	if e.cacheEntry.Value == nil {
 		return "<nil>"
 	}
+	if e.valueRedactor != nil {
+		return e.valueRedactor(e.cacheEntry.Key, e.cacheEntry.Value)
+	}
 	return e.cacheEntry.Value.String()
 }
 
@@ -90,6 +98,7 @@ func (m *Map) initEventsBuffer(maxSize int, eventsTTL time.Duration) {
 	b := &eventsBuffer{
 		logger:   m.Logger,
 		buffer:   container.NewRingBuffer(maxSize),
+		snapshot: container.NewSeqRing[*Event](maxSize),
 		eventTTL: eventsTTL,
 	}
 	if b.eventTTL > 0 {
@@ -123,8 +132,12 @@ func (m *Map) initEventsBuffer(maxSize int, eventsTTL time.Duration) {
 // eventsBuffer stores a buffer of events for auditing and debugging
 // purposes.
 type eventsBuffer struct {
-	logger        *slog.Logger
-	buffer        *container.RingBuffer
+	logger *slog.Logger
+	buffer *container.RingBuffer
+	// snapshot mirrors buffer in a lock-free sequence ring so that frequent
+	// observability scrapes can copy the events without contending with map
+	// operations on the map lock.
+	snapshot      *container.SeqRing[*Event]
 	eventTTL      time.Duration
 	subsLock      lock.RWMutex
 	subscriptions []*Handle
@@ -231,12 +244,45 @@ func (m *Map) DumpAndSubscribe(callback EventCallbackFunc, follow bool) (*Handle
 	return m.events.dumpAndSubscribe(callback, follow)
 }
 
+// EventsSnapshot returns a copy of the buffered events with sequence number
+// of at least since, from oldest to newest, along with the sequence number to
+// pass to a subsequent call to only receive newer events. Unlike
+// DumpAndSubscribe it does not take the map lock, so frequent scrapes of the
+// event buffer do not contend with map operations. Events older than the
+// configured TTL are filtered out, matching the dump behavior.
+func (m *Map) EventsSnapshot(since uint64) ([]*Event, uint64, error) {
+	if !m.eventsBufferEnabled {
+		return nil, 0, fmt.Errorf("map events not enabled for map %q", m.name)
+	}
+	events, next := m.events.snapshot.SnapshotSince(since)
+	if m.events.eventTTL > 0 {
+		events = slices.DeleteFunc(events, func(e *Event) bool {
+			return time.Since(e.Timestamp) > m.events.eventTTL
+		})
+	}
+	return events, next, nil
+}
+
 func (m *Map) IsEventsEnabled() bool {
 	return m.eventsBufferEnabled
 }
 
+// close terminates all active subscriptions of the events buffer.
+func (eb *eventsBuffer) close() {
+	eb.subsLock.Lock()
+	defer eb.subsLock.Unlock()
+
+	for _, sub := range eb.subscriptions {
+		sub.Close()
+	}
+	eb.subscriptions = nil
+}
+
 func (eb *eventsBuffer) add(e *Event) {
 	eb.buffer.Add(e)
+	if eb.snapshot != nil {
+		eb.snapshot.Append(e)
+	}
 	var activeSubs []*Handle
 	activeSubsLock := &lock.Mutex{}
 	wg := &sync.WaitGroup{}
@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package bpf
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FormatStruct derives a consistent string representation for MapKey and
+// MapValue types backed by tagged structs, so that implementing String()
+// reduces to returning FormatStruct(k). Each exported field is rendered as
+// "name=value", in declaration order, separated by spaces.
+//
+// The rendering is controlled by the `str` struct tag:
+//
+//   - `str:"-"` omits the field;
+//   - `str:"<name>"` overrides the rendered field name, which otherwise
+//     defaults to the `json` tag name or, lacking that, the lowercased Go
+//     field name;
+//   - the ",hex" option renders unsigned integers and byte arrays in
+//     hexadecimal.
+func FormatStruct(v any) string {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return "<nil>"
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return fmt.Sprintf("%v", rv.Interface())
+	}
+
+	var sb strings.Builder
+	formatStruct(&sb, rv)
+	return sb.String()
+}
+
+func formatStruct(sb *strings.Builder, rv reflect.Value) {
+	rt := rv.Type()
+	for i := range rt.NumField() {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, hex, skip := fieldName(field)
+		if skip {
+			continue
+		}
+
+		if sb.Len() > 0 {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(name)
+		sb.WriteByte('=')
+		formatValue(sb, rv.Field(i), hex)
+	}
+}
+
+// fieldName determines the rendered name of a struct field, along with
+// whether it shall be rendered in hexadecimal or skipped altogether.
+func fieldName(field reflect.StructField) (name string, hex, skip bool) {
+	name, opts, _ := strings.Cut(field.Tag.Get("str"), ",")
+	switch name {
+	case "-":
+		return "", false, true
+	case "":
+		if jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ","); jsonName != "" && jsonName != "-" {
+			name = jsonName
+		} else {
+			name = strings.ToLower(field.Name)
+		}
+	}
+	return name, opts == "hex", false
+}
+
+func formatValue(sb *strings.Builder, rv reflect.Value, hex bool) {
+	switch rv.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if hex {
+			fmt.Fprintf(sb, "%#x", rv.Uint())
+		} else {
+			fmt.Fprintf(sb, "%d", rv.Uint())
+		}
+	case reflect.Array, reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 && hex {
+			fmt.Fprintf(sb, "%#x", rv.Interface())
+			return
+		}
+		sb.WriteByte('[')
+		for i := range rv.Len() {
+			if i > 0 {
+				sb.WriteByte(' ')
+			}
+			formatValue(sb, rv.Index(i), hex)
+		}
+		sb.WriteByte(']')
+	case reflect.Struct:
+		if s, ok := rv.Interface().(fmt.Stringer); ok {
+			sb.WriteString(s.String())
+			return
+		}
+		var nested strings.Builder
+		formatStruct(&nested, rv)
+		sb.WriteByte('{')
+		sb.WriteString(nested.String())
+		sb.WriteByte('}')
+	default:
+		fmt.Fprintf(sb, "%v", rv.Interface())
+	}
+}
+
+// MarshalStructJSON derives a JSON representation consistent with
+// FormatStruct, honoring the same `str` tags for field naming and omission.
+// It is meant to back MarshalJSON implementations of MapKey and MapValue
+// types included in dumps and events.
+func MarshalStructJSON(v any) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return []byte("null"), nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return json.Marshal(rv.Interface())
+	}
+
+	fields := make(map[string]any)
+	rt := rv.Type()
+	for i := range rt.NumField() {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, hex, skip := fieldName(field)
+		if skip {
+			continue
+		}
+
+		value := rv.Field(i).Interface()
+		if hex {
+			var sb strings.Builder
+			formatValue(&sb, rv.Field(i), true)
+			value = sb.String()
+		}
+		fields[name] = value
+	}
+	return json.Marshal(fields)
+}
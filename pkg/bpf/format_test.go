@@ -0,0 +1,60 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package bpf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type formatKey struct {
+	Identity uint32   `json:"sec_label"`
+	DestPort uint16   `json:"dport"`
+	Flags    uint8    `str:"flags,hex"`
+	Mac      [6]uint8 `str:"mac,hex"`
+	Pad      uint8    `str:"-"`
+	Proto    uint8
+}
+
+func TestFormatStruct(t *testing.T) {
+	key := &formatKey{
+		Identity: 1234,
+		DestPort: 80,
+		Flags:    0x3,
+		Mac:      [6]uint8{0xde, 0xad, 0xbe, 0xef, 0x00, 0x01},
+		Pad:      42,
+		Proto:    6,
+	}
+
+	assert.Equal(t, "sec_label=1234 dport=80 flags=0x3 mac=0xdeadbeef0001 proto=6", FormatStruct(key))
+
+	// Non-pointer values and nil pointers are handled as well.
+	assert.Equal(t, "sec_label=1234 dport=80 flags=0x3 mac=0xdeadbeef0001 proto=6", FormatStruct(*key))
+	assert.Equal(t, "<nil>", FormatStruct((*formatKey)(nil)))
+
+	// Nested structs are rendered recursively.
+	type nested struct {
+		Inner formatKey `str:"inner"`
+		ID    uint64    `json:"id"`
+	}
+	assert.Equal(t,
+		"inner={sec_label=0 dport=0 flags=0x0 mac=0x000000000000 proto=0} id=7",
+		FormatStruct(nested{ID: 7}))
+}
+
+func TestMarshalStructJSON(t *testing.T) {
+	key := &formatKey{Identity: 1234, DestPort: 80, Flags: 0x3, Pad: 42}
+
+	data, err := MarshalStructJSON(key)
+	require.NoError(t, err)
+	assert.JSONEq(t,
+		`{"sec_label": 1234, "dport": 80, "flags": "0x3", "mac": "0x000000000000", "proto": 0}`,
+		string(data))
+
+	data, err = MarshalStructJSON((*formatKey)(nil))
+	require.NoError(t, err)
+	assert.Equal(t, "null", string(data))
+}
@@ -0,0 +1,205 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+//go:build linux
+
+package bpf
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"unsafe"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/asm"
+	"github.com/cilium/ebpf/link"
+
+	"github.com/cilium/cilium/pkg/logging/logfields"
+)
+
+// ProgIterator provides a typed wrapper around *Map that dumps the map via a
+// BPF iterator (seq_file) program where the kernel supports it. A single read
+// of the iterator file walks the whole map inside the kernel, which is
+// considerably cheaper for huge maps than issuing repeated lookup syscalls.
+//
+// On kernels without bpf_iter support for the map type, iteration falls back
+// transparently to a BatchIterator.
+type ProgIterator[KT, VT any, KP KeyPointer[KT], VP ValuePointer[VT]] struct {
+	m   *Map
+	err error
+}
+
+// NewProgIterator returns an iterator that dumps the map using a bpf_map_elem
+// iterator program, falling back to batched iteration when the kernel lacks
+// support. Any error encountered during iteration can be accessed via the
+// Err() function once iteration has finished.
+// The pointer type of KT & VT must implement Map{Key,Value}, respectively.
+//
+// Subsequent iterations via IterateAll reset all internal state and begin
+// iteration over.
+//
+// Example usage:
+//
+//	iter := NewProgIterator[TestKey, TestValue](m)
+//	for k, v := range iter.IterateAll(context.TODO()) {
+//		// ...
+//	}
+func NewProgIterator[KT any, VT any, KP KeyPointer[KT], VP ValuePointer[VT]](m *Map) *ProgIterator[KT, VT, KP, VP] {
+	return &ProgIterator[KT, VT, KP, VP]{
+		m: m,
+	}
+}
+
+// Err returns errors encountered during the previous iteration when
+// IterateAll(...) is called.
+//
+// If the iterator is reused, the error will be reset.
+func (pi *ProgIterator[KT, VT, KP, VP]) Err() error {
+	return pi.err
+}
+
+// IterateAll returns an iterator over all key/value pairs of the map. The
+// iterator program path is only attempted for plain hash map types, whose
+// kernel value layout matches VT; all other map types, as well as kernels
+// without bpf_iter support, use the BatchIterator fallback.
+func (pi *ProgIterator[KT, VT, KP, VP]) IterateAll(ctx context.Context) iter.Seq2[KP, VP] {
+	pi.err = nil
+
+	rc, err := pi.openIterator()
+	if err != nil {
+		pi.m.Logger.Debug("Map iterator program unavailable, falling back to batched iteration",
+			logfields.Error, err,
+		)
+		bi := NewBatchIterator[KT, VT, KP, VP](pi.m)
+		seq := bi.IterateAll(ctx)
+		pi.err = bi.Err()
+		return func(yield func(KP, VP) bool) {
+			for k, v := range seq {
+				if !yield(k, v) {
+					break
+				}
+			}
+			pi.err = bi.Err()
+		}
+	}
+
+	keySize := int(pi.m.KeySize())
+	record := make([]byte, keySize+int(pi.m.ValueSize()))
+
+	return func(yield func(KP, VP) bool) {
+		defer rc.Close()
+
+		r := bufio.NewReader(rc)
+		for {
+			if ctx.Err() != nil {
+				pi.err = ctx.Err()
+				return
+			}
+
+			if _, err := io.ReadFull(r, record); err != nil {
+				if !errors.Is(err, io.EOF) {
+					pi.err = fmt.Errorf("failed to read map iterator: %w", err)
+				}
+				return
+			}
+
+			var key KT
+			var value VT
+			copy(unsafe.Slice((*byte)(unsafe.Pointer(&key)), unsafe.Sizeof(key)), record[:keySize])
+			copy(unsafe.Slice((*byte)(unsafe.Pointer(&value)), unsafe.Sizeof(value)), record[keySize:])
+
+			if !yield(&key, &value) {
+				return
+			}
+		}
+	}
+}
+
+// openIterator loads the iterator program, attaches it to the map and opens
+// an instance of the resulting seq_file. Reading the returned file triggers
+// the in-kernel dump.
+func (pi *ProgIterator[KT, VT, KP, VP]) openIterator() (io.ReadCloser, error) {
+	switch pi.m.Type() {
+	// Per-CPU maps are excluded as the iterator context exposes only the
+	// current CPU's value, and LPM tries have no bpf_map_elem support.
+	case ebpf.Hash, ebpf.LRUHash:
+	default:
+		return nil, fmt.Errorf("unsupported map type %s", pi.m.Type())
+	}
+
+	var key KT
+	var value VT
+	if pi.m.KeySize() != uint32(unsafe.Sizeof(key)) || pi.m.ValueSize() != uint32(unsafe.Sizeof(value)) {
+		return nil, fmt.Errorf("key/value type sizes (%d/%d) do not match the map (%d/%d)",
+			unsafe.Sizeof(key), unsafe.Sizeof(value), pi.m.KeySize(), pi.m.ValueSize())
+	}
+
+	if err := pi.m.Open(); err != nil {
+		return nil, fmt.Errorf("failed to open map: %w", err)
+	}
+
+	prog, err := ebpf.NewProgram(mapIterProgSpec(pi.m.KeySize(), pi.m.ValueSize()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load map iterator program: %w", err)
+	}
+	defer prog.Close()
+
+	it, err := link.AttachIter(link.IterOptions{
+		Program: prog,
+		Map:     pi.m.m,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach map iterator: %w", err)
+	}
+	defer it.Close()
+
+	rc, err := it.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open map iterator instance: %w", err)
+	}
+	return rc, nil
+}
+
+// mapIterProgSpec assembles a minimal bpf_map_elem iterator program emitting
+// each key/value pair verbatim to the seq_file via bpf_seq_write. The kernel
+// discards partially written records on seq_file buffer overflow and retries
+// the element, so userspace always observes whole records.
+func mapIterProgSpec(keySize, valueSize uint32) *ebpf.ProgramSpec {
+	return &ebpf.ProgramSpec{
+		Name:       "cilium_map_iter",
+		Type:       ebpf.Tracing,
+		AttachType: ebpf.AttachTraceIter,
+		AttachTo:   "bpf_map_elem",
+		// bpf_seq_write is a GPL-only helper.
+		License: "GPL",
+		Instructions: asm.Instructions{
+			// r6 = ctx (struct bpf_iter__bpf_map_elem *)
+			asm.Mov.Reg(asm.R6, asm.R1),
+			// r7 = ctx->meta->seq
+			asm.LoadMem(asm.R7, asm.R6, 0, asm.DWord),
+			asm.LoadMem(asm.R7, asm.R7, 0, asm.DWord),
+			// r8 = ctx->key, NULL once the dump is complete.
+			asm.LoadMem(asm.R8, asm.R6, 16, asm.DWord),
+			asm.JEq.Imm(asm.R8, 0, "exit"),
+			// r9 = ctx->value
+			asm.LoadMem(asm.R9, asm.R6, 24, asm.DWord),
+			asm.JEq.Imm(asm.R9, 0, "exit"),
+			// bpf_seq_write(seq, key, keySize)
+			asm.Mov.Reg(asm.R1, asm.R7),
+			asm.Mov.Reg(asm.R2, asm.R8),
+			asm.Mov.Imm(asm.R3, int32(keySize)),
+			asm.FnSeqWrite.Call(),
+			// bpf_seq_write(seq, value, valueSize)
+			asm.Mov.Reg(asm.R1, asm.R7),
+			asm.Mov.Reg(asm.R2, asm.R9),
+			asm.Mov.Imm(asm.R3, int32(valueSize)),
+			asm.FnSeqWrite.Call(),
+			asm.Mov.Imm(asm.R0, 0).WithSymbol("exit"),
+			asm.Return(),
+		},
+	}
+}
@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package bpf
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cilium/ebpf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/cilium/cilium/pkg/testutils"
+)
+
+func TestProgIteratorTypes(t *testing.T) {
+	m := NewMap("cilium_test",
+		ebpf.Array,
+		&TestKey{},
+		&TestValue{}, 1, 0)
+	iter := NewProgIterator[TestKey, TestValue](m)
+	iter.IterateAll(context.TODO())
+	assert.Error(t, iter.Err())
+	assert.NotNil(t, iter)
+}
+
+func TestPrivilegedProgIterator(t *testing.T) {
+	testutils.PrivilegedTest(t)
+
+	runTest := func(mapType ebpf.MapType, size, mapSize int, t *testing.T) {
+		m := NewMap("cilium_test",
+			mapType,
+			&TestKey{},
+			&TestValue{},
+			mapSize,
+			0,
+		)
+		require.NoError(t, m.OpenOrCreate())
+		defer assert.NoError(t, m.UnpinIfExists())
+		for i := range size {
+			err := m.Update(&TestKey{Key: uint32(i)}, &TestValue{Value: uint32(i)})
+			assert.NoError(t, err)
+		}
+
+		ks := sets.New[int]()
+		vs := sets.New[int]()
+
+		iter := NewProgIterator[TestKey, TestValue](m)
+		count := 0
+		for k, v := range iter.IterateAll(context.TODO()) {
+			count++
+			ks.Insert(int(k.Key))
+			vs.Insert(int(v.Value))
+		}
+		require.NoError(t, iter.Err())
+		assert.Equal(t, size, count)
+
+		for i := range size {
+			require.Contains(t, ks, i, "expect iterate to return key=%d", i)
+			require.Contains(t, vs, i, "expect iterate to return val=%d", i)
+		}
+	}
+
+	for _, test := range []struct {
+		mapSize int
+		size    int
+	}{
+		{10, 10},
+		{1024, 1024},
+		{1 << 16, 1 << 14},
+	} {
+		t.Run(fmt.Sprintf("hash size=%d mapSize=%d", test.size, test.mapSize), func(t *testing.T) {
+			runTest(ebpf.Hash, test.size, test.mapSize, t)
+		})
+		// LPM tries have no bpf_map_elem iterator support and exercise the
+		// BatchIterator fallback.
+		t.Run(fmt.Sprintf("lpmtrie fallback size=%d mapSize=%d", test.size, test.mapSize), func(t *testing.T) {
+			mapSize := test.mapSize
+			m := NewMap("cilium_test",
+				ebpf.LPMTrie,
+				&TestLPMKey{},
+				&TestValue{},
+				mapSize,
+				0,
+			)
+			require.NoError(t, m.OpenOrCreate())
+			defer assert.NoError(t, m.UnpinIfExists())
+			for i := range test.size {
+				err := m.Update(&TestLPMKey{PrefixLen: 32, Key: uint32(i)}, &TestValue{Value: uint32(i)})
+				assert.NoError(t, err)
+			}
+
+			iter := NewProgIterator[TestLPMKey, TestValue](m)
+			count := 0
+			for range iter.IterateAll(context.TODO()) {
+				count++
+			}
+			require.NoError(t, iter.Err())
+			assert.Equal(t, test.size, count)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+//go:build linux
+
+package bpf
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// MapAccessRecord describes an external process holding a file descriptor to
+// a Cilium BPF map. It helps diagnose third-party tooling inspecting or
+// mutating Cilium maps.
+type MapAccessRecord struct {
+	// MapID is the kernel ID of the BPF map the process holds open.
+	MapID uint32
+	// MapName is the registered name of the map.
+	MapName string
+	// Pid is the process ID of the external process.
+	Pid int32
+	// Comm is the command name of the external process.
+	Comm string
+}
+
+// AuditMapAccess scans /proc for processes other than the agent itself
+// holding file descriptors to any of the registered BPF maps, identified by
+// matching the map IDs reported in /proc/<pid>/fdinfo. It requires the
+// privileges to read the fdinfo of other processes and silently skips
+// processes it cannot inspect.
+func AuditMapAccess() ([]MapAccessRecord, error) {
+	// Create a copy of mapRegister so we can unlock the mutex again as
+	// locking Map.lock inside of the mutex is not permitted
+	mutex.RLock()
+	maps := make([]*Map, 0, len(mapRegister))
+	for _, m := range mapRegister {
+		maps = append(maps, m)
+	}
+	mutex.RUnlock()
+
+	mapsByID := make(map[uint32]string, len(maps))
+	for _, m := range maps {
+		m.lock.RLock()
+		if m.m != nil {
+			if info, err := m.m.Info(); err == nil {
+				if id, ok := info.ID(); ok {
+					mapsByID[uint32(id)] = m.name
+				}
+			}
+		}
+		m.lock.RUnlock()
+	}
+
+	return auditMapAccess("/proc", os.Getpid(), mapsByID)
+}
+
+func auditMapAccess(procPath string, selfPid int, mapsByID map[uint32]string) ([]MapAccessRecord, error) {
+	procs, err := os.ReadDir(procPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []MapAccessRecord
+	for _, proc := range procs {
+		pid, err := strconv.Atoi(proc.Name())
+		if err != nil || pid == selfPid {
+			continue
+		}
+
+		fdInfoPath := filepath.Join(procPath, proc.Name(), "fdinfo")
+		fds, err := os.ReadDir(fdInfoPath)
+		if err != nil {
+			// Process exited or is not inspectable with our privileges.
+			continue
+		}
+
+		var comm string
+		for _, fd := range fds {
+			id, ok := fdInfoMapID(filepath.Join(fdInfoPath, fd.Name()))
+			if !ok {
+				continue
+			}
+			name, ok := mapsByID[id]
+			if !ok {
+				continue
+			}
+			if comm == "" {
+				out, err := os.ReadFile(filepath.Join(procPath, proc.Name(), "comm"))
+				if err != nil {
+					continue
+				}
+				comm = strings.TrimSpace(string(out))
+			}
+			records = append(records, MapAccessRecord{
+				MapID:   id,
+				MapName: name,
+				Pid:     int32(pid),
+				Comm:    comm,
+			})
+		}
+	}
+	return records, nil
+}
+
+// fdInfoMapID extracts the BPF map ID from the given fdinfo file. The map_id
+// field is only present for file descriptors referring to BPF maps.
+func fdInfoMapID(path string) (uint32, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if value, found := strings.CutPrefix(scanner.Text(), "map_id:"); found {
+			id, err := strconv.ParseUint(strings.TrimSpace(value), 10, 32)
+			if err != nil {
+				return 0, false
+			}
+			return uint32(id), true
+		}
+	}
+	return 0, false
+}
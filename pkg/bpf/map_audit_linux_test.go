@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package bpf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditMapAccess(t *testing.T) {
+	proc := t.TempDir()
+
+	writeProcFile := func(elems []string, content string) {
+		path := filepath.Join(append([]string{proc}, elems...)...)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	const bpftoolFdInfo = "pos:\t0\nflags:\t02000002\nmnt_id:\t15\nmap_type:\t1\nmap_id:\t42\nkey_size:\t4\nvalue_size:\t8\n"
+
+	// An external process holding an fd to map 42 and a regular file.
+	writeProcFile([]string{"1234", "comm"}, "bpftool\n")
+	writeProcFile([]string{"1234", "fdinfo", "3"}, bpftoolFdInfo)
+	writeProcFile([]string{"1234", "fdinfo", "4"}, "pos:\t0\nflags:\t0100002\nmnt_id:\t29\n")
+
+	// A process holding an fd to an unrelated map.
+	writeProcFile([]string{"2345", "comm"}, "other\n")
+	writeProcFile([]string{"2345", "fdinfo", "5"}, "pos:\t0\nmap_type:\t1\nmap_id:\t7\n")
+
+	// The agent itself must be excluded.
+	writeProcFile([]string{"999", "comm"}, "cilium-agent\n")
+	writeProcFile([]string{"999", "fdinfo", "6"}, bpftoolFdInfo)
+
+	// Non-pid entries in /proc are skipped.
+	writeProcFile([]string{"meminfo"}, "MemTotal: 1 kB\n")
+
+	records, err := auditMapAccess(proc, 999, map[uint32]string{42: "cilium_lxc"})
+	require.NoError(t, err)
+	require.Equal(t, []MapAccessRecord{
+		{MapID: 42, MapName: "cilium_lxc", Pid: 1234, Comm: "bpftool"},
+	}, records)
+}
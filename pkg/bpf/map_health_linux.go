@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+//go:build linux
+
+package bpf
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/cilium/hive/cell"
+	"github.com/cilium/hive/job"
+
+	"github.com/cilium/cilium/pkg/time"
+)
+
+const (
+	// mapHealthReportInterval is the interval at which the health of all
+	// registered maps is reported to the health subsystem.
+	mapHealthReportInterval = 30 * time.Second
+
+	// resolverBacklogDegradeAge is the age an outstanding error backlog must
+	// reach before it degrades the health of the map. Younger backlogs are
+	// expected, as the error resolver runs periodically.
+	resolverBacklogDegradeAge = time.Minute
+)
+
+// healthStatus returns the current health status of the map: an error
+// describing why the map is degraded, or nil along with a human readable
+// status message if it is healthy.
+func (m *Map) healthStatus() (string, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	if m.openErr != nil {
+		return "", fmt.Errorf("map could not be opened: %w", m.openErr)
+	}
+
+	if m.outstandingErrors && time.Since(m.outstandingSince) > resolverBacklogDegradeAge {
+		return "", fmt.Errorf("error resolver backlog outstanding since %s",
+			m.outstandingSince.Format(time.RFC3339))
+	}
+
+	if m.pressureGauge != nil && m.pressureThreshold > 0 {
+		if max := m.MaxEntries(); max > 0 {
+			if pressure := float64(len(m.cache)) / float64(max); pressure > m.pressureThreshold {
+				return "", fmt.Errorf("map pressure %.2f above threshold %.2f",
+					pressure, m.pressureThreshold)
+			}
+		}
+	}
+
+	return fmt.Sprintf("%d entries cached", len(m.cache)), nil
+}
+
+// RegisterMapHealthJob adds a timer job periodically reporting the health of
+// all registered maps to the given health reporter, with a separate scope per
+// map. A map is reported as degraded when it failed to open, when its error
+// resolver backlog persists longer than resolverBacklogDegradeAge, or when
+// its pressure exceeds the configured pressure metric threshold.
+func RegisterMapHealthJob(g job.Group, h cell.Health) {
+	scopes := map[string]cell.Health{}
+
+	g.Add(job.Timer(
+		"map-health",
+		func(context.Context) error {
+			mutex.RLock()
+			registered := make(map[string]*Map, len(mapRegister))
+			for _, m := range mapRegister {
+				registered[m.name] = m
+			}
+			mutex.RUnlock()
+
+			// Close the scopes of maps which have been unregistered.
+			for name, scope := range scopes {
+				if _, ok := registered[name]; !ok {
+					scope.Stopped("map unregistered")
+					scope.Close()
+					delete(scopes, name)
+				}
+			}
+
+			for _, name := range slices.Sorted(maps.Keys(registered)) {
+				scope, ok := scopes[name]
+				if !ok {
+					scope = h.NewScope(name)
+					scopes[name] = scope
+				}
+
+				if status, err := registered[name].healthStatus(); err != nil {
+					scope.Degraded("BPF map degraded", err)
+				} else {
+					scope.OK(status)
+				}
+			}
+
+			return nil
+		},
+		mapHealthReportInterval,
+	))
+}
@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+//go:build linux
+
+package bpf
+
+import (
+	"testing"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/rlimit"
+	"github.com/cilium/hive/hivetest"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+
+	"github.com/cilium/cilium/pkg/metrics"
+	"github.com/cilium/cilium/pkg/testutils"
+	"github.com/cilium/cilium/pkg/time"
+)
+
+func TestPrivilegedMapHealthStatus(t *testing.T) {
+	testutils.PrivilegedTest(t)
+
+	CheckOrMountFS(hivetest.Logger(t), "")
+
+	err := rlimit.RemoveMemlock()
+	require.NoError(t, err)
+
+	m := NewMap("cilium_test_health",
+		ebpf.Hash,
+		&TestKey{},
+		&TestValue{},
+		4,
+		unix.BPF_F_NO_PREALLOC,
+	).WithCache()
+
+	require.NoError(t, m.OpenOrCreate())
+	t.Cleanup(func() {
+		require.NoError(t, m.Close())
+	})
+
+	// A freshly opened map is healthy.
+	status, err := m.healthStatus()
+	require.NoError(t, err)
+	require.NotEmpty(t, status)
+
+	// A young error resolver backlog does not yet degrade the map.
+	m.lock.Lock()
+	m.setOutstandingErrors(true)
+	m.lock.Unlock()
+	_, err = m.healthStatus()
+	require.NoError(t, err)
+
+	// A backlog persisting beyond resolverBacklogDegradeAge does.
+	m.lock.Lock()
+	m.outstandingSince = time.Now().Add(-2 * resolverBacklogDegradeAge)
+	m.lock.Unlock()
+	_, err = m.healthStatus()
+	require.ErrorContains(t, err, "backlog")
+
+	// Clearing the backlog also clears the degradation.
+	m.lock.Lock()
+	m.setOutstandingErrors(false)
+	m.lock.Unlock()
+	_, err = m.healthStatus()
+	require.NoError(t, err)
+
+	// Pressure above the configured threshold degrades the map.
+	m.lock.Lock()
+	m.pressureGauge = &metrics.GaugeWithThreshold{}
+	m.pressureThreshold = 0.5
+	m.lock.Unlock()
+	for i := range uint32(3) {
+		require.NoError(t, m.Update(&TestKey{Key: i}, &TestValue{Value: i}))
+	}
+	_, err = m.healthStatus()
+	require.ErrorContains(t, err, "pressure")
+
+	// A map which failed to open reports the open error.
+	broken := NewMap("cilium_test_health_missing",
+		ebpf.Hash,
+		&TestKey{},
+		&TestValue{},
+		4,
+		unix.BPF_F_NO_PREALLOC,
+	)
+	broken.spec = nil
+	require.Error(t, broken.Open())
+	_, err = broken.healthStatus()
+	require.ErrorContains(t, err, "could not be opened")
+}
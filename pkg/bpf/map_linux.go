@@ -6,22 +6,30 @@
 package bpf
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"io/fs"
 	"iter"
 	"log/slog"
 	"math"
+	"math/rand/v2"
 	"os"
 	"path"
 	"reflect"
+	"slices"
 	"strings"
+	"sync/atomic"
+	"unsafe"
 
 	"github.com/cilium/ebpf"
 	"golang.org/x/sys/unix"
+	"golang.org/x/time/rate"
 
 	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/backoff"
 	"github.com/cilium/cilium/pkg/controller"
 	"github.com/cilium/cilium/pkg/lock"
 	"github.com/cilium/cilium/pkg/logging"
@@ -69,6 +77,10 @@ type cacheEntry struct {
 
 	DesiredAction DesiredAction
 	LastError     error
+
+	// lastSynced is the time the entry was last successfully written to the
+	// kernel, used to select eviction victims when the cache is bounded.
+	lastSynced time.Time
 }
 
 type Map struct {
@@ -98,10 +110,28 @@ type Map struct {
 	// pressure metric is enabled
 	cache map[string]*cacheEntry
 
+	// cacheLimit bounds the number of entries kept in the cache when
+	// non-zero, configured via WithCacheLimit.
+	cacheLimit int
+
+	// dirty contains the keys mutated since the last invocation of
+	// SyncDirty. It is only maintained when dirty tracking has been enabled
+	// via WithDirtyTracking.
+	dirty map[string]struct{}
+
+	// streamDumper is the typed StreamDumpJSON instantiation recorded via
+	// WithStreamDumper, or nil if streaming dumps are not supported.
+	streamDumper StreamDumperFunc
+
 	// errorResolverLastScheduled is the timestamp when the error resolver
 	// was last scheduled
 	errorResolverLastScheduled time.Time
 
+	// errorResolverInterval overrides the interval of the error resolver
+	// controller for this map. Zero means the default
+	// errorResolverSchedulerMinInterval.
+	errorResolverInterval time.Duration
+
 	// outstandingErrors states whether there are outstanding errors, occurred while
 	// syncing an entry with the kernel, that need to be resolved. This variable exists
 	// to avoid iterating over the full cache to check if reconciliation is necessary,
@@ -109,20 +139,77 @@ type Map struct {
 	// resolved while performing a subsequent Update/Delete operation on the same key.
 	outstandingErrors bool
 
+	// outstandingSince is the time at which outstandingErrors last
+	// transitioned to true, zero while there are no outstanding errors. It
+	// allows the health reporter to distinguish a transient backlog from one
+	// the error resolver fails to drain.
+	outstandingSince time.Time
+
+	// openErr records the error of the most recent failed attempt to open or
+	// create the map, cleared once the map is opened successfully.
+	openErr error
+
 	// pressureGauge is a metric that tracks the pressure on this map
 	pressureGauge *metrics.GaugeWithThreshold
 
+	// pressureThreshold is the threshold configured via
+	// WithPressureMetricThreshold, retained for health reporting.
+	pressureThreshold float64
+
 	// is true when events buffer is enabled.
 	eventsBufferEnabled bool
 
 	// contains optional event buffer which stores last n bpf map events.
 	events *eventsBuffer
 
+	// preUpdateHooks and postUpdateHooks are invoked around each mutation
+	// performed through this map, while holding lock for writing.
+	preUpdateHooks  []PreUpdateHook
+	postUpdateHooks []PostUpdateHook
+
+	// valueValidator, when set via WithValueValidator, is invoked on every
+	// entry read back from the kernel to guard consumers against poison
+	// entries, e.g. written by older versions.
+	valueValidator ValueValidator
+
+	// quarantineMap optionally receives a copy of each entry rejected by the
+	// value validator, configured via WithQuarantineMap.
+	quarantineMap *Map
+
+	// valueRedactor, when set via WithValueRedactor, replaces the value
+	// string representation in all dump output paths.
+	valueRedactor ValueRedactor
+
+	// syscallLimiter throttles the syscalls performed through this map when
+	// a budget has been configured via WithSyscallBudget. It is accessed
+	// atomically, as waiters must not hold the map lock.
+	syscallLimiter atomic.Pointer[rate.Limiter]
+
+	// invalidEntries counts the entries rejected by the value validator.
+	invalidEntries atomic.Uint64
+
 	// group is the metric group name for this map, it classifies maps of the same
 	// type that share the same metric group.
 	group string
 }
 
+// PreUpdateHook is invoked right before a mutation performed through the Map
+// is applied to the kernel. The value is nil for deletions.
+type PreUpdateHook func(action Action, key MapKey, value MapValue)
+
+// PostUpdateHook is invoked right after a mutation performed through the Map
+// has been applied to the kernel, with the outcome of the operation. The
+// value is nil for deletions.
+type PostUpdateHook func(action Action, key MapKey, value MapValue, err error)
+
+// ValueValidator checks an entry read back from the kernel and returns an
+// error describing why the entry is invalid, or nil if it is well-formed.
+type ValueValidator func(key MapKey, value MapValue) error
+
+// ValueRedactor returns the string representation to expose for the given
+// entry in dump outputs, replacing MapValue.String().
+type ValueRedactor func(key MapKey, value MapValue) string
+
 func (m *Map) Type() ebpf.MapType {
 	if m.m != nil {
 		return m.m.Type()
@@ -318,11 +405,26 @@ func (m *Map) NonPrefixedName() string {
 // error of resolution, the controller is-rescheduled in an expedited manner
 // with an exponential back-off.
 //
+// setOutstandingErrors updates the outstanding errors flag, recording the
+// time of the transition into the outstanding state for health reporting.
+//
+// m.lock must be held for writing
+func (m *Map) setOutstandingErrors(outstanding bool) {
+	switch {
+	case outstanding && !m.outstandingErrors:
+		m.outstandingSince = time.Now()
+	case !outstanding:
+		m.outstandingSince = time.Time{}
+	}
+	m.outstandingErrors = outstanding
+}
+
 // m.lock must be held for writing
 func (m *Map) scheduleErrorResolver() {
-	m.outstandingErrors = true
+	m.setOutstandingErrors(true)
 
-	if time.Since(m.errorResolverLastScheduled) <= errorResolverSchedulerMinInterval {
+	interval := m.errorResolverIntervalLocked()
+	if time.Since(m.errorResolverLastScheduled) <= interval {
 		return
 	}
 
@@ -334,13 +436,55 @@ func (m *Map) scheduleErrorResolver() {
 			controller.ControllerParams{
 				Group:       bpfMapSyncControllerGroup,
 				DoFunc:      m.resolveErrors,
-				RunInterval: errorResolverSchedulerMinInterval,
+				RunInterval: interval,
 			},
 		)
 	}()
 
 }
 
+// errorResolverIntervalLocked returns the effective interval of the error
+// resolver controller for this map.
+//
+// m.lock must be held for reading.
+func (m *Map) errorResolverIntervalLocked() time.Duration {
+	if m.errorResolverInterval > 0 {
+		return m.errorResolverInterval
+	}
+	return errorResolverSchedulerMinInterval
+}
+
+// ErrorResolverInterval returns the effective interval of the error resolver
+// controller for this map.
+func (m *Map) ErrorResolverInterval() time.Duration {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.errorResolverIntervalLocked()
+}
+
+// SetErrorResolverInterval overrides the interval of the error resolver
+// controller for this map, e.g. to temporarily speed up reconciliation during
+// incident recovery. A zero duration resets the interval to the default
+// errorResolverSchedulerMinInterval. If the controller is currently scheduled
+// due to outstanding errors, it is updated in place so that the new interval
+// takes effect immediately.
+func (m *Map) SetErrorResolverInterval(interval time.Duration) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.errorResolverInterval = interval
+
+	if m.outstandingErrors {
+		mapControllers.UpdateController(m.controllerName(),
+			controller.ControllerParams{
+				Group:       bpfMapSyncControllerGroup,
+				DoFunc:      m.resolveErrors,
+				RunInterval: m.errorResolverIntervalLocked(),
+			},
+		)
+	}
+}
+
 // WithCache enables use of a cache. This will store all entries inserted from
 // user space in a local cache (map) and will indicate the status of each
 // individual entry.
@@ -353,6 +497,282 @@ func (m *Map) WithCache() *Map {
 	return m
 }
 
+// WithCacheLimit bounds the cache enabled via WithCache to approximately the
+// given number of entries. When the limit is exceeded, the entries written to
+// the kernel least recently are evicted from the cache; as the kernel holds
+// the authoritative copy of synced entries, they can be reloaded from it on
+// demand. Entries pending synchronization are never evicted. Bounding the
+// cache reduces the steady-state memory consumption on maps with high key
+// churn, at the cost of the cache no longer reflecting the full map contents.
+// It must not be combined with the pressure metric, which is derived from the
+// cache size.
+func (m *Map) WithCacheLimit(limit int) *Map {
+	m.WithCache()
+	m.cacheLimit = limit
+	return m
+}
+
+// evictCacheEntries evicts successfully synced entries, least recently synced
+// first, until the cache is 10% below the configured limit, so that the cache
+// is not rescanned upon every subsequent insertion. Entries pending
+// synchronization, or tracked as dirty, are never evicted.
+//
+// m.lock must be held for writing
+func (m *Map) evictCacheEntries() {
+	if m.cacheLimit == 0 || len(m.cache) <= m.cacheLimit {
+		return
+	}
+
+	type victim struct {
+		key        string
+		lastSynced time.Time
+	}
+
+	victims := make([]victim, 0, len(m.cache))
+	for k, e := range m.cache {
+		if e.DesiredAction != OK || e.LastError != nil {
+			continue
+		}
+		if _, dirty := m.dirty[k]; dirty {
+			continue
+		}
+		victims = append(victims, victim{key: k, lastSynced: e.lastSynced})
+	}
+
+	slices.SortFunc(victims, func(a, b victim) int {
+		return a.lastSynced.Compare(b.lastSynced)
+	})
+
+	target := max(m.cacheLimit-m.cacheLimit/10, 1)
+	for _, v := range victims {
+		if len(m.cache) <= target {
+			break
+		}
+		delete(m.cache, v.key)
+	}
+}
+
+// RegisterPreUpdateHook registers fn to be invoked right before each update
+// or deletion performed through this map is applied to the kernel. Hooks run
+// while holding the map lock for writing: they must not block, and must not
+// operate on the map themselves. They allow derived state such as secondary
+// indexes, metrics or notifications to be maintained without wrapping the Map
+// in decorator types. Bulk operations (DeleteAll, ClearAll, ReplaceAll) do
+// not invoke per-key hooks.
+func (m *Map) RegisterPreUpdateHook(fn PreUpdateHook) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.preUpdateHooks = append(m.preUpdateHooks, fn)
+}
+
+// RegisterPostUpdateHook registers fn to be invoked right after each update
+// or deletion performed through this map has been applied to the kernel,
+// additionally receiving the outcome of the operation. The locking and bulk
+// operation rules of RegisterPreUpdateHook apply.
+func (m *Map) RegisterPostUpdateHook(fn PostUpdateHook) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.postUpdateHooks = append(m.postUpdateHooks, fn)
+}
+
+// m.lock must be held for writing
+func (m *Map) runPreUpdateHooks(action Action, key MapKey, value MapValue) {
+	for _, fn := range m.preUpdateHooks {
+		fn(action, key, value)
+	}
+}
+
+// WithValueValidator registers a validator invoked on every entry read back
+// from the kernel via Lookup or the dump functions. Invalid entries are
+// counted, optionally quarantined, and skipped during dumps; Lookup reports
+// them via ErrInvalidEntry. The validator does not inspect writes performed
+// through this map, and per-CPU dumps are not validated.
+func (m *Map) WithValueValidator(v ValueValidator) *Map {
+	m.valueValidator = v
+	return m
+}
+
+// WithQuarantineMap directs a copy of each entry rejected by the value
+// validator into the given map before the entry is skipped, so that poison
+// entries remain available for inspection. The quarantine map must be a
+// different map sharing this map's key and value types.
+func (m *Map) WithQuarantineMap(q *Map) *Map {
+	m.quarantineMap = q
+	return m
+}
+
+// WithValueRedactor registers a redaction function applied whenever values of
+// this map are rendered as strings, i.e. in Dump, GetModel and the events
+// buffer, so that maps carrying sensitive material (e.g., pre-shared keys)
+// do not leak secrets into API responses and support bundles. Lookups and
+// the callback-based dump functions still return the raw values.
+func (m *Map) WithValueRedactor(r ValueRedactor) *Map {
+	m.valueRedactor = r
+	return m
+}
+
+// valueString returns the string representation of the given entry value,
+// applying the configured redaction function, if any.
+func (m *Map) valueString(key MapKey, value MapValue) string {
+	if m.valueRedactor != nil {
+		return m.valueRedactor(key, value)
+	}
+	return value.String()
+}
+
+// WithSyscallBudget bounds the rate of syscalls performed through this map
+// (lookups, updates, deletions and dumps) to the given number of operations
+// per interval, with a token bucket of the same size. This prevents
+// background consumers, such as metrics collectors or garbage collectors,
+// from monopolizing the kernel-side map locks needed by latency-critical
+// callers. The budget can be adjusted at runtime via SetSyscallBudget.
+func (m *Map) WithSyscallBudget(ops int, interval time.Duration) *Map {
+	m.SetSyscallBudget(ops, interval)
+	return m
+}
+
+// SetSyscallBudget adjusts the syscall budget of this map at runtime. A
+// non-positive number of operations (or interval) removes the limit.
+func (m *Map) SetSyscallBudget(ops int, interval time.Duration) {
+	if ops <= 0 || interval <= 0 {
+		m.syscallLimiter.Store(nil)
+		return
+	}
+	m.syscallLimiter.Store(rate.NewLimiter(rate.Limit(float64(ops)/interval.Seconds()), ops))
+}
+
+// waitSyscallBudget blocks until the configured syscall budget grants n
+// tokens, approximating the number of syscalls the upcoming operation will
+// perform. It must be invoked before acquiring the map lock, so that
+// throttled callers do not starve it. Requests exceeding the bucket size
+// drain the full bucket instead.
+func (m *Map) waitSyscallBudget(n int) {
+	limiter := m.syscallLimiter.Load()
+	if limiter == nil {
+		return
+	}
+	n = max(min(n, limiter.Burst()), 1)
+	// The error is ignored, as it can only stem from an exceeded deadline
+	// or a burst overflow, neither of which applies here.
+	_ = limiter.WaitN(context.Background(), n)
+}
+
+// InvalidEntries returns the number of entries rejected by the value
+// validator since the map was created.
+func (m *Map) InvalidEntries() uint64 {
+	return m.invalidEntries.Load()
+}
+
+// handleInvalidEntry accounts an entry rejected by the value validator and
+// copies it to the quarantine map, if one has been configured. The quarantine
+// map is guarded by its own lock, so this is safe to call with m.lock held in
+// either mode.
+func (m *Map) handleInvalidEntry(key MapKey, value MapValue, err error) {
+	m.invalidEntries.Add(1)
+	m.Logger.Warn(
+		"Skipping invalid map entry",
+		logfields.Key, key,
+		logfields.Error, err,
+	)
+
+	if m.quarantineMap != nil {
+		if qErr := m.quarantineMap.Update(key, value); qErr != nil {
+			m.Logger.Warn(
+				"Unable to quarantine invalid map entry",
+				logfields.Key, key,
+				logfields.Error, qErr,
+			)
+		}
+	}
+}
+
+// m.lock must be held for writing
+func (m *Map) runPostUpdateHooks(action Action, key MapKey, value MapValue, err error) {
+	for _, fn := range m.postUpdateHooks {
+		fn(action, key, value, err)
+	}
+}
+
+// WithDirtyTracking enables recording of the keys mutated since the last
+// invocation of SyncDirty, so that small deltas can be re-synchronized to
+// the kernel without rewriting the full map. Dirty tracking requires the
+// value cache, hence this implies WithCache.
+func (m *Map) WithDirtyTracking() *Map {
+	m.WithCache()
+	if m.dirty == nil {
+		m.dirty = map[string]struct{}{}
+	}
+	return m
+}
+
+// markDirty records that the given key has been mutated, if dirty tracking
+// is enabled.
+//
+// m.lock must be held for writing
+func (m *Map) markDirty(key MapKey) {
+	if m.dirty != nil {
+		m.dirty[key.String()] = struct{}{}
+	}
+}
+
+// SyncDirty writes back to the kernel only the entries mutated since the
+// previous invocation, resolving possible discrepancies introduced by failed
+// operations without iterating over the full cache. Keys successfully synced
+// are removed from the dirty set; failed ones are kept, to be retried on the
+// next invocation.
+func (m *Map) SyncDirty() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	defer m.updatePressureMetric()
+
+	if m.dirty == nil {
+		return fmt.Errorf("dirty tracking not enabled for map %s", m.name)
+	}
+
+	if err := m.open(); err != nil {
+		return err
+	}
+
+	var errs []error
+	for k := range m.dirty {
+		entry, ok := m.cache[k]
+		if !ok {
+			// The entry has already been successfully deleted.
+			delete(m.dirty, k)
+			continue
+		}
+
+		var err error
+		switch entry.DesiredAction {
+		case Delete:
+			err = m.m.Delete(entry.Key)
+			if errors.Is(err, ebpf.ErrKeyNotExist) {
+				err = nil
+			}
+			if err == nil {
+				delete(m.cache, k)
+			}
+		default:
+			err = m.m.Update(entry.Key, entry.Value, ebpf.UpdateAny)
+			if err == nil {
+				entry.DesiredAction = OK
+				entry.LastError = nil
+				entry.lastSynced = time.Now()
+			}
+		}
+
+		if err != nil {
+			entry.LastError = err
+			errs = append(errs, fmt.Errorf("unable to sync key %s: %w", k, err))
+			continue
+		}
+
+		delete(m.dirty, k)
+	}
+
+	return errors.Join(errs...)
+}
+
 // WithEvents enables use of the event buffer, if the buffer is enabled.
 // This stores all map events (i.e. add/update/delete) in a bounded event buffer.
 // If eventTTL is not zero, than events that are older than the TTL
@@ -377,6 +797,45 @@ func (m *Map) WithEvents(c option.BPFEventBufferConfig) *Map {
 	return m
 }
 
+// EnableEvents enables the event buffer of the map at runtime, e.g. for
+// troubleshooting purposes. It is a no-op if event buffering is already
+// enabled, or if the given configuration is disabled.
+func (m *Map) EnableEvents(c option.BPFEventBufferConfig) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.eventsBufferEnabled || !c.Enabled {
+		return
+	}
+
+	m.Logger.Info(
+		"enabling events buffer at runtime",
+		logfields.Size, c.MaxSize,
+		logfields.TTL, c.TTL,
+	)
+	m.eventsBufferEnabled = true
+	m.initEventsBuffer(c.MaxSize, c.TTL)
+}
+
+// DisableEvents disables the event buffer of the map at runtime, closing all
+// active subscriptions and discarding the buffered events.
+func (m *Map) DisableEvents() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if !m.eventsBufferEnabled {
+		return
+	}
+
+	m.Logger.Info("disabling events buffer at runtime")
+	m.eventsBufferEnabled = false
+	if m.events.eventTTL > 0 {
+		mapControllers.RemoveController(fmt.Sprintf("bpf-event-buffer-gc-%s", m.name))
+	}
+	m.events.close()
+	m.events = nil
+}
+
 func (m *Map) WithGroupName(group string) *Map {
 	m.group = group
 	return m
@@ -396,6 +855,7 @@ func (m *Map) WithPressureMetricThreshold(registry *metrics.Registry, threshold
 	}
 
 	m.pressureGauge = registry.NewBPFMapPressureGauge(m.NonPrefixedName(), threshold)
+	m.pressureThreshold = threshold
 
 	return m
 }
@@ -557,18 +1017,59 @@ func (m *Map) IsOpen() bool {
 	return m.m != nil
 }
 
+// openOrCreateMaxAttempts bounds the number of times OpenOrCreate retries
+// opening the map after a transient pin race with another process.
+const openOrCreateMaxAttempts = 5
+
+// isTransientPinError returns true if the given error may be the result of
+// another process concurrently removing or recreating the map pin, e.g. the
+// pin vanishing between the create and load steps (ENOENT) or appearing
+// between the load and pin steps (EEXIST). Such failures are expected to
+// resolve on retry.
+func isTransientPinError(err error) bool {
+	return errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrExist)
+}
+
 // OpenOrCreate attempts to open the Map, or if it does not yet exist, create
 // the Map. If the existing map's attributes such as map type, key/value size,
 // capacity, etc. do not match the Map's attributes, then the map will be
 // deleted and reopened without any attempt to retain its previous contents.
 // If the map is marked as non-persistent, it will always be recreated.
 //
+// Failures caused by another process concurrently recreating the pin are
+// retried a bounded number of times with exponential backoff, so that
+// concurrent agent startup does not fail on these races.
+//
 // Returns whether the map was deleted and recreated, or an optional error.
 func (m *Map) OpenOrCreate() error {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
-	return m.openOrCreate(true)
+	retry := backoff.Exponential{
+		Logger: m.Logger,
+		Name:   "bpf-map-open-" + m.name,
+		Min:    10 * time.Millisecond,
+		Max:    500 * time.Millisecond,
+		Jitter: true,
+	}
+
+	var err error
+	for attempt := 1; attempt <= openOrCreateMaxAttempts; attempt++ {
+		err = m.openOrCreate(true)
+		if err == nil || !isTransientPinError(err) {
+			return err
+		}
+		if attempt < openOrCreateMaxAttempts {
+			m.Logger.Debug(
+				"Retrying map creation after transient pin race",
+				logfields.Attempt, attempt,
+				logfields.Error, err,
+			)
+			retry.Wait(context.Background())
+		}
+	}
+
+	return fmt.Errorf("opening map after %d attempts: %w", openOrCreateMaxAttempts, err)
 }
 
 // CreateUnpinned creates the map without pinning it to the file system.
@@ -615,6 +1116,7 @@ func (m *Map) openOrCreate(pin bool) error {
 
 	em, err := OpenOrCreateMap(m.Logger, m.spec, path.Dir(m.path))
 	if err != nil {
+		m.openErr = err
 		return err
 	}
 
@@ -626,6 +1128,7 @@ func (m *Map) openOrCreate(pin bool) error {
 
 	// Retain the Map.
 	m.m = em
+	m.openErr = nil
 
 	return nil
 }
@@ -653,13 +1156,15 @@ func (m *Map) open() error {
 
 	em, err := ebpf.LoadPinnedMap(m.path, nil)
 	if err != nil {
-		return fmt.Errorf("loading pinned map %s: %w", m.path, err)
+		m.openErr = fmt.Errorf("loading pinned map %s: %w", m.path, err)
+		return m.openErr
 	}
 
 	m.updateMetrics()
 	registerMap(m.Logger, m.path, m)
 
 	m.m = em
+	m.openErr = nil
 
 	return nil
 }
@@ -713,6 +1218,7 @@ func (m *Map) DumpWithCallback(cb DumpCallback) error {
 		return err
 	}
 
+	m.waitSyscallBudget(int(m.MaxEntries()))
 	m.lock.RLock()
 	defer m.lock.RUnlock()
 
@@ -722,6 +1228,13 @@ func (m *Map) DumpWithCallback(cb DumpCallback) error {
 
 	i := m.m.Iterate()
 	for i.Next(mk, mv) {
+		if m.valueValidator != nil {
+			if err := m.valueValidator(mk, mv); err != nil {
+				m.handleInvalidEntry(mk, mv, err)
+				continue
+			}
+		}
+
 		cb(mk, mv)
 
 		mk = m.key.New()
@@ -757,6 +1270,7 @@ func (m *Map) DumpPerCPUWithCallback(cb DumpPerCPUCallback) error {
 		return err
 	}
 
+	m.waitSyscallBudget(int(m.MaxEntries()))
 	m.lock.RLock()
 	defer m.lock.RUnlock()
 
@@ -825,6 +1339,8 @@ func (m *Map) DumpReliablyWithCallback(cb DumpCallback, stats *DumpStats) error
 		return err
 	}
 
+	m.waitSyscallBudget(int(m.MaxEntries()))
+
 	// Acquire a (write) lock here as callers can invoke map operations in the
 	// DumpCallback that need a (write) lock.
 	// See PR for more details. - https://github.com/cilium/cilium/pull/38590.
@@ -889,7 +1405,15 @@ func (m *Map) DumpReliablyWithCallback(cb DumpCallback, stats *DumpStats) error
 			continue
 		}
 
-		cb(currentKey, value)
+		verr := error(nil)
+		if m.valueValidator != nil {
+			verr = m.valueValidator(currentKey, value)
+		}
+		if verr != nil {
+			m.handleInvalidEntry(currentKey, value, verr)
+		} else {
+			cb(currentKey, value)
+		}
 
 		if nextKeyErr != nil {
 			if errors.Is(nextKeyErr, ebpf.ErrKeyNotExist) {
@@ -922,10 +1446,59 @@ type BatchIterator[KT, VT any, KP KeyPointer[KT], VP ValuePointer[VT]] struct {
 
 	// Iteration stats
 	batchSize int
+	retries   int
+
+	// cur tracks the position of the iteration after the most recently
+	// processed entry and backs Cursor().
+	cur             BatchCursor
+	resume          *BatchCursor
+	progress        func(BatchIteratorProgress)
+	randomStartSpan int
 
 	opts *ebpf.BatchOptions
 }
 
+// BatchCursor is an opaque checkpoint of a batched map iteration. It can be
+// serialized via MarshalBinary, persisted across agent restarts and passed to
+// a later iteration via WithResumeCursor to continue roughly where the
+// previous pass left off.
+type BatchCursor struct {
+	// processed is the number of entries iterated up to this position.
+	processed uint64
+	// key holds the raw bytes of the most recently iterated key.
+	key []byte
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (c *BatchCursor) MarshalBinary() ([]byte, error) {
+	out := make([]byte, 8, 8+len(c.key))
+	binary.LittleEndian.PutUint64(out, c.processed)
+	return append(out, c.key...), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (c *BatchCursor) UnmarshalBinary(data []byte) error {
+	if len(data) < 8 {
+		return fmt.Errorf("invalid batch cursor: %d bytes", len(data))
+	}
+	c.processed = binary.LittleEndian.Uint64(data[:8])
+	c.key = slices.Clone(data[8:])
+	return nil
+}
+
+// BatchIteratorProgress describes the state of an ongoing batched iteration.
+// It is passed to the callback installed via WithProgressCallback after every
+// completed batch lookup.
+type BatchIteratorProgress struct {
+	// Processed is the number of entries iterated so far, including entries
+	// skipped while fast-forwarding to a resume cursor.
+	Processed int
+	// ChunkSize is the current batch buffer size.
+	ChunkSize int
+	// Retries is the number of ENOSPC retries performed so far.
+	Retries int
+}
+
 // NewBatchIterator that allows for iterating a map using the bpf batch api.
 // This automatically handles concerns such as batch sizing and handling errors
 // when end of map is reached.
@@ -1026,6 +1599,69 @@ func WithStartingChunkSize[KT, VT any, KP KeyPointer[KT], VP ValuePointer[VT]](s
 	}
 }
 
+// WithProgressCallback returns a batch iterator option that installs a callback
+// which is invoked after every completed batch lookup with the number of entries
+// iterated so far, the current chunk size and the number of ENOSPC retries.
+// Long-running passes can use this to emit metrics or to decide when to cancel
+// the iteration and checkpoint via Cursor().
+func WithProgressCallback[KT, VT any, KP KeyPointer[KT], VP ValuePointer[VT]](fn func(BatchIteratorProgress)) BatchIteratorOpt[KT, VT, KP, VP] {
+	return func(in *BatchIterator[KT, VT, KP, VP]) *BatchIterator[KT, VT, KP, VP] {
+		in.progress = fn
+		return in
+	}
+}
+
+// WithResumeCursor returns a batch iterator option that resumes the next
+// iteration from the given checkpoint instead of from the start of the map.
+// The iterator fast-forwards without yielding until it has passed the
+// checkpointed key or, should that key no longer exist, until it has skipped
+// as many entries as the checkpointed pass had already processed.
+//
+// Resumption is best-effort: concurrent map mutations may cause a small number
+// of entries to be skipped or re-yielded around the checkpoint. This is
+// acceptable for periodic scans (such as GC passes) which revisit the whole
+// map over time. The option applies to the next call to IterateAll only.
+func WithResumeCursor[KT, VT any, KP KeyPointer[KT], VP ValuePointer[VT]](cursor *BatchCursor) BatchIteratorOpt[KT, VT, KP, VP] {
+	return func(in *BatchIterator[KT, VT, KP, VP]) *BatchIterator[KT, VT, KP, VP] {
+		in.resume = cursor
+		return in
+	}
+}
+
+// WithRandomStartOffset returns a batch iterator option that starts every
+// iteration cycle at a random entry offset, chosen uniformly from [0, span),
+// and wraps around so that a full cycle still yields every entry exactly once.
+// Periodic scans which only process a bounded number of entries per cycle no
+// longer favor the same leading buckets and statistically cover the whole map
+// over time. Callers typically pass the (approximate) number of entries in the
+// map as span.
+//
+// The option is ignored for a cycle resumed via WithResumeCursor.
+func WithRandomStartOffset[KT, VT any, KP KeyPointer[KT], VP ValuePointer[VT]](span int) BatchIteratorOpt[KT, VT, KP, VP] {
+	return func(in *BatchIterator[KT, VT, KP, VP]) *BatchIterator[KT, VT, KP, VP] {
+		in.randomStartSpan = span
+		return in
+	}
+}
+
+// Cursor returns a checkpoint of the position after the most recently
+// processed entry. Together with WithResumeCursor it allows a cancelled
+// iteration to be continued later, possibly by another process. The returned
+// cursor has batch granularity: entries of a partially consumed batch may be
+// re-yielded or skipped on resumption.
+func (bi *BatchIterator[KT, VT, KP, VP]) Cursor() *BatchCursor {
+	return &BatchCursor{
+		processed: bi.cur.processed,
+		key:       slices.Clone(bi.cur.key),
+	}
+}
+
+// rawKeyBytes returns the raw in-memory representation of a map key, which is
+// what identifies the key in a BatchCursor.
+func rawKeyBytes[KT any](k *KT) []byte {
+	return unsafe.Slice((*byte)(unsafe.Pointer(k)), unsafe.Sizeof(*k))
+}
+
 // CountAll is a helper function that returns the count of all elements in a batched
 // iterator.
 func CountAll[KT, VT any, KP KeyPointer[KT], VP ValuePointer[VT]](ctx context.Context, iter *BatchIterator[KT, VT, KP, VP]) (int, error) {
@@ -1073,16 +1709,47 @@ func (bi *BatchIterator[KT, VT, KP, VP]) IterateAll(ctx context.Context, opts ..
 	// reset values
 	bi.err = nil
 	bi.batchSize = 0
+	bi.retries = 0
 	bi.keys = make([]KT, bi.chunkSize)
 	bi.vals = make([]VT, bi.chunkSize)
+	bi.cur.processed = 0
+	bi.cur.key = bi.cur.key[:0]
+
+	// Consume any resume checkpoint: fast-forward without yielding until the
+	// checkpointed key has been passed, capped at the number of entries the
+	// checkpointed pass had processed in case the key has since been deleted.
+	var skipKey []byte
+	var skipCount uint64
+	skipping := false
+	if bi.resume != nil {
+		skipKey = bi.resume.key
+		skipCount = bi.resume.processed
+		skipping = len(skipKey) > 0 || skipCount > 0
+		bi.resume = nil
+	}
+
+	// Pick a fresh random starting offset for this cycle. The skipped leading
+	// entries are delivered at the end of the cycle by wrapping around.
+	wrap := false
+	if !skipping && bi.randomStartSpan > 1 {
+		if off := uint64(rand.IntN(bi.randomStartSpan)); off > 0 {
+			skipping = true
+			skipCount = off
+			wrap = true
+		}
+	}
 
-	processed := 0
 	var cursor ebpf.MapBatchCursor
 	return func(yield func(KP, VP) bool) {
 		if bi.Err() != nil {
 			return
 		}
 
+		// Entries still to deliver from the head of the map once the cycle
+		// has wrapped around after a random start offset.
+		wrapRemaining := uint64(0)
+		wrapped := false
+
 	iterate:
 		for {
 			if ctx.Err() != nil {
@@ -1113,6 +1780,7 @@ func (bi *BatchIterator[KT, VT, KP, VP]) IterateAll(ctx context.Context, opts ..
 					if retry == bi.maxBatchedRetries()-1 {
 						bi.err = batchErr
 					} else {
+						bi.retries++
 						bi.chunkSize *= 2
 						bi.keys = make([]KT, bi.chunkSize)
 						bi.vals = make([]VT, bi.chunkSize)
@@ -1127,13 +1795,49 @@ func (bi *BatchIterator[KT, VT, KP, VP]) IterateAll(ctx context.Context, opts ..
 
 				// Yield all received pairs.
 				for i := range bi.batchSize {
-					processed++
+					bi.cur.processed++
+					bi.cur.key = append(bi.cur.key[:0], rawKeyBytes(&bi.keys[i])...)
+					if skipping {
+						if bytes.Equal(bi.cur.key, skipKey) || bi.cur.processed >= skipCount {
+							skipping = false
+						}
+						continue
+					}
 					if !yield(&bi.keys[i], &bi.vals[i]) {
 						break iterate
 					}
+					if wrapped {
+						wrapRemaining--
+						if wrapRemaining == 0 {
+							break iterate
+						}
+					}
+				}
+
+				if bi.progress != nil {
+					bi.progress(BatchIteratorProgress{
+						Processed: int(bi.cur.processed),
+						ChunkSize: bi.chunkSize,
+						Retries:   bi.retries,
+					})
 				}
 
 				if done {
+					if wrap && !wrapped {
+						// End of the map reached after a random start offset:
+						// restart from the beginning to deliver the entries
+						// skipped at the head. If the map held fewer entries
+						// than the offset, everything was skipped and the
+						// whole map is delivered on the second pass.
+						wrapped = true
+						wrapRemaining = min(skipCount, bi.cur.processed)
+						skipping = false
+						if wrapRemaining == 0 {
+							break iterate
+						}
+						cursor = ebpf.MapBatchCursor{}
+						continue iterate
+					}
 					break iterate
 				}
 				break retry // finish retry loop for this batch.
@@ -1147,7 +1851,7 @@ func (bi *BatchIterator[KT, VT, KP, VP]) IterateAll(ctx context.Context, opts ..
 func (m *Map) Dump(hash map[string][]string) error {
 	callback := func(key MapKey, value MapValue) {
 		// No need to deep copy since we are creating strings.
-		hash[key.String()] = append(hash[key.String()], value.String())
+		hash[key.String()] = append(hash[key.String()], m.valueString(key, value))
 	}
 
 	if err := m.DumpWithCallback(callback); err != nil {
@@ -1178,11 +1882,28 @@ func (m *Map) DumpIfExists(hash map[string][]string) error {
 	return nil
 }
 
+var (
+	// ErrKeyNotExist is returned by Lookup when the key does not exist in
+	// the map. It is an alias for ebpf.ErrKeyNotExist, so that callers can
+	// distinguish missing keys from other failures without depending on
+	// ebpf-go directly.
+	ErrKeyNotExist = ebpf.ErrKeyNotExist
+
+	// ErrValueDecode is returned by Lookup when the looked-up value cannot
+	// be decoded into the map's value type.
+	ErrValueDecode = errors.New("unable to decode map value")
+
+	// ErrInvalidEntry is returned by Lookup when the looked-up entry is
+	// rejected by the validator registered via WithValueValidator.
+	ErrInvalidEntry = errors.New("invalid map entry")
+)
+
 func (m *Map) Lookup(key MapKey) (MapValue, error) {
 	if err := m.Open(); err != nil {
 		return nil, err
 	}
 
+	m.waitSyscallBudget(1)
 	m.lock.RLock()
 	defer m.lock.RUnlock()
 
@@ -1199,18 +1920,55 @@ func (m *Map) Lookup(key MapKey) (MapValue, error) {
 	}
 
 	if err != nil {
+		if errors.Is(err, ErrKeyNotExist) {
+			return nil, fmt.Errorf("lookup map %s: %w", m.Name(), err)
+		}
+
+		// Errors not originating from the syscall itself indicate that the
+		// looked-up value could not be decoded.
+		var errno unix.Errno
+		if !errors.As(err, &errno) {
+			return nil, fmt.Errorf("lookup map %s: %w: %w", m.Name(), ErrValueDecode, err)
+		}
+
 		return nil, err
 	}
 
+	if m.valueValidator != nil {
+		if verr := m.valueValidator(key, value); verr != nil {
+			m.handleInvalidEntry(key, value, verr)
+			return nil, fmt.Errorf("lookup map %s: %w: %w", m.Name(), ErrInvalidEntry, verr)
+		}
+	}
+
 	return value, nil
 }
 
+// LookupOK is like Lookup, but reports a missing key via the ok return value
+// instead of an error.
+func (m *Map) LookupOK(key MapKey) (value MapValue, ok bool, err error) {
+	value, err = m.Lookup(key)
+	switch {
+	case errors.Is(err, ErrKeyNotExist):
+		return nil, false, nil
+	case err != nil:
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
 func (m *Map) Update(key MapKey, value MapValue) error {
 	var err error
 
+	m.waitSyscallBudget(1)
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
+	// Runs after the cache bookkeeping below has completed.
+	defer func() {
+		m.runPostUpdateHooks(MapUpdate, key, value, err)
+	}()
+
 	defer func() {
 		desiredAction := OK
 		if err != nil {
@@ -1223,6 +1981,7 @@ func (m *Map) Update(key MapKey, value MapValue) error {
 			LastError:     err,
 		}
 		m.addToEventsLocked(MapUpdate, *entry)
+		m.markDirty(key)
 
 		if m.cache == nil {
 			return
@@ -1232,12 +1991,17 @@ func (m *Map) Update(key MapKey, value MapValue) error {
 			if err != nil {
 				m.scheduleErrorResolver()
 			}
-			m.cache[key.String()] = &cacheEntry{
+			ce := &cacheEntry{
 				Key:           key,
 				Value:         value,
 				DesiredAction: desiredAction,
 				LastError:     err,
 			}
+			if err == nil {
+				ce.lastSynced = time.Now()
+			}
+			m.cache[key.String()] = ce
+			m.evictCacheEntries()
 			m.updatePressureMetric()
 		} else if err == nil {
 			m.cache[key.String()] = nil
@@ -1245,6 +2009,8 @@ func (m *Map) Update(key MapKey, value MapValue) error {
 		}
 	}()
 
+	m.runPreUpdateHooks(MapUpdate, key, value)
+
 	if err = m.open(); err != nil {
 		return err
 	}
@@ -1271,6 +2037,7 @@ func (m *Map) deleteMapEvent(key MapKey, err error) {
 		DesiredAction: Delete,
 		LastError:     err,
 	})
+	m.markDirty(key)
 	m.deleteCacheEntry(key, err)
 }
 
@@ -1318,8 +2085,11 @@ func (m *Map) delete(key MapKey, ignoreMissing bool) (_ bool, err error) {
 		if err != nil {
 			m.updatePressureMetric()
 		}
+		m.runPostUpdateHooks(MapDelete, key, nil, err)
 	}()
 
+	m.runPreUpdateHooks(MapDelete, key, nil)
+
 	if err = m.open(); err != nil {
 		return false, err
 	}
@@ -1367,6 +2137,7 @@ func (m *Map) SilentDelete(key MapKey) (deleted bool, err error) {
 
 // Delete deletes the map entry corresponding to the given key.
 func (m *Map) Delete(key MapKey) error {
+	m.waitSyscallBudget(1)
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
@@ -1388,6 +2159,7 @@ func (m *Map) DeleteLocked(key MapKey) error {
 // entries. Note that if entries are added while the taversal is in progress,
 // such entries may survive the deletion process.
 func (m *Map) DeleteAll() error {
+	m.waitSyscallBudget(int(m.MaxEntries()))
 	m.lock.Lock()
 	defer m.lock.Unlock()
 	defer m.updatePressureMetric()
@@ -1434,6 +2206,130 @@ func (m *Map) DeleteAll() error {
 	return err
 }
 
+// MapEntry is a key/value pair used for bulk map operations.
+type MapEntry struct {
+	Key   MapKey
+	Value MapValue
+}
+
+// ReplaceAll atomically replaces the full contents of the map with the given
+// entries. A new generation of the map is created with the same properties,
+// populated via batch operations (falling back to individual updates when
+// unsupported by the kernel), and swapped in by atomically renaming its pin
+// over the existing one, so that readers resolving the map through the pin
+// never observe partial state.
+//
+// References resolved at load time, such as tail-call or map-in-map slots,
+// keep pointing to the old generation until updated. Callers replacing maps
+// referenced this way must re-point those references via the updateRefs
+// callback, which is invoked with the new map after the pin swap and before
+// the old generation is closed. updateRefs may be nil.
+func (m *Map) ReplaceAll(entries []MapEntry, updateRefs func(newMap *ebpf.Map) error) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	defer m.updatePressureMetric()
+
+	if err := m.open(); err != nil {
+		return err
+	}
+
+	spec := &ebpf.MapSpec{
+		Type:       m.m.Type(),
+		KeySize:    m.m.KeySize(),
+		ValueSize:  m.m.ValueSize(),
+		MaxEntries: m.m.MaxEntries(),
+		Flags:      m.m.Flags(),
+	}
+	if info, err := m.m.Info(); err == nil {
+		spec.Name = info.Name
+	}
+
+	newMap, err := ebpf.NewMap(spec)
+	if err != nil {
+		return fmt.Errorf("creating new generation of map %s: %w", m.name, err)
+	}
+
+	if err := populateMap(newMap, entries); err != nil {
+		newMap.Close()
+		return fmt.Errorf("populating new generation of map %s: %w", m.name, err)
+	}
+
+	// Pin the new generation next to the existing pin and atomically rename
+	// it over, so that there is no window during which the path is missing.
+	// Note that bpffs rejects file names containing dots.
+	tmpPath := m.path + "_new"
+	if err := newMap.Pin(tmpPath); err != nil {
+		// Remove a possibly stale pin left over from an earlier run and retry.
+		os.Remove(tmpPath)
+		if err := newMap.Pin(tmpPath); err != nil {
+			newMap.Close()
+			return fmt.Errorf("pinning new generation of map %s: %w", m.name, err)
+		}
+	}
+	if err := os.Rename(tmpPath, m.path); err != nil {
+		newMap.Unpin()
+		newMap.Close()
+		return fmt.Errorf("swapping pin of map %s: %w", m.name, err)
+	}
+
+	if updateRefs != nil {
+		if err := updateRefs(newMap); err != nil {
+			return fmt.Errorf("updating references to map %s: %w", m.name, err)
+		}
+	}
+
+	m.m.Close()
+	m.m = newMap
+
+	m.addToEventsLocked(MapDeleteAll, cacheEntry{})
+	if m.cache != nil {
+		m.cache = make(map[string]*cacheEntry, len(entries))
+	}
+
+	for _, entry := range entries {
+		ce := cacheEntry{Key: entry.Key, Value: entry.Value, DesiredAction: OK, lastSynced: time.Now()}
+		m.addToEventsLocked(MapUpdate, ce)
+		m.markDirty(entry.Key)
+
+		if m.withValueCache {
+			m.cache[entry.Key.String()] = &ce
+		} else if m.cache != nil {
+			m.cache[entry.Key.String()] = nil
+		}
+	}
+	m.evictCacheEntries()
+	m.setOutstandingErrors(false)
+
+	return nil
+}
+
+// populateMap fills the given map with the provided entries, preferring batch
+// operations and falling back to individual updates if unsupported.
+func populateMap(em *ebpf.Map, entries []MapEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	keys := make([]MapKey, 0, len(entries))
+	values := make([]MapValue, 0, len(entries))
+	for _, entry := range entries {
+		keys = append(keys, entry.Key)
+		values = append(values, entry.Value)
+	}
+
+	if _, err := em.BatchUpdate(keys, values, nil); err == nil {
+		return nil
+	}
+
+	for _, entry := range entries {
+		if err := em.Update(entry.Key, entry.Value, ebpf.UpdateAny); err != nil {
+			return fmt.Errorf("update key %s: %w", entry.Key, err)
+		}
+	}
+
+	return nil
+}
+
 func (m *Map) ClearAll() error {
 	if m.eventsBufferEnabled || m.withValueCache {
 		return fmt.Errorf("clear map: events buffer and value cache are not supported")
@@ -1482,7 +2378,7 @@ func (m *Map) GetModel() *models.BPFMap {
 	mapModel.Cache = make([]*models.BPFMapEntry, 0, len(m.cache))
 	if m.withValueCache {
 		m.lock.RLock()
-		defer m.lock.RUnlock()
+		cached := make(map[string]struct{}, len(m.cache))
 		for k, entry := range m.cache {
 			model := &models.BPFMapEntry{
 				Key:           k,
@@ -1494,10 +2390,29 @@ func (m *Map) GetModel() *models.BPFMap {
 			}
 
 			if entry.Value != nil {
-				model.Value = entry.Value.String()
+				model.Value = m.valueString(entry.Key, entry.Value)
 			}
 			mapModel.Cache = append(mapModel.Cache, model)
+			cached[k] = struct{}{}
+		}
+		m.lock.RUnlock()
+
+		if m.cacheLimit != 0 {
+			// The cache is bounded: synced entries may have been evicted
+			// from it, so reload them from the kernel, which holds the
+			// authoritative copy.
+			stats := NewDumpStats(m)
+			m.DumpReliablyWithCallback(func(key MapKey, value MapValue) {
+				if _, ok := cached[key.String()]; !ok {
+					mapModel.Cache = append(mapModel.Cache, &models.BPFMapEntry{
+						Key:           key.String(),
+						Value:         m.valueString(key, value),
+						DesiredAction: OK.String(),
+					})
+				}
+			}, stats)
 		}
+
 		return mapModel
 	}
 
@@ -1505,7 +2420,7 @@ func (m *Map) GetModel() *models.BPFMap {
 	filterCallback := func(key MapKey, value MapValue) {
 		mapModel.Cache = append(mapModel.Cache, &models.BPFMapEntry{
 			Key:   key.String(),
-			Value: value.String(),
+			Value: m.valueString(key, value),
 		})
 	}
 
@@ -1518,9 +2433,10 @@ func (m *Map) addToEventsLocked(action Action, entry cacheEntry) {
 		return
 	}
 	m.events.add(&Event{
-		action:     action,
-		Timestamp:  time.Now(),
-		cacheEntry: entry,
+		action:        action,
+		Timestamp:     time.Now(),
+		cacheEntry:    entry,
+		valueRedactor: m.valueRedactor,
 	})
 }
 
@@ -1552,7 +2468,7 @@ func (m *Map) resolveErrors(ctx context.Context) error {
 	// Errors appear to have already been resolved. This can happen if a subsequent
 	// Update/Delete operation acting on the same key succeeded.
 	if outstanding == 0 {
-		m.outstandingErrors = false
+		m.setOutstandingErrors(false)
 		return nil
 	}
 
@@ -1582,6 +2498,7 @@ func (m *Map) resolveErrors(ctx context.Context) error {
 			if err == nil {
 				e.DesiredAction = OK
 				e.LastError = nil
+				e.lastSynced = time.Now()
 				resolved++
 				outstanding--
 			} else {
@@ -1625,7 +2542,7 @@ func (m *Map) resolveErrors(ctx context.Context) error {
 		logfields.Duration, time.Since(started),
 	)
 
-	m.outstandingErrors = outstanding > 0
+	m.setOutstandingErrors(outstanding > 0)
 	if m.outstandingErrors {
 		return fmt.Errorf("%d map sync errors", outstanding)
 	}
@@ -5,8 +5,10 @@ package bpf
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strconv"
 	"strings"
@@ -115,6 +117,20 @@ func mapsEqual(a, b *Map) bool {
 		reflect.DeepEqual(a.spec, b.spec)
 }
 
+func TestErrorResolverInterval(t *testing.T) {
+	m := &Map{}
+
+	// The default interval applies when no override has been set.
+	require.Equal(t, errorResolverSchedulerMinInterval, m.ErrorResolverInterval())
+
+	m.SetErrorResolverInterval(time.Second)
+	require.Equal(t, time.Second, m.ErrorResolverInterval())
+
+	// A zero duration resets the interval to the default.
+	m.SetErrorResolverInterval(0)
+	require.Equal(t, errorResolverSchedulerMinInterval, m.ErrorResolverInterval())
+}
+
 func TestPrivilegedOpen(t *testing.T) {
 	setup(t)
 
@@ -230,6 +246,163 @@ func TestPrivilegedRecreateMap(t *testing.T) {
 	require.EqualValues(t, value, value2)
 }
 
+func TestPrivilegedReplaceAll(t *testing.T) {
+	testMap := setup(t)
+
+	// Pre-populate the map with entries partially overlapping the new set.
+	require.NoError(t, testMap.Update(&TestKey{Key: 1}, &TestValue{Value: 100}))
+	require.NoError(t, testMap.Update(&TestKey{Key: 2}, &TestValue{Value: 200}))
+
+	entries := []MapEntry{
+		{Key: &TestKey{Key: 2}, Value: &TestValue{Value: 201}},
+		{Key: &TestKey{Key: 3}, Value: &TestValue{Value: 300}},
+	}
+
+	var newID ebpf.MapID
+	require.NoError(t, testMap.ReplaceAll(entries, func(newMap *ebpf.Map) error {
+		info, err := newMap.Info()
+		require.NoError(t, err)
+		newID, _ = info.ID()
+		return nil
+	}))
+
+	// The old contents have been fully replaced.
+	value, err := testMap.Lookup(&TestKey{Key: 1})
+	require.Error(t, err)
+	require.Nil(t, value)
+	value, err = testMap.Lookup(&TestKey{Key: 2})
+	require.NoError(t, err)
+	require.Equal(t, &TestValue{Value: 201}, value)
+	value, err = testMap.Lookup(&TestKey{Key: 3})
+	require.NoError(t, err)
+	require.Equal(t, &TestValue{Value: 300}, value)
+
+	// The pin now points to the new generation of the map.
+	pinned, err := ebpf.LoadPinnedMap(testMap.path, nil)
+	require.NoError(t, err)
+	defer pinned.Close()
+	info, err := pinned.Info()
+	require.NoError(t, err)
+	pinnedID, _ := info.ID()
+	require.Equal(t, newID, pinnedID)
+
+	// The cache reflects the new contents.
+	require.Len(t, testMap.cache, 2)
+	require.Equal(t, OK, testMap.cache["key=2"].DesiredAction)
+}
+
+func TestPrivilegedLookupErrors(t *testing.T) {
+	testMap := setup(t)
+
+	key := &TestKey{Key: 168}
+	value := &TestValue{Value: 268}
+
+	// A missing key is reported via the ErrKeyNotExist sentinel.
+	_, err := testMap.Lookup(key)
+	require.ErrorIs(t, err, ErrKeyNotExist)
+
+	v, ok, err := testMap.LookupOK(key)
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Nil(t, v)
+
+	require.NoError(t, testMap.Update(key, value))
+
+	v, ok, err = testMap.LookupOK(key)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, value, v)
+}
+
+func TestPrivilegedSweepOrphanMapPins(t *testing.T) {
+	testMap := setup(t)
+	logger := hivetest.Logger(t)
+
+	// Pin a map outside of the registry to simulate a leftover from a
+	// crashed agent.
+	orphanPath := filepath.Join(TCGlobalsPath(), "cilium_orphan_test")
+	em, err := ebpf.NewMap(&ebpf.MapSpec{
+		Type:       ebpf.Hash,
+		KeySize:    4,
+		ValueSize:  4,
+		MaxEntries: 10,
+		Flags:      unix.BPF_F_NO_PREALLOC,
+	})
+	require.NoError(t, err)
+	defer em.Close()
+	require.NoError(t, em.Pin(orphanPath))
+	t.Cleanup(func() { os.Remove(orphanPath) })
+
+	find := func(orphans []OrphanMapPin, path string) *OrphanMapPin {
+		for i := range orphans {
+			if orphans[i].Path == path {
+				return &orphans[i]
+			}
+		}
+		return nil
+	}
+
+	// A plain sweep reports the orphan but leaves it in place.
+	orphans, err := SweepOrphanMapPins(logger, OrphanPinGracePeriod, false)
+	require.NoError(t, err)
+	orphan := find(orphans, orphanPath)
+	require.NotNil(t, orphan)
+	require.Equal(t, ebpf.Hash, orphan.Type)
+	require.False(t, orphan.Removed)
+	require.Nil(t, find(orphans, testMap.path), "registered map reported as orphan")
+	require.FileExists(t, orphanPath)
+
+	// The grace period protects recently modified pins from removal.
+	orphans, err = SweepOrphanMapPins(logger, OrphanPinGracePeriod, true)
+	require.NoError(t, err)
+	orphan = find(orphans, orphanPath)
+	require.NotNil(t, orphan)
+	require.False(t, orphan.Removed)
+	require.FileExists(t, orphanPath)
+
+	orphans, err = SweepOrphanMapPins(logger, 0, true)
+	require.NoError(t, err)
+	orphan = find(orphans, orphanPath)
+	require.NotNil(t, orphan)
+	require.True(t, orphan.Removed)
+	require.NoFileExists(t, orphanPath)
+}
+
+func TestPrivilegedRuntimeEventBuffer(t *testing.T) {
+	testMap := setup(t)
+
+	// Events are not enabled at construction time.
+	_, err := testMap.DumpAndSubscribe(nil, true)
+	require.Error(t, err)
+
+	_, err = ApplyEventBufferConfig("[invalid", option.BPFEventBufferConfig{})
+	require.Error(t, err)
+
+	names, err := ApplyEventBufferConfig("^cilium_te.*$", option.BPFEventBufferConfig{Enabled: true, MaxSize: 10})
+	require.NoError(t, err)
+	require.Equal(t, []string{"cilium_test"}, names)
+
+	handle, err := testMap.DumpAndSubscribe(nil, true)
+	require.NoError(t, err)
+
+	require.NoError(t, testMap.Update(&TestKey{Key: 110}, &TestValue{Value: 210}))
+
+	event := <-handle.C()
+	require.Equal(t, "key=110", event.GetKey())
+	require.Equal(t, "value=210", event.GetValue())
+
+	// Disabling events closes active subscriptions and rejects new ones.
+	names, err = ApplyEventBufferConfig("^cilium_test$", option.BPFEventBufferConfig{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"cilium_test"}, names)
+
+	_, ok := <-handle.C()
+	require.False(t, ok)
+
+	_, err = testMap.DumpAndSubscribe(nil, true)
+	require.Error(t, err)
+}
+
 func TestPrivilegedBasicManipulation(t *testing.T) {
 	setup(t)
 	// existingMap is the same as testMap. Opening should succeed.
@@ -813,6 +986,272 @@ func TestPrivilegedGetModel(t *testing.T) {
 	require.NotNil(t, model)
 }
 
+func TestPrivilegedCacheLimit(t *testing.T) {
+	testutils.PrivilegedTest(t)
+
+	CheckOrMountFS(hivetest.Logger(t), "")
+
+	err := rlimit.RemoveMemlock()
+	require.NoError(t, err)
+
+	testMap := NewMap("cilium_test_limit",
+		ebpf.Hash,
+		&TestKey{},
+		&TestValue{},
+		maxEntries,
+		unix.BPF_F_NO_PREALLOC,
+	).WithCacheLimit(10)
+
+	err = testMap.OpenOrCreate()
+	require.NoError(t, err, "Failed to create map")
+
+	t.Cleanup(func() {
+		require.NoError(t, testMap.Close())
+	})
+
+	for i := range maxEntries {
+		err := testMap.Update(&TestKey{Key: uint32(i)}, &TestValue{Value: uint32(i)})
+		require.NoError(t, err)
+	}
+
+	// The cache stays bounded, while the kernel retains all entries.
+	testMap.lock.RLock()
+	cacheLen := len(testMap.cache)
+	testMap.lock.RUnlock()
+	require.LessOrEqual(t, cacheLen, 10)
+
+	for i := range maxEntries {
+		value, err := testMap.Lookup(&TestKey{Key: uint32(i)})
+		require.NoError(t, err)
+		require.Equal(t, &TestValue{Value: uint32(i)}, value)
+	}
+
+	// The model reloads the evicted entries from the kernel.
+	model := testMap.GetModel()
+	require.NotNil(t, model)
+	require.Len(t, model.Cache, maxEntries)
+}
+
+func TestPrivilegedUpdateHooks(t *testing.T) {
+	testMap := setup(t)
+
+	type hookCall struct {
+		pre    bool
+		action Action
+		key    string
+		value  string
+		err    error
+	}
+
+	var calls []hookCall
+	testMap.RegisterPreUpdateHook(func(action Action, key MapKey, value MapValue) {
+		call := hookCall{pre: true, action: action, key: key.String()}
+		if value != nil {
+			call.value = value.String()
+		}
+		calls = append(calls, call)
+	})
+	testMap.RegisterPostUpdateHook(func(action Action, key MapKey, value MapValue, err error) {
+		call := hookCall{action: action, key: key.String(), err: err}
+		if value != nil {
+			call.value = value.String()
+		}
+		calls = append(calls, call)
+	})
+
+	key := &TestKey{Key: 42}
+	value := &TestValue{Value: 4242}
+
+	require.NoError(t, testMap.Update(key, value))
+	require.NoError(t, testMap.Delete(key))
+
+	require.Equal(t, []hookCall{
+		{pre: true, action: MapUpdate, key: "key=42", value: "value=4242"},
+		{action: MapUpdate, key: "key=42", value: "value=4242"},
+		{pre: true, action: MapDelete, key: "key=42"},
+		{action: MapDelete, key: "key=42"},
+	}, calls)
+}
+
+func TestPrivilegedValueValidator(t *testing.T) {
+	testutils.PrivilegedTest(t)
+
+	CheckOrMountFS(hivetest.Logger(t), "")
+
+	err := rlimit.RemoveMemlock()
+	require.NoError(t, err)
+
+	quarantine := NewMap("cilium_test_quar",
+		ebpf.Hash,
+		&TestKey{},
+		&TestValue{},
+		maxEntries,
+		unix.BPF_F_NO_PREALLOC,
+	)
+	require.NoError(t, quarantine.OpenOrCreate())
+
+	testMap := NewMap("cilium_test_valid",
+		ebpf.Hash,
+		&TestKey{},
+		&TestValue{},
+		maxEntries,
+		unix.BPF_F_NO_PREALLOC,
+	).WithValueValidator(func(key MapKey, value MapValue) error {
+		if value.(*TestValue).Value >= 1000 {
+			return errors.New("poison value")
+		}
+		return nil
+	}).WithQuarantineMap(quarantine)
+	require.NoError(t, testMap.OpenOrCreate())
+
+	t.Cleanup(func() {
+		require.NoError(t, testMap.Close())
+		require.NoError(t, quarantine.Close())
+	})
+
+	for i := range 5 {
+		require.NoError(t, testMap.Update(&TestKey{Key: uint32(i)}, &TestValue{Value: uint32(i)}))
+	}
+	// Writes are not validated, emulating a poison entry left behind by an
+	// older version.
+	poisonKey := &TestKey{Key: 100}
+	require.NoError(t, testMap.Update(poisonKey, &TestValue{Value: 4242}))
+
+	// Valid entries remain accessible, the poison entry is reported.
+	value, err := testMap.Lookup(&TestKey{Key: 1})
+	require.NoError(t, err)
+	require.Equal(t, &TestValue{Value: 1}, value)
+
+	_, err = testMap.Lookup(poisonKey)
+	require.ErrorIs(t, err, ErrInvalidEntry)
+
+	// Dumps skip the poison entry.
+	dumped := map[string]string{}
+	require.NoError(t, testMap.DumpWithCallback(func(key MapKey, value MapValue) {
+		dumped[key.String()] = value.String()
+	}))
+	require.Len(t, dumped, 5)
+	require.NotContains(t, dumped, poisonKey.String())
+
+	require.EqualValues(t, 2, testMap.InvalidEntries())
+
+	// The poison entry has been quarantined for inspection.
+	value, err = quarantine.Lookup(poisonKey)
+	require.NoError(t, err)
+	require.Equal(t, &TestValue{Value: 4242}, value)
+}
+
+func TestPrivilegedValueRedactor(t *testing.T) {
+	testutils.PrivilegedTest(t)
+
+	CheckOrMountFS(hivetest.Logger(t), "")
+
+	err := rlimit.RemoveMemlock()
+	require.NoError(t, err)
+
+	redact := func(key MapKey, value MapValue) string {
+		return "<redacted>"
+	}
+
+	testMap := NewMap("cilium_test_redact",
+		ebpf.Hash,
+		&TestKey{},
+		&TestValue{},
+		maxEntries,
+		unix.BPF_F_NO_PREALLOC,
+	).WithCache().
+		WithEvents(option.BPFEventBufferConfig{Enabled: true, MaxSize: 10}).
+		WithValueRedactor(redact)
+	require.NoError(t, testMap.OpenOrCreate())
+
+	t.Cleanup(func() {
+		require.NoError(t, testMap.Close())
+	})
+
+	handle, err := testMap.DumpAndSubscribe(nil, true)
+	require.NoError(t, err)
+	defer handle.Close()
+
+	key := &TestKey{Key: 1}
+	require.NoError(t, testMap.Update(key, &TestValue{Value: 201}))
+
+	// Lookups still return the raw value.
+	value, err := testMap.Lookup(key)
+	require.NoError(t, err)
+	require.Equal(t, &TestValue{Value: 201}, value)
+
+	// Dump redacts the value, while keys are left intact.
+	dump := map[string][]string{}
+	require.NoError(t, testMap.Dump(dump))
+	require.Equal(t, map[string][]string{"key=1": {"<redacted>"}}, dump)
+
+	// GetModel redacts the values served from the cache.
+	model := testMap.GetModel()
+	require.Len(t, model.Cache, 1)
+	require.Equal(t, "key=1", model.Cache[0].Key)
+	require.Equal(t, "<redacted>", model.Cache[0].Value)
+
+	// Events expose the redacted value as well.
+	event := <-handle.C()
+	require.Equal(t, "key=1", event.GetKey())
+	require.Equal(t, "<redacted>", event.GetValue())
+
+	// GetModel also redacts the values of maps without a value cache, which
+	// are dumped directly from the kernel.
+	uncached := NewMap("cilium_test_redact2",
+		ebpf.Hash,
+		&TestKey{},
+		&TestValue{},
+		maxEntries,
+		unix.BPF_F_NO_PREALLOC,
+	).WithValueRedactor(redact)
+	require.NoError(t, uncached.OpenOrCreate())
+
+	t.Cleanup(func() {
+		require.NoError(t, uncached.Close())
+	})
+
+	require.NoError(t, uncached.Update(&TestKey{Key: 2}, &TestValue{Value: 202}))
+	model = uncached.GetModel()
+	require.Len(t, model.Cache, 1)
+	require.Equal(t, "key=2", model.Cache[0].Key)
+	require.Equal(t, "<redacted>", model.Cache[0].Value)
+}
+
+func TestPrivilegedSyscallBudget(t *testing.T) {
+	testMap := setup(t)
+
+	key := &TestKey{Key: 1}
+	require.NoError(t, testMap.Update(key, &TestValue{Value: 201}))
+
+	// A budget of 2 operations per 100ms grants the first two lookups from
+	// the bucket, and delays each subsequent one by 50ms.
+	testMap.SetSyscallBudget(2, 100*time.Millisecond)
+
+	start := time.Now()
+	for range 6 {
+		_, err := testMap.Lookup(key)
+		require.NoError(t, err)
+	}
+	require.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+
+	// Dumps drain up to a full bucket.
+	start = time.Now()
+	require.NoError(t, testMap.DumpWithCallback(func(key MapKey, value MapValue) {}))
+	require.NoError(t, testMap.DumpWithCallback(func(key MapKey, value MapValue) {}))
+	require.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	// Removing the budget lifts the throttling.
+	testMap.SetSyscallBudget(0, 0)
+
+	start = time.Now()
+	for range 100 {
+		_, err := testMap.Lookup(key)
+		require.NoError(t, err)
+	}
+	require.Less(t, time.Since(start), time.Second)
+}
+
 func TestPrivilegedCheckAndUpgrade(t *testing.T) {
 	setup(t)
 
@@ -1131,3 +1570,117 @@ func TestPrivilegedBatchIterator(t *testing.T) {
 		}
 	}
 }
+
+func TestBatchCursorRoundTrip(t *testing.T) {
+	cursor := &BatchCursor{
+		processed: 42,
+		key:       []byte{0xde, 0xad, 0xbe, 0xef},
+	}
+
+	data, err := cursor.MarshalBinary()
+	require.NoError(t, err)
+
+	var decoded BatchCursor
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, cursor.processed, decoded.processed)
+	assert.Equal(t, cursor.key, decoded.key)
+
+	// Truncated data is rejected.
+	assert.Error(t, decoded.UnmarshalBinary(data[:4]))
+}
+
+func TestPrivilegedBatchIteratorResume(t *testing.T) {
+	testutils.PrivilegedTest(t)
+
+	const size = 1024
+	m := NewMap("cilium_test",
+		ebpf.Hash,
+		&TestKey{},
+		&TestValue{},
+		size,
+		0,
+	)
+	require.NoError(t, m.OpenOrCreate())
+	defer assert.NoError(t, m.UnpinIfExists())
+	for i := range size {
+		require.NoError(t, m.Update(&TestKey{Key: uint32(i)}, &TestValue{Value: uint32(i)}))
+	}
+
+	// Interrupt a first pass half-way through and checkpoint it.
+	seen := sets.New[int]()
+	var progress []BatchIteratorProgress
+	iter := NewBatchIterator[TestKey, TestValue](m)
+	for k := range iter.IterateAll(context.TODO(),
+		WithProgressCallback[TestKey, TestValue](func(p BatchIteratorProgress) {
+			progress = append(progress, p)
+		})) {
+		seen.Insert(int(k.Key))
+		if seen.Len() == size/2 {
+			break
+		}
+	}
+	require.NoError(t, iter.Err())
+	require.Len(t, seen, size/2)
+
+	// Checkpoints survive serialization.
+	data, err := iter.Cursor().MarshalBinary()
+	require.NoError(t, err)
+	var cursor BatchCursor
+	require.NoError(t, cursor.UnmarshalBinary(data))
+
+	// A second pass resumed from the checkpoint yields exactly the remainder.
+	iter = NewBatchIterator[TestKey, TestValue](m)
+	for k := range iter.IterateAll(context.TODO(), WithResumeCursor[TestKey, TestValue](&cursor)) {
+		require.NotContains(t, seen, int(k.Key), "entry yielded twice across resumption")
+		seen.Insert(int(k.Key))
+	}
+	require.NoError(t, iter.Err())
+	assert.Len(t, seen, size)
+
+	// The progress callback observed every batch of the first pass.
+	require.NotEmpty(t, progress)
+	for _, p := range progress {
+		assert.Positive(t, p.ChunkSize)
+	}
+	last := progress[len(progress)-1]
+	assert.Positive(t, last.Processed)
+	assert.LessOrEqual(t, last.Processed, size)
+}
+
+func TestPrivilegedBatchIteratorRandomStart(t *testing.T) {
+	testutils.PrivilegedTest(t)
+
+	const size = 64
+	m := NewMap("cilium_test",
+		ebpf.Hash,
+		&TestKey{},
+		&TestValue{},
+		size,
+		0,
+	)
+	require.NoError(t, m.OpenOrCreate())
+	defer assert.NoError(t, m.UnpinIfExists())
+	for i := range size {
+		require.NoError(t, m.Update(&TestKey{Key: uint32(i)}, &TestValue{Value: uint32(i)}))
+	}
+
+	firstKeys := sets.New[int]()
+	for range 16 {
+		seen := sets.New[int]()
+		iter := NewBatchIterator[TestKey, TestValue](m)
+		for k := range iter.IterateAll(context.TODO(), WithRandomStartOffset[TestKey, TestValue](size)) {
+			if seen.Len() == 0 {
+				firstKeys.Insert(int(k.Key))
+			}
+			require.NotContains(t, seen, int(k.Key), "entry yielded twice within one cycle")
+			seen.Insert(int(k.Key))
+		}
+		require.NoError(t, iter.Err())
+
+		// Despite the random start, a full cycle covers every entry once.
+		require.Len(t, seen, size)
+	}
+
+	// The starting point varies between cycles.
+	assert.Greater(t, firstKeys.Len(), 1)
+}
@@ -6,12 +6,16 @@
 package bpf
 
 import (
+	"fmt"
 	"log/slog"
 	"path"
+	"regexp"
+	"slices"
 
 	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/pkg/lock"
 	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/option"
 )
 
 var (
@@ -69,3 +73,38 @@ func GetOpenMaps() []*models.BPFMap {
 
 	return mapList
 }
+
+// ApplyEventBufferConfig applies the given event buffer configuration to all
+// registered maps whose name matches the given regular expression pattern,
+// enabling or disabling event buffering at runtime. It returns the names of
+// the maps the configuration was applied to.
+func ApplyEventBufferConfig(pattern string, c option.BPFEventBufferConfig) ([]string, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+
+	// Collect the matching maps first, as the maps' own locks must not be
+	// taken while holding the registry mutex.
+	var matched []*Map
+	mutex.RLock()
+	for _, m := range mapRegister {
+		if re.MatchString(m.name) {
+			matched = append(matched, m)
+		}
+	}
+	mutex.RUnlock()
+
+	names := make([]string, 0, len(matched))
+	for _, m := range matched {
+		if c.Enabled {
+			m.EnableEvents(c)
+		} else {
+			m.DisableEvents()
+		}
+		names = append(names, m.name)
+	}
+
+	slices.Sort(names)
+	return names, nil
+}
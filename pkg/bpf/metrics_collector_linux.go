@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+//go:build linux
+
+package bpf
+
+import (
+	"log/slog"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/metrics"
+)
+
+// PendingErrors returns the number of cached entries currently in Insert and
+// Delete pending state, i.e. awaiting resolution by the periodic error
+// resolver.
+func (m *Map) PendingErrors() (inserts, deletes int) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	if !m.withValueCache {
+		return 0, 0
+	}
+
+	for _, entry := range m.cache {
+		switch entry.DesiredAction {
+		case Insert:
+			inserts++
+		case Delete:
+			deletes++
+		}
+	}
+	return inserts, deletes
+}
+
+var mapPendingErrorsDesc = prometheus.NewDesc(
+	metrics.Namespace+"_bpf_map_pending_errors",
+	"Number of BPF map entries pending insertion or deletion due to earlier errors, per map",
+	[]string{metrics.LabelMapName, metrics.LabelOperation}, nil,
+)
+
+// mapErrorBacklogCollector is a prometheus collector exposing the number of
+// keys in pending state in the caches of all registered maps, so that stuck
+// reconciliation (e.g., due to a full map) is alertable.
+type mapErrorBacklogCollector struct{}
+
+// NewMapErrorBacklogCollector returns a collector exposing the error resolver
+// backlog of all registered maps.
+func NewMapErrorBacklogCollector() prometheus.Collector {
+	return &mapErrorBacklogCollector{}
+}
+
+// RegisterMapErrorBacklogCollector registers the error backlog collector with
+// the agent metrics registry.
+func RegisterMapErrorBacklogCollector(logger *slog.Logger) {
+	if err := metrics.Register(NewMapErrorBacklogCollector()); err != nil {
+		logger.Error(
+			"Failed to register the BPF map error backlog collector to Prometheus registry. "+
+				"cilium_bpf_map_pending_errors metrics will not be collected",
+			logfields.Error, err,
+		)
+	}
+}
+
+func (c *mapErrorBacklogCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- mapPendingErrorsDesc
+}
+
+func (c *mapErrorBacklogCollector) Collect(ch chan<- prometheus.Metric) {
+	// Create a copy of mapRegister so we can unlock the mutex again as
+	// locking Map.lock inside of the mutex is not permitted
+	mutex.RLock()
+	maps := make([]*Map, 0, len(mapRegister))
+	for _, m := range mapRegister {
+		maps = append(maps, m)
+	}
+	mutex.RUnlock()
+
+	for _, m := range maps {
+		inserts, deletes := m.PendingErrors()
+		ch <- prometheus.MustNewConstMetric(mapPendingErrorsDesc, prometheus.GaugeValue,
+			float64(inserts), m.NonPrefixedName(), metricOpUpdate)
+		ch <- prometheus.MustNewConstMetric(mapPendingErrorsDesc, prometheus.GaugeValue,
+			float64(deletes), m.NonPrefixedName(), metricOpDelete)
+	}
+}
@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+//go:build linux
+
+package bpf
+
+import (
+	"testing"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/hive/hivetest"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+
+	"github.com/cilium/cilium/pkg/metrics"
+)
+
+func TestMapErrorBacklogCollector(t *testing.T) {
+	logger := hivetest.Logger(t)
+
+	m := NewMap("cilium_test_backlog",
+		ebpf.Hash,
+		&TestKey{},
+		&TestValue{},
+		4,
+		unix.BPF_F_NO_PREALLOC,
+	).WithCache()
+
+	m.lock.Lock()
+	m.cache["pending-insert"] = &cacheEntry{Key: &TestKey{Key: 1}, DesiredAction: Insert}
+	m.cache["pending-delete"] = &cacheEntry{Key: &TestKey{Key: 2}, DesiredAction: Delete}
+	m.cache["synced"] = &cacheEntry{Key: &TestKey{Key: 3}, DesiredAction: OK}
+	m.lock.Unlock()
+
+	inserts, deletes := m.PendingErrors()
+	require.Equal(t, 1, inserts)
+	require.Equal(t, 1, deletes)
+
+	registerMap(logger, m.name, m)
+	t.Cleanup(func() { unregisterMap(logger, m.name, m) })
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		defer close(ch)
+		NewMapErrorBacklogCollector().Collect(ch)
+	}()
+
+	// Other tests may have maps registered as well, only look at ours.
+	got := map[string]float64{}
+	for metric := range ch {
+		var out dto.Metric
+		require.NoError(t, metric.Write(&out))
+
+		labels := map[string]string{}
+		for _, pair := range out.Label {
+			labels[pair.GetName()] = pair.GetValue()
+		}
+		if labels[metrics.LabelMapName] == m.NonPrefixedName() {
+			got[labels[metrics.LabelOperation]] = out.Gauge.GetValue()
+		}
+	}
+	require.Equal(t, map[string]float64{
+		metricOpUpdate: 1,
+		metricOpDelete: 1,
+	}, got)
+}
@@ -150,3 +150,105 @@ func (ops *mapOps[KV]) Update(ctx context.Context, txn statedb.ReadTxn, _ stated
 		return m.Put(entry.BinaryKey(), entry.BinaryValue())
 	})
 }
+
+// batchMapOps extends mapOps with batched update and delete operations using
+// the kernel batch map API, amortizing the syscall cost over many entries.
+type batchMapOps[KV KeyValue] struct {
+	mapOps[KV]
+}
+
+// NewBatchMapOps returns the reconciliation operations for map 'm', implementing
+// both reconciler.Operations and reconciler.BatchOperations. Passing both to
+// reconciler.Register makes the reconciler apply the desired entries from the
+// StateDB table using batch syscalls, falling back to per-entry operations to
+// attribute errors (retried individually with backoff, and reported through
+// the module health) to the entry that caused them.
+func NewBatchMapOps[KV KeyValue](m *Map) (reconciler.Operations[KV], reconciler.BatchOperations[KV]) {
+	ops := &batchMapOps[KV]{mapOps[KV]{m}}
+	return ops, ops
+}
+
+// marshalBatch appends the binary representation of the keys (and values, if
+// 'values' is not nil) of the given batch entries into flat buffers suitable
+// for the batch syscalls.
+func (ops *batchMapOps[KV]) marshalBatch(batch []reconciler.BatchEntry[KV], values bool) (keyBuf, valueBuf []byte, err error) {
+	keyBuf = make([]byte, 0, len(batch)*int(ops.m.KeySize()))
+	if values {
+		valueBuf = make([]byte, 0, len(batch)*int(ops.m.ValueSize()))
+	}
+
+	for i := range batch {
+		key, err := batch[i].Object.BinaryKey().MarshalBinary()
+		if err != nil {
+			return nil, nil, err
+		}
+		keyBuf = append(keyBuf, key...)
+
+		if values {
+			value, err := batch[i].Object.BinaryValue().MarshalBinary()
+			if err != nil {
+				return nil, nil, err
+			}
+			valueBuf = append(valueBuf, value...)
+		}
+	}
+
+	return keyBuf, valueBuf, nil
+}
+
+// UpdateBatch implements reconciler.BatchOperations. The whole batch is
+// applied with a single BPF_MAP_UPDATE_BATCH syscall; if the syscall fails
+// part-way through, or batch operations are unsupported, the remaining
+// entries are applied individually so that the error is attributed to the
+// entry that caused it, and only that entry is retried with backoff.
+func (ops *batchMapOps[KV]) UpdateBatch(ctx context.Context, txn statedb.ReadTxn, batch []reconciler.BatchEntry[KV]) {
+	done := 0
+	err := ops.withMap(func(m *ebpf.Map) error {
+		if ops.m.hasPerCPUValue() {
+			// The batch API expects per-CPU values, which KeyValue does not
+			// model. Fall back to per-entry updates.
+			return ebpf.ErrNotSupported
+		}
+
+		keys, values, err := ops.marshalBatch(batch, true)
+		if err != nil {
+			return err
+		}
+
+		done, err = m.BatchUpdate(keys, values, nil)
+		return err
+	})
+	if err == nil {
+		return
+	}
+
+	for i := done; i < len(batch); i++ {
+		batch[i].Result = ops.Update(ctx, txn, batch[i].Revision, batch[i].Object)
+	}
+}
+
+// DeleteBatch implements reconciler.BatchOperations, with the same fallback
+// semantics as UpdateBatch.
+func (ops *batchMapOps[KV]) DeleteBatch(ctx context.Context, txn statedb.ReadTxn, batch []reconciler.BatchEntry[KV]) {
+	done := 0
+	err := ops.withMap(func(m *ebpf.Map) error {
+		keys, _, err := ops.marshalBatch(batch, false)
+		if err != nil {
+			return err
+		}
+
+		// The batch delete stops at the first non-existing key, returning
+		// the number of keys deleted up to that point. The remaining entries
+		// are handled by the per-entry fallback below, which silently
+		// ignores deletions of non-existing keys.
+		done, err = m.BatchDelete(keys, nil)
+		return err
+	})
+	if err == nil {
+		return
+	}
+
+	for i := done; i < len(batch); i++ {
+		batch[i].Result = ops.Delete(ctx, txn, batch[i].Revision, batch[i].Object)
+	}
+}
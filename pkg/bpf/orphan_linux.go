@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+//go:build linux
+
+package bpf
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/cilium/ebpf"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/metrics"
+	"github.com/cilium/cilium/pkg/time"
+)
+
+// OrphanPinGracePeriod is the default minimum age a pin must have before
+// SweepOrphanMapPins is willing to remove it. It guards against removing
+// pins of maps which the agent has not yet gotten around to opening.
+const OrphanPinGracePeriod = 5 * time.Minute
+
+// OrphanMapPin describes a map pinned below the legacy map pin directory
+// which does not belong to any registered map, e.g. left behind by a crashed
+// agent or by a feature that has since been disabled.
+type OrphanMapPin struct {
+	// Path is the absolute bpffs path of the pin.
+	Path string
+	// Type is the type of the pinned map.
+	Type ebpf.MapType
+	// Age is the time since the pin was last modified.
+	Age time.Duration
+	// Removed is true if the pin was removed by the sweep.
+	Removed bool
+}
+
+var (
+	registerOrphanPinsGauge sync.Once
+	orphanPinsGauge         = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: metrics.Namespace + "_bpf_map_orphaned_pins",
+		Help: "Number of pinned BPF maps not belonging to any registered map, as of the last sweep",
+	})
+)
+
+// SweepOrphanMapPins scans the legacy map pin directory (<bpffs>/tc/globals)
+// for pinned maps which are not present in the map registry and reports
+// them. If remove is true, orphaned pins older than the grace period are
+// additionally unpinned. The number of orphans found is exported via the
+// cilium_bpf_map_orphaned_pins metric.
+func SweepOrphanMapPins(logger *slog.Logger, gracePeriod time.Duration, remove bool) ([]OrphanMapPin, error) {
+	registerOrphanPinsGauge.Do(func() {
+		if err := metrics.Register(orphanPinsGauge); err != nil {
+			logger.Warn("Failed to register the orphaned map pins metric", logfields.Error, err)
+		}
+	})
+
+	dir := TCGlobalsPath()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			orphanPinsGauge.Set(0)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading map pin directory %s: %w", dir, err)
+	}
+
+	var orphans []OrphanMapPin
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+
+		mutex.RLock()
+		_, registered := mapRegister[path]
+		mutex.RUnlock()
+		if registered {
+			continue
+		}
+
+		// Only consider map pins; program and link pins are out of scope.
+		em, err := ebpf.LoadPinnedMap(path, nil)
+		if err != nil {
+			logger.Debug("Skipping non-map pin",
+				logfields.Path, path,
+				logfields.Error, err,
+			)
+			continue
+		}
+		mapType := em.Type()
+		em.Close()
+
+		info, err := entry.Info()
+		if err != nil {
+			return orphans, fmt.Errorf("stat pin %s: %w", path, err)
+		}
+
+		orphan := OrphanMapPin{
+			Path: path,
+			Type: mapType,
+			Age:  time.Since(info.ModTime()),
+		}
+		if remove && orphan.Age >= gracePeriod {
+			if err := os.Remove(path); err != nil {
+				return orphans, fmt.Errorf("removing orphaned pin %s: %w", path, err)
+			}
+			logger.Info("Removed orphaned map pin", logfields.Path, path)
+			orphan.Removed = true
+		}
+		orphans = append(orphans, orphan)
+	}
+
+	orphanPinsGauge.Set(float64(len(orphans)))
+	return orphans, nil
+}
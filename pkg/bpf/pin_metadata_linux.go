@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+//go:build linux
+
+package bpf
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/cilium/ebpf"
+	"github.com/google/renameio/v2"
+
+	"github.com/cilium/cilium/pkg/defaults"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/time"
+	"github.com/cilium/cilium/pkg/version"
+)
+
+// pinMetadataRoot is the directory holding the per-map metadata blobs. Since
+// bpffs cannot hold regular files, the blobs live in the runtime state
+// directory, keyed by map name.
+var pinMetadataRoot = filepath.Join(defaults.RuntimePath, defaults.StateDir, "map-metadata")
+
+// PinMetadata records which agent created a pinned map. It is written
+// alongside map creation and retained across agent restarts, so that stale
+// pins left behind by a previous version can be attributed during upgrade
+// debugging.
+type PinMetadata struct {
+	// Version of the agent that created the map.
+	Version string `json:"version"`
+	// SpecHash is a digest of the map properties the map was created with.
+	SpecHash string `json:"spec-hash"`
+	// CreatedAt is the time the map was created.
+	CreatedAt time.Time `json:"created-at"`
+}
+
+// specHash digests the properties which determine pin compatibility. A spec
+// change which forces map recreation yields a different hash.
+func specHash(spec *ebpf.MapSpec) string {
+	sum := sha256.Sum256(fmt.Appendf(nil, "%s:%d:%d:%d:%d",
+		spec.Type, spec.KeySize, spec.ValueSize, spec.MaxEntries, spec.Flags))
+	return hex.EncodeToString(sum[:])
+}
+
+func pinMetadataPath(name string) string {
+	return filepath.Join(pinMetadataRoot, name+".json")
+}
+
+// recordPinMetadata writes the metadata blob for the given spec. Existing
+// metadata with a matching spec hash is left untouched: the map predates this
+// agent and the blob still names its creator. A mismatch means the map was
+// recreated, so the blob is replaced. Failures are logged but do not fail map
+// creation.
+func recordPinMetadata(logger *slog.Logger, spec *ebpf.MapSpec) {
+	hash := specHash(spec)
+	if old, err := ReadPinMetadata(spec.Name); err == nil && old.SpecHash == hash {
+		return
+	}
+
+	blob, err := json.Marshal(PinMetadata{
+		Version:   version.Version,
+		SpecHash:  hash,
+		CreatedAt: time.Now(),
+	})
+	if err == nil {
+		if err = os.MkdirAll(pinMetadataRoot, 0o755); err == nil {
+			err = renameio.WriteFile(pinMetadataPath(spec.Name), blob, 0o644)
+		}
+	}
+	if err != nil {
+		logger.Warn("Unable to record map pin metadata",
+			logfields.BPFMapName, spec.Name,
+			logfields.Error, err,
+		)
+	}
+}
+
+// ReadPinMetadata returns the recorded creation metadata for the map with the
+// given name, or an error satisfying os.IsNotExist if none was recorded.
+func ReadPinMetadata(name string) (*PinMetadata, error) {
+	blob, err := os.ReadFile(pinMetadataPath(name))
+	if err != nil {
+		return nil, err
+	}
+	meta := &PinMetadata{}
+	if err := json.Unmarshal(blob, meta); err != nil {
+		return nil, fmt.Errorf("corrupt metadata for map %s: %w", name, err)
+	}
+	return meta, nil
+}
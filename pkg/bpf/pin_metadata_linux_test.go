@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+//go:build linux
+
+package bpf
+
+import (
+	"os"
+	"testing"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/hive/hivetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cilium/cilium/pkg/version"
+)
+
+func TestPinMetadata(t *testing.T) {
+	oldRoot := pinMetadataRoot
+	pinMetadataRoot = t.TempDir()
+	t.Cleanup(func() { pinMetadataRoot = oldRoot })
+
+	logger := hivetest.Logger(t)
+	spec := &ebpf.MapSpec{
+		Name:       "cilium_test",
+		Type:       ebpf.Hash,
+		KeySize:    4,
+		ValueSize:  4,
+		MaxEntries: 16,
+	}
+
+	// No metadata recorded yet.
+	_, err := ReadPinMetadata(spec.Name)
+	assert.True(t, os.IsNotExist(err))
+
+	recordPinMetadata(logger, spec)
+	meta, err := ReadPinMetadata(spec.Name)
+	require.NoError(t, err)
+	assert.Equal(t, version.Version, meta.Version)
+	assert.Equal(t, specHash(spec), meta.SpecHash)
+	assert.False(t, meta.CreatedAt.IsZero())
+
+	// Re-recording with an unchanged spec keeps the original blob: the map
+	// was opened, not recreated.
+	recordPinMetadata(logger, spec)
+	again, err := ReadPinMetadata(spec.Name)
+	require.NoError(t, err)
+	assert.Equal(t, meta.CreatedAt, again.CreatedAt)
+
+	// A spec change forces recreation and replaces the blob.
+	spec.MaxEntries = 32
+	recordPinMetadata(logger, spec)
+	replaced, err := ReadPinMetadata(spec.Name)
+	require.NoError(t, err)
+	assert.NotEqual(t, meta.SpecHash, replaced.SpecHash)
+}
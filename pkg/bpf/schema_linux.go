@@ -0,0 +1,277 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+//go:build linux
+
+package bpf
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"unsafe"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/btf"
+	"golang.org/x/sys/unix"
+)
+
+// MapSchema describes the key and value types of a BPF map as reported by the
+// BTF information attached to it in the kernel. It allows entries of maps
+// opened without Go key/value types, e.g. maps pinned by a different Cilium
+// version or by external tooling, to be decoded into generic structured form
+// for debugging.
+type MapSchema struct {
+	Key   btf.Type
+	Value btf.Type
+}
+
+// bpfMapInfo mirrors struct bpf_map_info from the kernel UAPI. It is queried
+// directly as the ebpf library does not expose the BTF key/value type IDs.
+type bpfMapInfo struct {
+	mapType               uint32
+	id                    uint32
+	keySize               uint32
+	valueSize             uint32
+	maxEntries            uint32
+	mapFlags              uint32
+	name                  [16]byte
+	ifindex               uint32
+	btfVmlinuxValueTypeID uint32
+	netnsDev              uint64
+	netnsIno              uint64
+	btfID                 uint32
+	btfKeyTypeID          uint32
+	btfValueTypeID        uint32
+	btfVmlinuxID          uint32
+	mapExtra              uint64
+}
+
+// objGetInfoByFd is the BPF_OBJ_GET_INFO_BY_FD command of the bpf syscall.
+const objGetInfoByFd = 15
+
+func mapInfoByFd(fd int) (*bpfMapInfo, error) {
+	var info bpfMapInfo
+	attr := struct {
+		bpfFd   uint32
+		infoLen uint32
+		info    uint64
+	}{
+		bpfFd:   uint32(fd),
+		infoLen: uint32(unsafe.Sizeof(info)),
+		info:    uint64(uintptr(unsafe.Pointer(&info))),
+	}
+
+	_, _, errno := unix.Syscall(unix.SYS_BPF, objGetInfoByFd,
+		uintptr(unsafe.Pointer(&attr)), unsafe.Sizeof(attr))
+	if errno != 0 {
+		return nil, fmt.Errorf("BPF_OBJ_GET_INFO_BY_FD: %w", errno)
+	}
+	return &info, nil
+}
+
+// LoadMapSchema queries the kernel for the BTF key and value types of the
+// given map. It fails if the map was loaded without BTF information, or on
+// kernels predating BTF support for maps (4.18).
+func LoadMapSchema(m *ebpf.Map) (*MapSchema, error) {
+	info, err := mapInfoByFd(m.FD())
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve map info: %w", err)
+	}
+	if info.btfID == 0 {
+		return nil, fmt.Errorf("map %q carries no BTF information", unix.ByteSliceToString(info.name[:]))
+	}
+
+	handle, err := btf.NewHandleFromID(btf.ID(info.btfID))
+	if err != nil {
+		return nil, fmt.Errorf("unable to open BTF handle: %w", err)
+	}
+	defer handle.Close()
+
+	spec, err := handle.Spec(nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse BTF: %w", err)
+	}
+
+	key, err := spec.TypeByID(btf.TypeID(info.btfKeyTypeID))
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve key type: %w", err)
+	}
+	value, err := spec.TypeByID(btf.TypeID(info.btfValueTypeID))
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve value type: %w", err)
+	}
+
+	return &MapSchema{Key: key, Value: value}, nil
+}
+
+// DecodeKey decodes a raw map key according to the schema.
+func (s *MapSchema) DecodeKey(b []byte) (any, error) {
+	return decodeBTF(s.Key, b)
+}
+
+// DecodeValue decodes a raw map value according to the schema.
+func (s *MapSchema) DecodeValue(b []byte) (any, error) {
+	return decodeBTF(s.Value, b)
+}
+
+// DumpMapBTF iterates over all entries of the given map, decoding keys and
+// values via the BTF information attached to the map, and invokes the
+// callback for each entry. Structs are decoded into map[string]any, arrays
+// into []any (char arrays into strings), making the output suitable for
+// generic JSON serialization. Per-CPU maps are not supported.
+func DumpMapBTF(m *ebpf.Map, cb func(key, value any)) error {
+	schema, err := LoadMapSchema(m)
+	if err != nil {
+		return err
+	}
+
+	key := make([]byte, m.KeySize())
+	value := make([]byte, m.ValueSize())
+	iter := m.Iterate()
+	for iter.Next(&key, &value) {
+		k, err := schema.DecodeKey(key)
+		if err != nil {
+			return fmt.Errorf("unable to decode key: %w", err)
+		}
+		v, err := schema.DecodeValue(value)
+		if err != nil {
+			return fmt.Errorf("unable to decode value: %w", err)
+		}
+		cb(k, v)
+	}
+	return iter.Err()
+}
+
+// decodeBTF decodes the raw bytes in b according to the BTF type typ,
+// producing generic structured output. b must be at least as long as the
+// size of the type.
+func decodeBTF(typ btf.Type, b []byte) (any, error) {
+	size, err := btf.Sizeof(typ)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) < size {
+		return nil, fmt.Errorf("%d bytes of data for type %s of size %d", len(b), typ, size)
+	}
+	b = b[:size]
+
+	switch t := btf.UnderlyingType(typ).(type) {
+	case *btf.Int:
+		return decodeInt(t, b)
+
+	case *btf.Enum:
+		raw, err := decodeFixedInt(b[:t.Size], t.Signed)
+		if err != nil {
+			return nil, err
+		}
+		var numeric uint64
+		switch v := raw.(type) {
+		case int64:
+			numeric = uint64(v)
+		case uint64:
+			numeric = v
+		}
+		for _, ev := range t.Values {
+			if ev.Value == numeric {
+				return ev.Name, nil
+			}
+		}
+		return raw, nil
+
+	case *btf.Pointer:
+		return binary.NativeEndian.Uint64(b), nil
+
+	case *btf.Float:
+		switch t.Size {
+		case 4:
+			return math.Float32frombits(binary.NativeEndian.Uint32(b)), nil
+		case 8:
+			return math.Float64frombits(binary.NativeEndian.Uint64(b)), nil
+		}
+		return nil, fmt.Errorf("unsupported float size %d", t.Size)
+
+	case *btf.Array:
+		return decodeArray(t, b)
+
+	case *btf.Struct:
+		return decodeMembers(t.Members, b)
+
+	case *btf.Union:
+		return decodeMembers(t.Members, b)
+
+	default:
+		return nil, fmt.Errorf("unsupported BTF kind %T", t)
+	}
+}
+
+func decodeInt(t *btf.Int, b []byte) (any, error) {
+	switch t.Encoding {
+	case btf.Bool:
+		return b[0] != 0, nil
+	case btf.Char:
+		return b[0], nil
+	default:
+		return decodeFixedInt(b[:t.Size], t.Encoding == btf.Signed)
+	}
+}
+
+func decodeFixedInt(b []byte, signed bool) (any, error) {
+	var value uint64
+	switch len(b) {
+	case 1:
+		value = uint64(b[0])
+	case 2:
+		value = uint64(binary.NativeEndian.Uint16(b))
+	case 4:
+		value = uint64(binary.NativeEndian.Uint32(b))
+	case 8:
+		value = binary.NativeEndian.Uint64(b)
+	default:
+		return nil, fmt.Errorf("unsupported integer size %d", len(b))
+	}
+
+	if signed {
+		// Sign-extend from the original width.
+		shift := 64 - len(b)*8
+		return int64(value) << shift >> shift, nil
+	}
+	return value, nil
+}
+
+func decodeArray(t *btf.Array, b []byte) (any, error) {
+	elemSize, err := btf.Sizeof(t.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode char arrays into NUL-terminated strings for readability.
+	if i, ok := btf.UnderlyingType(t.Type).(*btf.Int); ok && i.Encoding == btf.Char {
+		return unix.ByteSliceToString(b), nil
+	}
+
+	elems := make([]any, 0, t.Nelems)
+	for i := 0; i < int(t.Nelems); i++ {
+		elem, err := decodeBTF(t.Type, b[i*elemSize:])
+		if err != nil {
+			return nil, err
+		}
+		elems = append(elems, elem)
+	}
+	return elems, nil
+}
+
+func decodeMembers(members []btf.Member, b []byte) (map[string]any, error) {
+	out := make(map[string]any, len(members))
+	for _, member := range members {
+		if member.BitfieldSize != 0 || member.Offset%8 != 0 {
+			return nil, fmt.Errorf("bitfield member %q is not supported", member.Name)
+		}
+		value, err := decodeBTF(member.Type, b[member.Offset.Bytes():])
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode member %q: %w", member.Name, err)
+		}
+		out[member.Name] = value
+	}
+	return out, nil
+}
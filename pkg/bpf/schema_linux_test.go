@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package bpf
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/cilium/ebpf/btf"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeBTF(t *testing.T) {
+	u8 := &btf.Int{Name: "__u8", Size: 1}
+	u16 := &btf.Int{Name: "__u16", Size: 2}
+	u32 := &btf.Int{Name: "__u32", Size: 4}
+	s32 := &btf.Int{Name: "int", Size: 4, Encoding: btf.Signed}
+	char := &btf.Int{Name: "char", Size: 1, Encoding: btf.Char}
+
+	le := binary.NativeEndian
+
+	t.Run("integers", func(t *testing.T) {
+		b := make([]byte, 4)
+		le.PutUint32(b, 0xfffffffe)
+
+		v, err := decodeBTF(u32, b)
+		require.NoError(t, err)
+		require.Equal(t, uint64(0xfffffffe), v)
+
+		v, err = decodeBTF(s32, b)
+		require.NoError(t, err)
+		require.Equal(t, int64(-2), v)
+
+		v, err = decodeBTF(&btf.Int{Size: 1, Encoding: btf.Bool}, []byte{1})
+		require.NoError(t, err)
+		require.Equal(t, true, v)
+	})
+
+	t.Run("enum", func(t *testing.T) {
+		enum := &btf.Enum{
+			Size: 4,
+			Values: []btf.EnumValue{
+				{Name: "POLICY_INGRESS", Value: 1},
+				{Name: "POLICY_EGRESS", Value: 2},
+			},
+		}
+
+		b := make([]byte, 4)
+		le.PutUint32(b, 2)
+		v, err := decodeBTF(enum, b)
+		require.NoError(t, err)
+		require.Equal(t, "POLICY_EGRESS", v)
+
+		le.PutUint32(b, 7)
+		v, err = decodeBTF(enum, b)
+		require.NoError(t, err)
+		require.Equal(t, uint64(7), v)
+	})
+
+	t.Run("char array", func(t *testing.T) {
+		arr := &btf.Array{Type: char, Nelems: 8}
+		v, err := decodeBTF(arr, []byte("foo\x00\x00\x00\x00\x00"))
+		require.NoError(t, err)
+		require.Equal(t, "foo", v)
+	})
+
+	t.Run("array", func(t *testing.T) {
+		arr := &btf.Array{Type: u16, Nelems: 2}
+		b := make([]byte, 4)
+		le.PutUint16(b, 80)
+		le.PutUint16(b[2:], 443)
+
+		v, err := decodeBTF(arr, b)
+		require.NoError(t, err)
+		require.Equal(t, []any{uint64(80), uint64(443)}, v)
+	})
+
+	t.Run("struct", func(t *testing.T) {
+		typ := &btf.Struct{
+			Name: "policy_key",
+			Size: 8,
+			Members: []btf.Member{
+				{Name: "sec_label", Type: u32, Offset: 0},
+				{Name: "dport", Type: u16, Offset: 32},
+				{Name: "protocol", Type: u8, Offset: 48},
+				{Name: "egress", Type: u8, Offset: 56},
+			},
+		}
+
+		b := make([]byte, 8)
+		le.PutUint32(b, 4242)
+		le.PutUint16(b[4:], 53)
+		b[6] = 17
+		b[7] = 1
+
+		v, err := decodeBTF(typ, b)
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{
+			"sec_label": uint64(4242),
+			"dport":     uint64(53),
+			"protocol":  uint64(17),
+			"egress":    uint64(1),
+		}, v)
+	})
+
+	t.Run("typedef", func(t *testing.T) {
+		td := &btf.Typedef{Name: "__le16", Type: u16}
+		b := make([]byte, 2)
+		le.PutUint16(b, 8080)
+
+		v, err := decodeBTF(td, b)
+		require.NoError(t, err)
+		require.Equal(t, uint64(8080), v)
+	})
+
+	t.Run("bitfield unsupported", func(t *testing.T) {
+		typ := &btf.Struct{
+			Size: 4,
+			Members: []btf.Member{
+				{Name: "flags", Type: u32, Offset: 0, BitfieldSize: 3},
+			},
+		}
+		_, err := decodeBTF(typ, make([]byte, 4))
+		require.Error(t, err)
+	})
+
+	t.Run("short buffer", func(t *testing.T) {
+		_, err := decodeBTF(u32, []byte{1, 2})
+		require.Error(t, err)
+	})
+}
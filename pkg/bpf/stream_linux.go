@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package bpf
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/rate"
+)
+
+// ErrStreamDumpUnsupported is returned by Map.StreamDump for maps without a
+// stream dumper registered via WithStreamDumper.
+var ErrStreamDumpUnsupported = errors.New("streaming dump is not supported for this map")
+
+// StreamDumperFunc is the signature of a typed StreamDumpJSON instantiation,
+// as recorded by WithStreamDumper.
+type StreamDumperFunc func(ctx context.Context, m *Map, w io.Writer, opts StreamDumpOptions) (int, error)
+
+// WithStreamDumper records the StreamDumpJSON instantiation matching the key
+// and value types of the given map, so that holders of the untyped *Map
+// (e.g., the map API handler) can stream its content through StreamDump. The
+// type parameters follow the same conventions as NewBatchIterator.
+func WithStreamDumper[KT any, VT any, KP KeyPointer[KT], VP ValuePointer[VT]](m *Map) *Map {
+	m.streamDumper = StreamDumpJSON[KT, VT, KP, VP]
+	return m
+}
+
+// SupportsStreamDump returns whether a stream dumper has been registered for
+// this map via WithStreamDumper.
+func (m *Map) SupportsStreamDump() bool {
+	return m.streamDumper != nil
+}
+
+// StreamDump invokes the stream dumper registered via WithStreamDumper, and
+// fails with ErrStreamDumpUnsupported if there is none.
+func (m *Map) StreamDump(ctx context.Context, w io.Writer, opts StreamDumpOptions) (int, error) {
+	if m.streamDumper == nil {
+		return 0, ErrStreamDumpUnsupported
+	}
+	return m.streamDumper(ctx, m, w, opts)
+}
+
+// StreamDumpOptions configures StreamDumpJSON.
+type StreamDumpOptions struct {
+	// PageSize is the number of entries written before the output is
+	// flushed and the rate limiter consulted. Defaults to
+	// defaultStreamDumpPageSize if unset.
+	PageSize int
+
+	// Limiter, if set, is waited on before dumping each page, bounding the
+	// rate at which entries are read from the kernel and written to the
+	// consumer. The limiter is not stopped by StreamDumpJSON.
+	Limiter *rate.Limiter
+}
+
+// defaultStreamDumpPageSize is the default number of entries per page
+// emitted by StreamDumpJSON.
+const defaultStreamDumpPageSize = 4096
+
+// StreamDumpJSON dumps the entries of map 'm' to the given writer as JSON
+// Lines (one models.BPFMapEntry object per line), using the batch lookup API
+// through BatchIterator. Entries are emitted in pages of PageSize entries,
+// flushing the output and waiting on the configured rate limiter between
+// pages, so that arbitrarily large maps can be served over a chunked API
+// response with bounded memory usage on both sides.
+//
+// It returns the number of entries written. The type parameters follow the
+// same conventions as NewBatchIterator.
+func StreamDumpJSON[KT any, VT any, KP KeyPointer[KT], VP ValuePointer[VT]](ctx context.Context, m *Map, w io.Writer, opts StreamDumpOptions) (count int, err error) {
+	if opts.PageSize <= 0 {
+		opts.PageSize = defaultStreamDumpPageSize
+	}
+
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+
+	flushPage := func() error {
+		if err := bw.Flush(); err != nil {
+			return fmt.Errorf("failed to flush dump page: %w", err)
+		}
+		if opts.Limiter != nil {
+			if err := opts.Limiter.Wait(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	iter := NewBatchIterator[KT, VT, KP, VP](m)
+	for k, v := range iter.IterateAll(ctx) {
+		if err := enc.Encode(&models.BPFMapEntry{
+			Key:   k.String(),
+			Value: v.String(),
+		}); err != nil {
+			return count, fmt.Errorf("failed to encode map entry: %w", err)
+		}
+		count++
+
+		if count%opts.PageSize == 0 {
+			if err := flushPage(); err != nil {
+				return count, err
+			}
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return count, err
+	}
+
+	if err := bw.Flush(); err != nil {
+		return count, fmt.Errorf("failed to flush dump page: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+//go:build linux
+
+package bpf
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/rlimit"
+	"github.com/cilium/hive/hivetest"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/rate"
+	"github.com/cilium/cilium/pkg/testutils"
+	"github.com/cilium/cilium/pkg/time"
+)
+
+func TestStreamDumpUnsupported(t *testing.T) {
+	m := NewMap("cilium_test_stream",
+		ebpf.Hash,
+		&TestKey{},
+		&TestValue{},
+		maxEntries,
+		unix.BPF_F_NO_PREALLOC,
+	)
+
+	require.False(t, m.SupportsStreamDump())
+	_, err := m.StreamDump(context.Background(), io.Discard, StreamDumpOptions{})
+	require.ErrorIs(t, err, ErrStreamDumpUnsupported)
+
+	m = WithStreamDumper[TestKey, TestValue](m)
+	require.True(t, m.SupportsStreamDump())
+}
+
+func TestPrivilegedStreamDumpJSON(t *testing.T) {
+	testutils.PrivilegedTest(t)
+
+	CheckOrMountFS(hivetest.Logger(t), "")
+	require.NoError(t, rlimit.RemoveMemlock())
+
+	const size = 10
+
+	m := WithStreamDumper[TestKey, TestValue](NewMap("cilium_test_stream",
+		ebpf.Hash,
+		&TestKey{},
+		&TestValue{},
+		maxEntries,
+		unix.BPF_F_NO_PREALLOC,
+	))
+	require.NoError(t, m.OpenOrCreate())
+	t.Cleanup(func() { require.NoError(t, m.UnpinIfExists()) })
+
+	for i := range size {
+		require.NoError(t, m.Update(&TestKey{Key: uint32(i)}, &TestValue{Value: uint32(i)}))
+	}
+
+	limiter := rate.NewLimiter(time.Millisecond, 1)
+	defer limiter.Stop()
+
+	// A page size smaller than the map exercises the intermediate flushes
+	// and the rate limiter waits.
+	var buf bytes.Buffer
+	count, err := m.StreamDump(context.Background(), &buf, StreamDumpOptions{
+		PageSize: 3,
+		Limiter:  limiter,
+	})
+	require.NoError(t, err)
+	require.Equal(t, size, count)
+
+	keys := make(map[string]string)
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var entry models.BPFMapEntry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		keys[entry.Key] = entry.Value
+	}
+	require.NoError(t, scanner.Err())
+
+	require.Len(t, keys, size)
+	for i := range size {
+		require.Equal(t, fmt.Sprintf("value=%d", i), keys[fmt.Sprintf("key=%d", i)])
+	}
+}
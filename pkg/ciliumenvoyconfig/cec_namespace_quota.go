@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package ciliumenvoyconfig
+
+import (
+	"fmt"
+)
+
+// namespaceQuota tracks the admission of CECs against the configured
+// per-namespace quota on the number of configs and the cumulative size of
+// their xDS resources. It protects Envoy instances shared between namespaces
+// from a single tenant consuming all capacity. A fresh tracker is used for
+// each processing round, with CECs admitted in name order so that enforcement
+// is deterministic.
+type namespaceQuota struct {
+	maxConfigs       int
+	maxResourceBytes int
+
+	configs       map[string]int
+	resourceBytes map[string]int
+}
+
+func newNamespaceQuota(config CECConfig) *namespaceQuota {
+	return &namespaceQuota{
+		maxConfigs:       config.EnvoyConfigNamespaceMaxConfigs,
+		maxResourceBytes: config.EnvoyConfigNamespaceMaxResourceBytes,
+		configs:          map[string]int{},
+		resourceBytes:    map[string]int{},
+	}
+}
+
+// admit accounts the given CEC against the quota of its namespace, returning
+// an error if admitting it would exceed the quota. Clusterwide configs are
+// not subject to the namespace quota.
+func (q *namespaceQuota) admit(cec *CEC) error {
+	ns := cec.Name.Namespace
+	if ns == "" {
+		return nil
+	}
+
+	if q.maxConfigs > 0 && q.configs[ns]+1 > q.maxConfigs {
+		return fmt.Errorf("namespace %s already has %d CiliumEnvoyConfigs, quota is %d",
+			ns, q.configs[ns], q.maxConfigs)
+	}
+	if q.maxResourceBytes > 0 && q.resourceBytes[ns]+cec.ResourceBytes > q.maxResourceBytes {
+		return fmt.Errorf("admitting %d bytes of xDS resources would exceed the namespace %s quota of %d bytes (%d bytes in use)",
+			cec.ResourceBytes, ns, q.maxResourceBytes, q.resourceBytes[ns])
+	}
+
+	q.configs[ns]++
+	q.resourceBytes[ns] += cec.ResourceBytes
+	return nil
+}
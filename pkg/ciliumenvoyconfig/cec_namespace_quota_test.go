@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package ciliumenvoyconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+)
+
+func quotaCEC(namespace, name string, resourceBytes int) *CEC {
+	return &CEC{
+		Name:          k8sTypes.NamespacedName{Namespace: namespace, Name: name},
+		ResourceBytes: resourceBytes,
+	}
+}
+
+func TestNamespaceQuota(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		quota := newNamespaceQuota(CECConfig{})
+		for i := range 100 {
+			require.NoError(t, quota.admit(quotaCEC("tenant-a", "cec", i*1000)))
+		}
+	})
+
+	t.Run("config count", func(t *testing.T) {
+		quota := newNamespaceQuota(CECConfig{EnvoyConfigNamespaceMaxConfigs: 2})
+
+		require.NoError(t, quota.admit(quotaCEC("tenant-a", "cec1", 0)))
+		require.NoError(t, quota.admit(quotaCEC("tenant-a", "cec2", 0)))
+		assert.ErrorContains(t, quota.admit(quotaCEC("tenant-a", "cec3", 0)), "quota is 2")
+
+		// Other namespaces have their own quota.
+		require.NoError(t, quota.admit(quotaCEC("tenant-b", "cec1", 0)))
+
+		// Clusterwide configs are exempt.
+		require.NoError(t, quota.admit(quotaCEC("", "ccec1", 0)))
+		require.NoError(t, quota.admit(quotaCEC("", "ccec2", 0)))
+		require.NoError(t, quota.admit(quotaCEC("", "ccec3", 0)))
+	})
+
+	t.Run("resource bytes", func(t *testing.T) {
+		quota := newNamespaceQuota(CECConfig{EnvoyConfigNamespaceMaxResourceBytes: 1000})
+
+		require.NoError(t, quota.admit(quotaCEC("tenant-a", "cec1", 600)))
+		assert.ErrorContains(t, quota.admit(quotaCEC("tenant-a", "cec2", 500)), "quota of 1000 bytes")
+
+		// A smaller config still fits within the remaining budget.
+		require.NoError(t, quota.admit(quotaCEC("tenant-a", "cec3", 400)))
+		require.NoError(t, quota.admit(quotaCEC("tenant-b", "cec1", 1000)))
+	})
+}
@@ -18,6 +18,7 @@ import (
 	envoy_config_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	envoy_config_route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
 	envoy_config_healthcheck "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/health_check/v3"
+	envoy_config_original_dst "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/listener/original_dst/v3"
 	envoy_config_http "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
 	envoy_config_tcp "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
 	envoy_config_tls "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
@@ -43,6 +44,7 @@ import (
 const (
 	ciliumBPFMetadataListenerFilterName = "cilium.bpf_metadata"
 	ciliumNetworkFilterName             = "cilium.network"
+	originalDstListenerFilterName       = "envoy.filters.listener.original_dst"
 	ciliumL7FilterName                  = "cilium.l7policy"
 	envoyRouterFilterName               = "envoy.filters.http.router"
 
@@ -63,6 +65,9 @@ type CECResourceParser struct {
 
 	defaultMaxConcurrentRetries uint32
 	httpLingerConfig            int
+
+	maxListeners    int
+	maxFilterChains int
 }
 
 type parserParams struct {
@@ -84,6 +89,8 @@ func newCECResourceParser(params parserParams) *CECResourceParser {
 		portAllocator:               params.PortAllocator,
 		defaultMaxConcurrentRetries: params.EnvoyConfig.ProxyMaxConcurrentRetries,
 		httpLingerConfig:            params.EnvoyConfig.EnvoyHTTPUpstreamLingerTimeout,
+		maxListeners:                params.CecConfig.EnvoyConfigMaxListeners,
+		maxFilterChains:             params.CecConfig.EnvoyConfigMaxFilterChains,
 	}
 
 	// Retrieve Ingress IPs from local Node.
@@ -132,7 +139,7 @@ type PortAllocator interface {
 //   - `useOriginalSourceAddr` is passed to the Envoy Cilium BPF Metadata listener filter on all Listeners.
 //   - `newResources` is passed as `true` when parsing resources that are being added or are the new version of the resources being updated,
 //     and as `false` if the resources are being removed or are the old version of the resources being updated. Only 'new' resources are validated.
-func (r *CECResourceParser) ParseResources(cecNamespace string, cecName string, xdsResources []cilium_v2.XDSResource, isL7LB bool, injectCiliumEnvoyFilters bool, useOriginalSourceAddr bool, newResources bool) (envoy.Resources, error) {
+func (r *CECResourceParser) ParseResources(cecNamespace string, cecName string, xdsResources []cilium_v2.XDSResource, isL7LB bool, injectCiliumEnvoyFilters bool, useOriginalSourceAddr bool, defaultSocketOptions bool, newResources bool) (envoy.Resources, error) {
 	// only validate new resources - old ones are already applied
 	validate := newResources
 
@@ -434,6 +441,14 @@ func (r *CECResourceParser) ParseResources(cecNamespace string, cecName string,
 		}
 	}
 
+	// Reject configs exceeding the configured Envoy capacity limits before
+	// any proxy ports are allocated for them.
+	if validate {
+		if err := r.checkListenerCapacity(cecNamespace, cecName, resources); err != nil {
+			return envoy.Resources{}, err
+		}
+	}
+
 	// Allocate TPROXY ports for listeners without address.
 	// Do this only after all other possible error cases.
 	for _, listener := range resources.Listeners {
@@ -441,7 +456,8 @@ func (r *CECResourceParser) ParseResources(cecNamespace string, cecName string,
 		isInternalListener := listener.GetInternalListener() != nil
 
 		if !isInternalListener {
-			if listener.GetAddress() == nil {
+			ciliumManagedAddress := listener.GetAddress() == nil
+			if ciliumManagedAddress {
 				listenerName := listener.Name
 				port, err := r.portAllocator.AllocateCRDProxyPort(listenerName)
 				if err != nil || port == 0 {
@@ -459,6 +475,34 @@ func (r *CECResourceParser) ParseResources(cecNamespace string, cecName string,
 				listener.Address, listener.AdditionalAddresses = envoy.GetLocalListenerAddresses(port, option.Config.IPv4Enabled(), option.Config.IPv6Enabled())
 			}
 
+			// Default Cilium-required socket settings for listeners whose
+			// address is allocated by Cilium: mark the listening socket as
+			// transparent so that traffic redirected to the proxy port is
+			// accepted for non-local destination addresses, and restore the
+			// original destination address for forwarding. Users no longer
+			// need to copy this boilerplate into every listener, but can
+			// still opt out via annotation or override the fields explicitly.
+			if defaultSocketOptions && ciliumManagedAddress {
+				if listener.Transparent == nil {
+					listener.Transparent = &wrapperspb.BoolValue{Value: true}
+				}
+				found := false
+				for _, lf := range listener.ListenerFilters {
+					if lf.Name == originalDstListenerFilterName {
+						found = true
+						break
+					}
+				}
+				if !found {
+					listener.ListenerFilters = append([]*envoy_config_listener.ListenerFilter{{
+						Name: originalDstListenerFilterName,
+						ConfigType: &envoy_config_listener.ListenerFilter_TypedConfig{
+							TypedConfig: toAny(&envoy_config_original_dst.OriginalDst{}),
+						},
+					}}, listener.ListenerFilters...)
+				}
+			}
+
 			// Inject Cilium bpf metadata listener filter, if not already present.
 			// This must be done after listener address/port is already set.
 			found := false
@@ -472,22 +516,7 @@ func (r *CECResourceParser) ParseResources(cecNamespace string, cecName string,
 				// Get the listener port from the listener's (main) address
 				port := uint16(listener.GetAddress().GetSocketAddress().GetPortValue())
 				// Only use zero linger for HTTP listener
-				isHTTPListener := func() bool {
-					for _, fc := range listener.FilterChains {
-						for _, filter := range fc.Filters {
-							tc := filter.GetTypedConfig()
-							if tc == nil {
-								continue
-							}
-							if tc.GetTypeUrl() == envoy.HttpConnectionManagerTypeURL {
-								return true
-							}
-						}
-					}
-					return false
-				}()
-
-				listener.ListenerFilters = append(listener.ListenerFilters, r.getBPFMetadataListenerFilter(useOriginalSourceAddr, isL7LB, port, isHTTPListener))
+				listener.ListenerFilters = append(listener.ListenerFilters, r.getBPFMetadataListenerFilter(useOriginalSourceAddr, isL7LB, port, isHTTPListener(listener)))
 			}
 		}
 
@@ -613,6 +642,116 @@ func (r *CECResourceParser) getBPFMetadataListenerFilter(useOriginalSourceAddr b
 	}
 }
 
+// isHTTPListener returns true if any filter chain of the listener contains an
+// HTTP connection manager.
+func isHTTPListener(listener *envoy_config_listener.Listener) bool {
+	for _, fc := range listener.FilterChains {
+		for _, filter := range fc.Filters {
+			tc := filter.GetTypedConfig()
+			if tc == nil {
+				continue
+			}
+			if tc.GetTypeUrl() == envoy.HttpConnectionManagerTypeURL {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// validateServiceProtocols checks the protocol hints of the service listeners
+// against the parsed xDS resources: a service hinted as http, http2 or grpc
+// must be redirected to a listener with an HTTP connection manager filter
+// chain. This catches HTTP/2 and gRPC services misconfigured through generic
+// TCP listeners before any traffic is redirected.
+func validateServiceProtocols(spec *cilium_v2.CiliumEnvoyConfigSpec, cecNamespace, cecName string, resources envoy.Resources) error {
+	for _, svcl := range spec.Services {
+		if !svcl.ExpectsHTTP() {
+			continue
+		}
+
+		var listener *envoy_config_listener.Listener
+		if svcl.Listener != "" {
+			// Listener names are qualified after parsing, so qualify the
+			// listener reference as well for it to match.
+			name, _ := api.ResourceQualifiedName(cecNamespace, cecName, svcl.Listener, api.ForceNamespace)
+			for _, l := range resources.Listeners {
+				if l.Name == name {
+					listener = l
+					break
+				}
+			}
+		} else if len(resources.Listeners) > 0 {
+			listener = resources.Listeners[0]
+		}
+
+		if listener == nil {
+			return fmt.Errorf("service %s/%s has protocol %q but no matching listener",
+				svcl.Namespace, svcl.Name, svcl.Protocol)
+		}
+		if !isHTTPListener(listener) {
+			return fmt.Errorf("service %s/%s has protocol %q but listener %q has no HTTP connection manager",
+				svcl.Namespace, svcl.Name, svcl.Protocol, listener.Name)
+		}
+	}
+	return nil
+}
+
+// listenerCapacityWarnFraction is the fraction of a configured capacity limit
+// at which a warning is emitted for configs that are still accepted.
+const listenerCapacityWarnFraction = 0.75
+
+// countFilterChains returns the number of filter chains the given listener
+// instantiates in Envoy.
+func countFilterChains(listener *envoy_config_listener.Listener) int {
+	count := len(listener.GetFilterChains())
+	if listener.GetDefaultFilterChain() != nil {
+		count++
+	}
+	return count
+}
+
+// checkListenerCapacity estimates the number of Envoy listeners and filter
+// chains the parsed resources will create and compares them against the
+// configured per-node limits. Configs exceeding a limit are rejected, configs
+// approaching one are accepted with a warning, protecting the node against
+// configs that would exhaust Envoy memory. Limits set to zero are not
+// enforced.
+func (r *CECResourceParser) checkListenerCapacity(cecNamespace, cecName string, resources envoy.Resources) error {
+	filterChains := 0
+	for _, listener := range resources.Listeners {
+		filterChains += countFilterChains(listener)
+	}
+
+	for _, check := range []struct {
+		resource string
+		count    int
+		limit    int
+	}{
+		{"listeners", len(resources.Listeners), r.maxListeners},
+		{"filter chains", filterChains, r.maxFilterChains},
+	} {
+		if check.limit <= 0 {
+			continue
+		}
+		if check.count > check.limit {
+			return fmt.Errorf("CEC %s/%s would create %d Envoy %s, exceeding the limit of %d",
+				cecNamespace, cecName, check.count, check.resource, check.limit)
+		}
+		if float64(check.count) >= float64(check.limit)*listenerCapacityWarnFraction {
+			r.logger.Warn("CiliumEnvoyConfig is approaching the Envoy capacity limit",
+				logfields.K8sNamespace, cecNamespace,
+				logfields.Name, cecName,
+				logfields.Resource, check.resource,
+				logfields.Count, check.count,
+				logfields.Limit, check.limit,
+			)
+		}
+	}
+
+	return nil
+}
+
 // qualifyAddress finds if there is a ServerListenerName in the address and qualifies it
 func qualifyAddress(namespace, name string, address *envoy_config_core.Address) {
 	internalAddress := address.GetEnvoyInternalAddress()
@@ -1005,3 +1144,18 @@ func InjectCiliumEnvoyFilters(meta *metav1.ObjectMeta, spec *cilium_v2.CiliumEnv
 
 	return len(spec.Services) > 0
 }
+
+// DefaultListenerSocketOptions returns true if the Cilium-required listener
+// socket settings should be defaulted into Listeners whose address is
+// allocated by Cilium. Defaults to true, users can opt out via annotation.
+func DefaultListenerSocketOptions(meta *metav1.ObjectMeta) bool {
+	if meta.GetAnnotations() != nil {
+		if v, ok := meta.GetAnnotations()[annotation.CECDefaultListenerSocketOptions]; ok {
+			if boolValue, err := strconv.ParseBool(v); err == nil {
+				return boolValue
+			}
+		}
+	}
+
+	return true
+}
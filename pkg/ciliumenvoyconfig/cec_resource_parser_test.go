@@ -300,7 +300,7 @@ func TestCiliumEnvoyConfig(t *testing.T) {
 	assert.Equal(t, "type.googleapis.com/envoy.config.listener.v3.Listener", cec.Spec.Resources[0].TypeUrl)
 	assert.Equal(t, "type.googleapis.com/envoy.extensions.transport_sockets.tls.v3.Secret", cec.Spec.Resources[1].TypeUrl)
 
-	resources, err := parser.ParseResources("namespace", "name", cec.Spec.Resources, false, false, false, true)
+	resources, err := parser.ParseResources("namespace", "name", cec.Spec.Resources, false, false, false, false, true)
 	require.NoError(t, err)
 	assert.Len(t, resources.Listeners, 1)
 	assert.Equal(t, "namespace/name/envoy-prometheus-metrics-listener", resources.Listeners[0].Name)
@@ -403,7 +403,7 @@ func TestCiliumEnvoyConfigValidation(t *testing.T) {
 	assert.Len(t, cec.Spec.Resources, 1)
 	assert.Equal(t, "type.googleapis.com/envoy.config.listener.v3.Listener", cec.Spec.Resources[0].TypeUrl)
 
-	resources, err := parser.ParseResources("namespace", "name", cec.Spec.Resources, false, false, false, false)
+	resources, err := parser.ParseResources("namespace", "name", cec.Spec.Resources, false, false, false, false, false)
 	require.NoError(t, err)
 	assert.Len(t, resources.Listeners, 1)
 	assert.Equal(t, "namespace/name/envoy-prometheus-metrics-listener", resources.Listeners[0].Name)
@@ -436,10 +436,93 @@ func TestCiliumEnvoyConfigValidation(t *testing.T) {
 	//
 	// Same with validation fails
 	//
-	resources, err = parser.ParseResources("namespace", "name", cec.Spec.Resources, false, false, false, true)
+	resources, err = parser.ParseResources("namespace", "name", cec.Spec.Resources, false, false, false, false, true)
 	assert.Error(t, err)
 }
 
+var ciliumEnvoyConfigTwoListeners = `apiVersion: cilium.io/v2
+kind: CiliumEnvoyConfig
+metadata:
+  name: capacity-listeners
+spec:
+  version_info: "0"
+  resources:
+  - "@type": type.googleapis.com/envoy.config.listener.v3.Listener
+    name: listener-1
+    address:
+      socket_address:
+        address: 127.0.0.1
+        port_value: 10001
+    filter_chains:
+    - filters:
+      - name: envoy.filters.network.http_connection_manager
+        typed_config:
+          "@type": type.googleapis.com/envoy.extensions.filters.network.http_connection_manager.v3.HttpConnectionManager
+          stat_prefix: listener_1
+          rds:
+            route_config_name: local_route
+          http_filters:
+          - name: envoy.filters.http.router
+  - "@type": type.googleapis.com/envoy.config.listener.v3.Listener
+    name: listener-2
+    address:
+      socket_address:
+        address: 127.0.0.1
+        port_value: 10002
+    filter_chains:
+    - filters:
+      - name: envoy.filters.network.http_connection_manager
+        typed_config:
+          "@type": type.googleapis.com/envoy.extensions.filters.network.http_connection_manager.v3.HttpConnectionManager
+          stat_prefix: listener_2
+          rds:
+            route_config_name: local_route
+          http_filters:
+          - name: envoy.filters.http.router
+`
+
+func TestCiliumEnvoyConfigListenerCapacity(t *testing.T) {
+	jsonBytes, err := yaml.YAMLToJSON([]byte(ciliumEnvoyConfigTwoListeners))
+	require.NoError(t, err)
+	cec := &cilium_v2.CiliumEnvoyConfig{}
+	err = json.Unmarshal(jsonBytes, cec)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name            string
+		maxListeners    int
+		maxFilterChains int
+		wantErr         bool
+	}{
+		{name: "unlimited"},
+		{name: "within limits", maxListeners: 2, maxFilterChains: 2},
+		{name: "too many listeners", maxListeners: 1, wantErr: true},
+		{name: "too many filter chains", maxFilterChains: 1, wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			parser := CECResourceParser{
+				logger:          hivetest.Logger(t),
+				portAllocator:   NewMockPortAllocator(),
+				maxListeners:    tc.maxListeners,
+				maxFilterChains: tc.maxFilterChains,
+			}
+
+			resources, err := parser.ParseResources("namespace", "name", cec.Spec.Resources, false, false, false, false, true)
+			if tc.wantErr {
+				require.ErrorContains(t, err, "exceeding the limit")
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, resources.Listeners, 2)
+
+			// Limits are only enforced on new resources.
+			parser.maxListeners = 1
+			_, err = parser.ParseResources("namespace", "name", cec.Spec.Resources, false, false, false, false, false)
+			require.NoError(t, err)
+		})
+	}
+}
+
 var ciliumEnvoyConfigNoAddress = `apiVersion: cilium.io/v2
 kind: CiliumEnvoyConfig
 metadata:
@@ -487,7 +570,7 @@ func TestCiliumEnvoyConfigNoAddress(t *testing.T) {
 	assert.Len(t, cec.Spec.Resources, 1)
 	assert.Equal(t, "type.googleapis.com/envoy.config.listener.v3.Listener", cec.Spec.Resources[0].TypeUrl)
 
-	resources, err := parser.ParseResources("namespace", "name", cec.Spec.Resources, false, false, false, true)
+	resources, err := parser.ParseResources("namespace", "name", cec.Spec.Resources, false, false, false, false, true)
 	require.NoError(t, err)
 	assert.Len(t, resources.Listeners, 1)
 	assert.Equal(t, "namespace/name/envoy-prometheus-metrics-listener", resources.Listeners[0].Name)
@@ -610,7 +693,7 @@ func TestCiliumEnvoyConfigMulti(t *testing.T) {
 	assert.Len(t, cec.Spec.Resources, 5)
 	assert.Equal(t, "type.googleapis.com/envoy.config.listener.v3.Listener", cec.Spec.Resources[0].TypeUrl)
 
-	resources, err := parser.ParseResources("namespace", "name", cec.Spec.Resources, false, false, false, true)
+	resources, err := parser.ParseResources("namespace", "name", cec.Spec.Resources, false, false, false, false, true)
 	require.NoError(t, err)
 	assert.Len(t, resources.Listeners, 1)
 	assert.Equal(t, "namespace/name/multi-resource-listener", resources.Listeners[0].Name)
@@ -802,7 +885,7 @@ func TestCiliumEnvoyConfigInternalListener(t *testing.T) {
 	assert.Equal(t, "type.googleapis.com/envoy.config.endpoint.v3.ClusterLoadAssignment", cec.Spec.Resources[0].TypeUrl)
 	assert.Equal(t, "type.googleapis.com/envoy.config.listener.v3.Listener", cec.Spec.Resources[1].TypeUrl)
 
-	resources, err := parser.ParseResources("namespace", "name", cec.Spec.Resources, false, false, false, true)
+	resources, err := parser.ParseResources("namespace", "name", cec.Spec.Resources, false, false, false, false, true)
 	require.NoError(t, err)
 
 	//
@@ -856,7 +939,7 @@ func TestCiliumEnvoyConfigMissingInternalListener(t *testing.T) {
 	assert.Len(t, cec.Spec.Resources, 1)
 	assert.Equal(t, "type.googleapis.com/envoy.config.endpoint.v3.ClusterLoadAssignment", cec.Spec.Resources[0].TypeUrl)
 
-	_, err = parser.ParseResources("namespace", "name", cec.Spec.Resources, false, false, false, true)
+	_, err = parser.ParseResources("namespace", "name", cec.Spec.Resources, false, false, false, false, true)
 	assert.ErrorContains(t, err, "missing internal listener: internal-listener")
 }
 
@@ -876,7 +959,7 @@ func TestCiliumEnvoyConfigTCPProxy(t *testing.T) {
 	assert.Len(t, cec.Spec.Resources, 2)
 	assert.Equal(t, "type.googleapis.com/envoy.config.listener.v3.Listener", cec.Spec.Resources[0].TypeUrl)
 
-	resources, err := parser.ParseResources("namespace", "name", cec.Spec.Resources, false, false, true, true)
+	resources, err := parser.ParseResources("namespace", "name", cec.Spec.Resources, false, false, true, false, true)
 	require.NoError(t, err)
 	assert.Len(t, resources.Listeners, 1)
 	assert.NotNil(t, resources.Listeners[0].Address)
@@ -1020,7 +1103,7 @@ func TestCiliumEnvoyConfigTCPProxyTermination(t *testing.T) {
 	assert.Len(t, cec.Spec.Resources, 2)
 	assert.Equal(t, "type.googleapis.com/envoy.config.listener.v3.Listener", cec.Spec.Resources[0].TypeUrl)
 
-	resources, err := parser.ParseResources("namespace", "name", cec.Spec.Resources, true, true, false, true)
+	resources, err := parser.ParseResources("namespace", "name", cec.Spec.Resources, true, true, false, false, true)
 	require.NoError(t, err)
 	assert.Len(t, resources.Listeners, 1)
 	assert.NotNil(t, resources.Listeners[0].Address)
@@ -1154,7 +1237,7 @@ func TestCiliumEnvoyConfigtHTTPHealthCheckFilter(t *testing.T) {
 	err = json.Unmarshal(jsonBytes, cec)
 	require.NoError(t, err)
 
-	resources, err := parser.ParseResources(cec.Namespace, cec.Name, cec.Spec.Resources, false, false, false, false)
+	resources, err := parser.ParseResources(cec.Namespace, cec.Name, cec.Spec.Resources, false, false, false, false, false)
 	require.NoError(t, err)
 	assert.Len(t, resources.Listeners, 1)
 	chain := resources.Listeners[0].FilterChains[0]
@@ -1284,7 +1367,7 @@ func TestCiliumEnvoyConfigCombinedValidationContext(t *testing.T) {
 	assert.Len(t, cec.Spec.Resources, 1)
 	assert.Equal(t, "type.googleapis.com/envoy.config.listener.v3.Listener", cec.Spec.Resources[0].TypeUrl)
 
-	resources, err := parser.ParseResources("namespace", "name", cec.Spec.Resources, false, false, false, true)
+	resources, err := parser.ParseResources("namespace", "name", cec.Spec.Resources, false, false, false, false, true)
 	require.NoError(t, err)
 
 	require.Len(t, resources.Listeners, 1)
@@ -1370,7 +1453,7 @@ func TestCiliumEnvoyConfigTlsSessionTicketKeys(t *testing.T) {
 	assert.Len(t, cec.Spec.Resources, 1)
 	assert.Equal(t, "type.googleapis.com/envoy.config.listener.v3.Listener", cec.Spec.Resources[0].TypeUrl)
 
-	resources, err := parser.ParseResources("namespace", "name", cec.Spec.Resources, false, false, false, true)
+	resources, err := parser.ParseResources("namespace", "name", cec.Spec.Resources, false, false, false, false, true)
 	require.NoError(t, err)
 
 	require.Len(t, resources.Listeners, 1)
@@ -1445,7 +1528,7 @@ func TestCiliumEnvoyConfigInjectCiliumFilters(t *testing.T) {
 	assert.Len(t, cec.Spec.Resources, 1)
 	assert.Equal(t, "type.googleapis.com/envoy.config.listener.v3.Listener", cec.Spec.Resources[0].TypeUrl)
 
-	resources, err := parser.ParseResources("namespace", "name", cec.Spec.Resources, true, false, false, true)
+	resources, err := parser.ParseResources("namespace", "name", cec.Spec.Resources, true, false, false, false, true)
 	require.NoError(t, err)
 
 	require.Len(t, resources.Listeners, 1)
@@ -1522,7 +1605,7 @@ func TestParseEnvoySpec(t *testing.T) {
 	assert.Equal(t, "type.googleapis.com/envoy.config.listener.v3.Listener", cec.Spec.Resources[0].TypeUrl)
 	assert.True(t, UseOriginalSourceAddress(&cec.ObjectMeta))
 
-	resources, err := parser.ParseResources("", "name", cec.Spec.Resources, len(cec.Spec.Services) > 0, InjectCiliumEnvoyFilters(&cec.ObjectMeta, &cec.Spec), UseOriginalSourceAddress(&cec.ObjectMeta), true)
+	resources, err := parser.ParseResources("", "name", cec.Spec.Resources, len(cec.Spec.Services) > 0, InjectCiliumEnvoyFilters(&cec.ObjectMeta, &cec.Spec), UseOriginalSourceAddress(&cec.ObjectMeta), false, true)
 	assert.NoError(t, err)
 	assert.Len(t, resources.Listeners, 1)
 	assert.Equal(t, uint32(10000), resources.Listeners[0].Address.GetSocketAddress().GetPortValue())
@@ -1600,7 +1683,7 @@ func TestParseEnvoySpecWithService(t *testing.T) {
 	assert.Equal(t, "type.googleapis.com/envoy.config.listener.v3.Listener", cec.Spec.Resources[0].TypeUrl)
 	assert.True(t, UseOriginalSourceAddress(&cec.ObjectMeta))
 
-	resources, err := parser.ParseResources("", "name", cec.Spec.Resources, len(cec.Spec.Services) > 0, InjectCiliumEnvoyFilters(&cec.ObjectMeta, &cec.Spec), UseOriginalSourceAddress(&cec.ObjectMeta), true)
+	resources, err := parser.ParseResources("", "name", cec.Spec.Resources, len(cec.Spec.Services) > 0, InjectCiliumEnvoyFilters(&cec.ObjectMeta, &cec.Spec), UseOriginalSourceAddress(&cec.ObjectMeta), false, true)
 	assert.NoError(t, err)
 	assert.Len(t, resources.Listeners, 1)
 	assert.Equal(t, uint32(1025), resources.Listeners[0].Address.GetSocketAddress().GetPortValue())
@@ -1786,3 +1869,128 @@ func Test_injectCiliumEnvoyFilters(t *testing.T) {
 		})
 	}
 }
+
+var defaultSocketOptionsCEC = `apiVersion: cilium.io/v2
+kind: CiliumEnvoyConfig
+metadata:
+  name: default-socket-options
+spec:
+  resources:
+  - "@type": type.googleapis.com/envoy.config.listener.v3.Listener
+    name: proxy-listener
+    filter_chains:
+    - filters:
+      - name: envoy.filters.network.tcp_proxy
+        typed_config:
+          "@type": type.googleapis.com/envoy.extensions.filters.network.tcp_proxy.v3.TcpProxy
+          stat_prefix: tcp_proxy
+          cluster: some-cluster
+`
+
+func TestDefaultListenerSocketOptions(t *testing.T) {
+	parser := CECResourceParser{
+		logger:        hivetest.Logger(t),
+		portAllocator: NewMockPortAllocator(),
+	}
+
+	parseCEC := func() *cilium_v2.CiliumEnvoyConfig {
+		jsonBytes, err := yaml.YAMLToJSON([]byte(defaultSocketOptionsCEC))
+		require.NoError(t, err)
+		cec := &cilium_v2.CiliumEnvoyConfig{}
+		require.NoError(t, json.Unmarshal(jsonBytes, cec))
+		return cec
+	}
+
+	// Cilium allocates the listener address, so the transparent socket mode
+	// and the original destination listener filter are defaulted in.
+	cec := parseCEC()
+	resources, err := parser.ParseResources("namespace", "name", cec.Spec.Resources, false, false, false, true, true)
+	require.NoError(t, err)
+	require.Len(t, resources.Listeners, 1)
+	listener := resources.Listeners[0]
+	require.NotNil(t, listener.Transparent)
+	assert.True(t, listener.Transparent.Value)
+	require.Len(t, listener.ListenerFilters, 2)
+	assert.Equal(t, originalDstListenerFilterName, listener.ListenerFilters[0].Name)
+	assert.Equal(t, ciliumBPFMetadataListenerFilterName, listener.ListenerFilters[1].Name)
+
+	// Opted out via annotation, no socket options are defaulted.
+	cec = parseCEC()
+	resources, err = parser.ParseResources("namespace", "name", cec.Spec.Resources, false, false, false, false, true)
+	require.NoError(t, err)
+	require.Len(t, resources.Listeners, 1)
+	listener = resources.Listeners[0]
+	assert.Nil(t, listener.Transparent)
+	require.Len(t, listener.ListenerFilters, 1)
+	assert.Equal(t, ciliumBPFMetadataListenerFilterName, listener.ListenerFilters[0].Name)
+}
+
+func TestDefaultListenerSocketOptionsAnnotation(t *testing.T) {
+	meta := &metav1.ObjectMeta{}
+	assert.True(t, DefaultListenerSocketOptions(meta))
+
+	meta.Annotations = map[string]string{annotation.CECDefaultListenerSocketOptions: "false"}
+	assert.False(t, DefaultListenerSocketOptions(meta))
+
+	meta.Annotations[annotation.CECDefaultListenerSocketOptions] = "true"
+	assert.True(t, DefaultListenerSocketOptions(meta))
+}
+
+func TestValidateServiceProtocols(t *testing.T) {
+	httpListener := &envoy_config_listener.Listener{
+		Name: "ns/cec/http-listener",
+		FilterChains: []*envoy_config_listener.FilterChain{{
+			Filters: []*envoy_config_listener.Filter{{
+				Name: "envoy.filters.network.http_connection_manager",
+				ConfigType: &envoy_config_listener.Filter_TypedConfig{
+					TypedConfig: toAny(&envoy_config_http.HttpConnectionManager{}),
+				},
+			}},
+		}},
+	}
+	tcpListener := &envoy_config_listener.Listener{
+		Name: "ns/cec/tcp-listener",
+		FilterChains: []*envoy_config_listener.FilterChain{{
+			Filters: []*envoy_config_listener.Filter{{
+				Name: "envoy.filters.network.tcp_proxy",
+				ConfigType: &envoy_config_listener.Filter_TypedConfig{
+					TypedConfig: toAny(&envoy_config_tcp.TcpProxy{}),
+				},
+			}},
+		}},
+	}
+	resources := envoy.Resources{
+		Listeners: []*envoy_config_listener.Listener{httpListener, tcpListener},
+	}
+
+	svc := func(protocol, listener string) *cilium_v2.CiliumEnvoyConfigSpec {
+		return &cilium_v2.CiliumEnvoyConfigSpec{
+			Services: []*cilium_v2.ServiceListener{{
+				Name:      "svc",
+				Namespace: "ns",
+				Listener:  listener,
+				Protocol:  protocol,
+			}},
+		}
+	}
+
+	// No hint: nothing is enforced.
+	assert.NoError(t, validateServiceProtocols(svc("", "tcp-listener"), "ns", "cec", resources))
+
+	// TCP hint is compatible with any listener.
+	assert.NoError(t, validateServiceProtocols(svc(cilium_v2.ServiceProtocolTCP, "tcp-listener"), "ns", "cec", resources))
+
+	// HTTP variants require an HTTP connection manager.
+	for _, protocol := range []string{cilium_v2.ServiceProtocolHTTP, cilium_v2.ServiceProtocolHTTP2, cilium_v2.ServiceProtocolGRPC} {
+		assert.NoError(t, validateServiceProtocols(svc(protocol, "http-listener"), "ns", "cec", resources))
+		assert.ErrorContains(t, validateServiceProtocols(svc(protocol, "tcp-listener"), "ns", "cec", resources),
+			"no HTTP connection manager")
+	}
+
+	// Without an explicit listener reference the first listener is used.
+	assert.NoError(t, validateServiceProtocols(svc(cilium_v2.ServiceProtocolGRPC, ""), "ns", "cec", resources))
+
+	// Dangling listener references are rejected.
+	assert.ErrorContains(t, validateServiceProtocols(svc(cilium_v2.ServiceProtocolGRPC, "missing"), "ns", "cec", resources),
+		"no matching listener")
+}
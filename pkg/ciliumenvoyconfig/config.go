@@ -10,11 +10,21 @@ import (
 )
 
 type CECConfig struct {
-	EnvoyConfigRetryInterval time.Duration
-	EnvoyConfigTimeout       time.Duration
+	EnvoyConfigRetryInterval             time.Duration
+	EnvoyConfigTimeout                   time.Duration
+	EnvoyConfigCleanupOrphans            bool
+	EnvoyConfigMaxListeners              int
+	EnvoyConfigMaxFilterChains           int
+	EnvoyConfigNamespaceMaxConfigs       int
+	EnvoyConfigNamespaceMaxResourceBytes int
 }
 
 func (r CECConfig) Flags(flags *pflag.FlagSet) {
 	flags.Duration("envoy-config-retry-interval", 15*time.Second, "Interval in which an attempt is made to reconcile failed EnvoyConfigs. If the duration is zero, the retry is deactivated.")
 	flags.Duration("envoy-config-timeout", 2*time.Minute, "Timeout that determines how long to wait for Envoy to N/ACK CiliumEnvoyConfig resources")
+	flags.Bool("envoy-config-cleanup-orphans", false, "Remove Envoy resources that are no longer backed by a CiliumEnvoyConfig instead of only reporting them")
+	flags.Int("envoy-config-max-listeners", 0, "Maximum number of Envoy listeners a single CiliumEnvoyConfig may create on this node, configs exceeding it are rejected. If zero, no limit is enforced.")
+	flags.Int("envoy-config-max-filter-chains", 0, "Maximum number of Envoy filter chains a single CiliumEnvoyConfig may create on this node, configs exceeding it are rejected. If zero, no limit is enforced.")
+	flags.Int("envoy-config-namespace-max-configs", 0, "Maximum number of CiliumEnvoyConfigs admitted per namespace on this node, configs exceeding it are rejected. If zero, no limit is enforced.")
+	flags.Int("envoy-config-namespace-max-resource-bytes", 0, "Maximum cumulative size in bytes of the xDS resources of all CiliumEnvoyConfigs admitted per namespace on this node, configs exceeding it are rejected. If zero, no limit is enforced.")
 }
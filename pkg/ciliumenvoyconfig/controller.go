@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"iter"
+	"log/slog"
 	"maps"
 	"slices"
 	"strconv"
@@ -27,6 +28,7 @@ import (
 	ciliumv2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
 	"github.com/cilium/cilium/pkg/loadbalancer"
 	"github.com/cilium/cilium/pkg/loadbalancer/writer"
+	"github.com/cilium/cilium/pkg/logging/logfields"
 	"github.com/cilium/cilium/pkg/option"
 	"github.com/cilium/cilium/pkg/policy/api"
 	"github.com/cilium/cilium/pkg/time"
@@ -35,10 +37,12 @@ import (
 type cecControllerParams struct {
 	cell.In
 
+	Log            *slog.Logger
 	DB             *statedb.DB
 	JobGroup       job.Group
 	ExpConfig      loadbalancer.Config
 	DaemonConfig   *option.DaemonConfig
+	Config         CECConfig
 	Metrics        Metrics
 	CECs           statedb.Table[*CEC]
 	EnvoyResources statedb.RWTable[*EnvoyResource]
@@ -106,12 +110,15 @@ func (c *cecController) processLoop(ctx context.Context, health cell.Health) err
 		writer:         c.Writer,
 	}
 	cecProcessor := cecProcessor{
+		log:            c.Log,
 		watchSets:      map[types.NamespacedName]*statedb.WatchSet{},
 		orphans:        map[types.NamespacedName]sets.Empty{},
 		cecs:           c.CECs,
 		writer:         c.Writer,
 		envoyResources: c.EnvoyResources,
 		featureMetrics: c.FeatureMetrics,
+		config:         c.Config,
+		quotaRejected:  sets.New[CECName](),
 	}
 
 	for {
@@ -167,23 +174,50 @@ func (c *cecController) processLoop(ctx context.Context, health cell.Health) err
 // The [backendProcessor] will fill in the Endpoints into the Origin=backendsync resources
 // afterwards.
 type cecProcessor struct {
+	log            *slog.Logger
 	watchSets      map[CECName]*statedb.WatchSet
 	orphans        sets.Set[CECName]
 	cecs           statedb.Table[*CEC]
 	writer         *writer.Writer
 	envoyResources statedb.RWTable[*EnvoyResource]
 	featureMetrics FeatureMetrics
+	config         CECConfig
+
+	// quotaRejected holds the CECs currently rejected due to their namespace
+	// exceeding the quota, to log the rejection only on transition.
+	quotaRejected sets.Set[CECName]
 }
 
 func (c *cecProcessor) process(wtxn statedb.WriteTxn, closedWatches []<-chan struct{}, allWatches *statedb.WatchSet) {
 	cecs, watch := c.cecs.AllWatch(wtxn)
 	allWatches.Add(watch)
 	existing := sets.New[CECName]()
+	quota := newNamespaceQuota(c.config)
 	for cec := range cecs {
 		if !cec.SelectsLocalNode {
 			continue
 		}
 
+		// Enforce the per-namespace quota. Rejected CECs are left out of
+		// [existing] so that any envoy resources previously created for them
+		// are removed as orphans below.
+		if err := quota.admit(cec); err != nil {
+			if !c.quotaRejected.Has(cec.Name) {
+				c.log.Warn("Rejecting CiliumEnvoyConfig: namespace quota exceeded",
+					logfields.K8sNamespace, cec.Name.Namespace,
+					logfields.Name, cec.Name.Name,
+					logfields.Error, err)
+				c.quotaRejected.Insert(cec.Name)
+			}
+			continue
+		}
+		if c.quotaRejected.Has(cec.Name) {
+			c.log.Info("CiliumEnvoyConfig admitted again, namespace within quota",
+				logfields.K8sNamespace, cec.Name.Namespace,
+				logfields.Name, cec.Name.Name)
+			c.quotaRejected.Delete(cec.Name)
+		}
+
 		existing.Insert(cec.Name)
 		c.orphans.Delete(cec.Name)
 
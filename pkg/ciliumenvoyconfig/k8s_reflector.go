@@ -12,6 +12,7 @@ import (
 	"github.com/cilium/hive/job"
 	"github.com/cilium/statedb"
 	"github.com/cilium/statedb/part"
+	"google.golang.org/protobuf/proto"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sTypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -28,6 +29,7 @@ import (
 	"github.com/cilium/cilium/pkg/logging/logfields"
 	"github.com/cilium/cilium/pkg/option"
 	"github.com/cilium/cilium/pkg/promise"
+	"github.com/cilium/cilium/pkg/time"
 )
 
 // Types for the ListerWatchers of the CEC resources. Abstracted so that tests can
@@ -117,10 +119,11 @@ func registerCECK8sReflector(
 		resources, err := p.ParseResources(
 			objMeta.GetNamespace(),
 			objMeta.GetName(),
-			spec.Resources,
+			spec.XDSResources(),
 			len(spec.Services) > 0,
 			InjectCiliumEnvoyFilters(objMeta, spec),
 			UseOriginalSourceAddress(objMeta),
+			DefaultListenerSocketOptions(objMeta),
 			true,
 		)
 		if err != nil {
@@ -131,6 +134,19 @@ func registerCECK8sReflector(
 			return nil, false
 		}
 
+		if spec.Drain.IsGraceful() {
+			resources.ListenerDrainWait = true
+			resources.ListenerDrainTimeout = time.Duration(spec.Drain.DrainTimeoutSeconds) * time.Second
+		}
+
+		if err := validateServiceProtocols(spec, objMeta.GetNamespace(), objMeta.GetName(), resources); err != nil {
+			log.Warn("Skipping CiliumEnvoyConfig due to service protocol mismatch",
+				logfields.K8sNamespace, objMeta.GetNamespace(),
+				logfields.Name, objMeta.GetName(),
+				logfields.Error, err)
+			return nil, false
+		}
+
 		var listeners part.Map[string, uint16]
 		for _, l := range resources.Listeners {
 			var proxyPort uint16
@@ -164,6 +180,11 @@ func registerCECK8sReflector(
 			}
 		}
 
+		resourceBytes := 0
+		for _, r := range spec.XDSResources() {
+			resourceBytes += proto.Size(r.Any)
+		}
+
 		cec := &CEC{
 			Name: k8sTypes.NamespacedName{
 				Name:      objMeta.GetName(),
@@ -176,6 +197,7 @@ func registerCECK8sReflector(
 			Spec:             spec,
 			Resources:        resources,
 			Listeners:        listeners,
+			ResourceBytes:    resourceBytes,
 		}
 		return cec, true
 	}
@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package ciliumenvoyconfig
+
+import (
+	"context"
+
+	"github.com/cilium/hive/job"
+	"github.com/cilium/statedb"
+
+	"github.com/cilium/cilium/pkg/envoy"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/time"
+)
+
+// orphanCheckInterval is how often the installed xDS resources are checked
+// against the desired EnvoyResource table.
+const orphanCheckInterval = 5 * time.Minute
+
+// installedResources records the xDS resources that were last pushed to Envoy
+// on behalf of an EnvoyResource, along with the table revision ("generation")
+// they were computed from.
+type installedResources struct {
+	generation statedb.Revision
+	resources  envoy.Resources
+	updatedAt  time.Time
+}
+
+// orphanTracker remembers which xDS resources have been installed into Envoy
+// for which EnvoyResource generation. Entries are added on successful
+// reconciliation and removed on successful deletion, so anything left without
+// a backing table object - e.g. after a failed deletion - is an orphan still
+// occupying listeners and clusters in Envoy.
+type orphanTracker struct {
+	mu        lock.Mutex
+	installed map[EnvoyResourceName]installedResources
+}
+
+func newOrphanTracker() *orphanTracker {
+	return &orphanTracker{
+		installed: map[EnvoyResourceName]installedResources{},
+	}
+}
+
+func (t *orphanTracker) recordUpdate(name EnvoyResourceName, generation statedb.Revision, resources envoy.Resources) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.installed[name] = installedResources{
+		generation: generation,
+		resources:  resources,
+		updatedAt:  time.Now(),
+	}
+}
+
+func (t *orphanTracker) recordDelete(name EnvoyResourceName) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.installed, name)
+}
+
+// OrphanedResources describes xDS resources that are still installed in Envoy
+// but no longer backed by a desired EnvoyResource.
+type OrphanedResources struct {
+	// Owner is the EnvoyResource the resources were installed for.
+	Owner EnvoyResourceName
+	// Generation is the table revision the resources were computed from.
+	Generation statedb.Revision
+	// InstalledAt is the time the resources were last pushed to Envoy.
+	InstalledAt time.Time
+	// Listeners and Clusters name the orphaned resources.
+	Listeners []string
+	Clusters  []string
+
+	resources envoy.Resources
+}
+
+// report returns the installed resources whose owner no longer exists in the
+// EnvoyResource table.
+func (t *orphanTracker) report(txn statedb.ReadTxn, tbl statedb.Table[*EnvoyResource]) []OrphanedResources {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var orphans []OrphanedResources
+	for name, installed := range t.installed {
+		if _, _, found := tbl.Get(txn, EnvoyResourceByName(name)); found {
+			continue
+		}
+		orphan := OrphanedResources{
+			Owner:       name,
+			Generation:  installed.generation,
+			InstalledAt: installed.updatedAt,
+			resources:   installed.resources,
+		}
+		for _, l := range installed.resources.Listeners {
+			orphan.Listeners = append(orphan.Listeners, l.Name)
+		}
+		for _, c := range installed.resources.Clusters {
+			orphan.Clusters = append(orphan.Clusters, c.Name)
+		}
+		orphans = append(orphans, orphan)
+	}
+	return orphans
+}
+
+// cleanup deletes the orphaned resources from Envoy and drops them from the
+// tracker on success.
+func (t *orphanTracker) cleanup(ctx context.Context, xds resourceMutator, orphan OrphanedResources) error {
+	if err := xds.DeleteEnvoyResources(ctx, orphan.resources); err != nil {
+		return err
+	}
+	t.recordDelete(orphan.Owner)
+	return nil
+}
+
+// runOrphanCheck periodically reports - and, if enabled, cleans up - xDS
+// resources left behind in Envoy without a backing EnvoyResource. By default
+// the check is a dry run which only logs what would be removed.
+func (ops *envoyOps) runOrphanCheck(db *statedb.DB, tbl statedb.Table[*EnvoyResource]) job.TimerFunc {
+	return func(ctx context.Context) error {
+		for _, orphan := range ops.orphans.report(db.ReadTxn(), tbl) {
+			log := ops.log.With(
+				logfields.Resource, orphan.Owner,
+				logfields.Generation, orphan.Generation,
+				logfields.ResourceListeners, orphan.Listeners,
+				logfields.ResourceClusters, orphan.Clusters,
+			)
+			if !ops.config.EnvoyConfigCleanupOrphans {
+				log.Info("Found orphaned Envoy resources (dry run, not removing)")
+				continue
+			}
+			if err := ops.orphans.cleanup(ctx, ops.xds, orphan); err != nil {
+				log.Warn("Failed to remove orphaned Envoy resources",
+					logfields.Error, err)
+				continue
+			}
+			log.Info("Removed orphaned Envoy resources")
+		}
+		return nil
+	}
+}
+
+func registerOrphanCheck(
+	g job.Group,
+	db *statedb.DB,
+	ops *envoyOps,
+	envoyResources statedb.RWTable[*EnvoyResource],
+) {
+	g.Add(job.Timer("orphan-check",
+		ops.runOrphanCheck(db, statedb.Table[*EnvoyResource](envoyResources)),
+		orphanCheckInterval))
+}
@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package ciliumenvoyconfig
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cilium/statedb"
+	envoy_config_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	envoy_config_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cilium/cilium/pkg/envoy"
+)
+
+type fakeResourceMutator struct {
+	deleted []envoy.Resources
+	err     error
+}
+
+func (f *fakeResourceMutator) DeleteEnvoyResources(_ context.Context, res envoy.Resources) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.deleted = append(f.deleted, res)
+	return nil
+}
+
+func (f *fakeResourceMutator) UpdateEnvoyResources(_ context.Context, _, _ envoy.Resources) error {
+	return nil
+}
+
+func TestOrphanTracker(t *testing.T) {
+	db := statedb.New()
+	tbl, err := NewEnvoyResourcesTable(db)
+	require.NoError(t, err)
+
+	backed := EnvoyResourceName{Origin: EnvoyResourceOriginCEC, Namespace: "ns", Name: "live"}
+	orphaned := EnvoyResourceName{Origin: EnvoyResourceOriginCEC, Namespace: "ns", Name: "gone"}
+
+	wtxn := db.WriteTxn(tbl)
+	_, _, err = tbl.Insert(wtxn, &EnvoyResource{Name: backed})
+	require.NoError(t, err)
+	wtxn.Commit()
+
+	tracker := newOrphanTracker()
+	tracker.recordUpdate(backed, 1, envoy.Resources{})
+	tracker.recordUpdate(orphaned, 2, envoy.Resources{
+		Listeners: []*envoy_config_listener.Listener{{Name: "ns/gone/listener"}},
+		Clusters:  []*envoy_config_cluster.Cluster{{Name: "ns/gone/cluster"}},
+	})
+
+	// Only the entry without a backing table object is reported.
+	orphans := tracker.report(db.ReadTxn(), tbl)
+	require.Len(t, orphans, 1)
+	assert.Equal(t, orphaned, orphans[0].Owner)
+	assert.Equal(t, statedb.Revision(2), orphans[0].Generation)
+	assert.Equal(t, []string{"ns/gone/listener"}, orphans[0].Listeners)
+	assert.Equal(t, []string{"ns/gone/cluster"}, orphans[0].Clusters)
+	assert.False(t, orphans[0].InstalledAt.IsZero())
+
+	// Cleanup removes the resources from Envoy and from the tracker.
+	xds := &fakeResourceMutator{}
+	require.NoError(t, tracker.cleanup(context.Background(), xds, orphans[0]))
+	require.Len(t, xds.deleted, 1)
+	assert.Empty(t, tracker.report(db.ReadTxn(), tbl))
+
+	// Deleting the backing object turns the remaining entry into an orphan,
+	// until its successful deletion is recorded.
+	wtxn = db.WriteTxn(tbl)
+	_, _, err = tbl.Delete(wtxn, &EnvoyResource{Name: backed})
+	require.NoError(t, err)
+	wtxn.Commit()
+
+	orphans = tracker.report(db.ReadTxn(), tbl)
+	require.Len(t, orphans, 1)
+	assert.Equal(t, backed, orphans[0].Owner)
+
+	tracker.recordDelete(backed)
+	assert.Empty(t, tracker.report(db.ReadTxn(), tbl))
+}
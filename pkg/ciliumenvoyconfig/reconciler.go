@@ -8,6 +8,7 @@ import (
 	"iter"
 	"log/slog"
 
+	"github.com/cilium/hive/job"
 	"github.com/cilium/statedb"
 	"github.com/cilium/statedb/reconciler"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -25,6 +26,7 @@ type envoyOps struct {
 	policyTrigger policyTrigger
 	writer        *writer.Writer
 	portAllocator PortAllocator
+	orphans       *orphanTracker
 }
 
 // Delete implements reconciler.Operations.
@@ -69,6 +71,9 @@ func (ops *envoyOps) Delete(ctx context.Context, _ statedb.ReadTxn, _ statedb.Re
 	if len(releasedListeners) > 0 {
 		ops.policyTrigger.TriggerPolicyUpdates()
 	}
+	if err == nil {
+		ops.orphans.recordDelete(res.Name)
+	}
 	return err
 }
 
@@ -78,7 +83,7 @@ func (ops *envoyOps) Prune(ctx context.Context, txn statedb.ReadTxn, objects ite
 }
 
 // Update implements reconciler.Operations.
-func (ops *envoyOps) Update(ctx context.Context, txn statedb.ReadTxn, _ statedb.Revision, res *EnvoyResource) error {
+func (ops *envoyOps) Update(ctx context.Context, txn statedb.ReadTxn, rev statedb.Revision, res *EnvoyResource) error {
 	resources := res.Resources
 
 	ctx, cancel := context.WithTimeout(ctx, ops.config.EnvoyConfigTimeout)
@@ -97,6 +102,7 @@ func (ops *envoyOps) Update(ctx context.Context, txn statedb.ReadTxn, _ statedb.
 
 		res.ReconciledResources = &resources
 		res.ReconciledResources.PortAllocationCallbacks = nil
+		ops.orphans.recordUpdate(res.Name, rev, resources)
 
 		// With the envoy resources successfully pushed to Envoy, set the proxy redirections
 		// for the associated services.
@@ -137,6 +143,8 @@ func registerEnvoyReconciler(
 	xds resourceMutator,
 	pt policyTrigger,
 	params reconciler.Params,
+	g job.Group,
+	db *statedb.DB,
 	writer *writer.Writer,
 	envoyResources statedb.RWTable[*EnvoyResource],
 	portAllocator PortAllocator,
@@ -148,7 +156,9 @@ func registerEnvoyReconciler(
 		writer:        writer,
 		policyTrigger: pt,
 		portAllocator: portAllocator,
+		orphans:       newOrphanTracker(),
 	}
+	registerOrphanCheck(g, db, ops, envoyResources)
 	_, err := reconciler.Register(
 		params,
 		envoyResources,
@@ -40,6 +40,10 @@ type CEC struct {
 
 	// Resources is the parsed envoy.Resources with the endpoints filled in.
 	Resources envoy.Resources
+
+	// ResourceBytes is the cumulative size of the xDS resources in the spec,
+	// counted against the per-namespace quota.
+	ResourceBytes int
 }
 
 func (cec *CEC) Clone() *CEC {
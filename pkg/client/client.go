@@ -295,6 +295,8 @@ type StatusDetails struct {
 	KubeProxyReplacementDetails bool
 	// ClockSourceDetails causes BPF time-keeping internals to be printed by FormatStatusResponse.
 	ClockSourceDetails bool
+	// KVStoreDetails causes kvstore details to be printed by FormatStatusResponse.
+	KVStoreDetails bool
 }
 
 var (
@@ -311,6 +313,7 @@ var (
 		BPFMapDetails:               true,
 		KubeProxyReplacementDetails: true,
 		ClockSourceDetails:          true,
+		KVStoreDetails:              true,
 	}
 )
 
@@ -320,6 +323,27 @@ var (
 func FormatStatusResponse(w io.Writer, sr *models.StatusResponse, sd StatusDetails) {
 	if sr.Kvstore != nil {
 		fmt.Fprintf(w, "KVStore:\t%s\t%s\n", sr.Kvstore.State, sr.Kvstore.Msg)
+		if sd.KVStoreDetails && sr.KvstoreDetail != nil {
+			detail := sr.KvstoreDetail
+			quorum := "true"
+			if !detail.HasQuorum {
+				quorum = fmt.Sprintf("false (%s, consecutive errors: %d)",
+					detail.QuorumError, detail.ConsecutiveQuorumErrors)
+			}
+			fmt.Fprintf(w, "  Quorum:\t%s\n", quorum)
+			fmt.Fprintf(w, "  Leases:\t%d (lock leases: %d)\n", detail.Leases, detail.LockLeases)
+			fmt.Fprintf(w, "  Last heartbeat:\t%.1fs ago\n", detail.LastHeartbeatAgeSeconds)
+			for _, ep := range detail.Endpoints {
+				status := ep.Version
+				if ep.Leader {
+					status += " (Leader)"
+				}
+				if ep.Error != "" {
+					status = "error: " + ep.Error
+				}
+				fmt.Fprintf(w, "  Endpoint %s:\t%s\n", ep.Endpoint, status)
+			}
+		}
 	}
 	if sr.ContainerRuntime != nil {
 		fmt.Fprintf(w, "ContainerRuntime:\t%s\t%s\n",
@@ -0,0 +1,194 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package clustercfg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	cmtypes "github.com/cilium/cilium/pkg/clustermesh/types"
+	"github.com/cilium/cilium/pkg/kvstore"
+)
+
+var (
+	// ErrClusterNameMismatch is returned by guarded write operations when
+	// the key embeds a cluster name different from the configured one.
+	ErrClusterNameMismatch = errors.New("key embeds a cluster name different from the configured one")
+
+	// ErrClusterConfigMismatch is returned by guarded write operations when
+	// the cluster configuration stored in the connected kvstore does not
+	// match the local cluster identity.
+	ErrClusterConfigMismatch = errors.New("cluster configuration in the kvstore does not match the local cluster")
+)
+
+// GuardWrites wraps the given client with a cluster identity guard: write
+// operations targeting a key which embeds a cluster name (i.e., falling
+// under one of the given cluster-scoped prefixes) fail with
+// ErrClusterNameMismatch unless the embedded name matches the configured
+// one. Additionally, once the cluster configuration is present in the
+// kvstore, its cluster ID is compared against the local one, failing writes
+// with ErrClusterConfigMismatch in case of disagreement.
+//
+// This guards all write paths of misconfigured agents against overwriting
+// the keys of another cluster, complementing the connection-level cluster ID
+// validation, which only protects connections established with the
+// corresponding interceptors.
+func GuardWrites(client kvstore.Client, clusterInfo cmtypes.ClusterInfo, clusterScopedPrefixes ...string) kvstore.Client {
+	return &guardedClient{
+		Client:      client,
+		clusterInfo: clusterInfo,
+		prefixes:    clusterScopedPrefixes,
+	}
+}
+
+type guardedClient struct {
+	kvstore.Client
+
+	// clusterInfo is the identity of the local cluster.
+	clusterInfo cmtypes.ClusterInfo
+
+	// prefixes is the set of key prefixes whose next path segment is
+	// expected to be the name of the local cluster.
+	prefixes []string
+
+	// validated is set once the cluster configuration stored in the kvstore
+	// has been successfully compared against the local cluster identity.
+	validated atomic.Bool
+}
+
+// checkWrite returns an error if the key embeds an unexpected cluster name,
+// or if the cluster configuration stored in the kvstore belongs to a
+// different cluster.
+func (gc *guardedClient) checkWrite(ctx context.Context, key string) error {
+	for _, prefix := range gc.prefixes {
+		rest, found := strings.CutPrefix(key, prefix+"/")
+		if !found {
+			continue
+		}
+
+		if name, _, _ := strings.Cut(rest, "/"); name != gc.clusterInfo.Name {
+			return fmt.Errorf("%w: key %q does not belong to cluster %q",
+				ErrClusterNameMismatch, key, gc.clusterInfo.Name)
+		}
+	}
+
+	if !gc.validated.Load() {
+		cfg, err := Get(ctx, gc.clusterInfo.Name, gc.Client)
+		switch {
+		case err != nil:
+			// The cluster configuration has not been written yet, or could
+			// not be retrieved. There is nothing to validate against; we
+			// will retry on the next write.
+			return nil
+		case cfg.ID != gc.clusterInfo.ID:
+			return fmt.Errorf("%w: expected cluster ID %d, found %d",
+				ErrClusterConfigMismatch, gc.clusterInfo.ID, cfg.ID)
+		}
+
+		gc.validated.Store(true)
+	}
+
+	return nil
+}
+
+func (gc *guardedClient) LockPath(ctx context.Context, path string) (kvstore.KVLocker, error) {
+	if err := gc.checkWrite(ctx, path); err != nil {
+		return nil, err
+	}
+	return gc.Client.LockPath(ctx, path)
+}
+
+func (gc *guardedClient) Delete(ctx context.Context, key string) error {
+	if err := gc.checkWrite(ctx, key); err != nil {
+		return err
+	}
+	return gc.Client.Delete(ctx, key)
+}
+
+func (gc *guardedClient) DeleteIfLocked(ctx context.Context, key string, lock kvstore.KVLocker) error {
+	if err := gc.checkWrite(ctx, key); err != nil {
+		return err
+	}
+	return gc.Client.DeleteIfLocked(ctx, key, lock)
+}
+
+func (gc *guardedClient) DeletePrefix(ctx context.Context, path string) error {
+	if err := gc.checkWrite(ctx, path); err != nil {
+		return err
+	}
+	return gc.Client.DeletePrefix(ctx, path)
+}
+
+func (gc *guardedClient) DeletePrefixIfLocked(ctx context.Context, path string, lock kvstore.KVLocker) error {
+	if err := gc.checkWrite(ctx, path); err != nil {
+		return err
+	}
+	return gc.Client.DeletePrefixIfLocked(ctx, path, lock)
+}
+
+func (gc *guardedClient) Update(ctx context.Context, key string, value []byte, lease bool) error {
+	if err := gc.checkWrite(ctx, key); err != nil {
+		return err
+	}
+	return gc.Client.Update(ctx, key, value, lease)
+}
+
+func (gc *guardedClient) UpdateIfLocked(ctx context.Context, key string, value []byte, lease bool, lock kvstore.KVLocker) error {
+	if err := gc.checkWrite(ctx, key); err != nil {
+		return err
+	}
+	return gc.Client.UpdateIfLocked(ctx, key, value, lease, lock)
+}
+
+func (gc *guardedClient) UpdateIfDifferent(ctx context.Context, key string, value []byte, lease bool) (bool, error) {
+	if err := gc.checkWrite(ctx, key); err != nil {
+		return false, err
+	}
+	return gc.Client.UpdateIfDifferent(ctx, key, value, lease)
+}
+
+func (gc *guardedClient) UpdateIfDifferentIfLocked(ctx context.Context, key string, value []byte, lease bool, lock kvstore.KVLocker) (bool, error) {
+	if err := gc.checkWrite(ctx, key); err != nil {
+		return false, err
+	}
+	return gc.Client.UpdateIfDifferentIfLocked(ctx, key, value, lease, lock)
+}
+
+func (gc *guardedClient) UpdateBatch(ctx context.Context, pairs []kvstore.KeyValue, lease bool) error {
+	for _, pair := range pairs {
+		if err := gc.checkWrite(ctx, pair.Key); err != nil {
+			return err
+		}
+	}
+	return gc.Client.UpdateBatch(ctx, pairs, lease)
+}
+
+func (gc *guardedClient) CreateOnly(ctx context.Context, key string, value []byte, lease bool) (bool, error) {
+	if err := gc.checkWrite(ctx, key); err != nil {
+		return false, err
+	}
+	return gc.Client.CreateOnly(ctx, key, value, lease)
+}
+
+func (gc *guardedClient) CreateOnlyIfLocked(ctx context.Context, key string, value []byte, lease bool, lock kvstore.KVLocker) (bool, error) {
+	if err := gc.checkWrite(ctx, key); err != nil {
+		return false, err
+	}
+	return gc.Client.CreateOnlyIfLocked(ctx, key, value, lease, lock)
+}
+
+func (gc *guardedClient) Txn(ctx context.Context, conditions []kvstore.TxnCondition, thenOps, elseOps []kvstore.TxnOp) (bool, error) {
+	// Both branches are checked upfront, as which one applies is only known
+	// once the transaction has been committed.
+	for _, op := range slices.Concat(thenOps, elseOps) {
+		if err := gc.checkWrite(ctx, op.Key); err != nil {
+			return false, err
+		}
+	}
+	return gc.Client.Txn(ctx, conditions, thenOps, elseOps)
+}
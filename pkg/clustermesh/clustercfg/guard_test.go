@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package clustercfg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cilium/statedb"
+	"github.com/stretchr/testify/require"
+
+	cmtypes "github.com/cilium/cilium/pkg/clustermesh/types"
+	"github.com/cilium/cilium/pkg/kvstore"
+)
+
+func TestGuardWrites(t *testing.T) {
+	ctx := context.Background()
+	client := kvstore.NewInMemoryClient(statedb.New(), "__local__")
+
+	guarded := GuardWrites(client, cmtypes.ClusterInfo{ID: 3, Name: "foo"},
+		"cilium/state/nodes/v1")
+
+	// Keys embedding the local cluster name, or no cluster name at all, are
+	// writable.
+	require.NoError(t, guarded.Update(ctx, "cilium/state/nodes/v1/foo/node1", []byte("value"), false))
+	require.NoError(t, guarded.Update(ctx, "cilium/state/identities/v1/id/1234", []byte("value"), false))
+
+	// Keys embedding a different cluster name are rejected.
+	err := guarded.Update(ctx, "cilium/state/nodes/v1/bar/node1", []byte("value"), false)
+	require.ErrorIs(t, err, ErrClusterNameMismatch)
+	_, err = guarded.CreateOnly(ctx, "cilium/state/nodes/v1/bar/node1", []byte("value"), false)
+	require.ErrorIs(t, err, ErrClusterNameMismatch)
+	err = guarded.DeletePrefixIfLocked(ctx, "cilium/state/nodes/v1/bar", nil)
+	require.ErrorIs(t, err, ErrClusterNameMismatch)
+	err = guarded.UpdateBatch(ctx, []kvstore.KeyValue{
+		{Key: "cilium/state/nodes/v1/foo/node1", Value: []byte("value")},
+		{Key: "cilium/state/nodes/v1/bar/node1", Value: []byte("value")},
+	}, false)
+	require.ErrorIs(t, err, ErrClusterNameMismatch)
+	_, err = guarded.Txn(ctx, nil,
+		[]kvstore.TxnOp{{Typ: kvstore.TxnOpPut, Key: "cilium/state/nodes/v1/bar/node1", Value: []byte("value")}}, nil)
+	require.ErrorIs(t, err, ErrClusterNameMismatch)
+
+	// A matching cluster configuration does not interfere with writes.
+	require.NoError(t, Set(ctx, "foo", cmtypes.CiliumClusterConfig{ID: 3}, client))
+	require.NoError(t, guarded.Update(ctx, "cilium/state/nodes/v1/foo/node2", []byte("value"), false))
+
+	// A cluster configuration belonging to a different cluster fails writes.
+	mismatching := GuardWrites(client, cmtypes.ClusterInfo{ID: 5, Name: "foo"},
+		"cilium/state/nodes/v1")
+	err = mismatching.Update(ctx, "cilium/state/nodes/v1/foo/node3", []byte("value"), false)
+	require.ErrorIs(t, err, ErrClusterConfigMismatch)
+}
@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package container
+
+import (
+	"sync/atomic"
+)
+
+// SeqRing is a fixed-capacity ring buffer in which every element is tagged
+// with a monotonically increasing sequence number. It supports a single
+// writer and any number of concurrent readers without locking: readers
+// detect slots overwritten while they were copying via the sequence numbers
+// and retry, so snapshots never block the writer.
+type SeqRing[T any] struct {
+	slots []atomic.Pointer[seqSlot[T]]
+	// seq is the sequence number of the next element to be appended, i.e.
+	// the total number of appends so far.
+	seq atomic.Uint64
+}
+
+type seqSlot[T any] struct {
+	seq   uint64
+	value T
+}
+
+// NewSeqRing constructs a new sequence ring holding up to size elements.
+func NewSeqRing[T any](size int) *SeqRing[T] {
+	return &SeqRing[T]{
+		slots: make([]atomic.Pointer[seqSlot[T]], size),
+	}
+}
+
+// Append adds an element to the ring, overwriting the oldest element once
+// the ring is full. Only a single writer may call Append at a time; readers
+// need no synchronization.
+func (r *SeqRing[T]) Append(value T) {
+	if len(r.slots) == 0 {
+		return
+	}
+	seq := r.seq.Load()
+	r.slots[seq%uint64(len(r.slots))].Store(&seqSlot[T]{seq: seq, value: value})
+	r.seq.Store(seq + 1)
+}
+
+// Snapshot returns a copy of the buffered elements from oldest to newest,
+// along with the sequence number of the next element to be appended. Passing
+// the returned sequence number to SnapshotSince yields only elements appended
+// after this snapshot was taken.
+func (r *SeqRing[T]) Snapshot() ([]T, uint64) {
+	return r.SnapshotSince(0)
+}
+
+// SnapshotSince returns a copy of the buffered elements with sequence number
+// of at least since, from oldest to newest, along with the sequence number of
+// the next element to be appended. Elements overwritten while the snapshot is
+// being taken are accounted for by restarting from the oldest still-buffered
+// element, so the result is always a consistent, gap-free suffix of the
+// append history.
+func (r *SeqRing[T]) SnapshotSince(since uint64) ([]T, uint64) {
+	size := uint64(len(r.slots))
+	if size == 0 {
+		return nil, 0
+	}
+
+	var values []T
+retry:
+	for {
+		end := r.seq.Load()
+		start := since
+		if end > size && start < end-size {
+			start = end - size
+		}
+
+		values = values[:0]
+		for i := start; i < end; i++ {
+			slot := r.slots[i%size].Load()
+			if slot == nil || slot.seq != i {
+				// The slot was overwritten by the writer lapping us while
+				// we were copying. Restart from the new oldest element.
+				continue retry
+			}
+			values = append(values, slot.value)
+		}
+		return values, end
+	}
+}
+
+// Size returns the number of elements currently buffered.
+func (r *SeqRing[T]) Size() int {
+	return int(min(r.seq.Load(), uint64(len(r.slots))))
+}
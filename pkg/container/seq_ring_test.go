@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package container
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeqRing(t *testing.T) {
+	r := NewSeqRing[int](4)
+
+	values, next := r.Snapshot()
+	assert.Empty(t, values)
+	assert.Equal(t, uint64(0), next)
+
+	for i := range 3 {
+		r.Append(i)
+	}
+	values, next = r.Snapshot()
+	assert.Equal(t, []int{0, 1, 2}, values)
+	assert.Equal(t, uint64(3), next)
+	assert.Equal(t, 3, r.Size())
+
+	// Overflow the ring, retaining only the newest 4 elements.
+	for i := 3; i < 10; i++ {
+		r.Append(i)
+	}
+	values, _ = r.Snapshot()
+	assert.Equal(t, []int{6, 7, 8, 9}, values)
+	assert.Equal(t, 4, r.Size())
+
+	// Incremental snapshots only return elements appended since, clamped
+	// to the oldest element still buffered.
+	values, next = r.SnapshotSince(next)
+	assert.Equal(t, []int{6, 7, 8, 9}, values)
+	assert.Equal(t, uint64(10), next)
+
+	values, _ = r.SnapshotSince(next)
+	assert.Empty(t, values)
+}
+
+func TestSeqRingEmpty(t *testing.T) {
+	r := NewSeqRing[int](0)
+	r.Append(1)
+	values, next := r.Snapshot()
+	assert.Empty(t, values)
+	assert.Equal(t, uint64(0), next)
+}
+
+func TestSeqRingConcurrent(t *testing.T) {
+	const (
+		size   = 8
+		writes = 10000
+	)
+	r := NewSeqRing[uint64](size)
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var next uint64
+			for {
+				var values []uint64
+				values, next = r.SnapshotSince(next)
+				// Snapshots must be a gap-free suffix of the append
+				// history, even while being lapped by the writer.
+				start := next - uint64(len(values))
+				for i, v := range values {
+					assert.Equal(t, start+uint64(i)+1, v)
+				}
+				select {
+				case <-done:
+					return
+				default:
+				}
+			}
+		}()
+	}
+
+	for i := range uint64(writes) {
+		r.Append(i + 1)
+	}
+	close(done)
+	wg.Wait()
+
+	values, next := r.Snapshot()
+	assert.Equal(t, uint64(writes), next)
+	assert.Len(t, values, size)
+	assert.Equal(t, uint64(writes), values[size-1])
+}
@@ -1172,6 +1172,7 @@ func (e *Endpoint) GetPolicyCorrelationInfoForKey(key policyTypes.Key) (
 	}
 	info.RuleLabels = ruleMeta.LabelArrayListString()
 	info.Log = ruleMeta.Log()
+	info.LogVerdicts = ruleMeta.VerdictLogging()
 	info.Revision = e.policyRevision
 	return info, err == nil
 }
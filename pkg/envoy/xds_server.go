@@ -1868,6 +1868,15 @@ type Resources struct {
 
 	// Callback functions that are called if the corresponding Listener change was successfully acked by Envoy
 	PortAllocationCallbacks map[string]func(context.Context) error `json:"-" yaml:"-"`
+
+	// ListenerDrainWait, when true, makes updates wait for the removal of
+	// replaced or deleted listeners to be acked by Envoy before new listeners
+	// are added, so that existing connections are drained first.
+	ListenerDrainWait bool `json:"-" yaml:"-"`
+
+	// ListenerDrainTimeout bounds the time spent waiting for the drain of the
+	// old listeners. Zero means the general Envoy xDS timeout applies.
+	ListenerDrainTimeout time.Duration `json:"-" yaml:"-"`
 }
 
 // ListenersAddedOrDeleted returns 'true' if a listener is added or removed when updating from 'old'
@@ -1931,11 +1940,11 @@ func (s *xdsServer) UpsertEnvoyResources(ctx context.Context, resources Resource
 		)
 	}
 	var wg *completion.WaitGroup
-	// Listener config may fail if it refers to a cluster that has not been added yet, so we
-	// must wait for Envoy to ACK cluster config before adding Listeners to be sure Listener
-	// config does not fail for this reason.
-	// Enable wait before new Listeners are added if clusters are also added.
-	if len(resources.Listeners) > 0 && len(resources.Clusters) > 0 {
+	// Listener and route config may fail if they refer to a cluster that has not been added
+	// yet, so we must wait for Envoy to ACK cluster config before adding Listeners or Routes
+	// to be sure their config does not fail for this reason.
+	// Enable wait before new Listeners or Routes are added if clusters are also added.
+	if (len(resources.Listeners) > 0 || len(resources.Routes) > 0) && len(resources.Clusters) > 0 {
 		wg = completion.NewWaitGroup(ctx)
 	}
 	var revertFuncs xds.AckingResourceMutatorRevertFuncList
@@ -1946,7 +1955,7 @@ func (s *xdsServer) UpsertEnvoyResources(ctx context.Context, resources Resource
 	// in which case we could wait forever for the ACKs. This could also
 	// happen if there is no listener referring to these named
 	// resources to begin with.
-	// If both listeners and clusters are added then wait for clusters.
+	// If listeners or routes are added together with clusters then wait for clusters.
 	for _, r := range resources.Secrets {
 		s.logger.Debug("Envoy upsertSecret",
 			logfields.ResourceName, r.Name,
@@ -1967,14 +1976,7 @@ func (s *xdsServer) UpsertEnvoyResources(ctx context.Context, resources Resource
 		)
 		revertFuncs = append(revertFuncs, s.upsertCluster(r.Name, r, wg))
 	}
-	for _, r := range resources.Routes {
-		s.logger.Debug("Envoy upsertRoute",
-			logfields.ResourceName, r.Name,
-			logfields.Resource, r,
-		)
-		revertFuncs = append(revertFuncs, s.upsertRoute(r.Name, r, nil))
-	}
-	// Wait before new Listeners are added if clusters were also added above.
+	// Wait before new Routes or Listeners are added if clusters were also added above.
 	if wg != nil {
 		start := time.Now()
 		s.logger.Debug("UpsertEnvoyResources: Waiting for cluster updates to complete...")
@@ -1992,6 +1994,13 @@ func (s *xdsServer) UpsertEnvoyResources(ctx context.Context, resources Resource
 		}
 		wg = nil
 	}
+	for _, r := range resources.Routes {
+		s.logger.Debug("Envoy upsertRoute",
+			logfields.ResourceName, r.Name,
+			logfields.Resource, r,
+		)
+		revertFuncs = append(revertFuncs, s.upsertRoute(r.Name, r, nil))
+	}
 	// Wait only if new Listeners are added, as they will always be acked.
 	// (unreferenced routes or endpoints (and maybe clusters) are not ACKed or NACKed).
 	if len(resources.Listeners) > 0 {
@@ -2035,13 +2044,26 @@ func (s *xdsServer) UpsertEnvoyResources(ctx context.Context, resources Resource
 }
 
 func (s *xdsServer) UpdateEnvoyResources(ctx context.Context, old, new Resources) error {
-	waitForDelete := false
+	// Graceful listener drain waits for the deletion of the old listeners to
+	// be acked before adding the new ones.
+	waitForDelete := new.ListenerDrainWait
 	var wg *completion.WaitGroup
 	var revertFuncs xds.AckingResourceMutatorRevertFuncList
-	// Wait only if new Listeners are added, as they will always be acked.
+	// Wait only if new Listeners are added, as they will always be acked, or if
+	// new Routes are added together with the clusters they may refer to, in
+	// which case the cluster ACKs are waited for before pushing the routes.
 	// (unreferenced routes or endpoints (and maybe clusters) are not ACKed or NACKed).
-	if len(new.Listeners) > 0 {
-		wg = completion.NewWaitGroup(ctx)
+	if len(new.Listeners) > 0 || (len(new.Routes) > 0 && len(new.Clusters) > 0) {
+		deleteCtx := ctx
+		if waitForDelete && new.ListenerDrainTimeout > 0 {
+			// Bound the drain wait with the configured timeout. Only the wait
+			// group for the deletes is affected, a fresh one on the original
+			// context is created for the adds below.
+			var cancel context.CancelFunc
+			deleteCtx, cancel = context.WithTimeout(ctx, new.ListenerDrainTimeout)
+			defer cancel()
+		}
+		wg = completion.NewWaitGroup(deleteCtx)
 	}
 	// Delete old listeners not added in 'new' or if old and new listener have different ports
 	var deleteListeners []*envoy_config_listener.Listener
@@ -2210,10 +2232,9 @@ func (s *xdsServer) UpdateEnvoyResources(ctx context.Context, old, new Resources
 	for _, r := range new.Clusters {
 		revertFuncs = append(revertFuncs, s.upsertCluster(r.Name, r, wg))
 	}
-	// Add new Routes
-	for _, r := range new.Routes {
-		revertFuncs = append(revertFuncs, s.upsertRoute(r.Name, r, nil))
-	}
+	// Wait for cluster ACKs before adding Routes or Listeners that may refer
+	// to them, so that their config does not transiently fail due to a missing
+	// cluster reference.
 	if wg != nil && len(new.Clusters) > 0 {
 		start := time.Now()
 		s.logger.Debug("UpdateEnvoyResources: Waiting for cluster updates to complete...")
@@ -2226,8 +2247,15 @@ func (s *xdsServer) UpdateEnvoyResources(ctx context.Context, old, new Resources
 		s.logger.Debug("UpdateEnvoyResources: Finished waiting for cluster updates",
 			logfields.Duration, time.Since(start),
 		)
-		// new wait group for adds
-		wg = completion.NewWaitGroup(ctx)
+		// new wait group for listener adds, if any
+		wg = nil
+		if len(new.Listeners) > 0 {
+			wg = completion.NewWaitGroup(ctx)
+		}
+	}
+	// Add new Routes
+	for _, r := range new.Routes {
+		revertFuncs = append(revertFuncs, s.upsertRoute(r.Name, r, nil))
 	}
 	// Add new Listeners
 	for _, r := range new.Listeners {
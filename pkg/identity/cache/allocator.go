@@ -41,7 +41,9 @@ import (
 var (
 	// IdentitiesPath is the path to where identities are stored in the
 	// key-value store.
-	IdentitiesPath = path.Join(kvstore.BaseKeyPrefix, "state", "identities", "v1")
+	//
+	// Deprecated: use kvstore.IdentitiesPrefix instead.
+	IdentitiesPath = kvstore.IdentitiesPrefix
 )
 
 // The filename for the local allocator checkpoont. This is periodically
@@ -35,7 +35,9 @@ const (
 var (
 	// IPIdentitiesPath is the path to where endpoint IPs are stored in the key-value
 	// store.
-	IPIdentitiesPath = path.Join(kvstore.BaseKeyPrefix, "state", "ip", "v1")
+	//
+	// Deprecated: use kvstore.IPCachePrefix instead.
+	IPIdentitiesPath = kvstore.IPCachePrefix
 
 	// AddressSpace is the address space (cluster, etc.) in which policy is
 	// computed. It is determined by the orchestration system / runtime.
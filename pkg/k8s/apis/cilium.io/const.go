@@ -15,6 +15,10 @@ const (
 	// k8s policy UID.
 	PolicyLabelUID = LabelPrefix + ".policy.uid"
 
+	// PolicyLabelRuleUID is the name of the policy label carrying a stable
+	// per-rule UID derived from the rule content and the k8s policy UID.
+	PolicyLabelRuleUID = LabelPrefix + ".policy.rule-uid"
+
 	// PolicyLabelNamespace is the policy's namespace set in k8s.
 	PolicyLabelNamespace = LabelPrefix + ".policy.namespace"
 
@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package v2
+
+import (
+	"fmt"
+
+	envoy_config_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	envoy_config_route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_config_http "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	envoy_config_tcp "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// Envoy filter names used by the resource constructors below.
+const (
+	tcpProxyFilterName    = "envoy.filters.network.tcp_proxy"
+	httpConnectionManager = "envoy.filters.network.http_connection_manager"
+	httpRouterFilterName  = "envoy.filters.http.router"
+)
+
+// toXDSResource validates the given message and wraps it into an XDSResource
+// ready to be placed into CiliumEnvoyConfigSpec.Resources.
+func toXDSResource(msg proto.Message) (XDSResource, error) {
+	if v, ok := msg.(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return XDSResource{}, fmt.Errorf("failed to validate %s: %w", msg.ProtoReflect().Descriptor().FullName(), err)
+		}
+	}
+	a, err := anypb.New(msg)
+	if err != nil {
+		return XDSResource{}, err
+	}
+	return XDSResource{Any: a}, nil
+}
+
+// NewTCPProxyListener returns a Listener resource that proxies raw TCP
+// traffic to the given cluster. The listener carries no address, leaving
+// Cilium to allocate a proxy port for it when the config is applied.
+//
+// The constructors in this file produce valid XDSResource entries for the
+// patterns commonly generated by controllers, so that they do not need to
+// hand-craft protojson.
+func NewTCPProxyListener(name, cluster string) (XDSResource, error) {
+	tcpProxy, err := anypb.New(&envoy_config_tcp.TcpProxy{
+		StatPrefix: name,
+		ClusterSpecifier: &envoy_config_tcp.TcpProxy_Cluster{
+			Cluster: cluster,
+		},
+	})
+	if err != nil {
+		return XDSResource{}, err
+	}
+	return toXDSResource(&envoy_config_listener.Listener{
+		Name: name,
+		FilterChains: []*envoy_config_listener.FilterChain{{
+			Filters: []*envoy_config_listener.Filter{{
+				Name: tcpProxyFilterName,
+				ConfigType: &envoy_config_listener.Filter_TypedConfig{
+					TypedConfig: tcpProxy,
+				},
+			}},
+		}},
+	})
+}
+
+// NewHTTPListener returns a Listener resource with an HTTP connection manager
+// that routes requests according to the RouteConfiguration of the given name,
+// e.g. one built with NewHTTPRouteTo. The listener carries no address,
+// leaving Cilium to allocate a proxy port for it when the config is applied.
+func NewHTTPListener(name, routeName string) (XDSResource, error) {
+	hcm, err := anypb.New(&envoy_config_http.HttpConnectionManager{
+		StatPrefix: name,
+		RouteSpecifier: &envoy_config_http.HttpConnectionManager_Rds{
+			Rds: &envoy_config_http.Rds{
+				RouteConfigName: routeName,
+			},
+		},
+		HttpFilters: []*envoy_config_http.HttpFilter{{
+			Name: httpRouterFilterName,
+		}},
+	})
+	if err != nil {
+		return XDSResource{}, err
+	}
+	return toXDSResource(&envoy_config_listener.Listener{
+		Name: name,
+		FilterChains: []*envoy_config_listener.FilterChain{{
+			Filters: []*envoy_config_listener.Filter{{
+				Name: httpConnectionManager,
+				ConfigType: &envoy_config_listener.Filter_TypedConfig{
+					TypedConfig: hcm,
+				},
+			}},
+		}},
+	})
+}
+
+// NewHTTPRouteTo returns a RouteConfiguration resource of the given name that
+// forwards all requests to the backends of the given service, synced to Envoy
+// via EDS when the service is listed in the backendServices of the CEC.
+func NewHTTPRouteTo(routeName string, service *Service) (XDSResource, error) {
+	return toXDSResource(&envoy_config_route.RouteConfiguration{
+		Name: routeName,
+		VirtualHosts: []*envoy_config_route.VirtualHost{{
+			Name:    routeName,
+			Domains: []string{"*"},
+			Routes: []*envoy_config_route.Route{{
+				Match: &envoy_config_route.RouteMatch{
+					PathSpecifier: &envoy_config_route.RouteMatch_Prefix{
+						Prefix: "/",
+					},
+				},
+				Action: &envoy_config_route.Route_Route{
+					Route: &envoy_config_route.RouteAction{
+						ClusterSpecifier: &envoy_config_route.RouteAction_Cluster{
+							Cluster: fmt.Sprintf("%s/%s", service.Namespace, service.Name),
+						},
+					},
+				},
+			}},
+		}},
+	})
+}
@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package v2
+
+import (
+	"encoding/json"
+	"testing"
+
+	envoy_config_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	envoy_config_route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_config_http "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	envoy_config_tcp "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTCPProxyListener(t *testing.T) {
+	res, err := NewTCPProxyListener("tcp-listener", "default/backend")
+	require.NoError(t, err)
+	require.Equal(t, "type.googleapis.com/envoy.config.listener.v3.Listener", res.GetTypeUrl())
+
+	msg, err := res.UnmarshalNew()
+	require.NoError(t, err)
+	listener := msg.(*envoy_config_listener.Listener)
+	require.Equal(t, "tcp-listener", listener.Name)
+	require.Nil(t, listener.Address)
+	require.Len(t, listener.FilterChains, 1)
+
+	filter := listener.FilterChains[0].Filters[0]
+	require.Equal(t, "envoy.filters.network.tcp_proxy", filter.Name)
+	tcpMsg, err := filter.GetTypedConfig().UnmarshalNew()
+	require.NoError(t, err)
+	tcpProxy := tcpMsg.(*envoy_config_tcp.TcpProxy)
+	require.Equal(t, "default/backend", tcpProxy.GetCluster())
+}
+
+func TestNewHTTPListenerAndRoute(t *testing.T) {
+	listenerRes, err := NewHTTPListener("http-listener", "http-route")
+	require.NoError(t, err)
+	routeRes, err := NewHTTPRouteTo("http-route", &Service{Namespace: "default", Name: "backend"})
+	require.NoError(t, err)
+	require.Equal(t, "type.googleapis.com/envoy.config.route.v3.RouteConfiguration", routeRes.GetTypeUrl())
+
+	msg, err := listenerRes.UnmarshalNew()
+	require.NoError(t, err)
+	listener := msg.(*envoy_config_listener.Listener)
+	require.Nil(t, listener.Address)
+	filter := listener.FilterChains[0].Filters[0]
+	require.Equal(t, "envoy.filters.network.http_connection_manager", filter.Name)
+	hcmMsg, err := filter.GetTypedConfig().UnmarshalNew()
+	require.NoError(t, err)
+	hcm := hcmMsg.(*envoy_config_http.HttpConnectionManager)
+	require.Equal(t, "http-route", hcm.GetRds().GetRouteConfigName())
+
+	msg, err = routeRes.UnmarshalNew()
+	require.NoError(t, err)
+	route := msg.(*envoy_config_route.RouteConfiguration)
+	require.Len(t, route.VirtualHosts, 1)
+	require.Equal(t, "default/backend", route.VirtualHosts[0].Routes[0].GetRoute().GetCluster())
+
+	// The resources survive the JSON round trip through the CRD.
+	spec := &CiliumEnvoyConfigSpec{Resources: []XDSResource{listenerRes, routeRes}}
+	data, err := json.Marshal(spec)
+	require.NoError(t, err)
+	decoded := &CiliumEnvoyConfigSpec{}
+	require.NoError(t, json.Unmarshal(data, decoded))
+	require.Len(t, decoded.Resources, 2)
+	require.True(t, decoded.Resources[0].DeepEqual(&listenerRes))
+	require.True(t, decoded.Resources[1].DeepEqual(&routeRes))
+}
@@ -79,12 +79,84 @@ type CiliumEnvoyConfigSpec struct {
 	// +kubebuilder:validation:Required
 	Resources []XDSResource `json:"resources,omitempty"`
 
+	// ResourcesBinary is a list of additional Envoy xDS resources, each
+	// specified as the base64-encoded binary serialization of a protobuf
+	// Any message. It accepts the same resource types as 'resources', but
+	// bypasses the limitations of the JSON encoding, e.g. for resources
+	// using extension types unknown to the JSON parser or for large
+	// configurations. The decoded resources are validated and applied
+	// together with the ones given in 'resources'.
+	//
+	// +kubebuilder:validation:Optional
+	ResourcesBinary []XDSResourceBinary `json:"resourcesBinary,omitempty"`
+
 	// NodeSelector is a label selector that determines to which nodes
 	// this configuration applies.
 	// If nil, then this config applies to all nodes.
 	//
 	// +kubebuilder:validation:Optional
 	NodeSelector *slim_metav1.LabelSelector `json:"nodeSelector,omitempty"`
+
+	// Drain configures how connections on the Envoy listeners of this
+	// configuration are drained when an update removes or replaces them.
+	// If nil, updated listeners are pushed immediately.
+	//
+	// +kubebuilder:validation:Optional
+	Drain *ListenerDrain `json:"drain,omitempty"`
+}
+
+// ListenerDrainMode selects the connection draining behavior applied when a
+// configuration update removes or replaces Envoy listeners.
+//
+// +kubebuilder:validation:Enum=Graceful;Immediate
+type ListenerDrainMode string
+
+const (
+	// ListenerDrainModeGraceful waits for the removal of the previous
+	// listeners to be acknowledged by Envoy before the updated ones are
+	// added, letting in-flight connections drain.
+	ListenerDrainModeGraceful ListenerDrainMode = "Graceful"
+	// ListenerDrainModeImmediate pushes the updated listeners without
+	// waiting, accepting the disruption of in-flight connections.
+	ListenerDrainModeImmediate ListenerDrainMode = "Immediate"
+)
+
+// ListenerDrain configures the connection draining applied when a
+// configuration update removes or replaces Envoy listeners.
+type ListenerDrain struct {
+	// Mode selects the draining behavior on configuration updates. Defaults
+	// to Immediate, matching the behavior when drain is not configured.
+	//
+	// +kubebuilder:validation:Optional
+	Mode ListenerDrainMode `json:"mode,omitempty"`
+
+	// DrainTimeoutSeconds bounds the time to wait for draining in Graceful
+	// mode, after which the updated listeners are pushed regardless. If
+	// zero, the general Envoy configuration timeout applies.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	DrainTimeoutSeconds int32 `json:"drainTimeoutSeconds,omitempty"`
+}
+
+// IsGraceful returns true if graceful connection draining has been
+// configured. It is safe to call on a nil receiver.
+func (d *ListenerDrain) IsGraceful() bool {
+	return d != nil && d.Mode == ListenerDrainModeGraceful
+}
+
+// XDSResources returns the xDS resources given in 'resources' and
+// 'resourcesBinary' as a single list.
+func (s *CiliumEnvoyConfigSpec) XDSResources() []XDSResource {
+	if len(s.ResourcesBinary) == 0 {
+		return s.Resources
+	}
+	combined := make([]XDSResource, 0, len(s.Resources)+len(s.ResourcesBinary))
+	combined = append(combined, s.Resources...)
+	for _, r := range s.ResourcesBinary {
+		combined = append(combined, XDSResource{Any: r.Any})
+	}
+	return combined
 }
 
 type Service struct {
@@ -140,6 +212,37 @@ type ServiceListener struct {
 	//
 	// +kubebuilder:validation:Optional
 	Listener string `json:"listener"`
+
+	// Protocol is an optional hint of the L7 protocol the service speaks.
+	// When set to an HTTP variant the referenced Envoy listener must contain
+	// an HTTP connection manager filter chain, preventing HTTP/2 or gRPC
+	// services from being accidentally redirected through a generic TCP
+	// listener. If omitted, no protocol expectations are enforced.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=http;http2;grpc;tcp
+	Protocol string `json:"protocol,omitempty"`
+}
+
+const (
+	// ServiceProtocolHTTP hints that the service speaks HTTP/1.x.
+	ServiceProtocolHTTP = "http"
+	// ServiceProtocolHTTP2 hints that the service speaks HTTP/2.
+	ServiceProtocolHTTP2 = "http2"
+	// ServiceProtocolGRPC hints that the service speaks gRPC.
+	ServiceProtocolGRPC = "grpc"
+	// ServiceProtocolTCP hints that the service speaks a plain TCP protocol.
+	ServiceProtocolTCP = "tcp"
+)
+
+// ExpectsHTTP returns true if the protocol hint requires the traffic to be
+// handled by an HTTP connection manager.
+func (l *ServiceListener) ExpectsHTTP() bool {
+	switch l.Protocol {
+	case ServiceProtocolHTTP, ServiceProtocolHTTP2, ServiceProtocolGRPC:
+		return true
+	}
+	return false
 }
 
 func (l *ServiceListener) ServiceName() loadbalancer.ServiceName {
@@ -192,3 +295,95 @@ func (u *XDSResource) UnmarshalJSON(b []byte) (err error) {
 	}
 	return nil
 }
+
+type XDSResourceBinary struct {
+	*anypb.Any `json:"-"`
+}
+
+// DeepCopyInto deep copies 'in' into 'out'.
+func (in *XDSResourceBinary) DeepCopyInto(out *XDSResourceBinary) {
+	out.Any, _ = proto.Clone(in.Any).(*anypb.Any)
+}
+
+// DeepEqual returns 'true' if 'a' and 'b' are equal.
+func (a *XDSResourceBinary) DeepEqual(b *XDSResourceBinary) bool {
+	return proto.Equal(a.Any, b.Any)
+}
+
+// MarshalJSON encodes the resource as a JSON string holding the
+// base64-encoded deterministic binary serialization of the Any message.
+func (u *XDSResourceBinary) MarshalJSON() ([]byte, error) {
+	raw, err := proto.MarshalOptions{Deterministic: true}.Marshal(u.Any)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(raw)
+}
+
+// UnmarshalJSON decodes a JSON string holding the base64-encoded binary
+// serialization of an Any message.
+func (u *XDSResourceBinary) UnmarshalJSON(b []byte) (err error) {
+	// xDS resources are not validated in K8s, recover from possible panics
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("CEC binary decoding paniced: %v", r)
+		}
+	}()
+	var raw []byte
+	if err := json.Unmarshal(b, &raw); err != nil {
+		// slogloggercheck: it's safe to use the default logger here as it has been initialized by the program up to this point.
+		logging.DefaultSlogLogger.Warn("Ignoring invalid CiliumEnvoyConfig base64 data",
+			logfields.Error, err,
+		)
+		return nil
+	}
+	u.Any = &anypb.Any{}
+	err = proto.Unmarshal(raw, u.Any)
+	if err != nil {
+		// slogloggercheck: it's safe to use the default logger here as it has been initialized by the program up to this point.
+		logging.DefaultSlogLogger.Warn("Ignoring invalid CiliumEnvoyConfig binary resource",
+			logfields.Error, err,
+		)
+	} else if option.Config.Debug {
+		// slogloggercheck: it's safe to use the default logger here as it has been initialized by the program up to this point.
+		logging.DefaultSlogLogger.Debug("CEC unmarshaled binary XDS Resource", logfields.Resource, prototext.Format(u.Any))
+	}
+	return nil
+}
+
+// Merge applies the fields set in the partial resource 'override' on top of
+// the resource in 'u', following protobuf merge semantics: singular fields
+// set in the override replace the base fields, message fields are merged
+// recursively, and repeated fields are concatenated. Both resources must
+// carry the same type URL. The merge operates on the decoded messages rather
+// than raw JSON, so unknown or misspelled override fields are rejected
+// instead of silently replacing unrelated parts of the base resource. This
+// allows layering a per-node or per-environment override onto a shared base
+// resource.
+func (u *XDSResource) Merge(override *XDSResource) error {
+	if u.Any == nil || override.Any == nil {
+		return fmt.Errorf("cannot merge missing xDS resource")
+	}
+	if u.Any.GetTypeUrl() != override.Any.GetTypeUrl() {
+		return fmt.Errorf("cannot merge xDS resource of type %s with type %s",
+			override.Any.GetTypeUrl(), u.Any.GetTypeUrl())
+	}
+
+	base, err := u.Any.UnmarshalNew()
+	if err != nil {
+		return fmt.Errorf("failed to decode base xDS resource: %w", err)
+	}
+	patch, err := override.Any.UnmarshalNew()
+	if err != nil {
+		return fmt.Errorf("failed to decode override xDS resource: %w", err)
+	}
+
+	proto.Merge(base, patch)
+
+	merged, err := anypb.New(base)
+	if err != nil {
+		return fmt.Errorf("failed to encode merged xDS resource: %w", err)
+	}
+	u.Any = merged
+	return nil
+}
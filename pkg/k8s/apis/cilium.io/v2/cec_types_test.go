@@ -4,13 +4,16 @@
 package v2
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
 	"sigs.k8s.io/yaml"
 
-	_ "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	_ "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	envoy_config_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	_ "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
 )
 
@@ -58,3 +61,79 @@ func TestParseEnvoySpec(t *testing.T) {
 	require.Len(t, cec.Spec.Resources, 1)
 	require.Equal(t, "type.googleapis.com/envoy.config.listener.v3.Listener", cec.Spec.Resources[0].TypeUrl)
 }
+
+func TestParseEnvoySpecBinary(t *testing.T) {
+	// Reuse the JSON spec to build the binary-encoded equivalent.
+	jsonBytes, err := yaml.YAMLToJSON([]byte(envoySpec))
+	require.NoError(t, err)
+	cec := &CiliumEnvoyConfig{}
+	require.NoError(t, json.Unmarshal(jsonBytes, &cec.Spec))
+	require.Len(t, cec.Spec.Resources, 1)
+
+	raw, err := proto.Marshal(cec.Spec.Resources[0].Any)
+	require.NoError(t, err)
+
+	binSpec, err := json.Marshal(&CiliumEnvoyConfigSpec{
+		ResourcesBinary: []XDSResourceBinary{{Any: cec.Spec.Resources[0].Any}},
+	})
+	require.NoError(t, err)
+	require.Contains(t, string(binSpec), base64.StdEncoding.EncodeToString(raw))
+
+	binCEC := &CiliumEnvoyConfig{}
+	require.NoError(t, json.Unmarshal(binSpec, &binCEC.Spec))
+	require.Empty(t, binCEC.Spec.Resources)
+	require.Len(t, binCEC.Spec.ResourcesBinary, 1)
+	require.True(t, binCEC.Spec.ResourcesBinary[0].DeepEqual(&XDSResourceBinary{Any: cec.Spec.Resources[0].Any}))
+
+	// The combined resource list contains the binary resources after the
+	// JSON ones.
+	combined := binCEC.Spec.XDSResources()
+	require.Len(t, combined, 1)
+	require.Equal(t, "type.googleapis.com/envoy.config.listener.v3.Listener", combined[0].TypeUrl)
+
+	// Invalid base64 or protobuf data is ignored, like invalid JSON
+	// resources are.
+	invalid := &CiliumEnvoyConfigSpec{}
+	require.NoError(t, json.Unmarshal([]byte(`{"resourcesBinary": ["not-base64!"]}`), invalid))
+	require.Len(t, invalid.ResourcesBinary, 1)
+	require.Nil(t, invalid.ResourcesBinary[0].Any)
+}
+
+func TestListenerDrainIsGraceful(t *testing.T) {
+	var drain *ListenerDrain
+	require.False(t, drain.IsGraceful())
+	require.False(t, (&ListenerDrain{}).IsGraceful())
+	require.False(t, (&ListenerDrain{Mode: ListenerDrainModeImmediate}).IsGraceful())
+	require.True(t, (&ListenerDrain{Mode: ListenerDrainModeGraceful}).IsGraceful())
+}
+
+func TestXDSResourceMerge(t *testing.T) {
+	parse := func(s string) XDSResource {
+		var r XDSResource
+		require.NoError(t, r.UnmarshalJSON([]byte(s)))
+		return r
+	}
+
+	base := parse(`{"@type": "type.googleapis.com/envoy.config.listener.v3.Listener",
+		"name": "test-listener",
+		"address": {"socket_address": {"address": "127.0.0.1", "port_value": 10000}}}`)
+	override := parse(`{"@type": "type.googleapis.com/envoy.config.listener.v3.Listener",
+		"address": {"socket_address": {"port_value": 10001}}}`)
+
+	require.NoError(t, base.Merge(&override))
+
+	msg, err := base.Any.UnmarshalNew()
+	require.NoError(t, err)
+	listener := msg.(*envoy_config_listener.Listener)
+	require.Equal(t, "test-listener", listener.Name)
+	require.Equal(t, "127.0.0.1", listener.GetAddress().GetSocketAddress().GetAddress())
+	require.Equal(t, uint32(10001), listener.GetAddress().GetSocketAddress().GetPortValue())
+
+	// Resources of different types cannot be merged.
+	cluster := parse(`{"@type": "type.googleapis.com/envoy.config.cluster.v3.Cluster", "name": "test-cluster"}`)
+	require.Error(t, base.Merge(&cluster))
+
+	// Missing resources cannot be merged.
+	require.Error(t, (&XDSResource{}).Merge(&override))
+	require.Error(t, base.Merge(&XDSResource{}))
+}
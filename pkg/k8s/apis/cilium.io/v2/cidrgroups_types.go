@@ -15,6 +15,7 @@ import (
 // +kubebuilder:resource:categories={cilium},singular="ciliumcidrgroup",path="ciliumcidrgroups",scope="Cluster",shortName={ccg}
 // +kubebuilder:object:root=true
 // +kubebuilder:storageversion
+// +kubebuilder:subresource:status
 // +deepequal-gen=false
 
 // CiliumCIDRGroup is a list of external CIDRs (i.e: CIDRs selecting peers
@@ -28,6 +29,12 @@ type CiliumCIDRGroup struct {
 
 	// +kubebuilder:validation:Required
 	Spec CiliumCIDRGroupSpec `json:"spec"`
+
+	// Status reflects how the CIDR group is used by network policies.
+	//
+	// +deepequal-gen=false
+	// +kubebuilder:validation:Optional
+	Status CiliumCIDRGroupStatus `json:"status,omitempty"`
 }
 
 type CiliumCIDRGroupSpec struct {
@@ -38,6 +45,36 @@ type CiliumCIDRGroupSpec struct {
 	ExternalCIDRs []api.CIDR `json:"externalCIDRs"`
 }
 
+const (
+	// CIDRGroupConditionResolved is the condition type recording whether
+	// all CIDRs of the group were successfully resolved and applied.
+	CIDRGroupConditionResolved = "cilium.io/CIDRsResolved"
+
+	// CIDRGroupReasonResolved is used with the Resolved condition when all
+	// CIDRs of the group were valid.
+	CIDRGroupReasonResolved = "CIDRsResolved"
+	// CIDRGroupReasonInvalid is used with the Resolved condition when one
+	// or more CIDRs of the group could not be parsed.
+	CIDRGroupReasonInvalid = "InvalidCIDRs"
+)
+
+// CiliumCIDRGroupStatus is the status of a CiliumCIDRGroup.
+type CiliumCIDRGroupStatus struct {
+	// ReferencingPolicies is the number of network policies currently
+	// referencing this CIDR group by name.
+	//
+	// +kubebuilder:validation:Optional
+	ReferencingPolicies int32 `json:"referencingPolicies"`
+
+	// Conditions reflect the state of the CIDR group, e.g. whether all of
+	// its CIDRs were successfully resolved.
+	//
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // +deepequal-gen=false
 
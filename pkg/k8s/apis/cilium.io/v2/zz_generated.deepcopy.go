@@ -1132,6 +1132,7 @@ func (in *CiliumCIDRGroup) DeepCopyInto(out *CiliumCIDRGroup) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -1207,6 +1208,29 @@ func (in *CiliumCIDRGroupSpec) DeepCopy() *CiliumCIDRGroupSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CiliumCIDRGroupStatus) DeepCopyInto(out *CiliumCIDRGroupStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CiliumCIDRGroupStatus.
+func (in *CiliumCIDRGroupStatus) DeepCopy() *CiliumCIDRGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CiliumCIDRGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CiliumClusterwideEnvoyConfig) DeepCopyInto(out *CiliumClusterwideEnvoyConfig) {
 	*out = *in
@@ -1600,11 +1624,23 @@ func (in *CiliumEnvoyConfigSpec) DeepCopyInto(out *CiliumEnvoyConfigSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ResourcesBinary != nil {
+		in, out := &in.ResourcesBinary, &out.ResourcesBinary
+		*out = make([]XDSResourceBinary, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.NodeSelector != nil {
 		in, out := &in.NodeSelector, &out.NodeSelector
 		*out = new(v1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Drain != nil {
+		in, out := &in.Drain, &out.Drain
+		*out = new(ListenerDrain)
+		**out = **in
+	}
 	return
 }
 
@@ -1375,6 +1375,23 @@ func (in *CiliumEnvoyConfigSpec) DeepEqual(other *CiliumEnvoyConfigSpec) bool {
 		}
 	}
 
+	if ((in.ResourcesBinary != nil) && (other.ResourcesBinary != nil)) || ((in.ResourcesBinary == nil) != (other.ResourcesBinary == nil)) {
+		in, other := &in.ResourcesBinary, &other.ResourcesBinary
+		if other == nil {
+			return false
+		}
+
+		if len(*in) != len(*other) {
+			return false
+		} else {
+			for i, inElement := range *in {
+				if !inElement.DeepEqual(&(*other)[i]) {
+					return false
+				}
+			}
+		}
+	}
+
 	if (in.NodeSelector == nil) != (other.NodeSelector == nil) {
 		return false
 	} else if in.NodeSelector != nil {
@@ -1383,6 +1400,31 @@ func (in *CiliumEnvoyConfigSpec) DeepEqual(other *CiliumEnvoyConfigSpec) bool {
 		}
 	}
 
+	if (in.Drain == nil) != (other.Drain == nil) {
+		return false
+	} else if in.Drain != nil {
+		if !in.Drain.DeepEqual(other.Drain) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// DeepEqual is an autogenerated deepequal function, deeply comparing the
+// receiver with other. in must be non-nil.
+func (in *ListenerDrain) DeepEqual(other *ListenerDrain) bool {
+	if other == nil {
+		return false
+	}
+
+	if in.Mode != other.Mode {
+		return false
+	}
+	if in.DrainTimeoutSeconds != other.DrainTimeoutSeconds {
+		return false
+	}
+
 	return true
 }
 
@@ -2516,5 +2558,9 @@ func (in *ServiceListener) DeepEqual(other *ServiceListener) bool {
 		return false
 	}
 
+	if in.Protocol != other.Protocol {
+		return false
+	}
+
 	return true
 }
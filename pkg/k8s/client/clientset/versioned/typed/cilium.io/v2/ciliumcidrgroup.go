@@ -26,6 +26,8 @@ type CiliumCIDRGroupsGetter interface {
 type CiliumCIDRGroupInterface interface {
 	Create(ctx context.Context, ciliumCIDRGroup *ciliumiov2.CiliumCIDRGroup, opts v1.CreateOptions) (*ciliumiov2.CiliumCIDRGroup, error)
 	Update(ctx context.Context, ciliumCIDRGroup *ciliumiov2.CiliumCIDRGroup, opts v1.UpdateOptions) (*ciliumiov2.CiliumCIDRGroup, error)
+	// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+	UpdateStatus(ctx context.Context, ciliumCIDRGroup *ciliumiov2.CiliumCIDRGroup, opts v1.UpdateOptions) (*ciliumiov2.CiliumCIDRGroup, error)
 	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
 	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
 	Get(ctx context.Context, name string, opts v1.GetOptions) (*ciliumiov2.CiliumCIDRGroup, error)
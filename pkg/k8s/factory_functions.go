@@ -125,14 +125,7 @@ func ConvertToNetworkV1IngressLoadBalancerIngress(slimLBIngs []slim_corev1.LoadB
 func TransformToCCNP(obj any) (any, error) {
 	switch concreteObj := obj.(type) {
 	case *cilium_v2.CiliumClusterwideNetworkPolicy:
-		return &types.SlimCNP{
-			CiliumNetworkPolicy: &cilium_v2.CiliumNetworkPolicy{
-				TypeMeta:   concreteObj.TypeMeta,
-				ObjectMeta: concreteObj.ObjectMeta,
-				Spec:       concreteObj.Spec,
-				Specs:      concreteObj.Specs,
-			},
-		}, nil
+		return SlimCNPFromCCNP(concreteObj), nil
 	case *types.SlimCNP:
 		return obj, nil
 	case cache.DeletedFinalStateUnknown:
@@ -143,17 +136,9 @@ func TransformToCCNP(obj any) (any, error) {
 		if !ok {
 			return nil, fmt.Errorf("unknown object type %T", concreteObj.Obj)
 		}
-		slimCNP := &types.SlimCNP{
-			CiliumNetworkPolicy: &cilium_v2.CiliumNetworkPolicy{
-				TypeMeta:   ccnp.TypeMeta,
-				ObjectMeta: ccnp.ObjectMeta,
-				Spec:       ccnp.Spec,
-				Specs:      ccnp.Specs,
-			},
-		}
 		dfsu := cache.DeletedFinalStateUnknown{
 			Key: concreteObj.Key,
-			Obj: slimCNP,
+			Obj: SlimCNPFromCCNP(ccnp),
 		}
 		return dfsu, nil
 
@@ -173,14 +158,7 @@ func TransformToCCNP(obj any) (any, error) {
 func TransformToCNP(obj any) (any, error) {
 	switch concreteObj := obj.(type) {
 	case *cilium_v2.CiliumNetworkPolicy:
-		return &types.SlimCNP{
-			CiliumNetworkPolicy: &cilium_v2.CiliumNetworkPolicy{
-				TypeMeta:   concreteObj.TypeMeta,
-				ObjectMeta: concreteObj.ObjectMeta,
-				Spec:       concreteObj.Spec,
-				Specs:      concreteObj.Specs,
-			},
-		}, nil
+		return SlimCNPFrom(concreteObj), nil
 	case *types.SlimCNP:
 		return obj, nil
 	case cache.DeletedFinalStateUnknown:
@@ -193,14 +171,7 @@ func TransformToCNP(obj any) (any, error) {
 		}
 		return cache.DeletedFinalStateUnknown{
 			Key: concreteObj.Key,
-			Obj: &types.SlimCNP{
-				CiliumNetworkPolicy: &cilium_v2.CiliumNetworkPolicy{
-					TypeMeta:   cnp.TypeMeta,
-					ObjectMeta: cnp.ObjectMeta,
-					Spec:       cnp.Spec,
-					Specs:      cnp.Specs,
-				},
-			},
+			Obj: SlimCNPFrom(cnp),
 		}, nil
 	default:
 		return nil, fmt.Errorf("unknown object type %T", concreteObj)
@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package k8s
+
+import (
+	"sync"
+
+	cilium_v2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+	"github.com/cilium/cilium/pkg/k8s/types"
+)
+
+// slimCNPPool recycles the wrapper objects allocated when converting
+// CiliumNetworkPolicies and CiliumClusterwideNetworkPolicies into their slim
+// representation. During policy storms every event allocates a fresh wrapper
+// only to discard it shortly after, e.g. for status-only updates that do not
+// change the policy generation. The pool only holds the wrappers: the Spec,
+// Specs and ObjectMeta remain shared with the original object and are never
+// copied. Selector reuse across policies is handled separately by the policy
+// watcher's compiled selector cache.
+var slimCNPPool = sync.Pool{
+	New: func() any {
+		return &types.SlimCNP{CiliumNetworkPolicy: &cilium_v2.CiliumNetworkPolicy{}}
+	},
+}
+
+// SlimCNPFrom converts a CiliumNetworkPolicy into its slim representation,
+// dropping the Status field. The returned object shares the ObjectMeta, Spec
+// and Specs with the given policy. Callers discarding the result while the
+// original policy is still referenced elsewhere may hand the wrapper back via
+// ReleaseSlimCNP to avoid the allocation on the next conversion.
+func SlimCNPFrom(cnp *cilium_v2.CiliumNetworkPolicy) *types.SlimCNP {
+	slim := slimCNPPool.Get().(*types.SlimCNP)
+	slim.TypeMeta = cnp.TypeMeta
+	slim.ObjectMeta = cnp.ObjectMeta
+	slim.Spec = cnp.Spec
+	slim.Specs = cnp.Specs
+	return slim
+}
+
+// SlimCNPFromCCNP converts a CiliumClusterwideNetworkPolicy into its slim
+// representation, with the same sharing semantics as SlimCNPFrom.
+func SlimCNPFromCCNP(ccnp *cilium_v2.CiliumClusterwideNetworkPolicy) *types.SlimCNP {
+	slim := slimCNPPool.Get().(*types.SlimCNP)
+	slim.TypeMeta = ccnp.TypeMeta
+	slim.ObjectMeta = ccnp.ObjectMeta
+	slim.Spec = ccnp.Spec
+	slim.Specs = ccnp.Specs
+	return slim
+}
+
+// ReleaseSlimCNP returns a wrapper obtained from SlimCNPFrom or
+// SlimCNPFromCCNP to the pool. The wrapper must no longer be referenced by
+// the caller. Only the wrapper fields are cleared; the shared Spec, Specs and
+// ObjectMeta of the original policy are left untouched.
+func ReleaseSlimCNP(slim *types.SlimCNP) {
+	if slim == nil || slim.CiliumNetworkPolicy == nil {
+		return
+	}
+	*slim.CiliumNetworkPolicy = cilium_v2.CiliumNetworkPolicy{}
+	slimCNPPool.Put(slim)
+}
@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+	"github.com/cilium/cilium/pkg/k8s/types"
+	"github.com/cilium/cilium/pkg/policy/api"
+)
+
+func benchmarkCNP() *v2.CiliumNetworkPolicy {
+	return &v2.CiliumNetworkPolicy{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "CiliumNetworkPolicy",
+			APIVersion: "cilium.io/v2",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "rule",
+			Namespace:  "ns",
+			Generation: 1,
+			Labels: map[string]string{
+				"app": "bench",
+			},
+		},
+		Spec: &api.Rule{
+			EndpointSelector: api.NewESFromLabels(),
+		},
+	}
+}
+
+func TestSlimCNPFrom(t *testing.T) {
+	cnp := benchmarkCNP()
+
+	slim := SlimCNPFrom(cnp)
+	require.Equal(t, cnp.TypeMeta, slim.TypeMeta)
+	require.Equal(t, cnp.ObjectMeta, slim.ObjectMeta)
+	require.Same(t, cnp.Spec, slim.Spec)
+
+	// A released wrapper is cleared and must not leak the previous policy
+	// into the next conversion.
+	ReleaseSlimCNP(slim)
+	other := SlimCNPFrom(&v2.CiliumNetworkPolicy{})
+	require.Empty(t, other.ObjectMeta.Name)
+	require.Nil(t, other.Spec)
+	ReleaseSlimCNP(other)
+
+	ccnp := &v2.CiliumClusterwideNetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-rule"},
+		Spec:       &api.Rule{EndpointSelector: api.NewESFromLabels()},
+	}
+	slim = SlimCNPFromCCNP(ccnp)
+	require.Equal(t, ccnp.ObjectMeta, slim.ObjectMeta)
+	require.Same(t, ccnp.Spec, slim.Spec)
+	ReleaseSlimCNP(slim)
+
+	// Releasing nil wrappers is a no-op.
+	ReleaseSlimCNP(nil)
+}
+
+// benchSink keeps the converted object alive so that the compiler does not
+// elide the allocation under benchmark.
+var benchSink *types.SlimCNP
+
+// BenchmarkSlimCNPFrom measures a conversion whose result is discarded right
+// away, as happens for every status-only update during a policy storm.
+func BenchmarkSlimCNPFrom(b *testing.B) {
+	cnp := benchmarkCNP()
+	b.ReportAllocs()
+	for b.Loop() {
+		benchSink = SlimCNPFrom(cnp)
+		ReleaseSlimCNP(benchSink)
+	}
+}
+
+// BenchmarkSlimCNPFromNoReuse provides the baseline without wrapper reuse,
+// equivalent to the previous inline conversion.
+func BenchmarkSlimCNPFromNoReuse(b *testing.B) {
+	cnp := benchmarkCNP()
+	b.ReportAllocs()
+	for b.Loop() {
+		benchSink = &types.SlimCNP{
+			CiliumNetworkPolicy: &v2.CiliumNetworkPolicy{
+				TypeMeta:   cnp.TypeMeta,
+				ObjectMeta: cnp.ObjectMeta,
+				Spec:       cnp.Spec,
+				Specs:      cnp.Specs,
+			},
+		}
+	}
+}
@@ -97,7 +97,7 @@ func NewKVStoreBackend(logger *slog.Logger, c KVStoreBackendConfiguration) (allo
 		basePrefix:  c.BasePath,
 		idPrefix:    path.Join(c.BasePath, "id"),
 		valuePrefix: path.Join(c.BasePath, "value"),
-		lockPrefix:  path.Join(c.BasePath, "locks"),
+		lockPrefix:  kvstore.LocksPrefix(c.BasePath),
 		suffix:      c.Suffix,
 		keyType:     c.Typ,
 		backend:     c.Backend,
@@ -630,6 +630,11 @@ func (k *kvstoreBackend) ListAndWatch(ctx context.Context, handler allocator.Cac
 			continue
 		}
 
+		if event.Typ == kvstore.EventTypeDrain {
+			// The terminal event before the channel is closed.
+			continue
+		}
+
 		id, err := k.keyToID(event.Key)
 		switch {
 		case err != nil:
@@ -5,6 +5,8 @@ package kvstore
 
 import (
 	"context"
+	"fmt"
+	"iter"
 	"log/slog"
 
 	"google.golang.org/grpc"
@@ -35,6 +37,11 @@ type ClusterSizeDependantIntervalFunc func(baseInterval time.Duration) time.Dura
 type ExtraOptions struct {
 	DialOption []grpc.DialOption
 
+	// CredentialProvider, if set, supplies short-lived tokens attached as
+	// per-RPC credentials to the etcd connection, for deployments where
+	// etcd is fronted by an auth proxy.
+	CredentialProvider CredentialProvider
+
 	// ClusterSizeDependantInterval defines the function to calculate
 	// intervals based on cluster size
 	ClusterSizeDependantInterval ClusterSizeDependantIntervalFunc
@@ -60,6 +67,62 @@ type ExtraOptions struct {
 	// MaxConsecutiveQuorumErrors represents the maximum number of consecutive
 	// quorum errors before recreating the etcd connection.
 	MaxConsecutiveQuorumErrors uint
+
+	// HeartbeatWriteInterval is the interval in which the heartbeat key is
+	// expected to be updated by the operator. Defaults to
+	// HeartbeatWriteInterval if unset.
+	HeartbeatWriteInterval time.Duration
+
+	// HeartbeatTolerance is the number of missed heartbeat updates after
+	// which the status checker declares heartbeat failure. Defaults to
+	// defaultHeartbeatTolerance if unset.
+	HeartbeatTolerance uint
+
+	// WatchCheckpointProvider, if set, supplies the per-prefix checkpoint
+	// stores allowing ListAndWatch watchers to resume from the last processed
+	// revision across agent restarts, instead of relisting the whole prefix.
+	// See RevisionCheckpoint for the applicable caveats.
+	WatchCheckpointProvider WatchCheckpointProviderFunc
+}
+
+// defaultHeartbeatTolerance is the default number of heartbeat write intervals
+// without any observed update after which the heartbeat is considered stale.
+const defaultHeartbeatTolerance = 2
+
+// validate returns an error if the options are invalid.
+func (e *ExtraOptions) validate() error {
+	if e == nil {
+		return nil
+	}
+
+	if e.HeartbeatWriteInterval < 0 {
+		return fmt.Errorf("heartbeat write interval must not be negative")
+	}
+
+	if e.HeartbeatTolerance == 1 {
+		return fmt.Errorf("heartbeat tolerance must be at least 2, to not declare failure upon a single missed update")
+	}
+
+	return nil
+}
+
+// HeartbeatStaleThreshold returns the duration since the last observed
+// heartbeat update after which the heartbeat is considered stale, and the
+// client declares heartbeat failure.
+func (e *ExtraOptions) HeartbeatStaleThreshold() time.Duration {
+	interval := HeartbeatWriteInterval
+	tolerance := time.Duration(defaultHeartbeatTolerance)
+
+	if e != nil {
+		if e.HeartbeatWriteInterval > 0 {
+			interval = e.HeartbeatWriteInterval
+		}
+		if e.HeartbeatTolerance > 0 {
+			tolerance = time.Duration(e.HeartbeatTolerance)
+		}
+	}
+
+	return tolerance * interval
 }
 
 // StatusCheckInterval returns the interval of status checks depending on the
@@ -88,6 +151,35 @@ func (e *ExtraOptions) StatusCheckInterval(allConnected bool) time.Duration {
 	return interval
 }
 
+// statusStabilityAlpha is the exponential smoothing factor applied to the
+// stability score after each successful status check.
+const statusStabilityAlpha = 0.25
+
+// statusIntervalSmoother adapts the status check interval based on a score
+// tracking how long the connection has been stable. The score is reset upon
+// failure and exponentially smoothed towards one by successful checks, so
+// that checks run rapidly right after a failure and progressively back off
+// towards the steady-state interval as the cluster remains healthy, rather
+// than jumping between the two extremes.
+type statusIntervalSmoother struct {
+	stability float64
+}
+
+// interval returns the duration to wait before the next status check, given
+// the outcome of the one which just completed. The returned interval ranges
+// between the failing and healthy intervals from StatusCheckInterval,
+// depending on the current stability score.
+func (s *statusIntervalSmoother) interval(e *ExtraOptions, healthy bool) time.Duration {
+	if healthy {
+		s.stability += statusStabilityAlpha * (1 - s.stability)
+	} else {
+		s.stability = 0
+	}
+
+	failing, steady := e.StatusCheckInterval(false), e.StatusCheckInterval(true)
+	return failing + time.Duration(s.stability*float64(steady-failing))
+}
+
 // backendModule is the interface that each kvstore backend has to implement.
 type backendModule interface {
 	// setConfig must configure the backend with the specified options.
@@ -134,6 +226,14 @@ type BackendOperations interface {
 	// Status returns the status of the kvstore client
 	Status() *models.Status
 
+	// StatusDetail returns a structured representation of the information
+	// summarized by the message returned by Status()
+	StatusDetail() *models.KVstoreStatus
+
+	// StatusChanged returns a channel which is closed when the status
+	// returned by Status() is next updated
+	StatusChanged() <-chan struct{}
+
 	// StatusCheckErrors returns a channel which receives status check
 	// errors
 	StatusCheckErrors() <-chan error
@@ -155,6 +255,9 @@ type BackendOperations interface {
 
 	DeletePrefix(ctx context.Context, path string) error
 
+	// DeletePrefixIfLocked deletes the complete keyspace of the specified prefix if the client is still holding the given lock.
+	DeletePrefixIfLocked(ctx context.Context, path string, lock KVLocker) error
+
 	// Update creates or updates a key.
 	Update(ctx context.Context, key string, value []byte, lease bool) error
 
@@ -167,15 +270,45 @@ type BackendOperations interface {
 	// UpdateIfDifferentIfLocked updates a key if the value is different and if the client is still holding the given lock.
 	UpdateIfDifferentIfLocked(ctx context.Context, key string, value []byte, lease bool, lock KVLocker) (bool, error)
 
+	// UpdateBatch creates or updates the given keys, coalescing the writes
+	// into transactional batches which are rate limited as a single unit.
+	// Compared to issuing the updates individually, it avoids the per-key
+	// round trips and rate limiting dominating the time needed to push many
+	// keys at once (e.g., at agent bootstrap). The batches are applied
+	// independently: a failure does not roll back previously applied ones.
+	UpdateBatch(ctx context.Context, pairs []KeyValue, lease bool) error
+
 	// CreateOnly atomically creates a key or fails if it already exists
 	CreateOnly(ctx context.Context, key string, value []byte, lease bool) (bool, error)
 
 	// CreateOnlyIfLocked atomically creates a key if the client is still holding the given lock or fails if it already exists
 	CreateOnlyIfLocked(ctx context.Context, key string, value []byte, lease bool, lock KVLocker) (bool, error)
 
+	// Txn atomically executes the given mutations as a single transaction:
+	// thenOps are applied if all conditions hold, elseOps otherwise. It
+	// returns whether the conditions held. Batching multiple mutations into
+	// a transaction trades N sequential round trips for a single one, at the
+	// cost of the backend limits on transaction size.
+	Txn(ctx context.Context, conditions []TxnCondition, thenOps, elseOps []TxnOp) (bool, error)
+
 	// ListPrefix returns a list of keys matching the prefix
 	ListPrefix(ctx context.Context, prefix string) (KeyValuePairs, error)
 
+	// ListPrefixFunc invokes the callback for every key matching the prefix,
+	// retrieving the keys in paginated batches instead of materializing the
+	// full result set, to reduce peak memory usage on large prefixes. The
+	// iteration stops early if the callback returns false.
+	ListPrefixFunc(ctx context.Context, prefix string, fn func(key string, value Value) bool) error
+
+	// ListPrefixPaginated returns an iterator over the keys matching the
+	// prefix, retrieved in pages of at most pageSize entries pinned at a
+	// single revision, so that listing large prefixes does not hit the
+	// backend limits on response size. A pageSize of zero or less selects
+	// the backend default. The returned channel conveys the error which
+	// possibly terminated the iteration early, and is closed once the
+	// iteration completes.
+	ListPrefixPaginated(ctx context.Context, prefix string, pageSize int) (iter.Seq2[string, Value], <-chan error)
+
 	// ListPrefixIfLocked returns a list of keys matching the prefix only if the client is still holding the given lock.
 	ListPrefixIfLocked(ctx context.Context, prefix string, lock KVLocker) (KeyValuePairs, error)
 
@@ -189,11 +322,26 @@ type BackendOperations interface {
 	// to the Events channel
 	ListAndWatch(ctx context.Context, prefix string) EventChan
 
+	// WatchKey creates a new watcher for a single key. Before doing this, it
+	// will retrieve the current value of the key and report it as a new key,
+	// followed by an EventTypeListDone event. Compared to ListAndWatch, it
+	// avoids the prefix machinery and the associated bookkeeping, making it
+	// cheaper for single-key consumers such as the heartbeat path.
+	WatchKey(ctx context.Context, key string) EventChan
+
 	// RegisterLeaseExpiredObserver registers a function which is executed when
 	// the lease associated with a key having the given prefix is detected as expired.
 	// If the function is nil, the previous observer (if any) is unregistered.
 	RegisterLeaseExpiredObserver(prefix string, fn func(key string))
 
+	// RegisterLeaseKeepaliveFailureObserver registers a function which is
+	// executed when the keepalives of the lease associated with a key having
+	// the given prefix are detected as repeatedly failing, before the lease
+	// actually expires. This allows consumers to proactively refresh critical
+	// keys, rather than reacting only after the expiration is propagated.
+	// If the function is nil, the previous observer (if any) is unregistered.
+	RegisterLeaseKeepaliveFailureObserver(prefix string, fn func(key string))
+
 	BackendOperationsUserMgmt
 }
 
@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cilium/cilium/pkg/time"
+)
+
+func TestHeartbeatStaleThreshold(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     *ExtraOptions
+		expected time.Duration
+	}{
+		{
+			name:     "nil options",
+			opts:     nil,
+			expected: 2 * time.Minute,
+		},
+		{
+			name:     "defaults",
+			opts:     &ExtraOptions{},
+			expected: 2 * time.Minute,
+		},
+		{
+			name:     "custom write interval",
+			opts:     &ExtraOptions{HeartbeatWriteInterval: 30 * time.Second},
+			expected: time.Minute,
+		},
+		{
+			name:     "custom tolerance",
+			opts:     &ExtraOptions{HeartbeatTolerance: 5},
+			expected: 5 * time.Minute,
+		},
+		{
+			name: "custom write interval and tolerance",
+			opts: &ExtraOptions{
+				HeartbeatWriteInterval: 10 * time.Second,
+				HeartbeatTolerance:     3,
+			},
+			expected: 30 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.opts.HeartbeatStaleThreshold())
+		})
+	}
+}
+
+func TestStatusIntervalSmoother(t *testing.T) {
+	opts := &ExtraOptions{}
+	failing, steady := opts.StatusCheckInterval(false), opts.StatusCheckInterval(true)
+
+	var s statusIntervalSmoother
+
+	// The first check after a failure runs at the rapid interval, and
+	// subsequent healthy checks progressively back off towards the
+	// steady-state interval.
+	assert.Equal(t, failing, s.interval(opts, false))
+	previous := failing
+	for range 20 {
+		interval := s.interval(opts, true)
+		assert.Greater(t, interval, previous)
+		assert.LessOrEqual(t, interval, steady)
+		previous = interval
+	}
+	assert.InDelta(t, steady, previous, float64(time.Second))
+
+	// A single failure resets the stability score.
+	assert.Equal(t, failing, s.interval(opts, false))
+	assert.Less(t, s.interval(opts, true), steady/2)
+}
+
+func TestExtraOptionsValidate(t *testing.T) {
+	var nilOpts *ExtraOptions
+	assert.NoError(t, nilOpts.validate())
+	assert.NoError(t, (&ExtraOptions{}).validate())
+	assert.NoError(t, (&ExtraOptions{HeartbeatWriteInterval: time.Minute, HeartbeatTolerance: 2}).validate())
+	assert.Error(t, (&ExtraOptions{HeartbeatWriteInterval: -time.Minute}).validate())
+	assert.Error(t, (&ExtraOptions{HeartbeatTolerance: 1}).validate())
+}
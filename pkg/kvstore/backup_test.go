@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/cilium/statedb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackupRestore(t *testing.T) {
+	db := statedb.New()
+	source := NewInMemoryClient(db, "__local__")
+	ctx := t.Context()
+
+	require.NoError(t, source.Update(ctx, "cilium/state/backup/v1/foo", []byte(`{"name":"foo"}`), false))
+	require.NoError(t, source.Update(ctx, "cilium/state/backup/v1/raw", []byte{0x00, 0xde, 0xad}, false))
+	require.NoError(t, source.Update(ctx, "other/key", []byte("skipped"), false))
+
+	var buf bytes.Buffer
+	count, err := Backup(ctx, source, "cilium/", &buf)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	target := NewInMemoryClient(statedb.New(), "__local__")
+	require.NoError(t, target.Update(ctx, "cilium/state/backup/v1/foo", []byte("stale"), false))
+	require.NoError(t, target.Update(ctx, "cilium/untouched", []byte("kept"), false))
+
+	restored, err := Restore(ctx, target, &buf)
+	require.NoError(t, err)
+	require.Equal(t, 2, restored)
+
+	// Binary values round-trip unchanged and existing keys are overwritten.
+	value, err := target.Get(ctx, "cilium/state/backup/v1/foo")
+	require.NoError(t, err)
+	assert.Equal(t, []byte(`{"name":"foo"}`), value)
+
+	value, err = target.Get(ctx, "cilium/state/backup/v1/raw")
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x00, 0xde, 0xad}, value)
+
+	// Keys outside the backup are left untouched, keys outside the backed up
+	// prefix are not carried over.
+	value, err = target.Get(ctx, "cilium/untouched")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("kept"), value)
+
+	value, err = target.Get(ctx, "other/key")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+}
+
+func TestRestoreRejectsForeignStreams(t *testing.T) {
+	target := NewInMemoryClient(statedb.New(), "__local__")
+	ctx := t.Context()
+
+	_, err := Restore(ctx, target, strings.NewReader(`{"key":"not a backup"}`))
+	assert.ErrorContains(t, err, "not a kvstore backup stream")
+
+	_, err = Restore(ctx, target, strings.NewReader(`{"magic":"cilium-kvstore-backup","version":99}`))
+	assert.ErrorContains(t, err, "unsupported backup version")
+
+	_, err = Restore(ctx, target, strings.NewReader("garbage"))
+	assert.ErrorContains(t, err, "reading backup header")
+}
@@ -81,6 +81,75 @@ func TestGetSet(t *testing.T) {
 	require.Empty(t, pairs)
 }
 
+func TestListPrefixFunc(t *testing.T) {
+	testutils.IntegrationTest(t)
+	// Use a small batch size so that the iteration spans multiple pages.
+	client := SetupDummyWithConfigOpts(t, "etcd",
+		map[string]string{EtcdRateLimitOption: "100", EtcdListLimitOption: "3"})
+
+	prefix := "unit-test/"
+	maxID := 8
+
+	for i := range maxID {
+		require.NoError(t, client.Update(context.TODO(), testKey(prefix, i), []byte(testValue(i)), false))
+	}
+
+	pairs := KeyValuePairs{}
+	require.NoError(t, client.ListPrefixFunc(context.Background(), prefix, func(key string, value Value) bool {
+		pairs[key] = value
+		return true
+	}))
+	require.Len(t, pairs, maxID)
+	for i := range maxID {
+		require.Equal(t, testValue(i), string(pairs[testKey(prefix, i)].Data))
+	}
+
+	// The iteration stops early when the callback returns false.
+	var count int
+	require.NoError(t, client.ListPrefixFunc(context.Background(), prefix, func(key string, value Value) bool {
+		count++
+		return count < 4
+	}))
+	require.Equal(t, 4, count)
+}
+
+func TestListPrefixPaginated(t *testing.T) {
+	testutils.IntegrationTest(t)
+	client := SetupDummyWithConfigOpts(t, "etcd", etcdOpts)
+
+	prefix := "unit-test/"
+	maxID := 8
+
+	for i := range maxID {
+		require.NoError(t, client.Update(context.TODO(), testKey(prefix, i), []byte(testValue(i)), false))
+	}
+
+	// Use a page size smaller than the number of keys so that the iteration
+	// spans multiple pages.
+	pairs := KeyValuePairs{}
+	seq, errs := client.ListPrefixPaginated(context.Background(), prefix, 3)
+	for key, value := range seq {
+		pairs[key] = value
+	}
+	require.NoError(t, <-errs)
+	require.Len(t, pairs, maxID)
+	for i := range maxID {
+		require.Equal(t, testValue(i), string(pairs[testKey(prefix, i)].Data))
+	}
+
+	// Breaking out of the loop terminates the iteration early.
+	var count int
+	seq, errs = client.ListPrefixPaginated(context.Background(), prefix, 3)
+	for range seq {
+		count++
+		if count == 4 {
+			break
+		}
+	}
+	require.NoError(t, <-errs)
+	require.Equal(t, 4, count)
+}
+
 func BenchmarkGet(b *testing.B) {
 	testutils.IntegrationTest(b)
 	client := SetupDummyWithConfigOpts(b, "etcd", etcdOpts)
@@ -130,6 +199,35 @@ func TestUpdate(t *testing.T) {
 	require.Equal(t, testValue(0), string(val))
 }
 
+func TestUpdateBatch(t *testing.T) {
+	testutils.IntegrationTest(t)
+	client := SetupDummyWithConfigOpts(t, "etcd", etcdOpts)
+
+	prefix := "unit-test/"
+
+	// Span multiple transaction batches to exercise the chunking.
+	count := etcdMaxTxnOps*2 + 10
+	pairs := make([]KeyValue, 0, count)
+	for i := range count {
+		pairs = append(pairs, KeyValue{Key: testKey(prefix, i), Value: []byte(testValue(i))})
+	}
+	require.NoError(t, client.UpdateBatch(context.Background(), pairs, true))
+
+	stored, err := client.ListPrefix(context.Background(), prefix)
+	require.NoError(t, err)
+	require.Len(t, stored, count)
+	for i := range count {
+		require.Equal(t, testValue(i), string(stored[testKey(prefix, i)].Data))
+	}
+
+	// Batched updates overwrite existing keys.
+	require.NoError(t, client.UpdateBatch(context.Background(),
+		[]KeyValue{{Key: testKey(prefix, 0), Value: []byte("overwritten")}}, false))
+	val, err := client.Get(context.TODO(), testKey(prefix, 0))
+	require.NoError(t, err)
+	require.Equal(t, "overwritten", string(val))
+}
+
 func TestCreateOnly(t *testing.T) {
 	testutils.IntegrationTest(t)
 	client := SetupDummyWithConfigOpts(t, "etcd", etcdOpts)
@@ -153,10 +251,68 @@ func TestCreateOnly(t *testing.T) {
 	require.Equal(t, testValue(0), string(val))
 }
 
-func expectEvent(t *testing.T, events EventChan, typ EventType, key string, val string) {
+func TestTxn(t *testing.T) {
+	testutils.IntegrationTest(t)
+	client := SetupDummyWithConfigOpts(t, "etcd", etcdOpts)
+
+	prefix := "unit-test/"
+	key1, key2 := testKey(prefix, 1), testKey(prefix, 2)
+
+	// Create both keys in a single transaction, guarded on their absence.
+	success, err := client.Txn(context.Background(), []TxnCondition{
+		{Typ: TxnKeyAbsent, Key: key1},
+		{Typ: TxnKeyAbsent, Key: key2},
+	}, []TxnOp{
+		{Typ: TxnOpPut, Key: key1, Value: []byte(testValue(1)), Lease: true},
+		{Typ: TxnOpPut, Key: key2, Value: []byte(testValue(2))},
+	}, nil)
+	require.NoError(t, err)
+	require.True(t, success)
+
+	val, err := client.Get(context.TODO(), key1)
+	require.NoError(t, err)
+	require.Equal(t, testValue(1), string(val))
+
+	// Re-running the same transaction fails the guard, and the else branch
+	// is applied instead.
+	success, err = client.Txn(context.Background(), []TxnCondition{
+		{Typ: TxnKeyAbsent, Key: key1},
+	}, []TxnOp{
+		{Typ: TxnOpPut, Key: key1, Value: []byte("then")},
+	}, []TxnOp{
+		{Typ: TxnOpPut, Key: key1, Value: []byte("else")},
+		{Typ: TxnOpDelete, Key: key2},
+	})
+	require.NoError(t, err)
+	require.False(t, success)
+
+	val, err = client.Get(context.TODO(), key1)
+	require.NoError(t, err)
+	require.Equal(t, "else", string(val))
+
+	val, err = client.Get(context.TODO(), key2)
+	require.NoError(t, err)
+	require.Nil(t, val)
+
+	// Value comparisons hold only for the expected value.
+	success, err = client.Txn(context.Background(), []TxnCondition{
+		{Typ: TxnValueEquals, Key: key1, Value: []byte("else")},
+	}, []TxnOp{
+		{Typ: TxnOpDelete, Key: key1},
+	}, nil)
+	require.NoError(t, err)
+	require.True(t, success)
+
+	val, err = client.Get(context.TODO(), key1)
+	require.NoError(t, err)
+	require.Nil(t, val)
+}
+
+func expectEvent(t *testing.T, events EventChan, typ EventType, key string, val string, seq uint64) {
 	select {
 	case event := <-events:
 		require.Equal(t, typ, event.Typ)
+		require.Equal(t, seq, event.Seq)
 
 		if event.Typ != EventTypeListDone {
 			require.Equal(t, key, event.Key)
@@ -182,34 +338,77 @@ func TestListAndWatch(t *testing.T) {
 	events := client.ListAndWatch(ctx, "foo2/")
 	require.NotNil(t, t)
 
-	expectEvent(t, events, EventTypeCreate, key1, val1)
-	expectEvent(t, events, EventTypeListDone, "", "")
+	expectEvent(t, events, EventTypeCreate, key1, val1, 1)
+	expectEvent(t, events, EventTypeListDone, "", "", 2)
 
 	success, err = client.CreateOnly(context.Background(), key2, []byte(val2), false)
 	require.NoError(t, err)
 	require.True(t, success)
-	expectEvent(t, events, EventTypeCreate, key2, val2)
+	expectEvent(t, events, EventTypeCreate, key2, val2, 3)
 
 	err = client.Delete(context.TODO(), key1)
 	require.NoError(t, err)
-	expectEvent(t, events, EventTypeDelete, key1, val1)
+	expectEvent(t, events, EventTypeDelete, key1, val1, 4)
 
 	success, err = client.CreateOnly(context.Background(), key1, []byte(val1), false)
 	require.NoError(t, err)
 	require.True(t, success)
-	expectEvent(t, events, EventTypeCreate, key1, val1)
+	expectEvent(t, events, EventTypeCreate, key1, val1, 5)
 
 	err = client.Delete(context.TODO(), key1)
 	require.NoError(t, err)
-	expectEvent(t, events, EventTypeDelete, key1, val1)
+	expectEvent(t, events, EventTypeDelete, key1, val1, 6)
 
 	err = client.Delete(context.TODO(), key2)
 	require.NoError(t, err)
-	expectEvent(t, events, EventTypeDelete, key2, val2)
+	expectEvent(t, events, EventTypeDelete, key2, val2, 7)
+
+	cancel()
+
+	// A terminal drain event is emitted before the channel is closed.
+	expectEvent(t, events, EventTypeDrain, "", "", 8)
+	_, ok := <-events
+	require.False(t, ok, "Received unexpected event")
+}
+
+func TestWatchKey(t *testing.T) {
+	testutils.IntegrationTest(t)
+	client := SetupDummyWithConfigOpts(t, "etcd", etcdOpts)
+
+	key, sibling := "foo3/key", "foo3/key/sibling"
+	val1, val2 := "val1", "val2"
+
+	success, err := client.CreateOnly(context.Background(), key, []byte(val1), false)
+	require.NoError(t, err)
+	require.True(t, success)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := client.WatchKey(ctx, key)
+
+	expectEvent(t, events, EventTypeCreate, key, val1, 1)
+	expectEvent(t, events, EventTypeListDone, "", "", 2)
+
+	// Changes to other keys sharing the prefix are not reported.
+	success, err = client.CreateOnly(context.Background(), sibling, []byte(val2), false)
+	require.NoError(t, err)
+	require.True(t, success)
+
+	require.NoError(t, client.Update(context.TODO(), key, []byte(val2), false))
+	expectEvent(t, events, EventTypeModify, key, val2, 3)
+
+	err = client.Delete(context.TODO(), key)
+	require.NoError(t, err)
+	expectEvent(t, events, EventTypeDelete, key, val2, 4)
+
+	success, err = client.CreateOnly(context.Background(), key, []byte(val1), false)
+	require.NoError(t, err)
+	require.True(t, success)
+	expectEvent(t, events, EventTypeCreate, key, val1, 5)
 
 	cancel()
 
-	// Wait for the Events channel to be closed
+	// A terminal drain event is emitted before the channel is closed.
+	expectEvent(t, events, EventTypeDrain, "", "", 6)
 	_, ok := <-events
 	require.False(t, ok, "Received unexpected event")
 }
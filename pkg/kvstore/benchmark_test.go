@@ -0,0 +1,143 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cilium/cilium/pkg/testutils"
+)
+
+// benchNumKeys is the number of keys the benchmarks populate under the test
+// prefix, to approximate the prefix sizes observed in medium-sized clusters
+// (e.g., identities and ipcache entries).
+const benchNumKeys = 4096
+
+// populateKeys upserts numKeys keys under the given prefix in batches.
+func populateKeys(b *testing.B, client Client, prefix string, numKeys int) {
+	const batchSize = 256
+
+	for start := 0; start < numKeys; start += batchSize {
+		pairs := make([]KeyValue, 0, batchSize)
+		for i := start; i < min(start+batchSize, numKeys); i++ {
+			pairs = append(pairs, KeyValue{
+				Key:   testKey(prefix, i),
+				Value: []byte(testValue(i)),
+			})
+		}
+		require.NoError(b, client.UpdateBatch(context.Background(), pairs, false))
+	}
+}
+
+// drainUntilListDone consumes events until the initial listing completes,
+// returning the number of consumed events.
+func drainUntilListDone(b *testing.B, events EventChan) int {
+	var count int
+	for event := range events {
+		if event.Typ == EventTypeListDone {
+			return count
+		}
+		count++
+	}
+	b.Fatal("events channel closed before the initial listing completed")
+	return count
+}
+
+// BenchmarkListPrefixLarge measures listing a prefix holding benchNumKeys
+// keys, as performed by consumers taking periodic snapshots.
+func BenchmarkListPrefixLarge(b *testing.B) {
+	testutils.IntegrationTest(b)
+	client := SetupDummyWithConfigOpts(b, "etcd", etcdOpts)
+
+	prefix := "unit-test/"
+	populateKeys(b, client, prefix, benchNumKeys)
+
+	b.ResetTimer()
+	for b.Loop() {
+		pairs, err := client.ListPrefix(context.Background(), prefix)
+		require.NoError(b, err)
+		require.Len(b, pairs, benchNumKeys)
+	}
+}
+
+// BenchmarkListAndWatchInitialList measures the initial listing phase of a
+// watcher over a prefix holding benchNumKeys keys, which dominates the cost
+// of (re)starting watchers after connectivity issues.
+func BenchmarkListAndWatchInitialList(b *testing.B) {
+	testutils.IntegrationTest(b)
+	client := SetupDummyWithConfigOpts(b, "etcd", etcdOpts)
+
+	prefix := "unit-test/"
+	populateKeys(b, client, prefix, benchNumKeys)
+
+	b.ResetTimer()
+	for b.Loop() {
+		ctx, cancel := context.WithCancel(context.Background())
+		events := client.ListAndWatch(ctx, prefix)
+		require.Equal(b, benchNumKeys, drainUntilListDone(b, events))
+		cancel()
+
+		b.StopTimer()
+		for range events {
+		}
+		b.StartTimer()
+	}
+}
+
+// BenchmarkConcurrentWatchListUpdate measures update throughput over a
+// populated prefix while watchers consume the resulting events and a
+// background goroutine repeatedly lists the prefix, approximating the
+// steady-state load of an agent. It establishes the baseline for changes
+// to the watcher logic (e.g., watch multiplexing).
+func BenchmarkConcurrentWatchListUpdate(b *testing.B) {
+	const numWatchers = 4
+
+	testutils.IntegrationTest(b)
+	client := SetupDummyWithConfigOpts(b, "etcd", etcdOpts)
+
+	prefix := "unit-test/"
+	populateKeys(b, client, prefix, benchNumKeys)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for range numWatchers {
+		events := client.ListAndWatch(ctx, prefix)
+		drainUntilListDone(b, events)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range events {
+			}
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for ctx.Err() == nil {
+			_, _ = client.ListPrefix(ctx, prefix)
+		}
+	}()
+
+	var next atomic.Uint64
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			i := int(next.Add(1)) % benchNumKeys
+			require.NoError(b, client.Update(ctx, testKey(prefix, i), []byte(testValue(i)), false))
+		}
+	})
+	b.StopTimer()
+
+	cancel()
+	wg.Wait()
+}
@@ -5,6 +5,7 @@ package kvstore
 
 import (
 	"cmp"
+	"context"
 	"fmt"
 	"log/slog"
 
@@ -67,10 +68,65 @@ func Cell(defaultBackend string) cell.Cell {
 			return cl, hive.NewScriptCmds(cl.commands())
 		}),
 
+		// LazyClient defers the connection establishment until the first
+		// operation is issued, so that optional features depending on it do
+		// not force kvstore connectivity at startup.
+		cell.Provide(func(in struct {
+			cell.In
+
+			Logger    *slog.Logger
+			Lifecycle cell.Lifecycle
+			Config    Config
+			Opts      ExtraOptions `optional:"true"`
+		}) LazyClient {
+			if in.Config.KVStore == DisabledBackendName {
+				return &lazyClient{enabled: false}
+			}
+
+			in.Opts.LeaseTTL = cmp.Or(in.Opts.LeaseTTL, in.Config.KVStoreLeaseTTL)
+			in.Opts.MaxConsecutiveQuorumErrors = cmp.Or(in.Opts.MaxConsecutiveQuorumErrors,
+				in.Config.KVstoreMaxConsecutiveQuorumErrors)
+
+			lc := &lazyClient{
+				enabled: true, cfg: in.Config, opts: in.Opts,
+				connected: make(chan struct{}),
+				logger:    in.Logger.With(logfields.BackendName, in.Config.KVStore),
+			}
+
+			in.Lifecycle.Append(lc)
+			return lc
+		}),
+
+		// The watch warm-up coordinator opens the watches registered by the
+		// consumers once the client is available, and closes its synced
+		// channel when all of them completed their initial listing. The
+		// application may plug that channel into the BootstrapComplete
+		// option to lift the bootstrap rate limit once the warm-up is over.
+		cell.Provide(NewWatchWarmup),
+		cell.Invoke(startWatchWarmup),
+
 		cell.Invoke(Config.Validate),
 	)
 }
 
+// startWatchWarmup starts the watch warm-up coordinator once the client has
+// been started, and stops the watches on shutdown.
+func startWatchWarmup(lc cell.Lifecycle, client Client, warmup *WatchWarmup) {
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.Append(cell.Hook{
+		OnStart: func(cell.HookContext) error {
+			if client.IsEnabled() {
+				warmup.Start(ctx, client)
+			}
+			return nil
+		},
+		OnStop: func(cell.HookContext) error {
+			cancel()
+			return nil
+		},
+	})
+}
+
 type Config struct {
 	KVStore                           string
 	KVStoreOpt                        map[string]string
@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cilium/cilium/pkg/time"
+)
+
+// checkpointSaveInterval is the minimum interval between two persist
+// operations of the same watch revision checkpoint, bounding the extra I/O
+// introduced by checkpointing on busy prefixes.
+const checkpointSaveInterval = 30 * time.Second
+
+// RevisionCheckpoint persists the last revision processed by a ListAndWatch
+// watcher, so that it can resume watching from there after an agent restart,
+// instead of relisting the whole prefix.
+//
+// Resuming skips the initial list: deletions occurred while the watcher was
+// not running are not replayed, hence checkpointing is only suitable for
+// consumers whose own state survives restarts and which tolerate converging
+// on missed deletions through other means (e.g., periodic reconciliation).
+// A checkpointed revision which has been compacted in the meanwhile triggers
+// a full relist through the usual watch error handling.
+type RevisionCheckpoint interface {
+	// Load returns the last persisted revision, or zero if none is available.
+	Load() (int64, error)
+
+	// Save persists the given revision.
+	Save(revision int64) error
+}
+
+// WatchCheckpointProviderFunc returns the revision checkpoint store to be
+// used for the given prefix, or nil if the prefix must not be checkpointed.
+type WatchCheckpointProviderFunc func(prefix string) RevisionCheckpoint
+
+// NewFileCheckpointProvider returns a checkpoint provider storing the watch
+// revisions as files inside the given directory (e.g., a bpffs subdirectory,
+// to match the lifecycle of the state the consumers persist there). The file
+// name is derived from the watched prefix.
+func NewFileCheckpointProvider(dir string) WatchCheckpointProviderFunc {
+	return func(prefix string) RevisionCheckpoint {
+		// Include a hash to prevent collisions between prefixes mapping to
+		// the same sanitized name.
+		hash := sha256.Sum256([]byte(prefix))
+		name := fmt.Sprintf("%s-%s.rev",
+			strings.Trim(strings.ReplaceAll(prefix, "/", "_"), "_"),
+			hex.EncodeToString(hash[:8]),
+		)
+		return &fileRevisionCheckpoint{path: filepath.Join(dir, name)}
+	}
+}
+
+// fileRevisionCheckpoint persists the revision as a plain file, written
+// atomically through a rename to prevent partial reads upon a crash.
+type fileRevisionCheckpoint struct {
+	path string
+}
+
+func (f *fileRevisionCheckpoint) Load() (int64, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	revision, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil || revision < 0 {
+		return 0, fmt.Errorf("corrupted revision checkpoint %q", f.path)
+	}
+	return revision, nil
+}
+
+func (f *fileRevisionCheckpoint) Save(revision int64) error {
+	tmp := f.path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strconv.FormatInt(revision, 10)), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.path)
+}
@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileRevisionCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state_nodes_v1.rev")
+	checkpoint := &fileRevisionCheckpoint{path: path}
+
+	// Loading a non-existing checkpoint returns zero, without error.
+	revision, err := checkpoint.Load()
+	require.NoError(t, err, "Load should succeed if the checkpoint does not exist")
+	require.Zero(t, revision)
+
+	require.NoError(t, checkpoint.Save(1234), "Save should succeed")
+	revision, err = checkpoint.Load()
+	require.NoError(t, err, "Load should succeed")
+	require.EqualValues(t, 1234, revision)
+
+	// Subsequent saves overwrite the previous revision.
+	require.NoError(t, checkpoint.Save(5678), "Save should succeed")
+	revision, err = checkpoint.Load()
+	require.NoError(t, err, "Load should succeed")
+	require.EqualValues(t, 5678, revision)
+
+	// A corrupted checkpoint is reported as an error.
+	require.NoError(t, os.WriteFile(path, []byte("invalid"), 0o600))
+	_, err = checkpoint.Load()
+	require.ErrorContains(t, err, "corrupted revision checkpoint")
+}
+
+func TestNewFileCheckpointProvider(t *testing.T) {
+	dir := t.TempDir()
+	provider := NewFileCheckpointProvider(dir)
+
+	first := provider("cilium/state/nodes/v1")
+	second := provider("cilium/state/identities/v1")
+
+	require.NoError(t, first.Save(10), "Save should succeed")
+	require.NoError(t, second.Save(20), "Save should succeed")
+
+	// The checkpoints of different prefixes must not interfere.
+	revision, err := first.Load()
+	require.NoError(t, err, "Load should succeed")
+	require.EqualValues(t, 10, revision)
+
+	revision, err = second.Load()
+	require.NoError(t, err, "Load should succeed")
+	require.EqualValues(t, 20, revision)
+
+	// The same prefix maps to the same checkpoint.
+	revision, err = provider("cilium/state/nodes/v1").Load()
+	require.NoError(t, err, "Load should succeed")
+	require.EqualValues(t, 10, revision)
+}
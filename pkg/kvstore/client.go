@@ -98,6 +98,11 @@ func NewClient(ctx context.Context, logger *slog.Logger, selectedBackend string,
 		return nil, errChan
 	}
 
+	if err := options.validate(); err != nil {
+		errChan <- err
+		return nil, errChan
+	}
+
 	if err := module.setConfig(logger, opts); err != nil {
 		errChan <- err
 		return nil, errChan
@@ -24,9 +24,10 @@ func Commands(client Client) map[string]script.Cmd {
 
 	cmds := cmds{client: client}
 	return map[string]script.Cmd{
-		"kvstore/update": cmds.update(),
-		"kvstore/delete": cmds.delete(),
-		"kvstore/list":   cmds.list(),
+		"kvstore/update":   cmds.update(),
+		"kvstore/delete":   cmds.delete(),
+		"kvstore/list":     cmds.list(),
+		"kvstore/selftest": cmds.selftest(),
 	}
 }
 
@@ -132,3 +133,54 @@ func (c cmds) list() script.Cmd {
 		},
 	)
 }
+
+func (c cmds) selftest() script.Cmd {
+	return script.Command(
+		script.CmdUsage{
+			Summary: "run kvstore client self-test",
+			Args:    "",
+			Flags: func(fs *pflag.FlagSet) {
+				fs.StringP("output", "o", "plain", "Output format. One of: (plain, json)")
+			},
+			Detail: []string{
+				"Performs write, read, watch and lock round-trips against a",
+				"dedicated test prefix and reports the duration of each operation.",
+			},
+		},
+		func(s *script.State, args ...string) (script.WaitFunc, error) {
+			if len(args) != 0 {
+				return nil, fmt.Errorf("%w: no arguments expected", script.ErrUsage)
+			}
+
+			report := SelfTest(s.Context(), c.client)
+
+			return func(s *script.State) (stdout string, stderr string, err error) {
+				var b bytes.Buffer
+				outfmt, _ := s.Flags.GetString("output")
+				switch outfmt {
+				case "plain":
+					for _, step := range report.Steps {
+						outcome := "ok"
+						if step.Error != "" {
+							outcome = "error: " + step.Error
+						}
+						fmt.Fprintf(&b, "%-8s %-14s %s\n", step.Name, step.Duration, outcome)
+					}
+				case "json":
+					enc := json.NewEncoder(&b)
+					enc.SetIndent("", "  ")
+					if err := enc.Encode(report); err != nil {
+						return "", "", err
+					}
+				default:
+					return "", "", fmt.Errorf("unexpected output format %q", outfmt)
+				}
+
+				if !report.Success {
+					err = errors.New("kvstore self-test failed")
+				}
+				return b.String(), "", err
+			}, nil
+		},
+	)
+}
@@ -0,0 +1,169 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+// Package configdist implements a lightweight configuration distribution
+// mechanism on top of the kvstore. The operator publishes named
+// configuration blobs under a well-known prefix, and agents subscribe to
+// them with typed decoding, checksum validation and canary-based rollout
+// gating, without requiring apiserver round-trips.
+package configdist
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"path"
+
+	"github.com/cilium/cilium/pkg/kvstore"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+)
+
+// ConfigPrefix is the kvstore prefix under which distributed configurations
+// are published, one key per configuration name.
+const ConfigPrefix = kvstore.BaseKeyPrefix + "/configdist/v1"
+
+// envelope wraps a published configuration payload with the metadata
+// required for validation and rollout gating.
+type envelope struct {
+	// Checksum is the hex encoded SHA-256 checksum of the payload, verified
+	// by subscribers before decoding.
+	Checksum string `json:"checksum"`
+
+	// CanaryPercent restricts the rollout to the given percentage of
+	// subscribers, selected deterministically based on their identity.
+	// Zero means no gating, i.e., full rollout.
+	CanaryPercent uint8 `json:"canary-percent,omitempty"`
+
+	// Payload is the configuration blob itself.
+	Payload json.RawMessage `json:"payload"`
+}
+
+// PublishOptions tune how a configuration is rolled out.
+type PublishOptions struct {
+	// CanaryPercent, when in range (0, 100), restricts the rollout to the
+	// given percentage of subscribers. Zero means full rollout.
+	CanaryPercent uint8
+}
+
+// configKey returns the kvstore key a configuration is published under.
+func configKey(name string) string {
+	return path.Join(ConfigPrefix, name)
+}
+
+// Publish marshals the given configuration and writes it under the
+// distribution prefix, from where subscribers pick it up. The value must be
+// JSON-serializable into the type the subscribers decode.
+func Publish(ctx context.Context, client kvstore.BackendOperations, name string, value any, opts PublishOptions) error {
+	if opts.CanaryPercent > 100 {
+		return fmt.Errorf("invalid canary percent %d", opts.CanaryPercent)
+	}
+
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal configuration %q: %w", name, err)
+	}
+
+	checksum := sha256.Sum256(payload)
+	blob, err := json.Marshal(envelope{
+		Checksum:      hex.EncodeToString(checksum[:]),
+		CanaryPercent: opts.CanaryPercent,
+		Payload:       payload,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal envelope for configuration %q: %w", name, err)
+	}
+
+	return client.Update(ctx, configKey(name), blob, false)
+}
+
+// Unpublish removes a previously published configuration, causing the
+// subscribers to fall back to their defaults.
+func Unpublish(ctx context.Context, client kvstore.BackendOperations, name string) error {
+	return client.Delete(ctx, configKey(name))
+}
+
+// selected returns whether the subscriber identified by identity falls
+// within the canary percentage, based on a stable hash of the identity and
+// the configuration name.
+func selected(name, identity string, canaryPercent uint8) bool {
+	if canaryPercent == 0 || canaryPercent >= 100 {
+		return true
+	}
+
+	h := fnv.New32a()
+	fmt.Fprint(h, name, "/", identity)
+	return h.Sum32()%100 < uint32(canaryPercent)
+}
+
+// decode validates the envelope checksum and decodes the payload into T.
+func decode[T any](value []byte, name, identity string) (cfg T, apply bool, err error) {
+	var env envelope
+	if err := json.Unmarshal(value, &env); err != nil {
+		return cfg, false, fmt.Errorf("failed to unmarshal envelope: %w", err)
+	}
+
+	checksum := sha256.Sum256(env.Payload)
+	if expected := hex.EncodeToString(checksum[:]); env.Checksum != expected {
+		return cfg, false, fmt.Errorf("checksum mismatch: expected %s, got %s", expected, env.Checksum)
+	}
+
+	if !selected(name, identity, env.CanaryPercent) {
+		return cfg, false, nil
+	}
+
+	if err := json.Unmarshal(env.Payload, &cfg); err != nil {
+		return cfg, false, fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+	return cfg, true, nil
+}
+
+// Subscribe watches the configuration published under the given name,
+// invoking apply for every update addressed to this subscriber and reset
+// when the configuration is deleted or no longer addressed to it (e.g., the
+// canary percentage was lowered). Corrupted blobs are logged and skipped,
+// retaining the previously applied configuration. It blocks until the
+// context is cancelled.
+//
+// identity is the stable identity of the subscriber (typically the node
+// name), used for canary selection.
+func Subscribe[T any](ctx context.Context, logger *slog.Logger, client kvstore.BackendOperations, name, identity string, apply func(T), reset func()) {
+	key := configKey(name)
+	scopedLog := logger.With(logfields.Key, key)
+	applied := false
+
+	for event := range client.ListAndWatch(ctx, key) {
+		switch event.Typ {
+		case kvstore.EventTypeCreate, kvstore.EventTypeModify:
+			cfg, ok, err := decode[T](event.Value, name, identity)
+			if err != nil {
+				scopedLog.Warn("Ignoring invalid distributed configuration",
+					logfields.Error, err,
+				)
+				continue
+			}
+
+			if !ok {
+				// Not addressed to this subscriber. Reset in case a
+				// previously applied configuration got its canary
+				// percentage lowered.
+				if applied {
+					applied = false
+					reset()
+				}
+				continue
+			}
+
+			applied = true
+			apply(cfg)
+		case kvstore.EventTypeDelete:
+			if applied {
+				applied = false
+				reset()
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package configdist
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cilium/hive/hivetest"
+	"github.com/cilium/statedb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cilium/cilium/pkg/kvstore"
+)
+
+type testConfig struct {
+	Limit int `json:"limit"`
+}
+
+func TestPublishSubscribe(t *testing.T) {
+	client := kvstore.NewInMemoryClient(statedb.New(), "__local__")
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	applied := make(chan testConfig, 1)
+	resetted := make(chan struct{}, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Subscribe(ctx, hivetest.Logger(t), client, "rate-limit", "node-1",
+			func(cfg testConfig) { applied <- cfg },
+			func() { resetted <- struct{}{} },
+		)
+	}()
+
+	require.NoError(t, Publish(ctx, client, "rate-limit", testConfig{Limit: 10}, PublishOptions{}))
+	assert.Equal(t, testConfig{Limit: 10}, <-applied)
+
+	// Updates are propagated.
+	require.NoError(t, Publish(ctx, client, "rate-limit", testConfig{Limit: 20}, PublishOptions{}))
+	assert.Equal(t, testConfig{Limit: 20}, <-applied)
+
+	// A corrupted blob is skipped, retaining the previous configuration.
+	require.NoError(t, client.Update(ctx, ConfigPrefix+"/rate-limit", []byte(`{"checksum":"bad","payload":{"limit":30}}`), false))
+	require.NoError(t, Publish(ctx, client, "rate-limit", testConfig{Limit: 40}, PublishOptions{}))
+	assert.Equal(t, testConfig{Limit: 40}, <-applied)
+
+	// Deleting the configuration resets the subscriber.
+	require.NoError(t, Unpublish(ctx, client, "rate-limit"))
+	<-resetted
+
+	cancel()
+	<-done
+}
+
+func TestPublishInvalidCanary(t *testing.T) {
+	client := kvstore.NewInMemoryClient(statedb.New(), "__local__")
+	assert.Error(t, Publish(t.Context(), client, "cfg", testConfig{}, PublishOptions{CanaryPercent: 101}))
+}
+
+func TestCanarySelection(t *testing.T) {
+	// Full rollout applies to everyone.
+	assert.True(t, selected("cfg", "node-1", 0))
+	assert.True(t, selected("cfg", "node-1", 100))
+
+	// The selection is deterministic and roughly proportional to the
+	// canary percentage.
+	const nodes = 1000
+	count := 0
+	for i := range nodes {
+		identity := fmt.Sprintf("node-%d", i)
+		sel := selected("cfg", identity, 25)
+		assert.Equal(t, sel, selected("cfg", identity, 25))
+		if sel {
+			count++
+		}
+	}
+	assert.InDelta(t, nodes/4, count, nodes/10)
+}
@@ -10,21 +10,27 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"iter"
 	"log/slog"
-	"math/rand/v2"
 	"os"
+	"runtime/pprof"
+	"slices"
 	"strconv"
 	"strings"
+	"sync/atomic"
 
 	"go.etcd.io/etcd/api/v3/mvccpb"
 	v3rpcErrors "go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 	"go.etcd.io/etcd/client/pkg/v3/logutil"
 	client "go.etcd.io/etcd/client/v3"
 	"go.etcd.io/etcd/client/v3/concurrency"
+	"go.etcd.io/etcd/client/v3/namespace"
 	clientyaml "go.etcd.io/etcd/client/v3/yaml"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	grpcbackoff "google.golang.org/grpc/backoff"
 	"sigs.k8s.io/yaml"
 
 	"github.com/cilium/cilium/api/v1/models"
@@ -55,6 +61,11 @@ const (
 	// during bootstrapping
 	EtcdBootstrapRateLimitOption = "etcd.bootstrapQps"
 
+	// EtcdOptionAdaptiveRateLimit enables adaptively lowering the rate limit
+	// while the status checker observes quorum errors or degraded latencies,
+	// ramping back up to etcd.qps when healthy.
+	EtcdOptionAdaptiveRateLimit = "etcd.adaptiveQps"
+
 	// EtcdMaxInflightOption specifies maximum inflight concurrent kv store operations
 	EtcdMaxInflightOption = "etcd.maxInflight"
 
@@ -62,8 +73,61 @@ const (
 	// by ListAndWatch operations. A 0 value equals to no limit.
 	EtcdListLimitOption = "etcd.limit"
 
+	// EtcdOptionReadAffinity enables pinning serializable read operations to
+	// the etcd endpoint with the lowest observed latency.
+	EtcdOptionReadAffinity = "etcd.readAffinity"
+
+	// EtcdOptionCompactionRetention enables periodic compaction of the etcd
+	// revision history, retaining the given time window. A 0 value disables
+	// the built-in compactor.
+	EtcdOptionCompactionRetention = "etcd.compactionRetention"
+
+	// EtcdMaxRequestBytesOption configures the maximum size in bytes of a
+	// value accepted by write operations. Oversized values fail fast with
+	// ErrTooLarge instead of being rejected by etcd with an opaque gRPC
+	// error.
+	EtcdMaxRequestBytesOption = "etcd.maxRequestBytes"
+
+	// EtcdOptionNamespace prefixes all keys read and written by this client
+	// with the given namespace, so that multiple Cilium installations (or
+	// Cilium and other applications) can safely share one etcd cluster. The
+	// prefix is applied client-side to the KV, Watch and Lease interfaces,
+	// and is invisible to the rest of the agent.
+	EtcdOptionNamespace = "etcd.namespace"
+
+	// EtcdOptionWatchStallTimeout triggers a relist when a watcher receives
+	// neither events nor progress notifications within the given window,
+	// detecting silently broken watch streams. It should be set comfortably
+	// above the progress notification interval of the etcd server (10
+	// minutes by default). A 0 value disables the stall detector.
+	EtcdOptionWatchStallTimeout = "etcd.watchStallTimeout"
+
+	// EtcdOptionInitialConnectionTimeout bounds the time spent waiting for
+	// the initial connection to the etcd server to be established before the
+	// client gives up, e.g. to fail fast when a remote etcd is
+	// misconfigured.
+	EtcdOptionInitialConnectionTimeout = "etcd.initialConnectionTimeout"
+
+	// EtcdOptionRetryBackoffBase configures the initial delay applied by the
+	// underlying gRPC connection when retrying failed connection attempts.
+	EtcdOptionRetryBackoffBase = "etcd.retryBackoffBase"
+
+	// EtcdOptionRetryBackoffMax configures the maximum delay applied by the
+	// underlying gRPC connection when retrying failed connection attempts.
+	EtcdOptionRetryBackoffMax = "etcd.retryBackoffMax"
+
+	// EtcdOptionScopeTimeouts configures client-side operation deadlines per
+	// key scope (as derived via GetScopeFromKey), as a comma-separated list
+	// of scope=duration pairs, e.g. "ip/v1=5s,identities/v1=30s". Operations
+	// on keys of unlisted scopes have no client-side deadline.
+	EtcdOptionScopeTimeouts = "etcd.scopeTimeouts"
+
 	// etcdMaxKeysPerLease is the maximum number of keys that can be attached to a lease
 	etcdMaxKeysPerLease = 1000
+
+	// etcdDefaultMaxRequestBytes mirrors the default etcd server-side
+	// request size limit (--max-request-bytes, 1.5 MiB).
+	etcdDefaultMaxRequestBytes = 1536 * 1024
 )
 
 // ErrLockLeaseExpired is an error whenever the lease of the lock does not
@@ -75,6 +139,10 @@ var ErrLockLeaseExpired = errors.New("transaction did not succeed: lock lease ex
 // aborted, and should not be logged as an error.
 var ErrOperationAbortedByInterceptor = errors.New("operation aborted")
 
+// ErrTooLarge is returned by write operations when the value exceeds the
+// configured etcd request size limit. The request is never sent to etcd.
+var ErrTooLarge = errors.New("value size exceeds maximum etcd request size")
+
 type etcdModule struct {
 	opts backendOptions
 }
@@ -131,6 +199,13 @@ func newEtcdModule() backendModule {
 					return err
 				},
 			},
+			EtcdOptionAdaptiveRateLimit: &backendOption{
+				description: "Adaptively lower the rate limit while etcd is degraded, ramping back up to etcd.qps when healthy",
+				validate: func(v string) error {
+					_, err := strconv.ParseBool(v)
+					return err
+				},
+			},
 			EtcdMaxInflightOption: &backendOption{
 				description: "Maximum inflight concurrent kv store operations; defaults to etcd.qps if unset",
 				validate: func(v string) error {
@@ -145,6 +220,65 @@ func newEtcdModule() backendModule {
 					return err
 				},
 			},
+			EtcdMaxRequestBytesOption: &backendOption{
+				description: "Max size in bytes of a value accepted by write operations; oversized values fail fast with ErrTooLarge (0 = etcd default of 1.5 MiB)",
+				validate: func(v string) error {
+					_, err := strconv.Atoi(v)
+					return err
+				},
+			},
+			EtcdOptionScopeTimeouts: &backendOption{
+				description: "Per-scope operation deadlines as a comma-separated list of scope=duration pairs (empty = no client-side deadlines)",
+				validate: func(v string) error {
+					_, err := parseScopeTimeouts(v)
+					return err
+				},
+			},
+			EtcdOptionReadAffinity: &backendOption{
+				description: "Pin serializable read operations to the lowest-latency etcd endpoint",
+				validate: func(v string) error {
+					_, err := strconv.ParseBool(v)
+					return err
+				},
+			},
+			EtcdOptionCompactionRetention: &backendOption{
+				description: "Periodically compact the etcd revision history, retaining the given time window (0 = disabled)",
+				validate: func(v string) error {
+					_, err := time.ParseDuration(v)
+					return err
+				},
+			},
+			EtcdOptionNamespace: &backendOption{
+				description: "Prefix applied client-side to all keys, to share an etcd cluster among multiple installations",
+			},
+			EtcdOptionWatchStallTimeout: &backendOption{
+				description: "Trigger a relist when a watcher receives neither events nor progress notifications within the given window (0 = disabled)",
+				validate: func(v string) error {
+					_, err := time.ParseDuration(v)
+					return err
+				},
+			},
+			EtcdOptionInitialConnectionTimeout: &backendOption{
+				description: "Timeout after which an unsuccessful initial connection attempt to etcd is given up",
+				validate: func(v string) error {
+					_, err := time.ParseDuration(v)
+					return err
+				},
+			},
+			EtcdOptionRetryBackoffBase: &backendOption{
+				description: "Initial delay between retries of failed connection attempts to etcd",
+				validate: func(v string) error {
+					_, err := time.ParseDuration(v)
+					return err
+				},
+			},
+			EtcdOptionRetryBackoffMax: &backendOption{
+				description: "Maximum delay between retries of failed connection attempts to etcd",
+				validate: func(v string) error {
+					_, err := time.ParseDuration(v)
+					return err
+				},
+			},
 		},
 	}
 }
@@ -158,7 +292,7 @@ func (e *etcdModule) setConfig(logger *slog.Logger, opts map[string]string) erro
 }
 
 func shuffleEndpoints(endpoints []string) {
-	rand.Shuffle(len(endpoints), func(i, j int) {
+	sim.Shuffle(len(endpoints), func(i, j int) {
 		endpoints[i], endpoints[j] = endpoints[j], endpoints[i]
 	})
 }
@@ -169,20 +303,55 @@ type clientOptions struct {
 
 	KeepAliveHeartbeat time.Duration
 	KeepAliveTimeout   time.Duration
-	RateLimit          int
-	BootstrapRateLimit int
-	MaxInflight        int
-	ListBatchSize      int
+
+	// InitialConnectionTimeout bounds the initial connection attempt, while
+	// RetryBackoffBase and RetryBackoffMax shape the gRPC backoff applied
+	// between reconnection attempts (0 = gRPC defaults).
+	InitialConnectionTimeout time.Duration
+	RetryBackoffBase         time.Duration
+	RetryBackoffMax          time.Duration
+
+	RateLimit           int
+	BootstrapRateLimit  int
+	AdaptiveRateLimit   bool
+	MaxInflight         int
+	ListBatchSize       int
+	MaxRequestBytes     int
+	ReadAffinity        bool
+	CompactionRetention time.Duration
+	ScopeTimeouts       map[string]time.Duration
+	WatchStallTimeout   time.Duration
+	Namespace           string
+}
+
+// parseScopeTimeouts parses a comma-separated list of scope=duration pairs
+// into the per-scope operation deadlines.
+func parseScopeTimeouts(value string) (map[string]time.Duration, error) {
+	timeouts := map[string]time.Duration{}
+	for _, pair := range strings.Split(value, ",") {
+		scope, duration, found := strings.Cut(pair, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid scope timeout %q, expected scope=duration", pair)
+		}
+		timeout, err := time.ParseDuration(duration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout for scope %q: %w", scope, err)
+		}
+		timeouts[scope] = timeout
+	}
+	return timeouts, nil
 }
 
 func (e *etcdModule) newClient(ctx context.Context, logger *slog.Logger, opts ExtraOptions) (BackendOperations, chan error) {
 	errChan := make(chan error, 1)
 
 	clientOptions := clientOptions{
-		KeepAliveHeartbeat: 15 * time.Second,
-		KeepAliveTimeout:   25 * time.Second,
-		RateLimit:          defaults.KVstoreQPS,
-		ListBatchSize:      256,
+		KeepAliveHeartbeat:       15 * time.Second,
+		KeepAliveTimeout:         25 * time.Second,
+		InitialConnectionTimeout: initialConnectionTimeout,
+		RateLimit:                defaults.KVstoreQPS,
+		ListBatchSize:            256,
+		MaxRequestBytes:          etcdDefaultMaxRequestBytes,
 	}
 
 	if o, ok := e.opts[EtcdRateLimitOption]; ok && o.value != "" {
@@ -193,6 +362,10 @@ func (e *etcdModule) newClient(ctx context.Context, logger *slog.Logger, opts Ex
 		clientOptions.BootstrapRateLimit, _ = strconv.Atoi(o.value)
 	}
 
+	if o, ok := e.opts[EtcdOptionAdaptiveRateLimit]; ok && o.value != "" {
+		clientOptions.AdaptiveRateLimit, _ = strconv.ParseBool(o.value)
+	}
+
 	if o, ok := e.opts[EtcdMaxInflightOption]; ok && o.value != "" {
 		clientOptions.MaxInflight, _ = strconv.Atoi(o.value)
 	}
@@ -205,6 +378,34 @@ func (e *etcdModule) newClient(ctx context.Context, logger *slog.Logger, opts Ex
 		clientOptions.ListBatchSize, _ = strconv.Atoi(o.value)
 	}
 
+	if o, ok := e.opts[EtcdMaxRequestBytesOption]; ok && o.value != "" {
+		clientOptions.MaxRequestBytes, _ = strconv.Atoi(o.value)
+	}
+
+	if clientOptions.MaxRequestBytes == 0 {
+		clientOptions.MaxRequestBytes = etcdDefaultMaxRequestBytes
+	}
+
+	if o, ok := e.opts[EtcdOptionScopeTimeouts]; ok && o.value != "" {
+		clientOptions.ScopeTimeouts, _ = parseScopeTimeouts(o.value)
+	}
+
+	if o, ok := e.opts[EtcdOptionReadAffinity]; ok && o.value != "" {
+		clientOptions.ReadAffinity, _ = strconv.ParseBool(o.value)
+	}
+
+	if o, ok := e.opts[EtcdOptionCompactionRetention]; ok && o.value != "" {
+		clientOptions.CompactionRetention, _ = time.ParseDuration(o.value)
+	}
+
+	if o, ok := e.opts[EtcdOptionWatchStallTimeout]; ok && o.value != "" {
+		clientOptions.WatchStallTimeout, _ = time.ParseDuration(o.value)
+	}
+
+	if o, ok := e.opts[EtcdOptionNamespace]; ok {
+		clientOptions.Namespace = o.value
+	}
+
 	if o, ok := e.opts[EtcdOptionKeepAliveTimeout]; ok && o.value != "" {
 		clientOptions.KeepAliveTimeout, _ = time.ParseDuration(o.value)
 	}
@@ -213,6 +414,18 @@ func (e *etcdModule) newClient(ctx context.Context, logger *slog.Logger, opts Ex
 		clientOptions.KeepAliveHeartbeat, _ = time.ParseDuration(o.value)
 	}
 
+	if o, ok := e.opts[EtcdOptionInitialConnectionTimeout]; ok && o.value != "" {
+		clientOptions.InitialConnectionTimeout, _ = time.ParseDuration(o.value)
+	}
+
+	if o, ok := e.opts[EtcdOptionRetryBackoffBase]; ok && o.value != "" {
+		clientOptions.RetryBackoffBase, _ = time.ParseDuration(o.value)
+	}
+
+	if o, ok := e.opts[EtcdOptionRetryBackoffMax]; ok && o.value != "" {
+		clientOptions.RetryBackoffMax, _ = time.ParseDuration(o.value)
+	}
+
 	clientOptions.Endpoint = e.opts[EtcdAddrOption].value
 	clientOptions.ConfigPath = e.opts[EtcdOptionConfig].value
 
@@ -242,7 +455,7 @@ func (e *etcdModule) newClient(ctx context.Context, logger *slog.Logger, opts Ex
 			logger.Info("Waiting for all etcd configuration files to be available",
 				logfields.Error, err,
 			)
-			time.Sleep(5 * time.Second)
+			sim.Sleep(ctx, 5*time.Second)
 		case err != nil:
 			errChan <- err
 			close(errChan)
@@ -322,25 +535,102 @@ type etcdClient struct {
 	// purposes, associated with a shorter TTL
 	lockLeaseManager *etcdLeaseManager
 
-	// statusLock protects status for read/write access
-	statusLock lock.RWMutex
-
-	// status is a snapshot of the latest etcd cluster status
-	status models.Status
+	// status is an atomically-swapped immutable snapshot of the latest etcd
+	// cluster status, so that high-frequency pollers do not contend with the
+	// status checker.
+	status atomic.Pointer[statusSnapshot]
 
 	extraOptions ExtraOptions
 
-	limiter       *ciliumrate.APILimiter
-	listBatchSize int
+	limiter         *ciliumrate.APILimiter
+	listBatchSize   int
+	maxRequestBytes int
+
+	// adaptiveLimit adjusts the limiter rate based on the etcd health
+	// observed by the status checker. It is nil unless adaptive rate
+	// limiting has been enabled via EtcdOptionAdaptiveRateLimit.
+	adaptiveLimit *adaptiveRateLimit
+
+	// scopeTimeouts holds the client-side operation deadline for each key
+	// scope, as configured via EtcdOptionScopeTimeouts. It is initialized
+	// once and never written to again.
+	scopeTimeouts map[string]time.Duration
+
+	// watchStallTimeout is the window without watch events or progress
+	// notifications after which a watcher declares its stream stalled and
+	// relists, as configured via EtcdOptionWatchStallTimeout. Zero disables
+	// stall detection.
+	watchStallTimeout time.Duration
+
+	// initialConnectionTimeout bounds the initial connection attempt, as
+	// configured via EtcdOptionInitialConnectionTimeout.
+	initialConnectionTimeout time.Duration
+
+	// caReloader keeps the trusted CA bundle in sync with its on-disk
+	// contents. It is nil unless the etcd configuration references one.
+	caReloader *caReloader
+
+	// watchFairness grants the watchers access to the shared rate limiter
+	// in weighted round-robin order, so that an aggressively relisting
+	// watcher cannot starve the others.
+	watchFairness *watchFairness
 
 	lastHeartbeat time.Time
 
-	leaseExpiredObservers lock.Map[string, func(string)]
+	// affinity pins serializable reads to the nearest endpoint. It is nil
+	// unless read affinity has been enabled via EtcdOptionReadAffinity.
+	affinity *endpointAffinity
+
+	// connStateEvents receives the gRPC connectivity state transitions
+	// observed by monitorConnectionState.
+	connStateEvents chan ConnectionStateEvent
+
+	leaseExpiredObservers          lock.Map[string, func(string)]
+	leaseKeepaliveFailureObservers lock.Map[string, func(string)]
+
+	// watchRevisions tracks, per watched prefix, the revision the watcher
+	// has processed up to, so that the status checker can export the lag
+	// versus the cluster head revision.
+	watchRevisions lock.Map[string, *atomic.Int64]
+
+	// watchKeyCounts tracks, per watched prefix, the approximate number of
+	// live keys observed by the watcher, so that the status checker can
+	// export them as per-scope gauges without listing against etcd.
+	watchKeyCounts lock.Map[string, *atomic.Int64]
 
 	// logger is the scoped logger associated with this client
 	logger *slog.Logger
 }
 
+// statusSnapshot is an immutable snapshot of the etcd cluster status. The
+// changed channel is closed when the snapshot gets replaced by a newer one.
+type statusSnapshot struct {
+	models.Status
+
+	// details is the structured counterpart of the human-readable message,
+	// when available.
+	details *models.KVstoreStatus
+
+	changed chan struct{}
+}
+
+// setStatus atomically publishes a new status snapshot, and notifies the
+// subscribers of the previous one.
+func (e *etcdClient) setStatus(state string, msg string) {
+	e.setStatusWithDetails(state, msg, nil)
+}
+
+// setStatusWithDetails is the variant of setStatus additionally attaching the
+// structured representation of the status.
+func (e *etcdClient) setStatusWithDetails(state string, msg string, details *models.KVstoreStatus) {
+	old := e.status.Swap(&statusSnapshot{
+		Status:  models.Status{State: state, Msg: msg},
+		details: details,
+		changed: make(chan struct{}),
+	})
+	close(old.changed)
+}
+
 type etcdMutex struct {
 	mutex    *concurrency.Mutex
 	onUnlock func()
@@ -381,14 +671,14 @@ func (e *etcdClient) maybeWaitForInitLock(ctx context.Context) error {
 
 		// Generate a random number so that we can acquire a lock even
 		// if other agents are killed while locking this path.
-		randNumber := strconv.FormatUint(rand.Uint64(), 16)
+		randNumber := strconv.FormatUint(sim.Uint64(), 16)
 		locker, err := e.LockPath(ctx, InitLockPath+"/"+randNumber)
 		if err == nil {
 			locker.Unlock(context.Background())
 			e.logger.Debug("Distributed lock successful, etcd has quorum")
 			return nil
 		}
-		limiter.Wait(ctx)
+		sim.BackoffWait(ctx, &limiter)
 	}
 }
 
@@ -409,6 +699,7 @@ func connectEtcdClient(ctx context.Context, logger *slog.Logger, errChan chan er
 		Endpoints: []string{clientOptions.Endpoint},
 	}
 
+	var caRel *caReloader
 	if cfgPath := clientOptions.ConfigPath; cfgPath != "" {
 		cfg, err := clientyaml.NewConfig(cfgPath)
 		if err != nil {
@@ -419,6 +710,21 @@ func connectEtcdClient(ctx context.Context, logger *slog.Logger, errChan chan er
 			if err != nil {
 				return nil, err
 			}
+
+			// Hot-reload the trusted CA bundle as well, so that a CA
+			// rotation does not require an agent restart.
+			caFile, err := getTrustedCAFile(cfgPath)
+			if err != nil {
+				return nil, err
+			}
+			if caFile != "" {
+				caRel, err = newCAReloader(logger, caFile)
+				if err != nil {
+					return nil, err
+				}
+				cfg.DialOptions = append(cfg.DialOptions,
+					grpc.WithTransportCredentials(newReloadingCredentials(cfg.TLS, caRel)))
+			}
 		}
 		config = cfg
 	}
@@ -434,6 +740,11 @@ func connectEtcdClient(ctx context.Context, logger *slog.Logger, errChan chan er
 	config.Context = ctx
 	// Configure the dial options provided by the caller.
 	config.DialOptions = append(config.DialOptions, opts.DialOption...)
+	// Attach the delegated per-RPC credentials, if configured.
+	if opts.CredentialProvider != nil {
+		config.DialOptions = append(config.DialOptions,
+			grpc.WithPerRPCCredentials(newTokenCredentials(logger, opts.CredentialProvider)))
+	}
 	// Set DialTimeout to 0, otherwise the creation of a new client will
 	// block until DialTimeout is reached or a connection to the server
 	// is made.
@@ -444,6 +755,20 @@ func connectEtcdClient(ctx context.Context, logger *slog.Logger, errChan chan er
 	// connection. Ideally it should be lower than staleLockTimeout
 	config.DialKeepAliveTimeout = clientOptions.KeepAliveTimeout
 
+	// Shape the backoff applied between retries of failed connection
+	// attempts, keeping the gRPC defaults for the parameters not configured.
+	if clientOptions.RetryBackoffBase > 0 || clientOptions.RetryBackoffMax > 0 {
+		bc := grpcbackoff.DefaultConfig
+		if clientOptions.RetryBackoffBase > 0 {
+			bc.BaseDelay = clientOptions.RetryBackoffBase
+		}
+		if clientOptions.RetryBackoffMax > 0 {
+			bc.MaxDelay = clientOptions.RetryBackoffMax
+		}
+		config.DialOptions = append(config.DialOptions,
+			grpc.WithConnectParams(grpc.ConnectParams{Backoff: bc}))
+	}
+
 	// Use the shared etcd client logger to prevent unnecessary allocations.
 	config.Logger = etcd3ClientLogger
 
@@ -452,23 +777,42 @@ func connectEtcdClient(ctx context.Context, logger *slog.Logger, errChan chan er
 		return nil, err
 	}
 
+	// Apply the client-side namespace, so that all keys read and written
+	// through this client are transparently prefixed with it.
+	if ns := clientOptions.Namespace; ns != "" {
+		c.KV = namespace.NewKV(c.KV, ns)
+		c.Watcher = namespace.NewWatcher(c.Watcher, ns)
+		c.Lease = namespace.NewLease(c.Lease, ns)
+	}
+
 	ec := &etcdClient{
-		client: c,
-		config: config,
-		status: models.Status{
-			State: models.StatusStateWarning,
-			Msg:   "Waiting for initial connection to be established",
-		},
-		stopStatusChecker: make(chan struct{}),
-		extraOptions:      opts,
-		listBatchSize:     clientOptions.ListBatchSize,
-		statusCheckErrors: make(chan error, 128),
+		client:                   c,
+		config:                   config,
+		stopStatusChecker:        make(chan struct{}),
+		extraOptions:             opts,
+		listBatchSize:            clientOptions.ListBatchSize,
+		maxRequestBytes:          clientOptions.MaxRequestBytes,
+		scopeTimeouts:            clientOptions.ScopeTimeouts,
+		watchStallTimeout:        clientOptions.WatchStallTimeout,
+		initialConnectionTimeout: cmp.Or(clientOptions.InitialConnectionTimeout, initialConnectionTimeout),
+		caReloader:               caRel,
+		statusCheckErrors:        make(chan error, 128),
+		connStateEvents:          make(chan ConnectionStateEvent, connStateEventsBufferSize),
+		watchFairness:            newWatchFairness(),
 		logger: logger.With(
 			logfields.Endpoints, config.Endpoints,
 			logfields.Config, clientOptions.ConfigPath,
 		),
 	}
 
+	ec.status.Store(&statusSnapshot{
+		Status: models.Status{
+			State: models.StatusStateWarning,
+			Msg:   "Waiting for initial connection to be established",
+		},
+		changed: make(chan struct{}),
+	})
+
 	initialLimit := clientOptions.RateLimit
 	// If BootstrapRateLimit and BootstrapComplete are provided, set the
 	// initial rate limit to BootstrapRateLimit and apply the standard rate limit
@@ -498,11 +842,30 @@ func connectEtcdClient(ctx context.Context, logger *slog.Logger, errChan chan er
 		ParallelRequests: clientOptions.MaxInflight,
 	}, ciliumratemetrics.APILimiterObserver())
 
+	if clientOptions.AdaptiveRateLimit {
+		ec.adaptiveLimit = newAdaptiveRateLimit(ec.logger, ec.limiter, clientOptions.RateLimit, opts.BootstrapComplete)
+	}
+
+	if clientOptions.ReadAffinity {
+		ec.affinity = newEndpointAffinity(ec.logger, c, *config)
+		ec.affinity.start()
+	}
+
+	go ec.monitorConnectionState()
+
+	if clientOptions.CompactionRetention > 0 {
+		go ec.runCompactor(clientOptions.CompactionRetention)
+	}
+
 	ec.logger.Info("Connecting to etcd server...")
 
 	leaseTTL := cmp.Or(opts.LeaseTTL, defaults.KVstoreLeaseTTL)
-	ec.leaseManager = newEtcdLeaseManager(ec.logger, c, leaseTTL, etcdMaxKeysPerLease, ec.expiredLeaseObserver)
-	ec.lockLeaseManager = newEtcdLeaseManager(ec.logger, c, defaults.LockLeaseTTL, etcdMaxKeysPerLease, nil)
+	ec.leaseManager = newEtcdLeaseManager(ec.logger, c, leaseTTL, etcdMaxKeysPerLease, ec.expiredLeaseObserver, ec.keepaliveFailureObserver)
+	ec.lockLeaseManager = newEtcdLeaseManager(ec.logger, c, defaults.LockLeaseTTL, etcdMaxKeysPerLease, nil, nil)
+
+	// Only the key leases are consolidated: the lock logic depends on the
+	// specific lease a given lock is attached to.
+	ec.leaseManager.startConsolidator()
 
 	go ec.asyncConnectEtcdClient(errChan)
 
@@ -516,10 +879,8 @@ func (e *etcdClient) asyncConnectEtcdClient(errChan chan<- error) {
 	)
 
 	propagateError := func(err error) {
-		e.statusLock.Lock()
-		e.status.State = models.StatusStateFailure
-		e.status.Msg = fmt.Sprintf("Failed to establish initial connection: %s", err.Error())
-		e.statusLock.Unlock()
+		e.setStatus(models.StatusStateFailure,
+			fmt.Sprintf("Failed to establish initial connection: %s", err.Error()))
 
 		errChan <- err
 		close(errChan)
@@ -528,7 +889,7 @@ func (e *etcdClient) asyncConnectEtcdClient(errChan chan<- error) {
 		close(e.statusCheckErrors)
 	}
 
-	wctx, wcancel := context.WithTimeout(ctx, initialConnectionTimeout)
+	wctx, wcancel := context.WithTimeout(ctx, e.initialConnectionTimeout)
 
 	// Don't create a session when running with lock quorum check disabled
 	// (i.e., for clustermesh clients), to not introduce unnecessary overhead
@@ -568,7 +929,7 @@ func (e *etcdClient) asyncConnectEtcdClient(errChan chan<- error) {
 		e.statusChecker()
 	}()
 
-	events := e.ListAndWatch(ctx, HeartbeatPath)
+	events := e.WatchKey(ctx, HeartbeatPath)
 	for event := range events {
 		switch event.Typ {
 		case EventTypeDelete:
@@ -580,6 +941,10 @@ func (e *etcdClient) asyncConnectEtcdClient(errChan chan<- error) {
 			// is also not an heartbeat signal.
 			close(listDone)
 			continue
+
+		case EventTypeDrain:
+			// The terminal event before the channel is closed.
+			continue
 		}
 
 		// It is tempting to compare against the heartbeat value stored in
@@ -638,6 +1003,8 @@ func (e *etcdClient) LockPath(ctx context.Context, path string) (locker KVLocker
 }
 
 func (e *etcdClient) DeletePrefix(ctx context.Context, path string) (err error) {
+	ctx, cancel := e.opTimeout(ctx, path)
+	defer cancel()
 	defer func() {
 		Trace(e.logger, "DeletePrefix",
 			logfields.Error, err,
@@ -651,38 +1018,445 @@ func (e *etcdClient) DeletePrefix(ctx context.Context, path string) (err error)
 
 	defer func(duration *spanstat.SpanStat) {
 		increaseMetric(path, metricDelete, "DeletePrefix", duration.EndError(err).Total(), err)
+		recordWrite(path, err)
+	}(spanstat.Start())
+
+	_, err = e.client.Delete(ctx, path, client.WithPrefix())
+	// Using lr.Error for convenience, as it matches lr.Done() when err is nil
+	lr.Error(err, -1)
+
+	if err == nil {
+		e.leaseManager.ReleasePrefix(path)
+	}
+
+	return Hint(err)
+}
+
+// DeletePrefixIfLocked deletes the complete keyspace of the given prefix if
+// the client is still holding the given lock, performing the range delete
+// inside a transaction guarded by the lock comparator.
+func (e *etcdClient) DeletePrefixIfLocked(ctx context.Context, path string, lock KVLocker) (err error) {
+	ctx, cancel := e.opTimeout(ctx, path)
+	defer cancel()
+	if traceEnabled {
+		defer func() {
+			Trace(e.logger, "DeletePrefixIfLocked",
+				logfields.Error, err,
+				fieldPrefix, path,
+			)
+		}()
+	}
+	lr, err := e.limiter.Wait(ctx)
+	if err != nil {
+		return Hint(err)
+	}
+	defer func(duration *spanstat.SpanStat) {
+		increaseMetric(path, metricDelete, "DeletePrefixLocked", duration.EndError(err).Total(), err)
+		recordWrite(path, err)
 	}(spanstat.Start())
 
-	_, err = e.client.Delete(ctx, path, client.WithPrefix())
-	// Using lr.Error for convenience, as it matches lr.Done() when err is nil
-	lr.Error(err, -1)
+	opDel := client.OpDelete(path, client.WithPrefix())
+	cmp := lock.Comparator().(client.Cmp)
+	txnReply, err := e.client.Txn(ctx).If(cmp).Then(opDel).Commit()
+	if err == nil && !txnReply.Succeeded {
+		err = ErrLockLeaseExpired
+	}
+	if err == nil {
+		e.leaseManager.ReleasePrefix(path)
+	}
+
+	// Using lr.Error for convenience, as it matches lr.Done() when err is nil
+	lr.Error(err, -1)
+	return Hint(err)
+}
+
+// watchDrainReason derives the reason carried by the terminal drain event
+// of a stopped watcher, distinguishing a shutdown requested by the consumer
+// from the etcd client being closed.
+func (e *etcdClient) watchDrainReason(ctx context.Context) error {
+	if err := context.Cause(ctx); err != nil {
+		return err
+	}
+	if err := context.Cause(e.client.Ctx()); err != nil {
+		return fmt.Errorf("etcd client closed: %w", err)
+	}
+	return nil
+}
+
+// watchLimiterWait waits on the shared rate limiter after being granted a
+// turn by the watcher fairness queue, so that watchers of different prefixes
+// are admitted in weighted round-robin order, FIFO within each scope.
+func (e *etcdClient) watchLimiterWait(ctx context.Context, scope string) (ciliumrate.LimitedRequest, error) {
+	if err := e.watchFairness.wait(ctx, scope); err != nil {
+		return nil, err
+	}
+	defer e.watchFairness.done()
+	return e.limiter.Wait(ctx)
+}
+
+// watch starts watching for changes in a prefix
+func (e *etcdClient) watch(ctx context.Context, prefix string, events emitter) {
+	localCache := watcherCache{}
+	listSignalSent := false
+	scope := GetScopeFromKey(prefix)
+
+	scopedLog := e.logger.With(fieldPrefix, prefix)
+	scopedLog.Info("Starting watcher")
+
+	// revisionTracker publishes the revision this watcher has processed up
+	// to, for the watch lag gauge exported by the status checker.
+	revisionTracker := &atomic.Int64{}
+	e.watchRevisions.Store(prefix, revisionTracker)
+	defer e.watchRevisions.Delete(prefix)
+
+	// keyCountTracker publishes the number of keys this watcher currently
+	// considers alive, for the per-prefix key count gauge exported by the
+	// status checker.
+	keyCountTracker := &atomic.Int64{}
+	e.watchKeyCounts.Store(prefix, keyCountTracker)
+	defer e.watchKeyCounts.Delete(prefix)
+
+	// When a checkpoint store is configured for this prefix, attempt to resume
+	// from the last processed revision, and periodically persist the progress.
+	var checkpoint RevisionCheckpoint
+	var resumeRev int64
+	if e.extraOptions.WatchCheckpointProvider != nil {
+		checkpoint = e.extraOptions.WatchCheckpointProvider(prefix)
+	}
+
+	if checkpoint != nil {
+		if rev, err := checkpoint.Load(); err != nil {
+			scopedLog.Info("Unable to load watch revision checkpoint, relisting all keys",
+				logfields.Error, err,
+			)
+		} else if rev > 0 {
+			resumeRev = rev + 1
+		}
+	}
+
+	var lastSave time.Time
+	saveCheckpoint := func(revision int64) {
+		if checkpoint == nil || time.Since(lastSave) < checkpointSaveInterval {
+			return
+		}
+
+		// Refresh the timestamp also upon failure, to avoid hammering a
+		// broken store on busy prefixes.
+		lastSave = time.Now()
+		if err := checkpoint.Save(revision); err != nil {
+			scopedLog.Info("Unable to persist watch revision checkpoint",
+				logfields.Error, err,
+				fieldRev, revision,
+			)
+		}
+	}
+
+	defer func() {
+		scopedLog.Info("Stopped watcher")
+		events.drain(e.watchDrainReason(ctx))
+	}()
+
+	// errLimiter is used to rate limit the retry of the first Get request in case an error
+	// has occurred, to prevent overloading the etcd server due to the more aggressive
+	// default rate limiter.
+	errLimiter := e.newExpBackoffRateLimiter("etcd-list-before-watch-error")
+
+	// stallTimer fires when neither watch events nor progress notifications
+	// have been received within the configured window, declaring the watch
+	// stream silently broken. It is armed once the watcher is established
+	// and reset on every response.
+	var stallTimer *time.Timer
+	var stallC <-chan time.Time
+	if e.watchStallTimeout > 0 {
+		stallTimer = time.NewTimerWithoutMaxDelay(e.watchStallTimeout)
+		stallTimer.Stop()
+		defer stallTimer.Stop()
+		stallC = stallTimer.C
+	}
+
+	resetStallTimer := func() {
+		if stallTimer == nil {
+			return
+		}
+		if !stallTimer.Stop() {
+			select {
+			case <-stallTimer.C:
+			default:
+			}
+		}
+		stallTimer.Reset(e.watchStallTimeout)
+	}
+
+	var nextRev int64
+
+reList:
+	for {
+		select {
+		case <-e.client.Ctx().Done():
+			return
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		if resumeRev > 0 {
+			// Resume from the checkpointed revision, skipping the initial
+			// list: the deltas since the checkpoint are delivered through the
+			// watch below. If the revision has been compacted in the
+			// meanwhile, the watch errors out and triggers a full relist.
+			scopedLog.Info(
+				"Resuming watcher from checkpointed revision",
+				fieldRev, resumeRev,
+			)
+
+			nextRev = resumeRev
+			resumeRev = 0
+			revisionTracker.Store(nextRev - 1)
+		} else {
+			recordRelist(scope)
+			lr, err := e.watchLimiterWait(ctx, scope)
+			if err != nil {
+				continue
+			}
+			kvs, revision, err := e.paginatedList(ctx, scopedLog, prefix)
+			if err != nil {
+				lr.Error(err, -1)
+
+				if attempt := errLimiter.Attempt(); attempt < 10 {
+					scopedLog.Info(
+						"Unable to list keys before starting watcher, will retry",
+						logfields.Error, Hint(err),
+						logfields.Attempt, attempt,
+					)
+				} else {
+					scopedLog.Warn(
+						"Unable to list keys before starting watcher, will retry",
+						logfields.Error, Hint(err),
+						logfields.Attempt, attempt,
+					)
+				}
+
+				sim.BackoffWait(ctx, &errLimiter)
+				continue
+			}
+			lr.Done()
+			errLimiter.Reset()
+
+			scopedLog.Info(
+				"Successfully listed keys before starting watcher",
+				logfields.Count, len(kvs),
+				fieldRev, revision,
+			)
+
+			for _, key := range kvs {
+				t := EventTypeCreate
+				if localCache.Exists(key.Key) {
+					t = EventTypeModify
+				}
+
+				localCache.MarkInUse(key.Key)
+
+				if traceEnabled {
+					scopedLog.Debug("Emitting list result",
+						logfields.EventType, t,
+						logfields.Key, key.Key,
+						logfields.Value, key.Value,
+					)
+				}
+
+				if !events.emit(ctx, KeyValueEvent{
+					Key:   string(key.Key),
+					Value: key.Value,
+					Typ:   t,
+				}) {
+					return
+				}
+			}
+
+			nextRev = revision + 1
+			revisionTracker.Store(revision)
+			saveCheckpoint(revision)
+
+			// Send out deletion events for all keys that were deleted
+			// between our last known revision and the latest revision
+			// received via Get
+			if !localCache.RemoveDeleted(func(k string) bool {
+				event := KeyValueEvent{
+					Key: k,
+					Typ: EventTypeDelete,
+				}
+
+				if traceEnabled {
+					scopedLog.Debug("Emitting EventTypeDelete event",
+						logfields.Key, k,
+					)
+				}
+				return events.emit(ctx, event)
+			}) {
+				return
+			}
+
+			keyCountTracker.Store(int64(len(localCache)))
+		}
+
+		// Only send the list signal once
+		if !listSignalSent {
+			if !events.emit(ctx, KeyValueEvent{Typ: EventTypeListDone}) {
+				return
+			}
+			listSignalSent = true
+		}
+
+	recreateWatcher:
+		scopedLog.Info(
+			"Starting to watch prefix",
+			fieldRev, nextRev,
+		)
+
+		lr, err := e.watchLimiterWait(ctx, scope)
+		if err != nil {
+			select {
+			case <-e.client.Ctx().Done():
+				return
+			case <-ctx.Done():
+				return
+			default:
+				goto recreateWatcher
+			}
+		}
+
+		// Progress notifications are requested so that the stall detector
+		// can tell an idle prefix apart from a broken watch stream.
+		etcdWatch := e.client.Watch(client.WithRequireLeader(ctx), prefix,
+			client.WithPrefix(), client.WithRev(nextRev), client.WithProgressNotify())
+		lr.Done()
+		resetStallTimer()
+
+		for {
+			select {
+			case <-e.client.Ctx().Done():
+				return
+			case <-ctx.Done():
+				return
+			case <-stallC:
+				scopedLog.Warn(
+					"No watch events or progress notifications received within the stall timeout. Triggering relist of all keys",
+					logfields.Timeout, e.watchStallTimeout,
+					fieldRev, nextRev,
+				)
+
+				localCache.MarkAllForDeletion()
+
+				goto reList
+			case r, ok := <-etcdWatch:
+				resetStallTimer()
+				if !ok {
+					sim.Sleep(ctx, 50*time.Millisecond)
+					goto recreateWatcher
+				}
+
+				if err := r.Err(); err != nil {
+					switch {
+					case errors.Is(err, ErrOperationAbortedByInterceptor):
+						// Aborted on purpose by a custom interceptor.
+						scopedLog.Debug("Etcd watcher aborted",
+							logfields.Error, Hint(err),
+							fieldRev, r.Header.Revision,
+						)
+					case errors.Is(err, v3rpcErrors.ErrCompacted):
+						// We tried to watch on a compacted
+						// revision that may no longer exist,
+						// recreate the watcher and try to
+						// watch on the next possible revision
+						scopedLog.Info("Tried watching on compacted revision. Triggering relist of all keys",
+							logfields.Error, Hint(err),
+							fieldRev, r.Header.Revision,
+						)
+					default:
+						scopedLog.Info("Etcd watcher errored. Triggering relist of all keys",
+							logfields.Error, Hint(err),
+							fieldRev, r.Header.Revision,
+						)
+					}
+
+					// mark all local keys in state for
+					// deletion unless the upcoming GET
+					// marks them alive
+					localCache.MarkAllForDeletion()
+
+					goto reList
+				}
+
+				nextRev = r.Header.Revision + 1
+				revisionTracker.Store(r.Header.Revision)
+				if traceEnabled {
+					scopedLog.Debug("Received event from etcd",
+						logfields.Response, r,
+					)
+				}
+
+				for _, ev := range r.Events {
+					event := KeyValueEvent{
+						Key:   string(ev.Kv.Key),
+						Value: ev.Kv.Value,
+					}
+
+					switch {
+					case ev.Type == client.EventTypeDelete:
+						event.Typ = EventTypeDelete
+						localCache.RemoveKey(ev.Kv.Key)
+					case ev.IsCreate():
+						event.Typ = EventTypeCreate
+						localCache.MarkInUse(ev.Kv.Key)
+					default:
+						event.Typ = EventTypeModify
+						localCache.MarkInUse(ev.Kv.Key)
+					}
+
+					if traceEnabled {
+						scopedLog.Debug("Emitting event",
+							logfields.EventType, event.Typ,
+							logfields.Key, event.Key,
+							logfields.Value, event.Value,
+						)
+					}
+					if !events.emit(ctx, event) {
+						return
+					}
+				}
 
-	if err == nil {
-		e.leaseManager.ReleasePrefix(path)
+				keyCountTracker.Store(int64(len(localCache)))
+				saveCheckpoint(r.Header.Revision)
+			}
+		}
 	}
-
-	return Hint(err)
 }
 
-// watch starts watching for changes in a prefix
-func (e *etcdClient) watch(ctx context.Context, prefix string, events emitter) {
-	localCache := watcherCache{}
+// watchKey starts watching for changes of a single key. Compared to watch,
+// it retrieves single values instead of paginated lists, and does not need
+// the watcher cache bookkeeping, as the state to reconcile after a watch
+// error is a single value.
+func (e *etcdClient) watchKey(ctx context.Context, key string, events emitter) {
+	// known tracks whether the consumer has last been notified about the
+	// key existing, to emit the correct event type after relists.
+	known := false
 	listSignalSent := false
+	scope := GetScopeFromKey(key)
 
-	scopedLog := e.logger.With(fieldPrefix, prefix)
-	scopedLog.Info("Starting watcher")
+	scopedLog := e.logger.With(fieldKey, key)
+	scopedLog.Info("Starting single-key watcher")
 
 	defer func() {
-		scopedLog.Info("Stopped watcher")
-		events.close()
+		scopedLog.Info("Stopped single-key watcher")
+		events.drain(e.watchDrainReason(ctx))
 	}()
 
 	// errLimiter is used to rate limit the retry of the first Get request in case an error
 	// has occurred, to prevent overloading the etcd server due to the more aggressive
 	// default rate limiter.
-	errLimiter := e.newExpBackoffRateLimiter("etcd-list-before-watch-error")
+	errLimiter := e.newExpBackoffRateLimiter("etcd-get-before-watch-error")
 
-reList:
+reGet:
 	for {
 		select {
 		case <-e.client.Ctx().Done():
@@ -692,86 +1466,62 @@ reList:
 		default:
 		}
 
-		lr, err := e.limiter.Wait(ctx)
+		lr, err := e.watchLimiterWait(ctx, scope)
 		if err != nil {
 			continue
 		}
-		kvs, revision, err := e.paginatedList(ctx, scopedLog, prefix)
+		res, err := e.serializableKV().Get(ctx, key, client.WithSerializable())
 		if err != nil {
 			lr.Error(err, -1)
 
 			if attempt := errLimiter.Attempt(); attempt < 10 {
 				scopedLog.Info(
-					"Unable to list keys before starting watcher, will retry",
+					"Unable to retrieve key before starting watcher, will retry",
 					logfields.Error, Hint(err),
 					logfields.Attempt, attempt,
 				)
 			} else {
 				scopedLog.Warn(
-					"Unable to list keys before starting watcher, will retry",
+					"Unable to retrieve key before starting watcher, will retry",
 					logfields.Error, Hint(err),
 					logfields.Attempt, attempt,
 				)
 			}
 
-			errLimiter.Wait(ctx)
+			sim.BackoffWait(ctx, &errLimiter)
 			continue
 		}
 		lr.Done()
 		errLimiter.Reset()
 
-		scopedLog.Info(
-			"Successfully listed keys before starting watcher",
-			logfields.Count, len(kvs),
-			fieldRev, revision,
-		)
-
-		for _, key := range kvs {
+		if len(res.Kvs) > 0 {
 			t := EventTypeCreate
-			if localCache.Exists(key.Key) {
+			if known {
 				t = EventTypeModify
 			}
-
-			localCache.MarkInUse(key.Key)
-
-			if traceEnabled {
-				scopedLog.Debug("Emitting list result",
-					logfields.EventType, t,
-					logfields.Key, key.Key,
-					logfields.Value, key.Value,
-				)
-			}
+			known = true
 
 			if !events.emit(ctx, KeyValueEvent{
-				Key:   string(key.Key),
-				Value: key.Value,
+				Key:   key,
+				Value: res.Kvs[0].Value,
 				Typ:   t,
 			}) {
 				return
 			}
-		}
-
-		nextRev := revision + 1
-
-		// Send out deletion events for all keys that were deleted
-		// between our last known revision and the latest revision
-		// received via Get
-		if !localCache.RemoveDeleted(func(k string) bool {
-			event := KeyValueEvent{
-				Key: k,
+		} else if known {
+			// The key was deleted between our last known revision and
+			// the latest revision received via Get.
+			known = false
+			if !events.emit(ctx, KeyValueEvent{
+				Key: key,
 				Typ: EventTypeDelete,
+			}) {
+				return
 			}
-
-			if traceEnabled {
-				scopedLog.Debug("Emitting EventTypeDelete event",
-					logfields.Key, k,
-				)
-			}
-			return events.emit(ctx, event)
-		}) {
-			return
 		}
 
+		nextRev := res.Header.Revision + 1
+
 		// Only send the list signal once
 		if !listSignalSent {
 			if !events.emit(ctx, KeyValueEvent{Typ: EventTypeListDone}) {
@@ -782,11 +1532,11 @@ reList:
 
 	recreateWatcher:
 		scopedLog.Info(
-			"Starting to watch prefix",
+			"Starting to watch key",
 			fieldRev, nextRev,
 		)
 
-		lr, err = e.limiter.Wait(ctx)
+		lr, err = e.watchLimiterWait(ctx, scope)
 		if err != nil {
 			select {
 			case <-e.client.Ctx().Done():
@@ -798,8 +1548,7 @@ reList:
 			}
 		}
 
-		etcdWatch := e.client.Watch(client.WithRequireLeader(ctx), prefix,
-			client.WithPrefix(), client.WithRev(nextRev))
+		etcdWatch := e.client.Watch(client.WithRequireLeader(ctx), key, client.WithRev(nextRev))
 		lr.Done()
 
 		for {
@@ -810,7 +1559,7 @@ reList:
 				return
 			case r, ok := <-etcdWatch:
 				if !ok {
-					time.Sleep(50 * time.Millisecond)
+					sim.Sleep(ctx, 50*time.Millisecond)
 					goto recreateWatcher
 				}
 
@@ -827,23 +1576,18 @@ reList:
 						// revision that may no longer exist,
 						// recreate the watcher and try to
 						// watch on the next possible revision
-						scopedLog.Info("Tried watching on compacted revision. Triggering relist of all keys",
+						scopedLog.Info("Tried watching on compacted revision. Triggering re-retrieval of the key",
 							logfields.Error, Hint(err),
 							fieldRev, r.Header.Revision,
 						)
 					default:
-						scopedLog.Info("Etcd watcher errored. Triggering relist of all keys",
+						scopedLog.Info("Etcd watcher errored. Triggering re-retrieval of the key",
 							logfields.Error, Hint(err),
 							fieldRev, r.Header.Revision,
 						)
 					}
 
-					// mark all local keys in state for
-					// deletion unless the upcoming GET
-					// marks them alive
-					localCache.MarkAllForDeletion()
-
-					goto reList
+					goto reGet
 				}
 
 				nextRev = r.Header.Revision + 1
@@ -862,13 +1606,13 @@ reList:
 					switch {
 					case ev.Type == client.EventTypeDelete:
 						event.Typ = EventTypeDelete
-						localCache.RemoveKey(ev.Kv.Key)
+						known = false
 					case ev.IsCreate():
 						event.Typ = EventTypeCreate
-						localCache.MarkInUse(ev.Kv.Key)
+						known = true
 					default:
 						event.Typ = EventTypeModify
-						localCache.MarkInUse(ev.Kv.Key)
+						known = true
 					}
 
 					if traceEnabled {
@@ -890,8 +1634,9 @@ reList:
 func (e *etcdClient) paginatedList(ctx context.Context, log *slog.Logger, prefix string) (kvs []*mvccpb.KeyValue, revision int64, err error) {
 	start, end := prefix, client.GetPrefixRangeEnd(prefix)
 
+	kv := e.serializableKV()
 	for {
-		res, err := e.client.Get(ctx, start, client.WithRange(end),
+		res, err := kv.Get(ctx, start, client.WithRange(end),
 			client.WithSort(client.SortByKey, client.SortAscend),
 			client.WithRev(revision), client.WithSerializable(),
 			client.WithLimit(int64(e.listBatchSize)),
@@ -928,7 +1673,9 @@ func (e *etcdClient) paginatedList(ctx context.Context, log *slog.Logger, prefix
 	}
 }
 
-func (e *etcdClient) determineEndpointStatus(ctx context.Context, endpointAddress string) (string, error) {
+// determineEndpointStatus returns the state of the given endpoint, along
+// with the revision it reported, or 0 when the status check failed.
+func (e *etcdClient) determineEndpointStatus(ctx context.Context, endpointAddress string) (*models.KVstoreEndpointState, int64) {
 	ctxTimeout, cancel := context.WithTimeout(ctx, statusCheckTimeout)
 	defer cancel()
 
@@ -938,15 +1685,65 @@ func (e *etcdClient) determineEndpointStatus(ctx context.Context, endpointAddres
 
 	status, err := e.client.Status(ctxTimeout, endpointAddress)
 	if err != nil {
-		return fmt.Sprintf("%s - %s", endpointAddress, err), Hint(err)
+		return &models.KVstoreEndpointState{Endpoint: endpointAddress, Error: err.Error()}, 0
+	}
+
+	return &models.KVstoreEndpointState{
+		Endpoint: endpointAddress,
+		Version:  status.Version,
+		Leader:   status.Header.MemberId == status.Leader,
+	}, status.Header.Revision
+}
+
+// recordWatchRevisionLags exports, for each watched prefix, the difference
+// between the given cluster head revision and the revision last processed by
+// its watcher, aggregated per metric scope. Watchers that have not completed
+// their initial listing yet are skipped.
+func (e *etcdClient) recordWatchRevisionLags(headRevision int64) {
+	lags := make(map[string]int64)
+	e.watchRevisions.Range(func(prefix string, rev *atomic.Int64) bool {
+		current := rev.Load()
+		if current == 0 {
+			return true
+		}
+		scope := bucketScopeFromKey(prefix)
+		lags[scope] = max(lags[scope], headRevision-current)
+		return true
+	})
+
+	for scope, lag := range lags {
+		recordWatchRevisionLag(scope, max(lag, 0))
+	}
+}
+
+// recordPrefixKeyCounts exports the approximate number of live keys observed
+// by the watcher of each prefix, aggregated per metric scope. This keeps
+// growth anomalies visible without issuing count requests against etcd.
+func (e *etcdClient) recordPrefixKeyCounts() {
+	counts := make(map[string]int64)
+	e.watchKeyCounts.Range(func(prefix string, count *atomic.Int64) bool {
+		counts[bucketScopeFromKey(prefix)] += count.Load()
+		return true
+	})
+
+	for scope, count := range counts {
+		recordPrefixKeyCount(scope, count)
+	}
+}
+
+// endpointStateString returns the human-readable representation of the given
+// endpoint state, as included in the status message.
+func endpointStateString(state *models.KVstoreEndpointState) string {
+	if state.Error != "" {
+		return fmt.Sprintf("%s - %s", state.Endpoint, state.Error)
 	}
 
-	str := fmt.Sprintf("%s - %s", endpointAddress, status.Version)
-	if status.Header.MemberId == status.Leader {
+	str := fmt.Sprintf("%s - %s", state.Endpoint, state.Version)
+	if state.Leader {
 		str += " (Leader)"
 	}
 
-	return str, nil
+	return str
 }
 
 func (e *etcdClient) statusChecker() {
@@ -954,6 +1751,7 @@ func (e *etcdClient) statusChecker() {
 
 	var consecutiveQuorumErrors uint
 	var err error
+	var smoother statusIntervalSmoother
 
 	e.RWMutex.Lock()
 	// Ensure that lastHearbeat is always set to a non-zero value when starting
@@ -967,15 +1765,19 @@ func (e *etcdClient) statusChecker() {
 
 	for {
 		newStatus := []string{}
+		var endpointStates []*models.KVstoreEndpointState
 		ok := 0
 
+		quorumStart := time.Now()
 		quorumError := e.isConnectedAndHasQuorum(ctx)
+		quorumLatency := time.Since(quorumStart)
 
 		e.RWMutex.RLock()
 		lastHeartbeat := e.lastHeartbeat
 		e.RWMutex.RUnlock()
 
-		if heartbeatDelta := time.Since(lastHeartbeat); heartbeatDelta > 2*HeartbeatWriteInterval {
+		heartbeatDelta := time.Since(lastHeartbeat)
+		if heartbeatDelta > e.extraOptions.HeartbeatStaleThreshold() {
 			recordQuorumError("no event received")
 			quorumError = fmt.Errorf("%s since last heartbeat update has been received", heartbeatDelta)
 		}
@@ -988,46 +1790,68 @@ func (e *etcdClient) statusChecker() {
 				ok = len(endpoints)
 			}
 		} else {
+			var headRevision int64
 			for _, ep := range endpoints {
-				st, err := e.determineEndpointStatus(ctx, ep)
-				if err == nil {
+				st, revision := e.determineEndpointStatus(ctx, ep)
+				if st.Error == "" {
 					ok++
 				}
+				headRevision = max(headRevision, revision)
+
+				endpointStates = append(endpointStates, st)
+				newStatus = append(newStatus, endpointStateString(st))
+			}
 
-				newStatus = append(newStatus, st)
+			if headRevision > 0 {
+				e.recordWatchRevisionLags(headRevision)
 			}
 		}
 
+		e.recordPrefixKeyCounts()
+
 		allConnected := len(endpoints) == ok
 
+		details := &models.KVstoreStatus{
+			Endpoints:               endpointStates,
+			Leases:                  int64(e.leaseManager.TotalLeases()),
+			LockLeases:              int64(e.lockLeaseManager.TotalLeases()),
+			HasQuorum:               quorumError == nil,
+			LastHeartbeatAgeSeconds: heartbeatDelta.Seconds(),
+		}
+
 		quorumString := "true"
 		if quorumError != nil {
-			quorumString = quorumError.Error()
 			consecutiveQuorumErrors++
-			quorumString += fmt.Sprintf(", consecutive-errors=%d", consecutiveQuorumErrors)
+			details.QuorumError = quorumError.Error()
+			quorumString = fmt.Sprintf("%s, consecutive-errors=%d", quorumError, consecutiveQuorumErrors)
 		} else {
 			consecutiveQuorumErrors = 0
 		}
+		details.ConsecutiveQuorumErrors = int64(consecutiveQuorumErrors)
 
-		e.statusLock.Lock()
-
+		var state, msg string
 		switch {
 		case consecutiveQuorumErrors > cmp.Or(e.extraOptions.MaxConsecutiveQuorumErrors, defaults.KVstoreMaxConsecutiveQuorumErrors):
 			err = fmt.Errorf("quorum check failed %d times in a row: %w", consecutiveQuorumErrors, quorumError)
-			e.status.State = models.StatusStateFailure
-			e.status.Msg = fmt.Sprintf("Err: %s", err.Error())
+			state = models.StatusStateFailure
+			msg = fmt.Sprintf("Err: %s", err.Error())
 		case len(endpoints) > 0 && ok == 0:
 			err = fmt.Errorf("not able to connect to any etcd endpoints")
-			e.status.State = models.StatusStateFailure
-			e.status.Msg = fmt.Sprintf("Err: %s", err.Error())
+			state = models.StatusStateFailure
+			msg = fmt.Sprintf("Err: %s", err.Error())
 		default:
 			err = nil
-			e.status.State = models.StatusStateOk
-			e.status.Msg = fmt.Sprintf("etcd: %d/%d connected, leases=%d, lock leases=%d, has-quorum=%s: %s",
-				ok, len(endpoints), e.leaseManager.TotalLeases(), e.lockLeaseManager.TotalLeases(), quorumString, strings.Join(newStatus, "; "))
+			state = models.StatusStateOk
+			msg = fmt.Sprintf("etcd: %d/%d connected, leases=%d, lock leases=%d, has-quorum=%s: %s",
+				ok, len(endpoints), details.Leases, details.LockLeases, quorumString, strings.Join(newStatus, "; "))
+		}
+
+		e.setStatusWithDetails(state, msg, details)
+
+		if e.adaptiveLimit != nil {
+			e.adaptiveLimit.observe(allConnected && quorumError == nil, quorumLatency)
 		}
 
-		e.statusLock.Unlock()
 		if err != nil {
 			select {
 			case e.statusCheckErrors <- err:
@@ -1044,23 +1868,37 @@ func (e *etcdClient) statusChecker() {
 		case <-e.stopStatusChecker:
 			close(e.statusCheckErrors)
 			return
-		case <-time.After(e.extraOptions.StatusCheckInterval(allConnected)):
+		case <-time.After(smoother.interval(&e.extraOptions, allConnected && quorumError == nil)):
 		}
 	}
 }
 
 func (e *etcdClient) Status() *models.Status {
-	e.statusLock.RLock()
-	defer e.statusLock.RUnlock()
-
+	status := e.status.Load()
 	return &models.Status{
-		State: e.status.State,
-		Msg:   e.status.Msg,
+		State: status.State,
+		Msg:   status.Msg,
 	}
 }
 
+// StatusDetail returns the structured representation of the etcd cluster
+// status backing the summary returned by Status(). It returns nil until the
+// status checker performed the first check.
+func (e *etcdClient) StatusDetail() *models.KVstoreStatus {
+	return e.status.Load().details
+}
+
+// StatusChanged returns a channel closed when the status reported by Status()
+// is next updated, allowing pollers to wait for changes instead of repeatedly
+// retrieving an identical status.
+func (e *etcdClient) StatusChanged() <-chan struct{} {
+	return e.status.Load().changed
+}
+
 // GetIfLocked returns value of key if the client is still holding the given lock.
 func (e *etcdClient) GetIfLocked(ctx context.Context, key string, lock KVLocker) (bv []byte, err error) {
+	ctx, cancel := e.opTimeout(ctx, key)
+	defer cancel()
 	if traceEnabled {
 		defer func() {
 			Trace(e.logger, "GetIfLocked",
@@ -1101,6 +1939,8 @@ func (e *etcdClient) GetIfLocked(ctx context.Context, key string, lock KVLocker)
 
 // Get returns value of key
 func (e *etcdClient) Get(ctx context.Context, key string) (bv []byte, err error) {
+	ctx, cancel := e.opTimeout(ctx, key)
+	defer cancel()
 	if traceEnabled {
 		defer func() {
 			Trace(e.logger, "Get",
@@ -1133,6 +1973,8 @@ func (e *etcdClient) Get(ctx context.Context, key string) (bv []byte, err error)
 
 // DeleteIfLocked deletes a key if the client is still holding the given lock.
 func (e *etcdClient) DeleteIfLocked(ctx context.Context, key string, lock KVLocker) (err error) {
+	ctx, cancel := e.opTimeout(ctx, key)
+	defer cancel()
 	if traceEnabled {
 		defer func() {
 			Trace(e.logger, "DeleteIfLocked",
@@ -1147,6 +1989,7 @@ func (e *etcdClient) DeleteIfLocked(ctx context.Context, key string, lock KVLock
 	}
 	defer func(duration *spanstat.SpanStat) {
 		increaseMetric(key, metricDelete, "DeleteLocked", duration.EndError(err).Total(), err)
+		recordWrite(key, err)
 	}(spanstat.Start())
 
 	opDel := client.OpDelete(key)
@@ -1166,6 +2009,8 @@ func (e *etcdClient) DeleteIfLocked(ctx context.Context, key string, lock KVLock
 
 // Delete deletes a key
 func (e *etcdClient) Delete(ctx context.Context, key string) (err error) {
+	ctx, cancel := e.opTimeout(ctx, key)
+	defer cancel()
 	if traceEnabled {
 		defer func() {
 			Trace(e.logger, "Delete",
@@ -1180,6 +2025,7 @@ func (e *etcdClient) Delete(ctx context.Context, key string) (err error) {
 	}
 	defer func(duration *spanstat.SpanStat) {
 		increaseMetric(key, metricDelete, "Delete", duration.EndError(err).Total(), err)
+		recordWrite(key, err)
 	}(spanstat.Start())
 
 	_, err = e.client.Delete(ctx, key)
@@ -1193,8 +2039,36 @@ func (e *etcdClient) Delete(ctx context.Context, key string) (err error) {
 	return Hint(err)
 }
 
+// opTimeout derives the context for an operation on the given key, applying
+// the deadline configured for the scope of the key, if any. This allows a
+// slow etcd to degrade features proportionally to their tolerance, e.g. with
+// a tighter budget for ipcache operations than for identity allocation.
+func (e *etcdClient) opTimeout(ctx context.Context, key string) (context.Context, context.CancelFunc) {
+	if timeout, ok := e.scopeTimeouts[GetScopeFromKey(key)]; ok {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return ctx, func() {}
+}
+
+// validateValueSize fails fast with ErrTooLarge when the given value exceeds
+// the configured etcd request size limit, rather than letting etcd reject
+// the request with an opaque gRPC error.
+func (e *etcdClient) validateValueSize(key string, action string, value []byte) error {
+	if len(value) <= e.maxRequestBytes {
+		return nil
+	}
+	recordOversizedRequest(key, action)
+	return fmt.Errorf("%w: value for key %s is %d bytes, limit is %d bytes",
+		ErrTooLarge, key, len(value), e.maxRequestBytes)
+}
+
 // UpdateIfLocked updates a key if the client is still holding the given lock.
 func (e *etcdClient) UpdateIfLocked(ctx context.Context, key string, value []byte, lease bool, lock KVLocker) (err error) {
+	ctx, cancel := e.opTimeout(ctx, key)
+	defer cancel()
+	if err := e.validateValueSize(key, "UpdateIfLocked", value); err != nil {
+		return err
+	}
 	if traceEnabled {
 		defer func() {
 			Trace(e.logger, "UpdateIfLocked",
@@ -1218,6 +2092,7 @@ func (e *etcdClient) UpdateIfLocked(ctx context.Context, key string, value []byt
 	}
 	defer func(duration *spanstat.SpanStat) {
 		increaseMetric(key, metricSet, "UpdateIfLocked", duration.EndError(err).Total(), err)
+		recordWrite(key, err)
 	}(spanstat.Start())
 
 	var txnReply *client.TxnResponse
@@ -1237,6 +2112,11 @@ func (e *etcdClient) UpdateIfLocked(ctx context.Context, key string, value []byt
 
 // Update creates or updates a key
 func (e *etcdClient) Update(ctx context.Context, key string, value []byte, lease bool) (err error) {
+	ctx, cancel := e.opTimeout(ctx, key)
+	defer cancel()
+	if err := e.validateValueSize(key, "Update", value); err != nil {
+		return err
+	}
 	if traceEnabled {
 		defer func() {
 			Trace(e.logger, "Update",
@@ -1260,6 +2140,7 @@ func (e *etcdClient) Update(ctx context.Context, key string, value []byte, lease
 	}
 	defer func(duration *spanstat.SpanStat) {
 		increaseMetric(key, metricSet, "Update", duration.EndError(err).Total(), err)
+		recordWrite(key, err)
 	}(spanstat.Start())
 
 	_, err = e.client.Put(ctx, key, string(value), client.WithLease(leaseID))
@@ -1272,6 +2153,8 @@ func (e *etcdClient) Update(ctx context.Context, key string, value []byte, lease
 
 // UpdateIfDifferentIfLocked updates a key if the value is different and if the client is still holding the given lock.
 func (e *etcdClient) UpdateIfDifferentIfLocked(ctx context.Context, key string, value []byte, lease bool, lock KVLocker) (recreated bool, err error) {
+	ctx, cancel := e.opTimeout(ctx, key)
+	defer cancel()
 	if traceEnabled {
 		defer func() {
 			Trace(e.logger, "UpdateIfDifferentIfLocked",
@@ -1317,11 +2200,14 @@ func (e *etcdClient) UpdateIfDifferentIfLocked(ctx context.Context, key string,
 		return true, e.UpdateIfLocked(ctx, key, value, lease, lock)
 	}
 
+	recordWriteAvoided(key)
 	return false, nil
 }
 
 // UpdateIfDifferent updates a key if the value is different
 func (e *etcdClient) UpdateIfDifferent(ctx context.Context, key string, value []byte, lease bool) (recreated bool, err error) {
+	ctx, cancel := e.opTimeout(ctx, key)
+	defer cancel()
 	if traceEnabled {
 		defer func() {
 			Trace(e.logger, "UpdateIfDifferent",
@@ -1355,11 +2241,17 @@ func (e *etcdClient) UpdateIfDifferent(ctx context.Context, key string, value []
 		return true, e.Update(ctx, key, value, lease)
 	}
 
+	recordWriteAvoided(key)
 	return false, nil
 }
 
 // CreateOnlyIfLocked atomically creates a key if the client is still holding the given lock or fails if it already exists
 func (e *etcdClient) CreateOnlyIfLocked(ctx context.Context, key string, value []byte, lease bool, lock KVLocker) (success bool, err error) {
+	ctx, cancel := e.opTimeout(ctx, key)
+	defer cancel()
+	if err := e.validateValueSize(key, "CreateOnlyLocked", value); err != nil {
+		return false, err
+	}
 	if traceEnabled {
 		defer func() {
 			Trace(e.logger, "CreateOnlyIfLocked",
@@ -1397,6 +2289,7 @@ func (e *etcdClient) CreateOnlyIfLocked(ctx context.Context, key string, value [
 	}
 	txnresp, err := e.client.Txn(ctx).If(cnds...).Then(req).Else(opGets...).Commit()
 	increaseMetric(key, metricSet, "CreateOnlyLocked", duration.EndError(err).Total(), err)
+	recordWrite(key, err)
 	if err != nil {
 		lr.Error(err, -1)
 		e.leaseManager.CancelIfExpired(err, leaseID)
@@ -1433,6 +2326,11 @@ func (e *etcdClient) CreateOnlyIfLocked(ctx context.Context, key string, value [
 
 // CreateOnly creates a key with the value and will fail if the key already exists
 func (e *etcdClient) CreateOnly(ctx context.Context, key string, value []byte, lease bool) (success bool, err error) {
+	ctx, cancel := e.opTimeout(ctx, key)
+	defer cancel()
+	if err := e.validateValueSize(key, "CreateOnly", value); err != nil {
+		return false, err
+	}
 	if traceEnabled {
 		defer func() {
 			Trace(e.logger, "CreateOnly",
@@ -1457,6 +2355,7 @@ func (e *etcdClient) CreateOnly(ctx context.Context, key string, value []byte, l
 	}
 	defer func(duration *spanstat.SpanStat) {
 		increaseMetric(key, metricSet, "CreateOnly", duration.EndError(err).Total(), err)
+		recordWrite(key, err)
 	}(spanstat.Start())
 
 	req := client.OpPut(key, string(value), client.WithLease(leaseID))
@@ -1474,8 +2373,139 @@ func (e *etcdClient) CreateOnly(ctx context.Context, key string, value []byte, l
 	return txnresp.Succeeded, nil
 }
 
+// etcdMaxTxnOps is the maximum number of operations in a single transaction,
+// matching the default value of the etcd --max-txn-ops flag.
+const etcdMaxTxnOps = 128
+
+// UpdateBatch creates or updates the given keys, grouped into transactions
+// of up to etcdMaxTxnOps operations each.
+func (e *etcdClient) UpdateBatch(ctx context.Context, pairs []KeyValue, lease bool) error {
+	for chunk := range slices.Chunk(pairs, etcdMaxTxnOps) {
+		ops := make([]TxnOp, 0, len(chunk))
+		for _, pair := range chunk {
+			ops = append(ops, TxnOp{Typ: TxnOpPut, Key: pair.Key, Value: pair.Value, Lease: lease})
+		}
+		if _, err := e.Txn(ctx, nil, ops, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// txnCmps translates the generic transaction conditions into etcd comparisons.
+func txnCmps(conditions []TxnCondition) ([]client.Cmp, error) {
+	cmps := make([]client.Cmp, 0, len(conditions))
+	for _, cnd := range conditions {
+		switch cnd.Typ {
+		case TxnKeyExists:
+			cmps = append(cmps, client.Compare(client.Version(cnd.Key), ">", 0))
+		case TxnKeyAbsent:
+			cmps = append(cmps, client.Compare(client.Version(cnd.Key), "=", 0))
+		case TxnValueEquals:
+			cmps = append(cmps, client.Compare(client.Value(cnd.Key), "=", string(cnd.Value)))
+		default:
+			return nil, fmt.Errorf("unsupported transaction condition type %q", cnd.Typ)
+		}
+	}
+	return cmps, nil
+}
+
+// txnOps translates the generic transaction mutations into etcd operations,
+// additionally returning the IDs of the attached leases.
+func (e *etcdClient) txnOps(ctx context.Context, ops []TxnOp) ([]client.Op, []client.LeaseID, error) {
+	out := make([]client.Op, 0, len(ops))
+	var leaseIDs []client.LeaseID
+	for _, op := range ops {
+		switch op.Typ {
+		case TxnOpPut:
+			if err := e.validateValueSize(op.Key, "Txn", op.Value); err != nil {
+				return nil, nil, err
+			}
+			var leaseID client.LeaseID
+			if op.Lease {
+				var err error
+				leaseID, err = e.leaseManager.GetLeaseID(ctx, op.Key)
+				if err != nil {
+					return nil, nil, Hint(err)
+				}
+				leaseIDs = append(leaseIDs, leaseID)
+			}
+			out = append(out, client.OpPut(op.Key, string(op.Value), client.WithLease(leaseID)))
+		case TxnOpDelete:
+			out = append(out, client.OpDelete(op.Key))
+		default:
+			return nil, nil, fmt.Errorf("unsupported transaction operation type %q", op.Typ)
+		}
+	}
+	return out, leaseIDs, nil
+}
+
+// Txn atomically executes the given mutations as a single etcd transaction.
+func (e *etcdClient) Txn(ctx context.Context, conditions []TxnCondition, thenOps, elseOps []TxnOp) (success bool, err error) {
+	if traceEnabled {
+		defer func() {
+			Trace(e.logger, "Txn",
+				logfields.Error, err,
+				fieldNumEntries, len(thenOps)+len(elseOps),
+				fieldSuccess, success,
+			)
+		}()
+	}
+
+	cmps, err := txnCmps(conditions)
+	if err != nil {
+		return false, err
+	}
+	thens, thenLeases, err := e.txnOps(ctx, thenOps)
+	if err != nil {
+		return false, err
+	}
+	elses, elseLeases, err := e.txnOps(ctx, elseOps)
+	if err != nil {
+		return false, err
+	}
+
+	// Attribute the metrics to the scope of the first mutation.
+	var scopeKey string
+	switch {
+	case len(thenOps) > 0:
+		scopeKey = thenOps[0].Key
+	case len(elseOps) > 0:
+		scopeKey = elseOps[0].Key
+	}
+
+	lr, err := e.limiter.Wait(ctx)
+	if err != nil {
+		return false, Hint(err)
+	}
+	defer func(duration *spanstat.SpanStat) {
+		increaseMetric(scopeKey, metricSet, "Txn", duration.EndError(err).Total(), err)
+	}(spanstat.Start())
+
+	txnresp, err := e.client.Txn(ctx).If(cmps...).Then(thens...).Else(elses...).Commit()
+	if err != nil {
+		lr.Error(err, -1)
+		for _, leaseID := range append(thenLeases, elseLeases...) {
+			e.leaseManager.CancelIfExpired(err, leaseID)
+		}
+		return false, Hint(err)
+	}
+	lr.Done()
+
+	applied := thenOps
+	if !txnresp.Succeeded {
+		applied = elseOps
+	}
+	for _, op := range applied {
+		recordWrite(op.Key, nil)
+	}
+	return txnresp.Succeeded, nil
+}
+
 // ListPrefixIfLocked returns a list of keys matching the prefix only if the client is still holding the given lock.
 func (e *etcdClient) ListPrefixIfLocked(ctx context.Context, prefix string, lock KVLocker) (v KeyValuePairs, err error) {
+	ctx, cancel := e.opTimeout(ctx, prefix)
+	defer cancel()
 	if traceEnabled {
 		defer func() {
 			Trace(e.logger, "ListPrefixIfLocked",
@@ -1520,6 +2550,8 @@ func (e *etcdClient) ListPrefixIfLocked(ctx context.Context, prefix string, lock
 
 // ListPrefix returns a map of matching keys
 func (e *etcdClient) ListPrefix(ctx context.Context, prefix string) (v KeyValuePairs, err error) {
+	ctx, cancel := e.opTimeout(ctx, prefix)
+	defer cancel()
 	if traceEnabled {
 		defer func() {
 			Trace(e.logger, "ListPrefix",
@@ -1557,10 +2589,119 @@ func (e *etcdClient) ListPrefix(ctx context.Context, prefix string) (v KeyValueP
 	return pairs, nil
 }
 
+// ListPrefixFunc invokes the callback for every key matching the prefix. The
+// keys are retrieved in paginated batches of at most listBatchSize entries,
+// pinned at the revision of the first response, so that the full result set
+// never needs to be materialized at once.
+func (e *etcdClient) ListPrefixFunc(ctx context.Context, prefix string, fn func(key string, value Value) bool) error {
+	return e.listPrefixFunc(ctx, "ListPrefixFunc", prefix, e.listBatchSize, fn)
+}
+
+// ListPrefixPaginated returns an iterator over the keys matching the prefix,
+// retrieved in pages of at most pageSize entries pinned at the revision of the
+// first response.
+func (e *etcdClient) ListPrefixPaginated(ctx context.Context, prefix string, pageSize int) (iter.Seq2[string, Value], <-chan error) {
+	if pageSize <= 0 {
+		pageSize = e.listBatchSize
+	}
+	errs := make(chan error, 1)
+	return func(yield func(string, Value) bool) {
+		defer close(errs)
+		if err := e.listPrefixFunc(ctx, "ListPrefixPaginated", prefix, pageSize, yield); err != nil {
+			errs <- err
+		}
+	}, errs
+}
+
+func (e *etcdClient) listPrefixFunc(ctx context.Context, op, prefix string, pageSize int, fn func(key string, value Value) bool) (err error) {
+	ctx, cancel := e.opTimeout(ctx, prefix)
+	defer cancel()
+	var entries int
+	if traceEnabled {
+		defer func() {
+			Trace(e.logger, op,
+				logfields.Error, err,
+				fieldPrefix, prefix,
+				fieldNumEntries, entries,
+			)
+		}()
+	}
+	defer func(duration *spanstat.SpanStat) {
+		increaseMetric(prefix, metricRead, op, duration.EndError(err).Total(), err)
+	}(spanstat.Start())
+
+	var (
+		start, end = prefix, client.GetPrefixRangeEnd(prefix)
+		revision   int64
+	)
+
+	kv := e.serializableKV()
+	for {
+		lr, err := e.limiter.Wait(ctx)
+		if err != nil {
+			return Hint(err)
+		}
+
+		res, err := kv.Get(ctx, start, client.WithRange(end),
+			client.WithSort(client.SortByKey, client.SortAscend),
+			client.WithRev(revision), client.WithSerializable(),
+			client.WithLimit(int64(pageSize)),
+		)
+		if err != nil {
+			lr.Error(err, -1)
+			return Hint(err)
+		}
+		lr.Done()
+
+		// Do not modify the revision once set, as subsequent Get queries may
+		// return higher revisions in case other operations are performed in
+		// parallel (regardless of whether we specify WithRev), leading to an
+		// inconsistent view over the prefix.
+		if revision == 0 {
+			revision = res.Header.Revision
+		}
+
+		for _, kv := range res.Kvs {
+			entries++
+			if !fn(string(kv.Key), Value{
+				Data:        kv.Value,
+				ModRevision: uint64(kv.ModRevision),
+				LeaseID:     kv.Lease,
+			}) {
+				return nil
+			}
+		}
+
+		if !res.More || len(res.Kvs) == 0 {
+			return nil
+		}
+
+		start = string(res.Kvs[len(res.Kvs)-1].Key) + "\x00"
+	}
+}
+
+// serializableKV returns the KV client to perform serializable read
+// operations with. When read affinity is enabled this is the client pinned
+// to the lowest-latency endpoint, otherwise the main client.
+func (e *etcdClient) serializableKV() client.KV {
+	if e.affinity != nil {
+		return e.affinity.kv()
+	}
+	return e.client
+}
+
 // Close closes the etcd session
 func (e *etcdClient) Close() {
 	close(e.stopStatusChecker)
 
+	if e.affinity != nil {
+		e.affinity.close()
+	}
+
+	if e.caReloader != nil {
+		e.caReloader.close()
+	}
+
 	if err := e.client.Close(); err != nil {
 		e.logger.Warn(
 			"Failed to close etcd client",
@@ -1577,7 +2718,24 @@ func (e *etcdClient) Close() {
 func (e *etcdClient) ListAndWatch(ctx context.Context, prefix string) EventChan {
 	events := make(chan KeyValueEvent)
 
-	go e.watch(ctx, prefix, emitter{events: events, scope: GetScopeFromKey(strings.TrimRight(prefix, "/"))})
+	// Label the watcher goroutine, so that profiles attribute the work to
+	// the watched prefix.
+	go pprof.Do(ctx, pprof.Labels(pprofSubsys, "etcd-watcher", pprofPrefix, prefix),
+		func(ctx context.Context) {
+			e.watch(ctx, prefix, emitter{events: events, scope: GetScopeFromKey(strings.TrimRight(prefix, "/"))})
+		})
+
+	return events
+}
+
+// WatchKey implements the BackendOperations.WatchKey using etcd
+func (e *etcdClient) WatchKey(ctx context.Context, key string) EventChan {
+	events := make(chan KeyValueEvent)
+
+	go pprof.Do(ctx, pprof.Labels(pprofSubsys, "etcd-watcher", pprofPrefix, key),
+		func(ctx context.Context) {
+			e.watchKey(ctx, key, emitter{events: events, scope: GetScopeFromKey(key)})
+		})
 
 	return events
 }
@@ -1602,6 +2760,27 @@ func (e *etcdClient) expiredLeaseObserver(key string) {
 	})
 }
 
+// RegisterLeaseKeepaliveFailureObserver registers a function which is executed
+// when the keepalives of the lease associated with a key having the given
+// prefix are detected as repeatedly failing, before the lease actually expires.
+// If the function is nil, the previous observer (if any) is unregistered.
+func (e *etcdClient) RegisterLeaseKeepaliveFailureObserver(prefix string, fn func(key string)) {
+	if fn == nil {
+		e.leaseKeepaliveFailureObservers.Delete(prefix)
+	} else {
+		e.leaseKeepaliveFailureObservers.Store(prefix, fn)
+	}
+}
+
+func (e *etcdClient) keepaliveFailureObserver(key string) {
+	e.leaseKeepaliveFailureObservers.Range(func(prefix string, fn func(string)) bool {
+		if strings.HasPrefix(key, prefix) {
+			fn(key)
+		}
+		return true
+	})
+}
+
 // UserEnforcePresence creates a user in etcd if not already present, and grants the specified roles.
 func (e *etcdClient) UserEnforcePresence(ctx context.Context, name string, roles []string) error {
 	e.logger.Debug("Creating user", FieldUser, name)
@@ -1669,6 +2848,21 @@ func getClientCertificateReloader(fpath string) (func(*tls.CertificateRequestInf
 // needed to implement certificates reload, not depending on the deprecated
 // newconfig/yamlConfig.
 type yamlKeyPairConfig struct {
-	Certfile string `json:"cert-file"`
-	Keyfile  string `json:"key-file"`
+	Certfile      string `json:"cert-file"`
+	Keyfile       string `json:"key-file"`
+	TrustedCAfile string `json:"trusted-ca-file"`
+}
+
+// getTrustedCAFile returns the path of the trusted CA bundle referenced by
+// the given etcd configuration file, if any.
+func getTrustedCAFile(fpath string) (string, error) {
+	yc := &yamlKeyPairConfig{}
+	b, err := os.ReadFile(fpath)
+	if err != nil {
+		return "", err
+	}
+	if err := yaml.Unmarshal(b, yc); err != nil {
+		return "", err
+	}
+	return yc.TrustedCAfile, nil
 }
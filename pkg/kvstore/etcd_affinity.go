@@ -0,0 +1,174 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	client "go.etcd.io/etcd/client/v3"
+
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/time"
+)
+
+// affinityProbeInterval is the interval in which the latency towards all
+// etcd endpoints is measured to determine the nearest one.
+const affinityProbeInterval = 5 * time.Minute
+
+// endpointAffinity pins serializable read operations to the etcd endpoint
+// with the lowest observed latency (e.g., the endpoint in the same
+// availability zone), reducing cross-AZ read latency and egress cost.
+// Latencies are measured via periodic status probes against all configured
+// endpoints; a dedicated client connected only to the nearest endpoint is
+// maintained for reads. Linearizable reads and writes are unaffected and
+// keep using the leader-aware balancer of the main client.
+type endpointAffinity struct {
+	logger *slog.Logger
+
+	// client is the main etcd client, used to probe the endpoints.
+	client *client.Client
+	// config is the configuration of the main client, reused to dial the
+	// read client towards the selected endpoint.
+	config client.Config
+
+	// mu protects readClient and endpoint.
+	mu lock.RWMutex
+	// readClient is connected to the nearest endpoint only. It is nil until
+	// the first successful probe.
+	readClient *client.Client
+	// endpoint is the address readClient is currently pinned to.
+	endpoint string
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newEndpointAffinity(logger *slog.Logger, c *client.Client, config client.Config) *endpointAffinity {
+	return &endpointAffinity{
+		logger: logger,
+		client: c,
+		config: config,
+		stop:   make(chan struct{}),
+	}
+}
+
+// start spawns the background prober selecting the nearest endpoint.
+func (ea *endpointAffinity) start() {
+	ea.wg.Add(1)
+	go func() {
+		defer ea.wg.Done()
+
+		// Probe immediately at startup, then periodically to adapt to
+		// endpoint or topology changes.
+		for {
+			if endpoint, latency, ok := ea.probeEndpoints(ea.client.Ctx()); ok {
+				ea.pinEndpoint(endpoint, latency)
+			}
+
+			select {
+			case <-ea.stop:
+				return
+			case <-ea.client.Ctx().Done():
+				return
+			case <-time.After(affinityProbeInterval):
+			}
+		}
+	}()
+}
+
+// close terminates the prober and releases the read client.
+func (ea *endpointAffinity) close() {
+	close(ea.stop)
+	ea.wg.Wait()
+
+	ea.mu.Lock()
+	defer ea.mu.Unlock()
+	if ea.readClient != nil {
+		ea.readClient.Close()
+		ea.readClient = nil
+	}
+}
+
+// kv returns the KV client serializable reads shall be performed with: the
+// client pinned to the nearest endpoint if available, the main client
+// otherwise.
+func (ea *endpointAffinity) kv() client.KV {
+	ea.mu.RLock()
+	defer ea.mu.RUnlock()
+	if ea.readClient != nil {
+		return ea.readClient
+	}
+	return ea.client
+}
+
+// probeEndpoints measures the latency towards all configured endpoints and
+// returns the one with the lowest latency. ok is false if no endpoint
+// replied.
+func (ea *endpointAffinity) probeEndpoints(ctx context.Context) (endpoint string, best time.Duration, ok bool) {
+	for _, ep := range ea.client.Endpoints() {
+		latency, err := ea.probe(ctx, ep)
+		if err != nil {
+			ea.logger.Debug("Failed to probe etcd endpoint latency",
+				logfields.Error, err,
+				logfields.Endpoint, ep,
+			)
+			continue
+		}
+
+		if !ok || latency < best {
+			endpoint, best, ok = ep, latency, true
+		}
+	}
+	return endpoint, best, ok
+}
+
+func (ea *endpointAffinity) probe(ctx context.Context, endpoint string) (time.Duration, error) {
+	ctxTimeout, cancel := context.WithTimeout(ctx, statusCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := ea.client.Status(ctxTimeout, endpoint); err != nil {
+		return 0, Hint(err)
+	}
+	return time.Since(start), nil
+}
+
+// pinEndpoint points the read client to the given endpoint, unless it is
+// already pinned to it.
+func (ea *endpointAffinity) pinEndpoint(endpoint string, latency time.Duration) {
+	ea.mu.RLock()
+	current := ea.endpoint
+	ea.mu.RUnlock()
+	if current == endpoint {
+		return
+	}
+
+	config := ea.config
+	config.Endpoints = []string{endpoint}
+	readClient, err := client.New(config)
+	if err != nil {
+		ea.logger.Warn("Failed to create etcd client towards nearest endpoint",
+			logfields.Error, err,
+			logfields.Endpoint, endpoint,
+		)
+		return
+	}
+
+	ea.logger.Info("Pinning serializable etcd reads to nearest endpoint",
+		logfields.Endpoint, endpoint,
+		logfields.Duration, latency,
+	)
+
+	ea.mu.Lock()
+	previous := ea.readClient
+	ea.readClient, ea.endpoint = readClient, endpoint
+	ea.mu.Unlock()
+
+	if previous != nil {
+		previous.Close()
+	}
+}
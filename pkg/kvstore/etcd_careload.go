@@ -0,0 +1,225 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+)
+
+// caReloader keeps the trusted CA bundle referenced by the etcd configuration
+// in sync with its on-disk contents, so that a CA rotation does not require
+// an agent restart. The directory containing the bundle is watched via
+// fsnotify (watching the directory rather than the file follows the symlink
+// swaps performed by Kubernetes on secret updates), and connections
+// established against a previous bundle are closed when it changes, forcing
+// gRPC to re-dial and re-validate them against the new one.
+type caReloader struct {
+	logger *slog.Logger
+	caFile string
+
+	// pool holds the currently trusted CA pool, consulted on every TLS
+	// handshake performed by reloadingCredentials.
+	pool atomic.Pointer[x509.CertPool]
+
+	// lastPEM is the raw bundle the pool was built from, used to skip
+	// spurious notifications which did not change the contents.
+	lastPEM []byte
+
+	// mu protects conns, the connections established against the current
+	// pool.
+	mu    lock.Mutex
+	conns map[net.Conn]struct{}
+
+	watcher *fsnotify.Watcher
+}
+
+// newCAReloader loads the given CA bundle and starts watching it for
+// changes. The returned reloader must be closed when the client using it is.
+func newCAReloader(logger *slog.Logger, caFile string) (*caReloader, error) {
+	r := &caReloader{
+		logger: logger.With(logfields.Path, caFile),
+		caFile: caFile,
+		conns:  make(map[net.Conn]struct{}),
+	}
+
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no CA certificates found in %s", caFile)
+	}
+	r.pool.Store(pool)
+	r.lastPEM = pem
+
+	r.watcher, err = fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create fsnotify watcher: %w", err)
+	}
+	if err := r.watcher.Add(filepath.Dir(caFile)); err != nil {
+		r.watcher.Close()
+		return nil, fmt.Errorf("failed to watch %q: %w", filepath.Dir(caFile), err)
+	}
+
+	go r.watch()
+	return r, nil
+}
+
+// watch processes the fsnotify events until the watcher is closed, reloading
+// the CA bundle when it changes.
+func (r *caReloader) watch() {
+	for {
+		select {
+		case event, ok := <-r.watcher.Events:
+			if !ok {
+				return
+			}
+			if !event.Has(fsnotify.Create) && !event.Has(fsnotify.Write) &&
+				!event.Has(fsnotify.Rename) && !event.Has(fsnotify.Remove) {
+				continue
+			}
+			r.reload()
+		case err, ok := <-r.watcher.Errors:
+			if !ok {
+				return
+			}
+			r.logger.Warn("Error while watching trusted CA bundle",
+				logfields.Error, err,
+			)
+		}
+	}
+}
+
+// reload re-reads the CA bundle, and swaps the trusted pool if its contents
+// changed. Connections validated against the previous pool are closed, so
+// that they are re-established and re-validated against the new one.
+func (r *caReloader) reload() {
+	pem, err := os.ReadFile(r.caFile)
+	if err != nil {
+		// Transiently expected while the bundle is being replaced, the
+		// event for the new file will trigger another reload.
+		r.logger.Debug("Failed to re-read trusted CA bundle",
+			logfields.Error, err,
+		)
+		return
+	}
+	if bytes.Equal(pem, r.lastPEM) {
+		return
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		r.logger.Warn("No CA certificates found in updated trusted CA bundle, keeping previous one")
+		return
+	}
+
+	r.pool.Store(pool)
+	r.lastPEM = pem
+
+	r.mu.Lock()
+	conns := r.conns
+	r.conns = make(map[net.Conn]struct{})
+	r.mu.Unlock()
+
+	for conn := range conns {
+		conn.Close()
+	}
+
+	r.logger.Info("Reloaded trusted CA bundle, re-dialing etcd connections",
+		logfields.Count, len(conns),
+	)
+}
+
+// track registers a connection established against the current pool, wrapping
+// it so that it is dropped from the registry once closed.
+func (r *caReloader) track(conn net.Conn) net.Conn {
+	tracked := &trackedConn{Conn: conn, reloader: r}
+	r.mu.Lock()
+	r.conns[conn] = struct{}{}
+	r.mu.Unlock()
+	return tracked
+}
+
+func (r *caReloader) untrack(conn net.Conn) {
+	r.mu.Lock()
+	delete(r.conns, conn)
+	r.mu.Unlock()
+}
+
+func (r *caReloader) close() {
+	r.watcher.Close()
+}
+
+// trackedConn unregisters itself from the reloader when closed.
+type trackedConn struct {
+	net.Conn
+	reloader *caReloader
+}
+
+func (c *trackedConn) Close() error {
+	c.reloader.untrack(c.Conn)
+	return c.Conn.Close()
+}
+
+// reloadingCredentials is a gRPC TransportCredentials implementation backing
+// each TLS handshake with the CA pool currently held by the reloader, unlike
+// the static pool baked into a tls.Config at client creation time.
+type reloadingCredentials struct {
+	base     *tls.Config
+	reloader *caReloader
+}
+
+// newReloadingCredentials returns gRPC transport credentials based on the
+// given TLS configuration, except that the trusted CA pool is taken from the
+// reloader on every handshake.
+func newReloadingCredentials(base *tls.Config, reloader *caReloader) credentials.TransportCredentials {
+	return &reloadingCredentials{base: base.Clone(), reloader: reloader}
+}
+
+func (c *reloadingCredentials) current() credentials.TransportCredentials {
+	cfg := c.base.Clone()
+	cfg.RootCAs = c.reloader.pool.Load()
+	return credentials.NewTLS(cfg)
+}
+
+func (c *reloadingCredentials) ClientHandshake(ctx context.Context, authority string, rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	conn, info, err := c.current().ClientHandshake(ctx, authority, rawConn)
+	if err != nil {
+		return conn, info, err
+	}
+	return c.reloader.track(conn), info, nil
+}
+
+func (c *reloadingCredentials) ServerHandshake(rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return c.current().ServerHandshake(rawConn)
+}
+
+func (c *reloadingCredentials) Info() credentials.ProtocolInfo {
+	return credentials.NewTLS(c.base).Info()
+}
+
+func (c *reloadingCredentials) Clone() credentials.TransportCredentials {
+	return &reloadingCredentials{base: c.base.Clone(), reloader: c.reloader}
+}
+
+func (c *reloadingCredentials) OverrideServerName(name string) error {
+	c.base.ServerName = name
+	return nil
+}
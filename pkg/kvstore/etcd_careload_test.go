@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cilium/hive/hivetest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cilium/cilium/pkg/time"
+)
+
+// selfSignedCAPEM returns the PEM encoding of a freshly generated self-signed
+// CA certificate.
+func selfSignedCAPEM(t *testing.T, commonName string) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestCAReloader(t *testing.T) {
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+	require.NoError(t, os.WriteFile(caFile, selfSignedCAPEM(t, "first"), 0o600))
+
+	reloader, err := newCAReloader(hivetest.Logger(t), caFile)
+	require.NoError(t, err)
+	defer reloader.close()
+
+	initial := reloader.pool.Load()
+	require.NotNil(t, initial)
+
+	// Simulate an established connection validated against the initial pool.
+	local, remote := net.Pipe()
+	defer remote.Close()
+	tracked := reloader.track(local)
+
+	// Replacing the bundle swaps the pool and closes tracked connections,
+	// forcing them to be re-dialed.
+	require.NoError(t, os.WriteFile(caFile, selfSignedCAPEM(t, "second"), 0o600))
+	require.Eventually(t, func() bool {
+		return reloader.pool.Load() != initial
+	}, 5*time.Second, 10*time.Millisecond)
+
+	_, err = remote.Read(make([]byte, 1))
+	require.ErrorIs(t, err, io.EOF)
+
+	reloader.mu.Lock()
+	require.Empty(t, reloader.conns)
+	reloader.mu.Unlock()
+
+	// A bundle without certificates is rejected, keeping the previous pool.
+	current := reloader.pool.Load()
+	require.NoError(t, os.WriteFile(caFile, []byte("garbage"), 0o600))
+	require.Never(t, func() bool {
+		return reloader.pool.Load() != current
+	}, 100*time.Millisecond, 10*time.Millisecond)
+
+	// Closing a tracked connection drops it from the registry.
+	local, remote2 := net.Pipe()
+	defer remote2.Close()
+	tracked = reloader.track(local)
+	require.NoError(t, tracked.Close())
+	reloader.mu.Lock()
+	require.Empty(t, reloader.conns)
+	reloader.mu.Unlock()
+}
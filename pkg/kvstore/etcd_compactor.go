@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"context"
+	"errors"
+
+	v3rpcErrors "go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	client "go.etcd.io/etcd/client/v3"
+
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/time"
+)
+
+const (
+	// compactLockPath is the lock ensuring that a single client issues
+	// compaction requests at a time, serving as a poor man's leader election.
+	compactLockPath = BaseKeyPrefix + "/.compact-lock"
+
+	// compactionCheckInterval is the interval in which revision samples are
+	// taken and due compactions are issued.
+	compactionCheckInterval = 5 * time.Minute
+)
+
+// revisionSample associates an observed etcd store revision with the time it
+// was observed, to translate the time based retention window into a
+// revision to compact to.
+type revisionSample struct {
+	revision int64
+	at       time.Time
+}
+
+// runCompactor periodically compacts the etcd revision history, retaining
+// the given time window. This prevents environments without etcd
+// auto-compaction from accumulating unbounded revision history for Cilium's
+// high-churn prefixes. The compaction is guarded by a distributed lock, so
+// that a single client issues the requests even when the compactor is
+// enabled on multiple members. It blocks until the client context is
+// cancelled.
+func (e *etcdClient) runCompactor(retention time.Duration) {
+	e.logger.Info("Starting kvstore revision compactor",
+		logfields.Interval, compactionCheckInterval,
+		logfields.Duration, retention,
+	)
+
+	ctx := e.client.Ctx()
+	var samples []revisionSample
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(compactionCheckInterval):
+		}
+
+		revision, err := e.currentRevision(ctx)
+		if err != nil {
+			e.logger.Debug("Failed to retrieve current etcd revision",
+				logfields.Error, err,
+			)
+			continue
+		}
+		samples = append(samples, revisionSample{revision: revision, at: time.Now()})
+
+		// Find the most recent sample which already fell out of the
+		// retention window, and drop the older ones.
+		target := -1
+		for i, sample := range samples {
+			if time.Since(sample.at) >= retention {
+				target = i
+			}
+		}
+		if target < 0 {
+			continue
+		}
+		compactTo := samples[target].revision
+		samples = samples[target+1:]
+
+		e.compact(ctx, compactTo)
+	}
+}
+
+// currentRevision returns the current revision of the etcd store.
+func (e *etcdClient) currentRevision(ctx context.Context) (int64, error) {
+	ctxTimeout, cancel := context.WithTimeout(ctx, statusCheckTimeout)
+	defer cancel()
+
+	// The key does not need to exist, we are only interested in the store
+	// revision returned along with the response header.
+	res, err := e.client.Get(ctxTimeout, HeartbeatPath, client.WithSerializable())
+	if err != nil {
+		return 0, Hint(err)
+	}
+	return res.Header.Revision, nil
+}
+
+// compact issues a compaction up to the given revision, if the compaction
+// lock could be acquired.
+func (e *etcdClient) compact(ctx context.Context, revision int64) {
+	locker, err := e.LockPath(ctx, compactLockPath)
+	if err != nil {
+		// Another client holds the lock and takes care of the compaction.
+		e.logger.Debug("Skipping compaction, lock not acquired",
+			logfields.Error, err,
+		)
+		return
+	}
+	defer locker.Unlock(context.Background())
+
+	ctxTimeout, cancel := context.WithTimeout(ctx, statusCheckTimeout)
+	defer cancel()
+
+	switch _, err := e.client.Compact(ctxTimeout, revision); {
+	case err == nil:
+		e.logger.Info("Compacted etcd revision history",
+			fieldRev, revision,
+		)
+	case errors.Is(err, v3rpcErrors.ErrCompacted):
+		// Already compacted past this revision, e.g., by etcd itself or by
+		// another client between the lock acquisition of this one.
+		e.logger.Debug("Etcd revision history already compacted",
+			fieldRev, revision,
+		)
+	default:
+		e.logger.Warn("Failed to compact etcd revision history",
+			logfields.Error, Hint(err),
+			fieldRev, revision,
+		)
+	}
+}
@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"google.golang.org/grpc/connectivity"
+
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/time"
+)
+
+// ConnectionStateEvent describes a gRPC connectivity state transition of the
+// kvstore client connection.
+type ConnectionStateEvent struct {
+	// Target is the target the connection is established towards.
+	Target string
+
+	// From and To are the connectivity states the connection transitioned
+	// between (e.g., READY, TRANSIENT_FAILURE).
+	From, To connectivity.State
+
+	// Timestamp is the time the transition was observed.
+	Timestamp time.Time
+}
+
+// ConnectionStateNotifier is implemented by kvstore clients able to report
+// the connectivity state transitions of the underlying connection.
+type ConnectionStateNotifier interface {
+	// ConnectionStateEvents returns the channel connectivity state
+	// transitions are emitted on. Events are dropped if the channel is not
+	// drained fast enough.
+	ConnectionStateEvents() <-chan ConnectionStateEvent
+}
+
+// connStateEventsBufferSize is the capacity of the connection state events
+// channel. Transitions observed while the channel is full are accounted in
+// the metrics only.
+const connStateEventsBufferSize = 128
+
+// ConnectionStateEvents implements ConnectionStateNotifier.
+func (e *etcdClient) ConnectionStateEvents() <-chan ConnectionStateEvent {
+	return e.connStateEvents
+}
+
+// monitorConnectionState observes the gRPC connectivity state transitions of
+// the client connection, surfacing them through both metrics and the events
+// channel, to allow correlating agent symptoms with etcd connection flaps.
+// It blocks until the client context is cancelled.
+func (e *etcdClient) monitorConnectionState() {
+	conn := e.client.ActiveConnection()
+	state := conn.GetState()
+
+	for conn.WaitForStateChange(e.client.Ctx(), state) {
+		newState := conn.GetState()
+		e.logger.Debug("Observed kvstore connection state transition",
+			logfields.State, newState.String(),
+		)
+		recordConnectivityStateTransition(conn.Target(), state.String(), newState.String())
+
+		select {
+		case e.connStateEvents <- ConnectionStateEvent{
+			Target:    conn.Target(),
+			From:      state,
+			To:        newState,
+			Timestamp: time.Now(),
+		}:
+		default:
+		}
+
+		state = newState
+	}
+}
@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"google.golang.org/grpc/credentials"
+
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/time"
+)
+
+// CredentialProvider supplies short-lived tokens attached as per-RPC
+// credentials to the etcd connection, enabling deployments where etcd is
+// fronted by an auth proxy expecting e.g. a JWT from an external token
+// service.
+type CredentialProvider interface {
+	// Token returns the token to attach to outgoing RPCs, together with its
+	// expiry time. It is invoked lazily when the cached token is about to
+	// expire; implementations may block while fetching a fresh token.
+	Token(ctx context.Context) (token string, expiry time.Time, err error)
+}
+
+// credentialRefreshMargin is how long before expiry a cached token is
+// refreshed, so that in-flight RPCs do not carry an almost-expired token.
+const credentialRefreshMargin = 30 * time.Second
+
+// tokenCredentials implements grpc/credentials.PerRPCCredentials on top of a
+// CredentialProvider, caching the token between RPCs and refreshing it before
+// it expires.
+var _ credentials.PerRPCCredentials = (*tokenCredentials)(nil)
+
+type tokenCredentials struct {
+	logger   *slog.Logger
+	provider CredentialProvider
+
+	mu     lock.Mutex
+	token  string
+	expiry time.Time
+}
+
+func newTokenCredentials(logger *slog.Logger, provider CredentialProvider) *tokenCredentials {
+	return &tokenCredentials{
+		logger:   logger,
+		provider: provider,
+	}
+}
+
+// GetRequestMetadata implements credentials.PerRPCCredentials.
+func (c *tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	token, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"authorization": token}, nil
+}
+
+// RequireTransportSecurity implements credentials.PerRPCCredentials. Like the
+// etcd client's own password credentials, tokens may be sent over plaintext
+// connections, as TLS termination commonly happens at the auth proxy.
+func (c *tokenCredentials) RequireTransportSecurity() bool {
+	return false
+}
+
+// get returns the cached token, refreshing it via the provider when it is
+// about to expire. A fetch failure is tolerated as long as the cached token
+// is still valid.
+func (c *tokenCredentials) get(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && time.Until(c.expiry) > credentialRefreshMargin {
+		return c.token, nil
+	}
+
+	token, expiry, err := c.provider.Token(ctx)
+	if err != nil {
+		if c.token != "" && time.Until(c.expiry) > 0 {
+			c.logger.Warn(
+				"Failed to refresh etcd credentials, using cached token until expiry",
+				logfields.Error, err,
+				logfields.Expiration, c.expiry,
+			)
+			return c.token, nil
+		}
+		return "", fmt.Errorf("failed to fetch etcd credentials: %w", err)
+	}
+
+	c.token = token
+	c.expiry = expiry
+	return c.token, nil
+}
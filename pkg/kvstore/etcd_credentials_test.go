@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cilium/hive/hivetest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cilium/cilium/pkg/time"
+)
+
+type fakeCredentialProvider struct {
+	token   string
+	expiry  time.Time
+	err     error
+	fetches int
+}
+
+func (f *fakeCredentialProvider) Token(ctx context.Context) (string, time.Time, error) {
+	f.fetches++
+	return f.token, f.expiry, f.err
+}
+
+func TestTokenCredentials(t *testing.T) {
+	ctx := context.Background()
+
+	provider := &fakeCredentialProvider{token: "first", expiry: time.Now().Add(time.Hour)}
+	creds := newTokenCredentials(hivetest.Logger(t), provider)
+
+	md, err := creds.GetRequestMetadata(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"authorization": "first"}, md)
+	require.Equal(t, 1, provider.fetches)
+
+	// The token is cached until it approaches expiry.
+	provider.token = "second"
+	md, err = creds.GetRequestMetadata(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "first", md["authorization"])
+	require.Equal(t, 1, provider.fetches)
+
+	// A token within the refresh margin is replaced.
+	creds.expiry = time.Now().Add(credentialRefreshMargin / 2)
+	md, err = creds.GetRequestMetadata(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "second", md["authorization"])
+	require.Equal(t, 2, provider.fetches)
+
+	// A failed refresh falls back to the cached token while still valid.
+	creds.expiry = time.Now().Add(credentialRefreshMargin / 2)
+	provider.err = errors.New("token service unavailable")
+	md, err = creds.GetRequestMetadata(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "second", md["authorization"])
+
+	// Once the cached token expired, the failure is propagated.
+	creds.expiry = time.Now().Add(-time.Second)
+	_, err = creds.GetRequestMetadata(ctx)
+	require.ErrorContains(t, err, "token service unavailable")
+}
@@ -4,9 +4,11 @@
 package kvstore
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"log/slog"
+	"slices"
 	"strings"
 	"sync"
 
@@ -20,9 +22,29 @@ import (
 	"github.com/cilium/cilium/pkg/time"
 )
 
+const (
+	// leaseConsolidationInterval is the interval at which the lease
+	// consolidator evaluates whether the keys spread over many sparsely
+	// used leases can be compacted onto fewer ones.
+	leaseConsolidationInterval = 5 * time.Minute
+
+	// leaseConsolidationMaxKeys bounds the number of keys reassigned during
+	// a single consolidation run, limiting the extra load on etcd.
+	leaseConsolidationMaxKeys = 64
+
+	// keepaliveFailureThreshold is the number of consecutive lease health
+	// check failures after which the keepalive failure observers are
+	// notified, so that a single slow check does not raise false alarms.
+	keepaliveFailureThreshold = 2
+)
+
 type leaseInfo struct {
 	count   uint32
 	session *concurrency.Session
+
+	// draining prevents new keys from being attached to the lease while the
+	// consolidator is reassigning the current ones to other leases.
+	draining bool
 }
 
 // etcdLeaseManager manages the acquisition of the leases, and keeps track of
@@ -35,6 +57,11 @@ type etcdLeaseManager struct {
 	limit   uint32
 	expired func(key string)
 
+	// keepaliveFailed, if set, is invoked with the keys attached to a lease
+	// whose keepalives have been observed to fail repeatedly, before the
+	// lease actually expires.
+	keepaliveFailed func(key string)
+
 	mu      lock.RWMutex
 	leases  map[client.LeaseID]*leaseInfo
 	keys    map[string]client.LeaseID
@@ -45,14 +72,15 @@ type etcdLeaseManager struct {
 }
 
 // newEtcdLeaseManager builds and returns a new lease manager instance.
-func newEtcdLeaseManager(logger *slog.Logger, cl *client.Client, ttl time.Duration, limit uint32, expired func(key string)) *etcdLeaseManager {
+func newEtcdLeaseManager(logger *slog.Logger, cl *client.Client, ttl time.Duration, limit uint32, expired, keepaliveFailed func(key string)) *etcdLeaseManager {
 	return &etcdLeaseManager{
 		client: cl,
 		log:    logger,
 
-		ttl:     ttl,
-		limit:   limit,
-		expired: expired,
+		ttl:             ttl,
+		limit:           limit,
+		expired:         expired,
+		keepaliveFailed: keepaliveFailed,
 
 		current: client.NoLease,
 		leases:  make(map[client.LeaseID]*leaseInfo),
@@ -105,7 +133,7 @@ func (elm *etcdLeaseManager) GetSession(ctx context.Context, key string) (*concu
 	}
 
 	// Return the current lease if it has not been used more than limit times
-	if info := elm.leases[elm.current]; info != nil && info.count < elm.limit {
+	if info := elm.leases[elm.current]; info != nil && !info.draining && info.count < elm.limit {
 		info.count++
 		elm.keys[key] = elm.current
 		elm.mu.Unlock()
@@ -115,7 +143,7 @@ func (elm *etcdLeaseManager) GetSession(ctx context.Context, key string) (*concu
 
 	// Otherwise, loop through the other known leases to see if any has been released
 	for lease, info := range elm.leases {
-		if info.count < elm.limit {
+		if !info.draining && info.count < elm.limit {
 			elm.current = lease
 			info.count++
 			elm.keys[key] = elm.current
@@ -255,6 +283,11 @@ func (elm *etcdLeaseManager) newSession(ctx context.Context) (session *concurren
 	elm.wg.Add(1)
 	go elm.waitForExpiration(session)
 
+	if elm.keepaliveFailed != nil {
+		elm.wg.Add(1)
+		go elm.monitorKeepalives(session)
+	}
+
 	elm.log.Info(
 		"New lease successfully acquired",
 		logfields.LeaseID, leaseID,
@@ -277,6 +310,15 @@ func (elm *etcdLeaseManager) waitForExpiration(session *concurrency.Session) {
 	default:
 	}
 
+	elm.mu.Lock()
+	if _, tracked := elm.leases[session.Lease()]; !tracked {
+		// The lease was intentionally revoked by the consolidator, which
+		// already took care of updating the bookkeeping.
+		elm.mu.Unlock()
+		return
+	}
+	elm.mu.Unlock()
+
 	elm.log.Warn(
 		"Lease expired",
 		logfields.LeaseID, session.Lease(),
@@ -301,6 +343,292 @@ func (elm *etcdLeaseManager) waitForExpiration(session *concurrency.Session) {
 	}
 }
 
+// monitorKeepalives periodically checks the remaining TTL of the given lease,
+// and notifies the keepalive failure observers when it keeps decaying, i.e.,
+// when the keepalives are repeatedly failing, before the lease actually
+// expires. This allows consumers to proactively refresh critical keys, rather
+// than reacting only after downstream nodes have observed the deletion.
+func (elm *etcdLeaseManager) monitorKeepalives(session *concurrency.Session) {
+	defer elm.wg.Done()
+
+	var failures int
+	notified := false
+
+	for {
+		select {
+		case <-session.Done():
+			return
+		case <-elm.client.Ctx().Done():
+			return
+		case <-time.After(elm.ttl / 3):
+		}
+
+		if elm.keepaliveHealthy(session.Lease()) {
+			failures = 0
+			notified = false
+			continue
+		}
+
+		failures++
+		if failures < keepaliveFailureThreshold || notified {
+			continue
+		}
+
+		// Notify only once until the keepalives recover, to avoid repeatedly
+		// triggering the observers while the lease keeps being unhealthy.
+		notified = true
+
+		keys := elm.leaseKeys(session.Lease())
+		elm.log.Warn(
+			"Lease keepalives are failing, notifying observers",
+			logfields.LeaseID, session.Lease(),
+			fieldNumEntries, len(keys),
+		)
+
+		for _, key := range keys {
+			elm.keepaliveFailed(key)
+		}
+	}
+}
+
+// keepaliveHealthy returns whether the keepalives of the given lease appear
+// to be healthy, based on its remaining TTL: a value lower than half of the
+// configured one indicates that the recent keepalives have not been answered.
+func (elm *etcdLeaseManager) keepaliveHealthy(id client.LeaseID) bool {
+	ctx, cancel := context.WithTimeout(elm.client.Ctx(), statusCheckTimeout)
+	defer cancel()
+
+	resp, err := elm.client.TimeToLive(ctx, id)
+	if err != nil {
+		return false
+	}
+
+	return resp.TTL >= int64(elm.ttl.Seconds())/2
+}
+
+// leaseKeys returns the keys currently attached to the given lease.
+func (elm *etcdLeaseManager) leaseKeys(id client.LeaseID) []string {
+	elm.mu.RLock()
+	defer elm.mu.RUnlock()
+
+	var keys []string
+	for key, lease := range elm.keys {
+		if lease == id {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// startConsolidator starts a background process which periodically compacts
+// the keys spread over many sparsely used leases (e.g., after churn) onto
+// fewer ones, reducing the keepalive traffic and the etcd lease bookkeeping.
+// It must not be used for lock leases, as the lock logic depends on the
+// specific lease a lock is attached to. The process terminates when the etcd
+// client is closed.
+func (elm *etcdLeaseManager) startConsolidator() {
+	elm.wg.Add(1)
+	go func() {
+		defer elm.wg.Done()
+		for {
+			select {
+			case <-elm.client.Ctx().Done():
+				return
+			case <-time.After(leaseConsolidationInterval):
+				elm.consolidate(elm.client.Ctx())
+			}
+		}
+	}()
+}
+
+// consolidate reassigns the keys attached to the most sparsely used leases
+// onto the remaining ones, revoking the leases drained this way. It returns
+// the number of reassigned keys, which is bounded per run to limit the extra
+// load on etcd; the remaining candidates are processed by subsequent runs.
+func (elm *etcdLeaseManager) consolidate(ctx context.Context) int {
+	donors := elm.pickDonors()
+	if len(donors) == 0 {
+		return 0
+	}
+
+	var moved int
+	for _, donor := range donors {
+		moved += elm.drainLease(ctx, donor, leaseConsolidationMaxKeys-moved)
+	}
+
+	elm.mu.Lock()
+	var drained []*concurrency.Session
+	for _, donor := range donors {
+		info := elm.leases[donor]
+		if info == nil {
+			continue
+		}
+
+		if info.count == 0 {
+			delete(elm.leases, donor)
+			drained = append(drained, info.session)
+			continue
+		}
+
+		// Not fully drained (e.g., due to concurrent updates or the per-run
+		// budget): return the lease to the general pool, so that it can be
+		// picked again by a subsequent run.
+		info.draining = false
+	}
+	elm.mu.Unlock()
+
+	for _, session := range drained {
+		if err := session.Close(); err != nil {
+			elm.log.Debug(
+				"Failed to revoke drained lease",
+				logfields.Error, err,
+				logfields.LeaseID, session.Lease(),
+			)
+		}
+	}
+
+	if moved > 0 || len(drained) > 0 {
+		elm.log.Info(
+			"Consolidated leases",
+			fieldNumEntries, moved,
+			fieldNumLeases, len(drained),
+		)
+	}
+	return moved
+}
+
+// pickDonors selects the leases to drain so that the attached keys can fit
+// onto the minimum number of leases, marking them as draining to prevent new
+// keys from being attached in the meanwhile. The lease currently in use for
+// new keys is never selected.
+func (elm *etcdLeaseManager) pickDonors() []client.LeaseID {
+	elm.mu.Lock()
+	defer elm.mu.Unlock()
+
+	if elm.limit == 0 {
+		return nil
+	}
+
+	var total uint32
+	for _, info := range elm.leases {
+		total += info.count
+	}
+
+	needed := int((total + elm.limit - 1) / elm.limit)
+	if len(elm.leases) <= needed {
+		return nil
+	}
+
+	candidates := make([]client.LeaseID, 0, len(elm.leases))
+	for id := range elm.leases {
+		if id != elm.current {
+			candidates = append(candidates, id)
+		}
+	}
+
+	// Donate from the most sparsely used leases first.
+	slices.SortFunc(candidates, func(a, b client.LeaseID) int {
+		return cmp.Compare(elm.leases[a].count, elm.leases[b].count)
+	})
+
+	donors := candidates[:min(len(candidates), len(elm.leases)-needed)]
+	for _, id := range donors {
+		elm.leases[id].draining = true
+	}
+	return donors
+}
+
+// drainLease reassigns up to budget keys attached to the given lease onto
+// leases with spare capacity, returning the number of reassigned keys.
+func (elm *etcdLeaseManager) drainLease(ctx context.Context, donor client.LeaseID, budget int) int {
+	var moved int
+	skip := make(map[string]struct{})
+	for moved < budget {
+		key, target, ok := elm.reserve(donor, skip)
+		if !ok {
+			break
+		}
+
+		if elm.reassign(ctx, key, donor, target) {
+			moved++
+		} else {
+			// Skip the key for the remainder of this run, so that repeated
+			// conflicts cannot turn the drain into a hot loop.
+			skip[key] = struct{}{}
+		}
+	}
+	return moved
+}
+
+// reserve picks a key attached to the donor lease and a target lease with
+// spare capacity, reserving a slot on the latter. The reservation is rolled
+// back by reassign if the move does not complete.
+func (elm *etcdLeaseManager) reserve(donor client.LeaseID, skip map[string]struct{}) (string, *leaseInfo, bool) {
+	elm.mu.Lock()
+	defer elm.mu.Unlock()
+
+	var key string
+	var found bool
+	for candidate, id := range elm.keys {
+		if id == donor {
+			if _, skipped := skip[candidate]; skipped {
+				continue
+			}
+			key, found = candidate, true
+			break
+		}
+	}
+	if !found {
+		return "", nil, false
+	}
+
+	for id, info := range elm.leases {
+		if id != donor && !info.draining && info.count < elm.limit {
+			info.count++
+			return key, info, true
+		}
+	}
+	return "", nil, false
+}
+
+// reassign re-puts the key to attach it to the target lease, guarded on the
+// value not having been modified in the meanwhile. Conflicting keys are left
+// untouched, as the concurrent writer re-picked a lease on its own.
+func (elm *etcdLeaseManager) reassign(ctx context.Context, key string, donor client.LeaseID, target *leaseInfo) (success bool) {
+	defer func(duration *spanstat.SpanStat) {
+		increaseMetric("lease", metricSet, "ConsolidateLease", duration.End(success).Total(), nil)
+	}(spanstat.Start())
+
+	var applied bool
+	resp, err := elm.client.Get(ctx, key)
+	if err == nil && len(resp.Kvs) > 0 {
+		kv := resp.Kvs[0]
+		txnresp, err := elm.client.Txn(ctx).
+			If(client.Compare(client.ModRevision(key), "=", kv.ModRevision)).
+			Then(client.OpPut(key, string(kv.Value), client.WithLease(target.session.Lease()))).
+			Commit()
+		applied = err == nil && txnresp.Succeeded
+	}
+
+	elm.mu.Lock()
+	defer elm.mu.Unlock()
+
+	// Roll back the reservation if the move did not complete, or the key was
+	// released or reassigned in the meanwhile.
+	if !applied || elm.keys[key] != donor {
+		if target.count > 0 {
+			target.count--
+		}
+		return false
+	}
+
+	elm.keys[key] = target.session.Lease()
+	if info := elm.leases[donor]; info != nil && info.count > 0 {
+		info.count--
+	}
+	return true
+}
+
 func (elm *etcdLeaseManager) releaseUnlocked(key string) {
 	leaseID := elm.keys[key]
 	if leaseID != client.NoLease {
@@ -8,11 +8,14 @@ import (
 	"errors"
 	"fmt"
 	"slices"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/cilium/hive/hivetest"
 	"github.com/stretchr/testify/require"
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
 	v3rpcErrors "go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 	client "go.etcd.io/etcd/client/v3"
 )
@@ -29,6 +32,9 @@ type fakeEtcdLeaseClient struct {
 
 	lease    client.LeaseID
 	contexts map[client.LeaseID]context.Context
+
+	// timeToLive, if set, overrides the result of the TimeToLive calls.
+	timeToLive func(id client.LeaseID) (*client.LeaseTimeToLiveResponse, error)
 }
 
 func newFakeEtcdClient(leases *fakeEtcdLeaseClient) *client.Client {
@@ -75,6 +81,9 @@ func (f *fakeEtcdLeaseClient) Revoke(ctx context.Context, id client.LeaseID) (*c
 	return nil, ErrNotImplemented
 }
 func (f *fakeEtcdLeaseClient) TimeToLive(ctx context.Context, id client.LeaseID, opts ...client.LeaseOption) (*client.LeaseTimeToLiveResponse, error) {
+	if f.timeToLive != nil {
+		return f.timeToLive(id)
+	}
 	return nil, ErrNotImplemented
 }
 func (f *fakeEtcdLeaseClient) Leases(ctx context.Context) (*client.LeaseLeasesResponse, error) {
@@ -88,7 +97,7 @@ func (f *fakeEtcdLeaseClient) Close() error { return ErrNotImplemented }
 func TestLeaseManager(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	cl := newFakeEtcdLeaseClient(ctx, 10)
-	mgr := newEtcdLeaseManager(hivetest.Logger(t), newFakeEtcdClient(&cl), 10*time.Second, 5, nil)
+	mgr := newEtcdLeaseManager(hivetest.Logger(t), newFakeEtcdClient(&cl), 10*time.Second, 5, nil, nil)
 
 	t.Cleanup(func() {
 		cancel()
@@ -147,7 +156,7 @@ func TestLeaseManager(t *testing.T) {
 func TestLeaseManagerParallel(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	cl := newFakeEtcdLeaseClient(ctx, 10)
-	mgr := newEtcdLeaseManager(hivetest.Logger(t), newFakeEtcdClient(&cl), 10*time.Second, 5, nil)
+	mgr := newEtcdLeaseManager(hivetest.Logger(t), newFakeEtcdClient(&cl), 10*time.Second, 5, nil, nil)
 
 	t.Cleanup(func() {
 		cancel()
@@ -182,7 +191,7 @@ func TestLeaseManagerParallel(t *testing.T) {
 func TestLeaseManagerReleasePrefix(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	cl := newFakeEtcdLeaseClient(ctx, 10)
-	mgr := newEtcdLeaseManager(hivetest.Logger(t), newFakeEtcdClient(&cl), 10*time.Second, 5, nil)
+	mgr := newEtcdLeaseManager(hivetest.Logger(t), newFakeEtcdClient(&cl), 10*time.Second, 5, nil, nil)
 
 	t.Cleanup(func() {
 		cancel()
@@ -213,7 +222,7 @@ func TestLeaseManagerCancelIfExpired(t *testing.T) {
 
 	ctx, cancel := context.WithCancel(context.Background())
 	cl := newFakeEtcdLeaseClient(ctx, 10)
-	mgr := newEtcdLeaseManager(hivetest.Logger(t), newFakeEtcdClient(&cl), 10*time.Second, 5, observer)
+	mgr := newEtcdLeaseManager(hivetest.Logger(t), newFakeEtcdClient(&cl), 10*time.Second, 5, observer, nil)
 
 	t.Cleanup(func() {
 		close(expiredCH)
@@ -257,10 +266,239 @@ func TestLeaseManagerCancelIfExpired(t *testing.T) {
 	require.Equal(t, client.LeaseID(4), leaseID)
 }
 
+// fakeEtcdKVClient implements the subset of the KV interface exercised by the
+// lease consolidator: Get, and Txn guarded on the mod revision of the key.
+type fakeEtcdKVClient struct {
+	kvs map[string]*mvccpb.KeyValue
+
+	// onGet, if set, is invoked after each Get, to simulate concurrent updates.
+	onGet func(key string)
+}
+
+func (f *fakeEtcdKVClient) Get(ctx context.Context, key string, opts ...client.OpOption) (*client.GetResponse, error) {
+	kv, ok := f.kvs[key]
+	if !ok {
+		return &client.GetResponse{}, nil
+	}
+
+	resp := &client.GetResponse{Kvs: []*mvccpb.KeyValue{{
+		Key: kv.Key, Value: kv.Value, ModRevision: kv.ModRevision,
+	}}, Count: 1}
+
+	if f.onGet != nil {
+		f.onGet(key)
+	}
+	return resp, nil
+}
+
+func (f *fakeEtcdKVClient) Put(ctx context.Context, key, val string, opts ...client.OpOption) (*client.PutResponse, error) {
+	return nil, ErrNotImplemented
+}
+func (f *fakeEtcdKVClient) Delete(ctx context.Context, key string, opts ...client.OpOption) (*client.DeleteResponse, error) {
+	return nil, ErrNotImplemented
+}
+func (f *fakeEtcdKVClient) Compact(ctx context.Context, rev int64, opts ...client.CompactOption) (*client.CompactResponse, error) {
+	return nil, ErrNotImplemented
+}
+func (f *fakeEtcdKVClient) Do(ctx context.Context, op client.Op) (client.OpResponse, error) {
+	return client.OpResponse{}, ErrNotImplemented
+}
+func (f *fakeEtcdKVClient) Txn(ctx context.Context) client.Txn {
+	return &fakeEtcdTxn{kv: f}
+}
+
+type fakeEtcdTxn struct {
+	kv   *fakeEtcdKVClient
+	cmps []client.Cmp
+	then []client.Op
+}
+
+func (t *fakeEtcdTxn) If(cmps ...client.Cmp) client.Txn { t.cmps = cmps; return t }
+func (t *fakeEtcdTxn) Then(ops ...client.Op) client.Txn { t.then = ops; return t }
+func (t *fakeEtcdTxn) Else(ops ...client.Op) client.Txn { return t }
+func (t *fakeEtcdTxn) Commit() (*client.TxnResponse, error) {
+	for i := range t.cmps {
+		// The consolidator only emits mod revision equality comparisons.
+		rev := t.cmps[i].TargetUnion.(*pb.Compare_ModRevision).ModRevision
+		var current int64
+		if kv := t.kv.kvs[string(t.cmps[i].KeyBytes())]; kv != nil {
+			current = kv.ModRevision
+		}
+		if current != rev {
+			return &client.TxnResponse{}, nil
+		}
+	}
+
+	for _, op := range t.then {
+		key := string(op.KeyBytes())
+		kv := t.kv.kvs[key]
+		if kv == nil {
+			kv = &mvccpb.KeyValue{Key: []byte(key)}
+			t.kv.kvs[key] = kv
+		}
+		kv.Value = op.ValueBytes()
+		kv.ModRevision++
+	}
+	return &client.TxnResponse{Succeeded: true}, nil
+}
+
+func TestLeaseManagerConsolidate(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cl := newFakeEtcdLeaseClient(ctx, 10)
+	etcd := newFakeEtcdClient(&cl)
+	kv := &fakeEtcdKVClient{kvs: make(map[string]*mvccpb.KeyValue)}
+	etcd.KV = kv
+	mgr := newEtcdLeaseManager(hivetest.Logger(t), etcd, 10*time.Second, 5, nil, nil)
+
+	t.Cleanup(func() {
+		cancel()
+		mgr.Wait()
+	})
+
+	for i := range 10 {
+		key := fmt.Sprintf("key%d", i)
+		leaseID, err := mgr.GetLeaseID(ctx, key)
+		require.NoError(t, err, "GetLeaseID should succeed")
+		require.Equal(t, client.LeaseID(1+i/5), leaseID)
+		kv.kvs[key] = &mvccpb.KeyValue{Key: []byte(key), Value: []byte("value"), ModRevision: int64(i + 1)}
+	}
+
+	// Both leases are fully used: there's nothing to consolidate.
+	require.Equal(t, 0, mgr.consolidate(ctx))
+	require.Equal(t, uint32(2), mgr.TotalLeases())
+
+	// Release most keys attached to the first lease, and a couple attached to
+	// the second (i.e., current) one, so that the remaining key fits there.
+	for i := range 4 {
+		mgr.Release(fmt.Sprintf("key%d", i))
+	}
+	mgr.Release("key8")
+	mgr.Release("key9")
+
+	// The remaining key should have been reassigned to the second lease, and
+	// the first one revoked.
+	require.Equal(t, 1, mgr.consolidate(ctx))
+	require.Equal(t, uint32(1), mgr.TotalLeases())
+	require.True(t, mgr.KeyHasLease("key4", client.LeaseID(2)))
+
+	// A subsequent run has nothing left to do.
+	require.Equal(t, 0, mgr.consolidate(ctx))
+
+	// New keys can still be attached through the surviving lease.
+	leaseID, err := mgr.GetLeaseID(ctx, "key10")
+	require.NoError(t, err, "GetLeaseID should succeed")
+	require.Equal(t, client.LeaseID(2), leaseID)
+}
+
+func TestLeaseManagerConsolidateConflict(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cl := newFakeEtcdLeaseClient(ctx, 10)
+	etcd := newFakeEtcdClient(&cl)
+	kv := &fakeEtcdKVClient{kvs: make(map[string]*mvccpb.KeyValue)}
+	etcd.KV = kv
+	mgr := newEtcdLeaseManager(hivetest.Logger(t), etcd, 10*time.Second, 5, nil, nil)
+
+	t.Cleanup(func() {
+		cancel()
+		mgr.Wait()
+	})
+
+	for i := range 10 {
+		key := fmt.Sprintf("key%d", i)
+		leaseID, err := mgr.GetLeaseID(ctx, key)
+		require.NoError(t, err, "GetLeaseID should succeed")
+		require.Equal(t, client.LeaseID(1+i/5), leaseID)
+		kv.kvs[key] = &mvccpb.KeyValue{Key: []byte(key), Value: []byte("value"), ModRevision: int64(i + 1)}
+	}
+
+	for i := range 4 {
+		mgr.Release(fmt.Sprintf("key%d", i))
+	}
+	mgr.Release("key9")
+
+	// Simulate a concurrent update of the remaining key attached to the first
+	// lease: the mod revision no longer matches the one observed by Get, hence
+	// the reassignment must be skipped, and the lease retained.
+	kv.onGet = func(key string) { kv.kvs[key].ModRevision++ }
+
+	require.Equal(t, 0, mgr.consolidate(ctx))
+	require.Equal(t, uint32(2), mgr.TotalLeases())
+	require.True(t, mgr.KeyHasLease("key4", client.LeaseID(1)))
+}
+
+func TestLeaseManagerKeepaliveFailure(t *testing.T) {
+	failedCh := make(chan string, 16)
+	observer := func(key string) { failedCh <- key }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cl := newFakeEtcdLeaseClient(ctx, 0)
+
+	var healthy atomic.Bool
+	healthy.Store(true)
+	cl.timeToLive = func(id client.LeaseID) (*client.LeaseTimeToLiveResponse, error) {
+		if healthy.Load() {
+			return &client.LeaseTimeToLiveResponse{ID: id, TTL: 1}, nil
+		}
+		return nil, errors.New("keepalive failed")
+	}
+
+	// Use a short TTL so that the keepalive monitor runs frequently.
+	mgr := newEtcdLeaseManager(hivetest.Logger(t), newFakeEtcdClient(&cl), 300*time.Millisecond, 5, nil, observer)
+
+	t.Cleanup(func() {
+		cancel()
+		mgr.Wait()
+	})
+
+	for i := range 3 {
+		_, err := mgr.GetLeaseID(ctx, fmt.Sprintf("key%d", i))
+		require.NoError(t, err, "GetLeaseID should succeed")
+	}
+
+	expectKeys := func() {
+		t.Helper()
+		var keys []string
+		for range 3 {
+			select {
+			case key := <-failedCh:
+				keys = append(keys, key)
+			case <-time.After(5 * time.Second):
+				t.Fatal("timed out waiting for keepalive failure notifications")
+			}
+		}
+		require.ElementsMatch(t, keys, []string{"key0", "key1", "key2"})
+	}
+
+	expectNoKeys := func() {
+		t.Helper()
+		select {
+		case key := <-failedCh:
+			t.Fatalf("unexpected keepalive failure notification for key %s", key)
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+
+	// No notification is emitted while the keepalives are healthy.
+	expectNoKeys()
+
+	// The observer is notified about all the attached keys once the
+	// keepalives fail repeatedly, but only once until they recover.
+	healthy.Store(false)
+	expectKeys()
+	expectNoKeys()
+
+	// The observer is notified again if the keepalives fail after having
+	// recovered in the meanwhile.
+	healthy.Store(true)
+	expectNoKeys()
+	healthy.Store(false)
+	expectKeys()
+}
+
 func TestLeaseManagerKeyHasLease(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	cl := newFakeEtcdLeaseClient(ctx, 10)
-	mgr := newEtcdLeaseManager(hivetest.Logger(t), newFakeEtcdClient(&cl), 10*time.Second, 5, nil)
+	mgr := newEtcdLeaseManager(hivetest.Logger(t), newFakeEtcdClient(&cl), 10*time.Second, 5, nil, nil)
 
 	t.Cleanup(func() {
 		cancel()
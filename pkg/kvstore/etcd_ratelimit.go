@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"log/slog"
+
+	"golang.org/x/time/rate"
+
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	ciliumrate "github.com/cilium/cilium/pkg/rate"
+	"github.com/cilium/cilium/pkg/time"
+)
+
+const (
+	// adaptiveRateLimitMinFactor bounds how far the rate limit can be lowered
+	// with respect to the configured one while etcd is degraded.
+	adaptiveRateLimitMinFactor = 0.125
+
+	// adaptiveRateLimitBackoffFactor is the multiplicative decrease applied to
+	// the rate limit upon each status check observing a degraded etcd.
+	adaptiveRateLimitBackoffFactor = 0.5
+
+	// adaptiveRateLimitRecoveryFactor is the multiplicative increase applied
+	// to the rate limit upon each healthy status check, until the configured
+	// rate is restored.
+	adaptiveRateLimitRecoveryFactor = 1.25
+
+	// adaptiveRateLimitLatencyThreshold is the quorum check duration above
+	// which etcd is considered degraded even if the check itself succeeded.
+	adaptiveRateLimitLatencyThreshold = 1 * time.Second
+)
+
+// adaptiveRateLimit tunes the rate of the shared API limiter based on the
+// etcd health observed by the status checker: the rate is multiplicatively
+// decreased upon quorum errors or slow quorum checks, and ramped back up
+// towards the configured limit while the cluster stays healthy, so that a
+// struggling etcd is not hammered with the full request load.
+type adaptiveRateLimit struct {
+	logger  *slog.Logger
+	limiter *ciliumrate.APILimiter
+
+	// bootstrap, if non-nil, inhibits any adjustment until closed, to avoid
+	// interfering with the bootstrap rate limit handling.
+	bootstrap <-chan struct{}
+
+	// baseline is the configured rate limit, i.e. the upper bound.
+	baseline float64
+
+	// current is the rate limit currently applied. It is only accessed by the
+	// status checker.
+	current float64
+}
+
+func newAdaptiveRateLimit(logger *slog.Logger, limiter *ciliumrate.APILimiter, baseline int, bootstrap <-chan struct{}) *adaptiveRateLimit {
+	recordRateLimit(float64(baseline))
+	return &adaptiveRateLimit{
+		logger:    logger,
+		limiter:   limiter,
+		bootstrap: bootstrap,
+		baseline:  float64(baseline),
+		current:   float64(baseline),
+	}
+}
+
+// observe adjusts the rate limit based on the outcome and duration of the
+// latest quorum check. It is invoked by the status checker after each check.
+func (a *adaptiveRateLimit) observe(healthy bool, latency time.Duration) {
+	if a.bootstrap != nil {
+		select {
+		case <-a.bootstrap:
+		default:
+			return
+		}
+	}
+
+	var target float64
+	if !healthy || latency > adaptiveRateLimitLatencyThreshold {
+		target = max(a.current*adaptiveRateLimitBackoffFactor, a.baseline*adaptiveRateLimitMinFactor)
+	} else {
+		target = min(a.current*adaptiveRateLimitRecoveryFactor, a.baseline)
+	}
+
+	if target == a.current {
+		return
+	}
+
+	a.logger.Info(
+		"Adjusting client QPS limit based on etcd health",
+		logfields.EtcdQPSLimit, target,
+		fieldSuccess, healthy,
+		logfields.Duration, latency,
+	)
+
+	a.current = target
+	a.limiter.SetRateLimit(rate.Limit(target))
+	recordRateLimit(target)
+}
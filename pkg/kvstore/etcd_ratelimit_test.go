@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"testing"
+
+	"github.com/cilium/hive/hivetest"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+
+	ciliumrate "github.com/cilium/cilium/pkg/rate"
+	"github.com/cilium/cilium/pkg/time"
+)
+
+func TestAdaptiveRateLimit(t *testing.T) {
+	logger := hivetest.Logger(t)
+	limiter := ciliumrate.NewAPILimiter(logger, "etcd-test", ciliumrate.APILimiterParameters{
+		RateLimit: rate.Limit(100),
+		RateBurst: 100,
+	}, nil)
+
+	adaptive := newAdaptiveRateLimit(logger, limiter, 100, nil)
+	require.EqualValues(t, 100, adaptive.current)
+
+	// Degraded checks multiplicatively decrease the rate, down to the floor.
+	adaptive.observe(false, time.Millisecond)
+	require.EqualValues(t, 50, adaptive.current)
+
+	for range 10 {
+		adaptive.observe(false, time.Millisecond)
+	}
+	require.EqualValues(t, 100*adaptiveRateLimitMinFactor, adaptive.current)
+
+	// A slow quorum check is treated as degraded even if it succeeded.
+	adaptive.observe(true, time.Millisecond)
+	current := adaptive.current
+	adaptive.observe(true, 2*adaptiveRateLimitLatencyThreshold)
+	require.Less(t, adaptive.current, current)
+
+	// Healthy checks ramp the rate back up, capped at the configured limit.
+	for range 20 {
+		adaptive.observe(true, time.Millisecond)
+	}
+	require.EqualValues(t, 100, adaptive.current)
+}
+
+func TestAdaptiveRateLimitBootstrap(t *testing.T) {
+	logger := hivetest.Logger(t)
+	limiter := ciliumrate.NewAPILimiter(logger, "etcd-test", ciliumrate.APILimiterParameters{
+		RateLimit: rate.Limit(100),
+		RateBurst: 100,
+	}, nil)
+
+	bootstrap := make(chan struct{})
+	adaptive := newAdaptiveRateLimit(logger, limiter, 100, bootstrap)
+
+	// No adjustment takes place while bootstrap is in progress, to avoid
+	// interfering with the bootstrap rate limit.
+	adaptive.observe(false, time.Millisecond)
+	require.EqualValues(t, 100, adaptive.current)
+
+	close(bootstrap)
+	adaptive.observe(false, time.Millisecond)
+	require.EqualValues(t, 50, adaptive.current)
+}
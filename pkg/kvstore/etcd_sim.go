@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"context"
+	"math/rand/v2"
+
+	"github.com/cilium/cilium/pkg/backoff"
+	"github.com/cilium/cilium/pkg/time"
+)
+
+// simulation groups the clock and randomness injection points of the etcd
+// client. In production it is backed by the real clock and the global random
+// number generator; tests can swap it via setSimulation to run the
+// watch/retry logic deterministically, without sleeping real time.
+type simulation struct {
+	// Sleep blocks for the given duration, or until the context is
+	// cancelled.
+	Sleep func(ctx context.Context, d time.Duration)
+
+	// BackoffWait waits according to the given exponential backoff limiter.
+	BackoffWait func(ctx context.Context, b *backoff.Exponential) error
+
+	// Shuffle randomizes the order of n elements through the swap function,
+	// as used to spread client connections across the etcd endpoints.
+	Shuffle func(n int, swap func(i, j int))
+
+	// Uint64 returns a random number, as used to construct unique lock
+	// paths.
+	Uint64 func() uint64
+}
+
+// sim is the active simulation. It must only be replaced via setSimulation,
+// before any client has been created.
+var sim = defaultSimulation()
+
+func defaultSimulation() simulation {
+	return simulation{
+		Sleep: func(ctx context.Context, d time.Duration) {
+			select {
+			case <-ctx.Done():
+			case <-time.After(d):
+			}
+		},
+		BackoffWait: func(ctx context.Context, b *backoff.Exponential) error {
+			return b.Wait(ctx)
+		},
+		Shuffle: rand.Shuffle,
+		Uint64:  rand.Uint64,
+	}
+}
+
+// setSimulation overrides the clock and randomness sources of the etcd
+// client, and returns a function restoring the defaults. Zero fields of the
+// provided simulation are left at their default. It is intended for tests
+// only, and must not be called concurrently with client operations.
+func setSimulation(s simulation) (restore func()) {
+	previous := sim
+
+	if s.Sleep != nil {
+		sim.Sleep = s.Sleep
+	}
+	if s.BackoffWait != nil {
+		sim.BackoffWait = s.BackoffWait
+	}
+	if s.Shuffle != nil {
+		sim.Shuffle = s.Shuffle
+	}
+	if s.Uint64 != nil {
+		sim.Uint64 = s.Uint64
+	}
+
+	return func() { sim = previous }
+}
@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cilium/cilium/pkg/backoff"
+	"github.com/cilium/cilium/pkg/time"
+)
+
+func TestSetSimulation(t *testing.T) {
+	var sleeps []time.Duration
+	var waits int
+
+	restore := setSimulation(simulation{
+		Sleep:       func(ctx context.Context, d time.Duration) { sleeps = append(sleeps, d) },
+		BackoffWait: func(ctx context.Context, b *backoff.Exponential) error { waits++; return nil },
+		// Reverse instead of shuffling, for a deterministic order.
+		Shuffle: func(n int, swap func(i, j int)) {
+			for i, j := 0, n-1; i < j; i, j = i+1, j-1 {
+				swap(i, j)
+			}
+		},
+		Uint64: func() uint64 { return 42 },
+	})
+	defer restore()
+
+	// The injected hooks replace their real counterparts: no real time is
+	// slept and the endpoint order is deterministic.
+	sim.Sleep(t.Context(), time.Hour)
+	assert.Equal(t, []time.Duration{time.Hour}, sleeps)
+
+	assert.NoError(t, sim.BackoffWait(t.Context(), &backoff.Exponential{Min: time.Hour}))
+	assert.Equal(t, 1, waits)
+
+	endpoints := []string{"a", "b", "c"}
+	shuffleEndpoints(endpoints)
+	assert.Equal(t, []string{"c", "b", "a"}, endpoints)
+
+	assert.Equal(t, uint64(42), sim.Uint64())
+
+	// Restoring brings back the defaults.
+	restore()
+	shuffleEndpoints(endpoints)
+	assert.Empty(t, sleeps[1:])
+}
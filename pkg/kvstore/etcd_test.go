@@ -19,6 +19,7 @@ import (
 	etcdAPI "go.etcd.io/etcd/client/v3"
 	"k8s.io/apimachinery/pkg/util/rand"
 
+	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/pkg/testutils"
 )
 
@@ -293,6 +294,40 @@ func TestDeleteIfLocked(t *testing.T) {
 	}
 }
 
+func TestDeletePrefixIfLocked(t *testing.T) {
+	client, e := setupEtcdLockedSuite(t)
+
+	randomPath := t.TempDir()
+	prefix := randomPath + "/foo"
+
+	// Deleting a locked prefix removes all the keys under it.
+	kvlocker, err := client.LockPath(context.Background(), "locks/"+prefix+"/.lock")
+	require.NoError(t, err)
+	_, err = e.Put(context.Background(), prefix+"/key1", "bar")
+	require.NoError(t, err)
+	_, err = e.Put(context.Background(), prefix+"/key2", "baz")
+	require.NoError(t, err)
+
+	require.NoError(t, client.DeletePrefixIfLocked(context.TODO(), prefix, kvlocker))
+	gr, err := e.Get(context.Background(), prefix, etcdAPI.WithPrefix())
+	require.NoError(t, err)
+	require.Equal(t, int64(0), gr.Count)
+	require.NoError(t, kvlocker.Unlock(context.TODO()))
+
+	// If the lock was lost, the keys are left untouched.
+	kvlocker, err = client.LockPath(context.Background(), "locks/"+prefix+"/.lock")
+	require.NoError(t, err)
+	_, err = e.Put(context.Background(), prefix+"/key1", "bar")
+	require.NoError(t, err)
+	require.NoError(t, kvlocker.Unlock(context.TODO()))
+
+	require.Equal(t, ErrLockLeaseExpired, client.DeletePrefixIfLocked(context.TODO(), prefix, kvlocker))
+	gr, err = e.Get(context.Background(), prefix, etcdAPI.WithPrefix())
+	require.NoError(t, err)
+	require.Equal(t, int64(1), gr.Count)
+	require.Equal(t, []byte("bar"), gr.Kvs[0].Value)
+}
+
 func TestUpdateIfLocked(t *testing.T) {
 	client, e := setupEtcdLockedSuite(t)
 
@@ -1196,6 +1231,132 @@ func TestShuffleEndpoints(t *testing.T) {
 	}
 }
 
+func TestValidateValueSize(t *testing.T) {
+	e := &etcdClient{maxRequestBytes: 16}
+
+	require.NoError(t, e.validateValueSize("cilium/state/foo", "Update", make([]byte, 16)))
+
+	err := e.validateValueSize("cilium/state/foo", "Update", make([]byte, 17))
+	require.ErrorIs(t, err, ErrTooLarge)
+}
+
+func TestScopeTimeouts(t *testing.T) {
+	timeouts, err := parseScopeTimeouts("ip/v1=5s,identities/v1=30s")
+	require.NoError(t, err)
+	require.Equal(t, map[string]time.Duration{
+		"ip/v1":         5 * time.Second,
+		"identities/v1": 30 * time.Second,
+	}, timeouts)
+
+	_, err = parseScopeTimeouts("ip/v1")
+	require.Error(t, err)
+	_, err = parseScopeTimeouts("ip/v1=bogus")
+	require.Error(t, err)
+
+	e := &etcdClient{scopeTimeouts: timeouts}
+
+	// Keys of a configured scope get a deadline matching the budget.
+	ctx, cancel := e.opTimeout(context.Background(), "cilium/state/ip/v1/default/1.2.3.4")
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	require.True(t, ok)
+	require.WithinDuration(t, time.Now().Add(5*time.Second), deadline, time.Second)
+
+	// Keys of other scopes are not affected.
+	ctx, cancel = e.opTimeout(context.Background(), "cilium/state/nodes/v1/default/foo")
+	defer cancel()
+	_, ok = ctx.Deadline()
+	require.False(t, ok)
+}
+
+func TestStatusSnapshot(t *testing.T) {
+	e := &etcdClient{}
+	e.status.Store(&statusSnapshot{
+		Status:  models.Status{State: models.StatusStateWarning, Msg: "initial"},
+		changed: make(chan struct{}),
+	})
+
+	require.Equal(t, models.StatusStateWarning, e.Status().State)
+
+	changed := e.StatusChanged()
+	select {
+	case <-changed:
+		t.Fatal("StatusChanged() channel unexpectedly closed")
+	default:
+	}
+
+	e.setStatus(models.StatusStateOk, "connected")
+
+	// The channel retrieved before the update is now closed, and the new
+	// snapshot is visible.
+	select {
+	case <-changed:
+	default:
+		t.Fatal("StatusChanged() channel not closed upon status update")
+	}
+
+	status := e.Status()
+	require.Equal(t, models.StatusStateOk, status.State)
+	require.Equal(t, "connected", status.Msg)
+}
+
+func TestEtcdConnectionOptions(t *testing.T) {
+	m := newEtcdModule().(*etcdModule)
+	for _, opt := range []string{
+		EtcdOptionInitialConnectionTimeout,
+		EtcdOptionRetryBackoffBase,
+		EtcdOptionRetryBackoffMax,
+	} {
+		require.NoError(t, m.opts[opt].validate("30s"))
+		require.Error(t, m.opts[opt].validate("bogus"))
+	}
+}
+
+func TestEtcdNamespace(t *testing.T) {
+	testutils.IntegrationTest(t)
+
+	opts := maps.Clone(etcdOpts)
+	opts[EtcdOptionNamespace] = "cilium-test-ns/"
+	client := SetupDummyWithConfigOpts(t, "etcd", opts)
+
+	// Initialize a separate etcd client without the namespace applied, to
+	// inspect the actually stored keys.
+	cfg := etcdAPI.Config{
+		Endpoints:   []string{etcdDummyAddress},
+		DialTimeout: 5 * time.Second,
+	}
+	raw, err := etcdAPI.New(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, raw.Close()) })
+
+	ctx := context.Background()
+	require.NoError(t, client.Update(ctx, "test/namespaced/key", []byte("value"), true))
+
+	// The key is transparently stored under the namespace prefix.
+	resp, err := raw.Get(ctx, "cilium-test-ns/test/namespaced/key")
+	require.NoError(t, err)
+	require.Len(t, resp.Kvs, 1)
+	require.Equal(t, []byte("value"), resp.Kvs[0].Value)
+	require.NotZero(t, resp.Kvs[0].Lease)
+
+	resp, err = raw.Get(ctx, "test/namespaced/key")
+	require.NoError(t, err)
+	require.Empty(t, resp.Kvs)
+
+	// Reads and watches through the namespaced client are unaffected.
+	value, err := client.Get(ctx, "test/namespaced/key")
+	require.NoError(t, err)
+	require.Equal(t, []byte("value"), value)
+
+	events := client.ListAndWatch(ctx, "test/namespaced")
+	for event := range events {
+		if event.Typ == EventTypeCreate {
+			require.Equal(t, "test/namespaced/key", event.Key)
+			break
+		}
+	}
+}
+
 func TestEtcdRateLimiter(t *testing.T) {
 	testutils.IntegrationTest(t)
 
@@ -1362,6 +1523,15 @@ func testEtcdRateLimiter(t *testing.T, qps, count int, cmp func(require.TestingT
 			useKVLocker:     true,
 			populateKVPairs: true,
 		},
+		{
+			fn: func(t *testing.T, client BackendOperations, key string, k int, locker KVLocker) {
+				err := client.DeletePrefixIfLocked(ctx, getKey(k), locker)
+				require.NoError(t, err)
+			},
+			name:            "DeletePrefixIfLocked",
+			useKVLocker:     true,
+			populateKVPairs: true,
+		},
 	}
 
 	for _, tt := range tests {
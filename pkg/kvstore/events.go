@@ -6,6 +6,7 @@ import (
 	"context"
 
 	"github.com/cilium/cilium/pkg/spanstat"
+	"github.com/cilium/cilium/pkg/time"
 )
 
 // EventType defines the type of watch event that occurred
@@ -20,6 +21,10 @@ const (
 	EventTypeDelete
 	//EventTypeListDone signals that the initial list operation has completed
 	EventTypeListDone
+	// EventTypeDrain is the terminal event emitted right before the events
+	// channel is closed. Its Value carries the human readable reason for
+	// which the watcher stopped, empty for a regular shutdown.
+	EventTypeDrain
 )
 
 // String() returns the human readable format of an event type
@@ -33,6 +38,8 @@ func (t EventType) String() string {
 		return "delete"
 	case EventTypeListDone:
 		return "listDone"
+	case EventTypeDrain:
+		return "drain"
 	default:
 		return "unknown"
 	}
@@ -48,6 +55,13 @@ type KeyValueEvent struct {
 
 	// Value is the kvstore value associated with the key
 	Value []byte
+
+	// Seq is a per-prefix sequence number, increasing by exactly one for
+	// every event emitted on the watch, including across relists triggered
+	// by compaction or watch errors. The first event carries sequence
+	// number 1. Consumers fanning events out can use it to detect gaps or
+	// reordering.
+	Seq uint64
 }
 
 // EventChan is a channel to receive events on
@@ -58,11 +72,18 @@ type EventChan <-chan KeyValueEvent
 type emitter struct {
 	events chan<- KeyValueEvent
 	scope  string
+
+	// seq is the sequence number attached to the last emitted event. It is
+	// scoped to the emitter, and thus to the watched prefix, so that relists
+	// continue the sequence rather than restarting it.
+	seq uint64
 }
 
 // emit attempts to notify the watcher of an event within the given context.
 // returning false if the context is done before the event is emitted.
-func (e emitter) emit(ctx context.Context, event KeyValueEvent) bool {
+func (e *emitter) emit(ctx context.Context, event KeyValueEvent) bool {
+	e.seq++
+	event.Seq = e.seq
 	queueStart := spanstat.Start()
 	var ok bool
 	select {
@@ -71,6 +92,9 @@ func (e emitter) emit(ctx context.Context, event KeyValueEvent) bool {
 		ok = true
 	}
 	trackEventQueued(e.scope, event.Typ, queueStart.End(ok).Total())
+	if ok {
+		recordEvent(e.scope, event.Typ)
+	}
 	return ok
 }
 
@@ -78,3 +102,30 @@ func (e emitter) emit(ctx context.Context, event KeyValueEvent) bool {
 func (e emitter) close() {
 	close(e.events)
 }
+
+// drainTimeout bounds the time drain waits for the consumer to receive the
+// terminal event, so that a consumer which already stopped receiving does
+// not leak the watcher goroutine.
+const drainTimeout = time.Second
+
+// drain emits a terminal EventTypeDrain event carrying the given reason and
+// then closes the events channel, allowing consumers to distinguish a
+// regular shutdown from a watch failure. The emission is abandoned after
+// drainTimeout in case the consumer is no longer receiving.
+func (e *emitter) drain(reason error) {
+	event := KeyValueEvent{Typ: EventTypeDrain}
+	if reason != nil {
+		event.Value = []byte(reason.Error())
+	}
+	e.seq++
+	event.Seq = e.seq
+	queueStart := spanstat.Start()
+	var ok bool
+	select {
+	case e.events <- event:
+		ok = true
+	case <-time.After(drainTimeout):
+	}
+	trackEventQueued(e.scope, event.Typ, queueStart.End(ok).Total())
+	e.close()
+}
@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cilium/cilium/pkg/time"
+)
+
+// TestEmitterSequence asserts that the emitter attaches a contiguous,
+// monotonically increasing sequence number to every event, and that the
+// sequence is not restarted when the watcher relists all keys after a
+// compaction-triggered watch error.
+func TestEmitterSequence(t *testing.T) {
+	events := make(chan KeyValueEvent, 16)
+	e := &emitter{events: events, scope: "test"}
+
+	// Initial list.
+	require.True(t, e.emit(context.Background(), KeyValueEvent{Typ: EventTypeCreate, Key: "foo/a"}))
+	require.True(t, e.emit(context.Background(), KeyValueEvent{Typ: EventTypeCreate, Key: "foo/b"}))
+	require.True(t, e.emit(context.Background(), KeyValueEvent{Typ: EventTypeListDone}))
+
+	// Relist after a compaction error: the same keys are listed again and a
+	// stale one is dropped. The sequence continues where it left off.
+	require.True(t, e.emit(context.Background(), KeyValueEvent{Typ: EventTypeModify, Key: "foo/a"}))
+	require.True(t, e.emit(context.Background(), KeyValueEvent{Typ: EventTypeDelete, Key: "foo/b"}))
+
+	close(events)
+	var seq uint64
+	for event := range events {
+		seq++
+		require.Equal(t, seq, event.Seq)
+	}
+	require.Equal(t, uint64(5), seq)
+}
+
+// TestEmitterSequenceContextDone asserts that an event dropped due to
+// context cancellation still consumes a sequence number, surfacing the gap
+// to consumers instead of hiding the lost event.
+func TestEmitterSequenceContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	events := make(chan KeyValueEvent, 1)
+	e := &emitter{events: events, scope: "test"}
+
+	// Fill the buffer so that the cancelled emit cannot be delivered.
+	require.True(t, e.emit(context.Background(), KeyValueEvent{Typ: EventTypeCreate, Key: "foo/a"}))
+	require.False(t, e.emit(ctx, KeyValueEvent{Typ: EventTypeCreate, Key: "foo/b"}))
+	require.Equal(t, uint64(1), (<-events).Seq)
+
+	require.True(t, e.emit(context.Background(), KeyValueEvent{Typ: EventTypeCreate, Key: "foo/c"}))
+	require.Equal(t, uint64(3), (<-events).Seq)
+}
+
+// TestEmitterDrain asserts that draining the emitter delivers a terminal
+// event carrying the shutdown reason and then closes the channel, and that
+// an abandoned consumer does not block the drain indefinitely.
+func TestEmitterDrain(t *testing.T) {
+	events := make(chan KeyValueEvent, 16)
+	e := &emitter{events: events, scope: "test"}
+
+	require.True(t, e.emit(context.Background(), KeyValueEvent{Typ: EventTypeCreate, Key: "foo/a"}))
+	e.drain(errors.New("watch failed"))
+
+	require.Equal(t, uint64(1), (<-events).Seq)
+	event, ok := <-events
+	require.True(t, ok)
+	require.Equal(t, EventTypeDrain, event.Typ)
+	require.Equal(t, uint64(2), event.Seq)
+	require.Equal(t, "watch failed", string(event.Value))
+
+	_, ok = <-events
+	require.False(t, ok, "expected the channel to be closed after the drain event")
+
+	// A regular shutdown carries no reason.
+	events = make(chan KeyValueEvent, 1)
+	e = &emitter{events: events, scope: "test"}
+	e.drain(nil)
+	event = <-events
+	require.Equal(t, EventTypeDrain, event.Typ)
+	require.Empty(t, event.Value)
+
+	// The drain event is abandoned if the consumer stopped receiving, but
+	// the channel is still closed.
+	events = make(chan KeyValueEvent)
+	e = &emitter{events: events, scope: "test"}
+	done := make(chan struct{})
+	go func() {
+		e.drain(nil)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * drainTimeout):
+		t.Fatal("drain did not return with an abandoned consumer")
+	}
+	_, ok = <-events
+	require.False(t, ok, "expected the channel to be closed after the drain timeout")
+}
@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// ExportFormat is the output format of ExportPrefix.
+type ExportFormat string
+
+const (
+	// ExportFormatJSON emits one JSON object per key (JSON Lines).
+	ExportFormatJSON ExportFormat = "json"
+
+	// ExportFormatCSV emits a CSV document with a header row.
+	ExportFormatCSV ExportFormat = "csv"
+)
+
+// exportEntry is the wire format of a single exported key in JSON output.
+// Exactly one of Value and ValueBase64 is populated, depending on whether
+// the value could be decoded based on the registered prefix schemas.
+type exportEntry struct {
+	Key         string          `json:"key"`
+	ModRevision uint64          `json:"modRevision"`
+	LeaseID     int64           `json:"leaseID,omitempty"`
+	Value       json.RawMessage `json:"value,omitempty"`
+	ValueBase64 string          `json:"valueBase64,omitempty"`
+}
+
+// exportValue returns the value as raw JSON if the schema registered for the
+// key declares JSON encoded values, and base64 otherwise, so that arbitrary
+// binary values survive the text based output formats.
+func exportValue(key string, value []byte) (decoded json.RawMessage, encoded string) {
+	if schema, ok := schemaForKey(key); ok && schema.Encoding == "json" && json.Valid(value) {
+		return json.RawMessage(value), ""
+	}
+	return nil, base64.StdEncoding.EncodeToString(value)
+}
+
+// ExportPrefix streams all keys below the given prefix to w in the requested
+// format, for support bundles and audits without requiring direct etcdctl
+// access. Values of prefixes whose registered schema (see
+// RegisterPrefixSchema) declares JSON encoding are emitted decoded, all
+// others as base64. Keys are retrieved in paginated batches, bounding the
+// peak memory usage independently of the prefix size.
+func ExportPrefix(ctx context.Context, client BackendOperations, prefix string, format ExportFormat, w io.Writer) error {
+	switch format {
+	case ExportFormatJSON:
+		return exportJSON(ctx, client, prefix, w)
+	case ExportFormatCSV:
+		return exportCSV(ctx, client, prefix, w)
+	default:
+		return fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+func exportJSON(ctx context.Context, client BackendOperations, prefix string, w io.Writer) error {
+	encoder := json.NewEncoder(w)
+
+	var encodeErr error
+	err := client.ListPrefixFunc(ctx, prefix, func(key string, value Value) bool {
+		entry := exportEntry{
+			Key:         key,
+			ModRevision: value.ModRevision,
+			LeaseID:     value.LeaseID,
+		}
+		entry.Value, entry.ValueBase64 = exportValue(key, value.Data)
+
+		encodeErr = encoder.Encode(entry)
+		return encodeErr == nil
+	})
+	if err != nil {
+		return err
+	}
+	return encodeErr
+}
+
+func exportCSV(ctx context.Context, client BackendOperations, prefix string, w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"key", "modRevision", "leaseID", "encoding", "value"}); err != nil {
+		return err
+	}
+
+	var writeErr error
+	err := client.ListPrefixFunc(ctx, prefix, func(key string, value Value) bool {
+		encoding, data := "base64", ""
+		if decoded, encoded := exportValue(key, value.Data); decoded != nil {
+			encoding, data = "json", string(decoded)
+		} else {
+			data = encoded
+		}
+
+		writeErr = writer.Write([]string{
+			key,
+			strconv.FormatUint(value.ModRevision, 10),
+			strconv.FormatInt(value.LeaseID, 10),
+			encoding,
+			data,
+		})
+		return writeErr == nil
+	})
+	if err != nil {
+		return err
+	}
+	if writeErr != nil {
+		return writeErr
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
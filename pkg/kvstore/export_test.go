@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/cilium/statedb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportPrefix(t *testing.T) {
+	require.NoError(t, RegisterPrefixSchema(PrefixSchema{
+		Prefix:   "cilium/state/export/v1",
+		Pattern:  "cilium/state/export/v1/<name>",
+		Encoding: "json",
+		Owner:    "test",
+	}))
+
+	db := statedb.New()
+	client := NewInMemoryClient(db, "__local__")
+	ctx := t.Context()
+
+	require.NoError(t, client.Update(ctx, "cilium/state/export/v1/foo", []byte(`{"name":"foo"}`), false))
+	require.NoError(t, client.Update(ctx, "cilium/state/export/v1/raw", []byte{0xde, 0xad}, false))
+	require.NoError(t, client.Update(ctx, "cilium/unregistered/key", []byte("opaque"), false))
+	require.NoError(t, client.Update(ctx, "other/key", []byte("skipped"), false))
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, ExportPrefix(ctx, client, "cilium/", ExportFormatJSON, &buf))
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 3)
+
+		entries := make(map[string]exportEntry)
+		for _, line := range lines {
+			var entry exportEntry
+			require.NoError(t, json.Unmarshal([]byte(line), &entry))
+			entries[entry.Key] = entry
+		}
+
+		// JSON values below a registered prefix are emitted decoded.
+		assert.JSONEq(t, `{"name":"foo"}`, string(entries["cilium/state/export/v1/foo"].Value))
+		assert.Empty(t, entries["cilium/state/export/v1/foo"].ValueBase64)
+		assert.NotZero(t, entries["cilium/state/export/v1/foo"].ModRevision)
+
+		// Binary and unregistered values fall back to base64.
+		assert.Equal(t, base64.StdEncoding.EncodeToString([]byte{0xde, 0xad}),
+			entries["cilium/state/export/v1/raw"].ValueBase64)
+		assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("opaque")),
+			entries["cilium/unregistered/key"].ValueBase64)
+
+		// Keys outside the requested prefix are not exported.
+		assert.NotContains(t, entries, "other/key")
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, ExportPrefix(ctx, client, "cilium/state/export/v1", ExportFormatCSV, &buf))
+
+		records, err := csv.NewReader(&buf).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 3)
+		assert.Equal(t, []string{"key", "modRevision", "leaseID", "encoding", "value"}, records[0])
+
+		rows := make(map[string][]string)
+		for _, record := range records[1:] {
+			rows[record[0]] = record
+		}
+		assert.Equal(t, "json", rows["cilium/state/export/v1/foo"][3])
+		assert.Equal(t, `{"name":"foo"}`, rows["cilium/state/export/v1/foo"][4])
+		assert.Equal(t, "base64", rows["cilium/state/export/v1/raw"][3])
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.Error(t, ExportPrefix(ctx, client, "cilium/", "yaml", &buf))
+	})
+}
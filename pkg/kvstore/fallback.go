@@ -0,0 +1,294 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"log/slog"
+	"sync/atomic"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/time"
+)
+
+// fallbackProbeInterval is the interval at which the health of the configured
+// backends is re-evaluated, both to fail over away from an unhealthy active
+// backend and to fail back to a recovered higher priority one.
+const fallbackProbeInterval = 30 * time.Second
+
+// WithFallback composes the given clients into a single one dispatching all
+// operations to the highest priority healthy backend, in the order they are
+// provided (e.g., primary etcd, secondary etcd, read-only local cache). When
+// the status checker of the active backend declares failure, operations
+// automatically fail over to the next healthy backend, and fail back once a
+// higher priority one recovers.
+//
+// Watches are bound to the backend active when they are started: they are not
+// transparently migrated upon failover, and consumers observe the usual
+// channel closure when the original backend goes away. The StatusChanged()
+// channel is notified whenever the active backend changes.
+func WithFallback(logger *slog.Logger, backends ...Client) Client {
+	if len(backends) == 1 {
+		return backends[0]
+	}
+
+	fc := &fallbackClient{
+		logger:            logger,
+		backends:          backends,
+		statusChanged:     make(chan struct{}),
+		statusCheckErrors: make(chan error, 128),
+		stop:              make(chan struct{}),
+	}
+	go fc.monitor()
+	return fc
+}
+
+type fallbackClient struct {
+	logger *slog.Logger
+
+	// backends is the ordered list of backends, from the highest to the
+	// lowest priority.
+	backends []Client
+
+	// activeIdx is the index of the backend operations are currently
+	// dispatched to.
+	activeIdx atomic.Int32
+
+	// statusMu guards statusChanged, which is closed and recreated whenever
+	// the active backend changes.
+	statusMu      lock.Mutex
+	statusChanged chan struct{}
+
+	// statusCheckErrors receives the status check errors of the active
+	// backend which could not be resolved by failing over.
+	statusCheckErrors chan error
+
+	// stop is closed upon Close() to terminate the monitor.
+	stop chan struct{}
+}
+
+// active returns the backend operations are currently dispatched to.
+func (fc *fallbackClient) active() Client {
+	return fc.backends[fc.activeIdx.Load()]
+}
+
+// reconcile switches to the highest priority backend currently reporting a
+// healthy status, returning whether a switch took place. The active backend
+// is retained if no backend is healthy.
+func (fc *fallbackClient) reconcile() bool {
+	current := int(fc.activeIdx.Load())
+	next := current
+	for i, backend := range fc.backends {
+		if backend.Status().State == models.StatusStateOk {
+			next = i
+			break
+		}
+	}
+
+	if next == current {
+		return false
+	}
+
+	fc.activeIdx.Store(int32(next))
+	fc.logger.Info("Switched active kvstore backend",
+		logfields.Index, next,
+		logfields.Status, fc.backends[next].Status().Msg,
+	)
+
+	fc.statusMu.Lock()
+	close(fc.statusChanged)
+	fc.statusChanged = make(chan struct{})
+	fc.statusMu.Unlock()
+	return true
+}
+
+// monitor fails over upon status check errors of the active backend, and
+// periodically re-evaluates the backends health to fail back to a recovered
+// higher priority one.
+func (fc *fallbackClient) monitor() {
+	for {
+		select {
+		case <-fc.stop:
+			return
+
+		case err, ok := <-fc.active().StatusCheckErrors():
+			if !ok {
+				// The active backend stopped reporting status check errors
+				// (i.e., it was closed). Wait for the next probe before
+				// re-evaluating, to avoid busy looping on the closed channel.
+				select {
+				case <-fc.stop:
+					return
+				case <-time.After(fallbackProbeInterval):
+					fc.reconcile()
+				}
+				continue
+			}
+
+			// Forward the error only if the failover did not resolve it, as
+			// subscribers commonly treat these errors as fatal.
+			if !fc.reconcile() {
+				select {
+				case fc.statusCheckErrors <- err:
+				default:
+					fc.logger.Warn(
+						"Status check error channel is full, dropping this error",
+						logfields.Error, err,
+					)
+				}
+			}
+
+		case <-time.After(fallbackProbeInterval):
+			fc.reconcile()
+		}
+	}
+}
+
+func (fc *fallbackClient) IsEnabled() bool {
+	return fc.active().IsEnabled()
+}
+
+func (fc *fallbackClient) Status() *models.Status {
+	idx := int(fc.activeIdx.Load())
+	status := fc.backends[idx].Status()
+	return &models.Status{
+		State: status.State,
+		Msg:   fmt.Sprintf("backend %d/%d active: %s", idx+1, len(fc.backends), status.Msg),
+	}
+}
+
+func (fc *fallbackClient) StatusDetail() *models.KVstoreStatus {
+	return fc.active().StatusDetail()
+}
+
+func (fc *fallbackClient) StatusChanged() <-chan struct{} {
+	fc.statusMu.Lock()
+	defer fc.statusMu.Unlock()
+	return fc.statusChanged
+}
+
+func (fc *fallbackClient) StatusCheckErrors() <-chan error {
+	return fc.statusCheckErrors
+}
+
+func (fc *fallbackClient) LockPath(ctx context.Context, path string) (KVLocker, error) {
+	return fc.active().LockPath(ctx, path)
+}
+
+func (fc *fallbackClient) Get(ctx context.Context, key string) ([]byte, error) {
+	return fc.active().Get(ctx, key)
+}
+
+func (fc *fallbackClient) GetIfLocked(ctx context.Context, key string, lock KVLocker) ([]byte, error) {
+	return fc.active().GetIfLocked(ctx, key, lock)
+}
+
+func (fc *fallbackClient) Delete(ctx context.Context, key string) error {
+	return fc.active().Delete(ctx, key)
+}
+
+func (fc *fallbackClient) DeleteIfLocked(ctx context.Context, key string, lock KVLocker) error {
+	return fc.active().DeleteIfLocked(ctx, key, lock)
+}
+
+func (fc *fallbackClient) DeletePrefix(ctx context.Context, path string) error {
+	return fc.active().DeletePrefix(ctx, path)
+}
+
+func (fc *fallbackClient) DeletePrefixIfLocked(ctx context.Context, path string, lock KVLocker) error {
+	return fc.active().DeletePrefixIfLocked(ctx, path, lock)
+}
+
+func (fc *fallbackClient) Update(ctx context.Context, key string, value []byte, lease bool) error {
+	return fc.active().Update(ctx, key, value, lease)
+}
+
+func (fc *fallbackClient) UpdateIfLocked(ctx context.Context, key string, value []byte, lease bool, lock KVLocker) error {
+	return fc.active().UpdateIfLocked(ctx, key, value, lease, lock)
+}
+
+func (fc *fallbackClient) UpdateIfDifferent(ctx context.Context, key string, value []byte, lease bool) (bool, error) {
+	return fc.active().UpdateIfDifferent(ctx, key, value, lease)
+}
+
+func (fc *fallbackClient) UpdateIfDifferentIfLocked(ctx context.Context, key string, value []byte, lease bool, lock KVLocker) (bool, error) {
+	return fc.active().UpdateIfDifferentIfLocked(ctx, key, value, lease, lock)
+}
+
+func (fc *fallbackClient) UpdateBatch(ctx context.Context, pairs []KeyValue, lease bool) error {
+	return fc.active().UpdateBatch(ctx, pairs, lease)
+}
+
+func (fc *fallbackClient) CreateOnly(ctx context.Context, key string, value []byte, lease bool) (bool, error) {
+	return fc.active().CreateOnly(ctx, key, value, lease)
+}
+
+func (fc *fallbackClient) CreateOnlyIfLocked(ctx context.Context, key string, value []byte, lease bool, lock KVLocker) (bool, error) {
+	return fc.active().CreateOnlyIfLocked(ctx, key, value, lease, lock)
+}
+
+func (fc *fallbackClient) Txn(ctx context.Context, conditions []TxnCondition, thenOps, elseOps []TxnOp) (bool, error) {
+	return fc.active().Txn(ctx, conditions, thenOps, elseOps)
+}
+
+func (fc *fallbackClient) ListPrefix(ctx context.Context, prefix string) (KeyValuePairs, error) {
+	return fc.active().ListPrefix(ctx, prefix)
+}
+
+func (fc *fallbackClient) ListPrefixFunc(ctx context.Context, prefix string, fn func(key string, value Value) bool) error {
+	return fc.active().ListPrefixFunc(ctx, prefix, fn)
+}
+
+func (fc *fallbackClient) ListPrefixPaginated(ctx context.Context, prefix string, pageSize int) (iter.Seq2[string, Value], <-chan error) {
+	return fc.active().ListPrefixPaginated(ctx, prefix, pageSize)
+}
+
+func (fc *fallbackClient) ListPrefixIfLocked(ctx context.Context, prefix string, lock KVLocker) (KeyValuePairs, error) {
+	return fc.active().ListPrefixIfLocked(ctx, prefix, lock)
+}
+
+func (fc *fallbackClient) ListAndWatch(ctx context.Context, prefix string) EventChan {
+	return fc.active().ListAndWatch(ctx, prefix)
+}
+
+func (fc *fallbackClient) WatchKey(ctx context.Context, key string) EventChan {
+	return fc.active().WatchKey(ctx, key)
+}
+
+// RegisterLeaseExpiredObserver registers the observer with all the backends,
+// so that expirations keep being observed across failovers.
+func (fc *fallbackClient) RegisterLeaseExpiredObserver(prefix string, fn func(key string)) {
+	for _, backend := range fc.backends {
+		backend.RegisterLeaseExpiredObserver(prefix, fn)
+	}
+}
+
+// RegisterLeaseKeepaliveFailureObserver registers the observer with all the
+// backends, so that keepalive failures keep being observed across failovers.
+func (fc *fallbackClient) RegisterLeaseKeepaliveFailureObserver(prefix string, fn func(key string)) {
+	for _, backend := range fc.backends {
+		backend.RegisterLeaseKeepaliveFailureObserver(prefix, fn)
+	}
+}
+
+func (fc *fallbackClient) UserEnforcePresence(ctx context.Context, name string, roles []string) error {
+	return fc.active().UserEnforcePresence(ctx, name, roles)
+}
+
+func (fc *fallbackClient) UserEnforceAbsence(ctx context.Context, name string) error {
+	return fc.active().UserEnforceAbsence(ctx, name)
+}
+
+// Close terminates the monitor and closes all the backends.
+func (fc *fallbackClient) Close() {
+	close(fc.stop)
+	for _, backend := range fc.backends {
+		backend.Close()
+	}
+	close(fc.statusCheckErrors)
+}
@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cilium/hive/hivetest"
+	"github.com/cilium/statedb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/time"
+)
+
+// fakeStatusBackend wraps an in-memory client overriding the status
+// reporting, to exercise the failover logic.
+type fakeStatusBackend struct {
+	Client
+	state atomic.Pointer[string]
+	errs  chan error
+}
+
+func newFakeStatusBackend(db *statedb.DB, name string) *fakeStatusBackend {
+	backend := &fakeStatusBackend{
+		Client: NewInMemoryClient(db, name),
+		errs:   make(chan error, 1),
+	}
+	backend.setState(models.StatusStateOk)
+	return backend
+}
+
+func (f *fakeStatusBackend) Status() *models.Status {
+	return &models.Status{State: *f.state.Load(), Msg: "fake"}
+}
+
+func (f *fakeStatusBackend) StatusCheckErrors() <-chan error { return f.errs }
+
+func (f *fakeStatusBackend) setState(state string) { f.state.Store(&state) }
+
+func expectStatusChange(t *testing.T, statusChanged <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-statusChanged:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout while waiting for the active backend to change")
+	}
+}
+
+func TestWithFallback(t *testing.T) {
+	db := statedb.New()
+	primary := newFakeStatusBackend(db, "primary")
+	secondary := newFakeStatusBackend(db, "secondary")
+
+	client := WithFallback(hivetest.Logger(t), primary, secondary)
+	ctx := t.Context()
+
+	// Operations are dispatched to the primary backend while healthy.
+	require.NoError(t, client.Update(ctx, "foo", []byte("value"), false))
+	value, err := primary.Get(ctx, "foo")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+	assert.Contains(t, client.Status().Msg, "backend 1/2 active")
+
+	// A status check failure of the primary triggers the failover.
+	statusChanged := client.StatusChanged()
+	primary.setState(models.StatusStateFailure)
+	primary.errs <- errors.New("status check failed")
+	expectStatusChange(t, statusChanged)
+
+	require.NoError(t, client.Update(ctx, "bar", []byte("value"), false))
+	value, err = secondary.Get(ctx, "bar")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+	assert.Contains(t, client.Status().Msg, "backend 2/2 active")
+
+	// Once the primary recovers, the next health re-evaluation fails back.
+	statusChanged = client.StatusChanged()
+	primary.setState(models.StatusStateOk)
+	secondary.errs <- errors.New("status check failed")
+	expectStatusChange(t, statusChanged)
+	assert.Contains(t, client.Status().Msg, "backend 1/2 active")
+
+	// Errors which cannot be resolved by failing over are forwarded.
+	primary.setState(models.StatusStateFailure)
+	secondary.setState(models.StatusStateFailure)
+	primary.errs <- errors.New("no backend available")
+	select {
+	case err := <-client.StatusCheckErrors():
+		assert.ErrorContains(t, err, "no backend available")
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout while waiting for the status check error to be forwarded")
+	}
+}
+
+func TestWithFallbackSingleBackend(t *testing.T) {
+	backend := newFakeStatusBackend(statedb.New(), "primary")
+	client := WithFallback(hivetest.Logger(t), backend)
+
+	// A single backend is returned as is, without the dispatching layer.
+	assert.False(t, strings.Contains(client.Status().Msg, "active"))
+}
@@ -0,0 +1,350 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/time"
+)
+
+// IndexFunc computes the set of index values for the given key/value pair.
+// Returning an empty slice means that the pair is not indexed.
+type IndexFunc func(key string, value []byte) []string
+
+// Indexers maps an index name to the function computing the index values.
+type Indexers map[string]IndexFunc
+
+// StoreEventHandler receives notifications about mutations of the keys cached
+// by a Store. Handlers are invoked from the watcher goroutine, hence they
+// shall not perform long-running operations, to avoid blocking the event
+// processing for all other handlers.
+type StoreEventHandler interface {
+	// OnStoreUpdate is called when a key is created or modified, as well as
+	// for every cached key upon resync.
+	OnStoreUpdate(key string, value []byte)
+
+	// OnStoreDelete is called when a key is deleted.
+	OnStoreDelete(key string)
+}
+
+// StoreConfiguration is the set of configuration parameters of a Store.
+type StoreConfiguration struct {
+	// Prefix is the kvstore prefix watched by the store. This parameter is
+	// required.
+	Prefix string
+
+	// Backend is the kvstore backend to use. This parameter is required.
+	Backend BackendOperations
+
+	// ResyncInterval is the interval in which all cached entries are
+	// re-delivered to the registered event handlers as update events.
+	// Defaults to 0 (i.e., no periodic resync is performed) if unset.
+	ResyncInterval time.Duration
+
+	// Indexers is the optional set of indexers used to look up cached
+	// entries by field through Store.ByIndex.
+	Indexers Indexers
+
+	Context context.Context
+}
+
+// validate is invoked by NewStore to validate and complete the configuration.
+// It returns nil when the configuration is valid.
+func (c *StoreConfiguration) validate() error {
+	if c.Prefix == "" {
+		return fmt.Errorf("prefix must be specified")
+	}
+
+	if c.Backend == nil {
+		return fmt.Errorf("backend must be specified")
+	}
+
+	if c.Context == nil {
+		c.Context = context.Background()
+	}
+
+	return nil
+}
+
+// Store is a read-only cache of a kvstore prefix kept up-to-date through
+// ListAndWatch, mirroring the behavior of client-go shared informers. It
+// supports field indexers, periodic resync and fan-out of events to multiple
+// registered handlers.
+type Store struct {
+	logger *slog.Logger
+
+	// conf is a copy of the store configuration. This field is never mutated
+	// after NewStore() so it is safe to access this without a lock.
+	conf StoreConfiguration
+
+	// mutex protects mutations to objects, indices and handlers
+	mutex lock.RWMutex
+
+	// objects is the cached representation of the watched prefix, keyed by
+	// the kvstore key relative to the prefix.
+	objects map[string][]byte
+
+	// indices maps each index name to the computed index values, and in turn
+	// to the set of keys matching each value.
+	indices map[string]map[string]sets.Set[string]
+
+	// handlers is the list of registered event handlers.
+	handlers []StoreEventHandler
+
+	// synced is closed when the initial list operation has completed.
+	synced chan struct{}
+
+	// stop stops the kvstore watcher.
+	stop context.CancelFunc
+
+	wg sync.WaitGroup
+}
+
+// NewStore creates a new Store based on the provided configuration, and
+// starts the watcher keeping it synchronized with the kvstore. An error is
+// returned if the configuration is invalid.
+func NewStore(logger *slog.Logger, c StoreConfiguration) (*Store, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+
+	s := &Store{
+		logger:  logger.With(fieldPrefix, c.Prefix),
+		conf:    c,
+		objects: map[string][]byte{},
+		indices: make(map[string]map[string]sets.Set[string], len(c.Indexers)),
+		synced:  make(chan struct{}),
+	}
+
+	for name := range c.Indexers {
+		s.indices[name] = map[string]sets.Set[string]{}
+	}
+
+	// Wrap the context, so that we can subsequently stop the kvstore watcher.
+	s.conf.Context, s.stop = context.WithCancel(s.conf.Context)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.watcher()
+	}()
+
+	return s, nil
+}
+
+// AddEventHandler registers an additional event handler. Registered handlers
+// receive all subsequent events, but no artificial events for already cached
+// entries; use Replay to explicitly deliver them.
+func (s *Store) AddEventHandler(handler StoreEventHandler) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.handlers = append(s.handlers, handler)
+}
+
+// Replay delivers an update event to the given handler for every entry
+// currently present in the cache.
+func (s *Store) Replay(handler StoreEventHandler) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	for key, value := range s.objects {
+		handler.OnStoreUpdate(key, value)
+	}
+}
+
+// WaitForSync blocks until the initial list of entries has been retrieved
+// from the kvstore, or the context is canceled.
+func (s *Store) WaitForSync(ctx context.Context) error {
+	select {
+	case <-s.synced:
+		return nil
+	case <-s.conf.Context.Done():
+		return s.conf.Context.Err()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Get returns the cached value associated with the given key (relative to the
+// store prefix), and whether it is present.
+func (s *Store) Get(key string) ([]byte, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	value, ok := s.objects[key]
+	return value, ok
+}
+
+// List returns a copy of all cached entries.
+func (s *Store) List() map[string][]byte {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return maps.Clone(s.objects)
+}
+
+// NumEntries returns the number of cached entries.
+func (s *Store) NumEntries() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return len(s.objects)
+}
+
+// ByIndex returns the keys of the cached entries whose index values, for the
+// given index, include the provided value. An error is returned if the index
+// does not exist.
+func (s *Store) ByIndex(index, value string) ([]string, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	keys, ok := s.indices[index]
+	if !ok {
+		return nil, fmt.Errorf("index %q does not exist", index)
+	}
+
+	return sets.List(keys[value]), nil
+}
+
+// Close stops the watcher and releases the store resources. The cached
+// entries are left in the kvstore intact.
+func (s *Store) Close() {
+	s.stop()
+	s.wg.Wait()
+}
+
+// updateKey caches the given key/value pair, updates the indices and notifies
+// the registered handlers.
+func (s *Store) updateKey(key string, value []byte) {
+	s.mutex.Lock()
+	s.deleteFromIndices(key)
+	s.objects[key] = value
+	s.addToIndices(key, value)
+	handlers := s.cachedHandlers()
+	s.mutex.Unlock()
+
+	for _, handler := range handlers {
+		handler.OnStoreUpdate(key, value)
+	}
+}
+
+// deleteKey removes the given key from the cache and the indices, and
+// notifies the registered handlers.
+func (s *Store) deleteKey(key string) {
+	s.mutex.Lock()
+	_, ok := s.objects[key]
+	s.deleteFromIndices(key)
+	delete(s.objects, key)
+	handlers := s.cachedHandlers()
+	s.mutex.Unlock()
+
+	if !ok {
+		s.logger.Warn(
+			"Unable to find deleted key in local state",
+			logfields.Key, key,
+		)
+		return
+	}
+
+	for _, handler := range handlers {
+		handler.OnStoreDelete(key)
+	}
+}
+
+// resync re-delivers all cached entries to the registered handlers.
+func (s *Store) resync() {
+	s.mutex.RLock()
+	objects := maps.Clone(s.objects)
+	handlers := s.cachedHandlers()
+	s.mutex.RUnlock()
+
+	s.logger.Debug(
+		"Resyncing store entries to registered handlers",
+		fieldNumEntries, len(objects),
+	)
+
+	for key, value := range objects {
+		for _, handler := range handlers {
+			handler.OnStoreUpdate(key, value)
+		}
+	}
+}
+
+// cachedHandlers returns the current list of handlers. s.mutex must be held.
+func (s *Store) cachedHandlers() []StoreEventHandler {
+	return slices.Clone(s.handlers)
+}
+
+// addToIndices inserts the given key into all matching indices. s.mutex must
+// be held for writing.
+func (s *Store) addToIndices(key string, value []byte) {
+	for name, indexer := range s.conf.Indexers {
+		for _, indexed := range indexer(key, value) {
+			set, ok := s.indices[name][indexed]
+			if !ok {
+				set = sets.New[string]()
+				s.indices[name][indexed] = set
+			}
+			set.Insert(key)
+		}
+	}
+}
+
+// deleteFromIndices removes the given key from all indices. s.mutex must be
+// held for writing.
+func (s *Store) deleteFromIndices(key string) {
+	for name := range s.conf.Indexers {
+		for indexed, set := range s.indices[name] {
+			set.Delete(key)
+			if set.Len() == 0 {
+				delete(s.indices[name], indexed)
+			}
+		}
+	}
+}
+
+func (s *Store) watcher() {
+	var resync <-chan time.Time
+	if s.conf.ResyncInterval > 0 {
+		ticker := time.NewTicker(s.conf.ResyncInterval)
+		defer ticker.Stop()
+		resync = ticker.C
+	}
+
+	events := s.conf.Backend.ListAndWatch(s.conf.Context, s.conf.Prefix)
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+
+			if event.Typ == EventTypeListDone {
+				s.logger.Debug("Initial list of entries received from kvstore")
+				close(s.synced)
+				continue
+			}
+
+			key := strings.TrimPrefix(strings.TrimPrefix(event.Key, s.conf.Prefix), "/")
+
+			switch event.Typ {
+			case EventTypeCreate, EventTypeModify:
+				s.updateKey(key, event.Value)
+			case EventTypeDelete:
+				s.deleteKey(key)
+			}
+
+		case <-resync:
+			s.resync()
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/cilium/hive/hivetest"
+	"github.com/cilium/statedb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/time"
+)
+
+// fakeStoreHandler records the events delivered to it by a Store.
+type fakeStoreHandler struct {
+	mu      lock.Mutex
+	updates map[string]int
+	deletes []string
+}
+
+func newFakeStoreHandler() *fakeStoreHandler {
+	return &fakeStoreHandler{updates: make(map[string]int)}
+}
+
+func (h *fakeStoreHandler) OnStoreUpdate(key string, value []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.updates[key]++
+}
+
+func (h *fakeStoreHandler) OnStoreDelete(key string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.deletes = append(h.deletes, key)
+}
+
+func (h *fakeStoreHandler) updateCount(key string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.updates[key]
+}
+
+func (h *fakeStoreHandler) deleted() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return slices.Clone(h.deletes)
+}
+
+func TestStoreIndexers(t *testing.T) {
+	ctx := context.Background()
+	prefix := "cilium/state/test/v1"
+
+	client := NewInMemoryClient(statedb.New(), "__local__")
+	defer client.Close()
+
+	require.NoError(t, client.Update(ctx, prefix+"/foo", []byte("red"), false))
+
+	s, err := NewStore(hivetest.Logger(t), StoreConfiguration{
+		Prefix:  prefix,
+		Backend: client,
+		Indexers: Indexers{
+			"by-color": func(key string, value []byte) []string {
+				return []string{string(value)}
+			},
+		},
+	})
+	require.NoError(t, err)
+	defer s.Close()
+
+	require.NoError(t, s.WaitForSync(ctx))
+
+	keys, err := s.ByIndex("by-color", "red")
+	require.NoError(t, err)
+	require.Equal(t, []string{"foo"}, keys)
+
+	_, err = s.ByIndex("unknown", "red")
+	require.ErrorContains(t, err, "does not exist")
+
+	// Modifying an entry moves it to the new index value.
+	require.NoError(t, client.Update(ctx, prefix+"/foo", []byte("blue"), false))
+	require.EventuallyWithT(t, func(c *assert.CollectT) {
+		keys, err := s.ByIndex("by-color", "blue")
+		require.NoError(c, err)
+		require.Equal(c, []string{"foo"}, keys)
+
+		keys, err = s.ByIndex("by-color", "red")
+		require.NoError(c, err)
+		require.Empty(c, keys)
+	}, time.Second, 10*time.Millisecond)
+
+	// Deleting an entry removes it from the indices and the cache.
+	require.NoError(t, client.Delete(ctx, prefix+"/foo"))
+	require.EventuallyWithT(t, func(c *assert.CollectT) {
+		keys, err := s.ByIndex("by-color", "blue")
+		require.NoError(c, err)
+		require.Empty(c, keys)
+
+		_, ok := s.Get("foo")
+		require.False(c, ok)
+		require.Equal(c, 0, s.NumEntries())
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestStoreResync(t *testing.T) {
+	ctx := context.Background()
+	prefix := "cilium/state/test/v1"
+
+	client := NewInMemoryClient(statedb.New(), "__local__")
+	defer client.Close()
+
+	require.NoError(t, client.Update(ctx, prefix+"/foo", []byte("v1"), false))
+
+	s, err := NewStore(hivetest.Logger(t), StoreConfiguration{
+		Prefix:         prefix,
+		Backend:        client,
+		ResyncInterval: 10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	defer s.Close()
+
+	handler := newFakeStoreHandler()
+	s.AddEventHandler(handler)
+
+	require.NoError(t, s.WaitForSync(ctx))
+
+	// The initial update is followed by periodic re-deliveries of the
+	// cached entry.
+	require.EventuallyWithT(t, func(c *assert.CollectT) {
+		require.GreaterOrEqual(c, handler.updateCount("foo"), 3)
+	}, time.Second, 10*time.Millisecond)
+
+	require.Empty(t, handler.deleted())
+}
+
+func TestStoreHandlerRegistration(t *testing.T) {
+	ctx := context.Background()
+	prefix := "cilium/state/test/v1"
+
+	client := NewInMemoryClient(statedb.New(), "__local__")
+	defer client.Close()
+
+	require.NoError(t, client.Update(ctx, prefix+"/foo", []byte("v1"), false))
+
+	s, err := NewStore(hivetest.Logger(t), StoreConfiguration{
+		Prefix:  prefix,
+		Backend: client,
+	})
+	require.NoError(t, err)
+	defer s.Close()
+
+	require.NoError(t, s.WaitForSync(ctx))
+
+	// A handler registered after the initial sync receives no artificial
+	// events for the already cached entries...
+	handler := newFakeStoreHandler()
+	s.AddEventHandler(handler)
+	require.Equal(t, 0, handler.updateCount("foo"))
+
+	// ... until they are explicitly replayed.
+	s.Replay(handler)
+	require.Equal(t, 1, handler.updateCount("foo"))
+
+	// Subsequent events are delivered to the handler.
+	require.NoError(t, client.Update(ctx, prefix+"/bar", []byte("v1"), false))
+	require.EventuallyWithT(t, func(c *assert.CollectT) {
+		require.Equal(c, 1, handler.updateCount("bar"))
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, client.Delete(ctx, prefix+"/bar"))
+	require.EventuallyWithT(t, func(c *assert.CollectT) {
+		require.Equal(c, []string{"bar"}, handler.deleted())
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,303 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/time"
+)
+
+// journalDefaultMaxSize is the size threshold over which the journal file is
+// rotated, keeping the previous generation as a backup. The journal hence
+// retains between one and two generations of history on disk.
+const journalDefaultMaxSize = 10 * 1024 * 1024
+
+// JournalConfig configures the write-ahead journaling of kvstore mutations.
+type JournalConfig struct {
+	// Path is the location of the journal file. The previous generation is
+	// kept at Path + ".old" upon rotation.
+	Path string
+
+	// MaxSize is the size in bytes over which the journal file is rotated.
+	// Defaults to journalDefaultMaxSize if unset.
+	MaxSize int64
+
+	// RedactedPrefixes is the list of key prefixes whose keys embed sensitive
+	// information. The portion of the key following the matched prefix is
+	// redacted, and the value hash is omitted.
+	RedactedPrefixes []string
+}
+
+// journalEntry is the wire format of a single journal record. Each mutation
+// emits two records sharing the same sequence number: one written before
+// issuing the operation, carrying the operation, key and value hash, and one
+// written after completion, carrying the outcome. An operation record without
+// a matching outcome record indicates a mutation in flight when the journal
+// stopped.
+type journalEntry struct {
+	Seq       uint64 `json:"seq"`
+	Time      string `json:"time,omitempty"`
+	Op        string `json:"op,omitempty"`
+	Key       string `json:"key,omitempty"`
+	ValueHash string `json:"valueHash,omitempty"`
+	Outcome   string `json:"outcome,omitempty"`
+}
+
+// WithJournal wraps the given client journaling all mutations (operation,
+// key, hash of the value and outcome) to a local size-bounded file, so that
+// the exact write sequence leading up to an incident can be replayed offline.
+// Each record is written before issuing the corresponding operation, hence a
+// mutation in flight during a crash is still accounted for. Journaling
+// failures are logged but do not fail the guarded operations.
+func WithJournal(logger *slog.Logger, client Client, cfg JournalConfig) (Client, error) {
+	if cfg.MaxSize == 0 {
+		cfg.MaxSize = journalDefaultMaxSize
+	}
+
+	jc := &journalClient{
+		Client: client,
+		cfg:    cfg,
+		logger: logger,
+	}
+
+	if err := jc.open(); err != nil {
+		return nil, fmt.Errorf("failed to open kvstore journal: %w", err)
+	}
+
+	return jc, nil
+}
+
+type journalClient struct {
+	Client
+
+	cfg    JournalConfig
+	logger *slog.Logger
+
+	// mu serializes journal writes and protects the fields below.
+	mu   lock.Mutex
+	file *os.File
+	size int64
+	seq  uint64
+}
+
+func (jc *journalClient) open() error {
+	file, err := os.OpenFile(jc.cfg.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	jc.file = file
+	jc.size = info.Size()
+	return nil
+}
+
+// record journals the given operation, returning a completion function to be
+// invoked with the outcome once the operation terminated.
+func (jc *journalClient) record(op, key string, value []byte) func(error) {
+	entry := journalEntry{
+		Time: time.Now().UTC().Format(time.RFC3339Nano),
+		Op:   op,
+		Key:  key,
+	}
+
+	if value != nil {
+		hash := sha256.Sum256(value)
+		entry.ValueHash = hex.EncodeToString(hash[:])
+	}
+
+	for _, prefix := range jc.cfg.RedactedPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			entry.Key = prefix + "<redacted>"
+			entry.ValueHash = ""
+			break
+		}
+	}
+
+	jc.mu.Lock()
+	jc.seq++
+	entry.Seq = jc.seq
+	jc.write(entry)
+	jc.mu.Unlock()
+
+	return func(err error) {
+		outcome := "ok"
+		if err != nil {
+			outcome = err.Error()
+		}
+
+		jc.mu.Lock()
+		jc.write(journalEntry{Seq: entry.Seq, Outcome: outcome})
+		jc.mu.Unlock()
+	}
+}
+
+// write appends the given entry to the journal file, rotating it beforehand
+// in case the configured size threshold has been exceeded. It must be called
+// with the mutex held.
+func (jc *journalClient) write(entry journalEntry) {
+	if jc.size >= jc.cfg.MaxSize {
+		jc.file.Close()
+		if err := os.Rename(jc.cfg.Path, jc.cfg.Path+".old"); err != nil {
+			jc.logger.Warn("Failed to rotate kvstore journal",
+				logfields.Error, err,
+				logfields.Path, jc.cfg.Path,
+			)
+		}
+
+		if err := jc.open(); err != nil {
+			jc.logger.Warn("Failed to reopen kvstore journal",
+				logfields.Error, err,
+				logfields.Path, jc.cfg.Path,
+			)
+			return
+		}
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		jc.logger.Warn("Failed to marshal kvstore journal entry",
+			logfields.Error, err,
+		)
+		return
+	}
+
+	data = append(data, '\n')
+	n, err := jc.file.Write(data)
+	jc.size += int64(n)
+	if err != nil {
+		jc.logger.Warn("Failed to write kvstore journal entry",
+			logfields.Error, err,
+			logfields.Path, jc.cfg.Path,
+		)
+	}
+}
+
+func (jc *journalClient) Close() {
+	jc.Client.Close()
+
+	jc.mu.Lock()
+	jc.file.Close()
+	jc.mu.Unlock()
+}
+
+func (jc *journalClient) LockPath(ctx context.Context, path string) (KVLocker, error) {
+	done := jc.record("LockPath", path, nil)
+	locker, err := jc.Client.LockPath(ctx, path)
+	done(err)
+	return locker, err
+}
+
+func (jc *journalClient) Delete(ctx context.Context, key string) error {
+	done := jc.record("Delete", key, nil)
+	err := jc.Client.Delete(ctx, key)
+	done(err)
+	return err
+}
+
+func (jc *journalClient) DeleteIfLocked(ctx context.Context, key string, lock KVLocker) error {
+	done := jc.record("DeleteIfLocked", key, nil)
+	err := jc.Client.DeleteIfLocked(ctx, key, lock)
+	done(err)
+	return err
+}
+
+func (jc *journalClient) DeletePrefix(ctx context.Context, path string) error {
+	done := jc.record("DeletePrefix", path, nil)
+	err := jc.Client.DeletePrefix(ctx, path)
+	done(err)
+	return err
+}
+
+func (jc *journalClient) DeletePrefixIfLocked(ctx context.Context, path string, lock KVLocker) error {
+	done := jc.record("DeletePrefixIfLocked", path, nil)
+	err := jc.Client.DeletePrefixIfLocked(ctx, path, lock)
+	done(err)
+	return err
+}
+
+func (jc *journalClient) Update(ctx context.Context, key string, value []byte, lease bool) error {
+	done := jc.record("Update", key, value)
+	err := jc.Client.Update(ctx, key, value, lease)
+	done(err)
+	return err
+}
+
+func (jc *journalClient) UpdateIfLocked(ctx context.Context, key string, value []byte, lease bool, lock KVLocker) error {
+	done := jc.record("UpdateIfLocked", key, value)
+	err := jc.Client.UpdateIfLocked(ctx, key, value, lease, lock)
+	done(err)
+	return err
+}
+
+func (jc *journalClient) UpdateIfDifferent(ctx context.Context, key string, value []byte, lease bool) (bool, error) {
+	done := jc.record("UpdateIfDifferent", key, value)
+	recreated, err := jc.Client.UpdateIfDifferent(ctx, key, value, lease)
+	done(err)
+	return recreated, err
+}
+
+func (jc *journalClient) UpdateIfDifferentIfLocked(ctx context.Context, key string, value []byte, lease bool, lock KVLocker) (bool, error) {
+	done := jc.record("UpdateIfDifferentIfLocked", key, value)
+	recreated, err := jc.Client.UpdateIfDifferentIfLocked(ctx, key, value, lease, lock)
+	done(err)
+	return recreated, err
+}
+
+func (jc *journalClient) CreateOnly(ctx context.Context, key string, value []byte, lease bool) (bool, error) {
+	done := jc.record("CreateOnly", key, value)
+	created, err := jc.Client.CreateOnly(ctx, key, value, lease)
+	done(err)
+	return created, err
+}
+
+func (jc *journalClient) CreateOnlyIfLocked(ctx context.Context, key string, value []byte, lease bool, lock KVLocker) (bool, error) {
+	done := jc.record("CreateOnlyIfLocked", key, value)
+	created, err := jc.Client.CreateOnlyIfLocked(ctx, key, value, lease, lock)
+	done(err)
+	return created, err
+}
+
+func (jc *journalClient) UpdateBatch(ctx context.Context, pairs []KeyValue, lease bool) error {
+	dones := make([]func(error), 0, len(pairs))
+	for _, pair := range pairs {
+		dones = append(dones, jc.record("UpdateBatch", pair.Key, pair.Value))
+	}
+	err := jc.Client.UpdateBatch(ctx, pairs, lease)
+	for _, done := range dones {
+		done(err)
+	}
+	return err
+}
+
+func (jc *journalClient) Txn(ctx context.Context, conditions []TxnCondition, thenOps, elseOps []TxnOp) (bool, error) {
+	// A transaction is journaled as a single record attributed to the key of
+	// its first mutation, mirroring how DeletePrefix covers multiple keys.
+	var key string
+	switch {
+	case len(thenOps) > 0:
+		key = thenOps[0].Key
+	case len(elseOps) > 0:
+		key = elseOps[0].Key
+	}
+	done := jc.record("Txn", key, nil)
+	succeeded, err := jc.Client.Txn(ctx, conditions, thenOps, elseOps)
+	done(err)
+	return succeeded, err
+}
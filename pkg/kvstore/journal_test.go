@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cilium/hive/hivetest"
+	"github.com/cilium/statedb"
+	"github.com/stretchr/testify/require"
+)
+
+func readJournal(t *testing.T, path string) (entries []journalEntry) {
+	t.Helper()
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry journalEntry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+	require.NoError(t, scanner.Err())
+	return entries
+}
+
+func TestJournal(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "journal")
+
+	client, err := WithJournal(hivetest.Logger(t),
+		NewInMemoryClient(statedb.New(), "__local__"),
+		JournalConfig{
+			Path:             path,
+			RedactedPrefixes: []string{"cilium/secrets/"},
+		})
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.Update(ctx, "cilium/state/nodes/v1/foo", []byte("value"), false))
+	require.NoError(t, client.Delete(ctx, "cilium/state/nodes/v1/foo"))
+	require.NoError(t, client.Update(ctx, "cilium/secrets/ns/name", []byte("secret"), false))
+
+	entries := readJournal(t, path)
+	require.Len(t, entries, 6)
+
+	hash := sha256.Sum256([]byte("value"))
+
+	// Operation records precede the corresponding outcome records, and share
+	// the sequence number.
+	require.Equal(t, journalEntry{
+		Seq: 1, Time: entries[0].Time, Op: "Update",
+		Key: "cilium/state/nodes/v1/foo", ValueHash: hex.EncodeToString(hash[:]),
+	}, entries[0])
+	require.Equal(t, journalEntry{Seq: 1, Outcome: "ok"}, entries[1])
+
+	require.Equal(t, "Delete", entries[2].Op)
+	require.Empty(t, entries[2].ValueHash)
+	require.Equal(t, journalEntry{Seq: 2, Outcome: "ok"}, entries[3])
+
+	// Keys matching a redacted prefix are stripped, and the value hash omitted.
+	require.Equal(t, "cilium/secrets/<redacted>", entries[4].Key)
+	require.Empty(t, entries[4].ValueHash)
+	require.Equal(t, journalEntry{Seq: 3, Outcome: "ok"}, entries[5])
+}
+
+func TestJournalRotation(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "journal")
+
+	client, err := WithJournal(hivetest.Logger(t),
+		NewInMemoryClient(statedb.New(), "__local__"),
+		JournalConfig{Path: path, MaxSize: 256})
+	require.NoError(t, err)
+	defer client.Close()
+
+	for range 16 {
+		require.NoError(t, client.Update(ctx, "cilium/state/nodes/v1/foo", []byte("value"), false))
+	}
+
+	// The journal has been rotated, preserving the previous generation.
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Less(t, info.Size(), int64(512))
+
+	old := readJournal(t, path+".old")
+	recent := readJournal(t, path)
+	require.NotEmpty(t, old)
+	require.NotEmpty(t, recent)
+
+	// Sequence numbers continue across rotations.
+	require.GreaterOrEqual(t, recent[0].Seq, old[len(old)-1].Seq)
+}
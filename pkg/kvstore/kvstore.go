@@ -20,6 +20,12 @@ type Value struct {
 // KeyValuePairs is a map of key=value pairs
 type KeyValuePairs map[string]Value
 
+// KeyValue is a single key/value pair to be written, part of a batch.
+type KeyValue struct {
+	Key   string
+	Value []byte
+}
+
 const (
 	// BaseKeyPrefix is the base prefix that should be used for all keys
 	BaseKeyPrefix = "cilium"
@@ -34,6 +34,20 @@ func TestValidateScopesFromKey(t *testing.T) {
 	}
 }
 
+func TestBucketScopeFromKey(t *testing.T) {
+	// Keys under the standard prefixes keep their scope.
+	require.Equal(t, "identities/v1", bucketScopeFromKey("cilium/state/identities/v1/id/1234"))
+	require.Equal(t, "nodes/v1", bucketScopeFromKey("cilium/state/nodes/v1/default/runtime"))
+
+	// Arbitrary keys are accounted under "other" to bound cardinality.
+	require.Equal(t, metricScopeOther, bucketScopeFromKey("cilium/state/garbage/v9/foo"))
+	require.Equal(t, metricScopeOther, bucketScopeFromKey("some/random/key/with/depth"))
+
+	// Additional scopes can be registered explicitly.
+	RegisterMetricScope("test/v1")
+	require.Equal(t, "test/v1", bucketScopeFromKey("cilium/state/test/v1/foo"))
+}
+
 func TestStateToCachePrefix(t *testing.T) {
 	tests := []struct {
 		name     string
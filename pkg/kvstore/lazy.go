@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+
+	"github.com/cilium/hive/cell"
+)
+
+// LazyClient is a handle to the kvstore client which defers establishing the
+// connection until the first operation is issued. It allows optional features
+// to depend on the kvstore without forcing connectivity to be established at
+// agent startup in deployments where the kvstore is optional. All consumers
+// of the same LazyClient share a single underlying client.
+type LazyClient interface {
+	// IsEnabled returns true if kvstore support is enabled. It never
+	// triggers the connection attempt.
+	IsEnabled() bool
+
+	// Client returns the shared kvstore client, dialing the backend on
+	// first use. It blocks until the connection has been established, the
+	// connection attempt failed, or the context got cancelled. The outcome
+	// of the connection attempt is latched: all subsequent calls return
+	// the same client or error.
+	Client(ctx context.Context) (BackendOperations, error)
+}
+
+type lazyClient struct {
+	enabled bool
+
+	cfg    Config
+	opts   ExtraOptions
+	logger *slog.Logger
+
+	connect   sync.Once
+	connected chan struct{}
+	started   atomic.Bool
+
+	client BackendOperations
+	err    error
+}
+
+func (lc *lazyClient) IsEnabled() bool {
+	return lc.enabled
+}
+
+func (lc *lazyClient) Client(ctx context.Context) (BackendOperations, error) {
+	if !lc.enabled {
+		return nil, fmt.Errorf("kvstore support is not enabled")
+	}
+
+	lc.connect.Do(func() {
+		lc.started.Store(true)
+		go lc.dial()
+	})
+
+	select {
+	case <-lc.connected:
+		return lc.client, lc.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// dial establishes the shared connection. It is detached from the context of
+// the caller which triggered it, so that the cancellation of one consumer
+// does not tear down the client for the others.
+func (lc *lazyClient) dial() {
+	defer close(lc.connected)
+
+	lc.logger.Info("Establishing connection to kvstore on first use")
+	client, errCh := NewClient(context.Background(), lc.logger, lc.cfg.KVStore, lc.cfg.KVStoreOpt, lc.opts)
+	if err := <-errCh; err != nil {
+		if client != nil {
+			client.Close()
+		}
+
+		lc.err = fmt.Errorf("failed to establish connection to kvstore: %w", err)
+		return
+	}
+
+	lc.logger.Info("Connection to kvstore successfully established")
+	lc.client = client
+}
+
+func (lc *lazyClient) Start(cell.HookContext) error {
+	return nil
+}
+
+func (lc *lazyClient) Stop(cell.HookContext) error {
+	if !lc.started.Load() {
+		// The connection was never dialed.
+		return nil
+	}
+
+	<-lc.connected
+	if lc.client != nil {
+		lc.client.Close()
+	}
+	return nil
+}
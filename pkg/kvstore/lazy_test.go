@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cilium/hive/hivetest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazyClientDisabled(t *testing.T) {
+	lc := &lazyClient{enabled: false}
+	require.False(t, lc.IsEnabled())
+
+	_, err := lc.Client(context.Background())
+	require.Error(t, err)
+
+	// Stop must not block when the connection was never dialed.
+	require.NoError(t, lc.Stop(context.Background()))
+}
+
+func TestLazyClientDialError(t *testing.T) {
+	lc := &lazyClient{
+		enabled:   true,
+		cfg:       Config{KVStore: "nonexistent-backend"},
+		connected: make(chan struct{}),
+		logger:    hivetest.Logger(t),
+	}
+	require.True(t, lc.IsEnabled())
+
+	_, err := lc.Client(context.Background())
+	require.ErrorContains(t, err, "nonexistent-backend")
+
+	// The outcome is latched: subsequent calls return the same error
+	// without a new connection attempt.
+	_, err2 := lc.Client(context.Background())
+	require.Equal(t, err, err2)
+
+	require.NoError(t, lc.Stop(context.Background()))
+}
+
+func TestLazyClientContextCancelled(t *testing.T) {
+	lc := &lazyClient{enabled: true, connected: make(chan struct{})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Simulate a connection attempt still in flight.
+	lc.connect.Do(func() { lc.started.Store(true) })
+
+	_, err := lc.Client(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+}
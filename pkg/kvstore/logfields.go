@@ -26,6 +26,9 @@ const (
 	// fieldRemainingEntries is the number of entries still to be retrieved
 	fieldRemainingEntries = "remainingEntries"
 
+	// fieldNumLeases is the number of leases involved in the operation
+	fieldNumLeases = "numLeases"
+
 	// fieldAttachLease is true if the key must be attached to a lease
 	fieldAttachLease = "attachLease"
 
@@ -39,3 +42,11 @@ const (
 
 	fieldSuccess = "success"
 )
+
+const (
+	// pprofSubsys labels the kvstore client goroutines in profiles.
+	pprofSubsys = "subsys"
+
+	// pprofPrefix labels the watcher goroutines with the watched prefix.
+	pprofPrefix = "prefix"
+)
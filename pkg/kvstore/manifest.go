@@ -0,0 +1,141 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/cilium/cilium/pkg/lock"
+)
+
+// PrefixSchema describes the layout of the keys stored below a kvstore
+// prefix: their structure, value encoding, owning subsystem and lease usage.
+type PrefixSchema struct {
+	// Prefix is the kvstore prefix the schema applies to.
+	Prefix string `json:"prefix"`
+
+	// Pattern documents the structure of the keys below the prefix, with
+	// placeholders in angle brackets (e.g., "cilium/state/nodes/v1/<cluster>/<node>").
+	Pattern string `json:"pattern,omitempty"`
+
+	// Encoding is the encoding of the stored values (e.g., "json").
+	Encoding string `json:"encoding"`
+
+	// Owner is the subsystem owning the prefix.
+	Owner string `json:"owner"`
+
+	// Leased indicates whether the keys are attached to a lease, and hence
+	// disappear automatically when their owner goes away.
+	Leased bool `json:"leased"`
+}
+
+// schemaRegistry records the schemas of all registered kvstore prefixes,
+// from which the key layout manifest is generated.
+var schemaRegistry = struct {
+	lock.RWMutex
+	schemas map[string]PrefixSchema
+}{schemas: make(map[string]PrefixSchema)}
+
+// RegisterPrefixSchema records the schema of a kvstore prefix, making it
+// part of the exported key layout manifest. It returns an error if the
+// prefix is empty or already registered.
+func RegisterPrefixSchema(schema PrefixSchema) error {
+	if schema.Prefix == "" {
+		return fmt.Errorf("prefix must not be empty")
+	}
+
+	schemaRegistry.Lock()
+	defer schemaRegistry.Unlock()
+	if _, ok := schemaRegistry.schemas[schema.Prefix]; ok {
+		return fmt.Errorf("prefix %q already registered", schema.Prefix)
+	}
+	schemaRegistry.schemas[schema.Prefix] = schema
+	return nil
+}
+
+// Manifest returns the schemas of all registered prefixes, sorted by prefix,
+// so that the output is stable and can be diffed across versions to detect
+// accidental key layout changes.
+func Manifest() []PrefixSchema {
+	schemaRegistry.RLock()
+	defer schemaRegistry.RUnlock()
+
+	manifest := make([]PrefixSchema, 0, len(schemaRegistry.schemas))
+	for _, schema := range schemaRegistry.schemas {
+		manifest = append(manifest, schema)
+	}
+	slices.SortFunc(manifest, func(a, b PrefixSchema) int {
+		return strings.Compare(a.Prefix, b.Prefix)
+	})
+	return manifest
+}
+
+// schemaForKey returns the registered schema covering the given key, if any.
+// The longest matching prefix wins when multiple schemas cover the key.
+func schemaForKey(key string) (PrefixSchema, bool) {
+	schemaRegistry.RLock()
+	defer schemaRegistry.RUnlock()
+
+	var best PrefixSchema
+	var found bool
+	for prefix, schema := range schemaRegistry.schemas {
+		if strings.HasPrefix(key, prefix) && (!found || len(prefix) > len(best.Prefix)) {
+			best, found = schema, true
+		}
+	}
+	return best, found
+}
+
+// ExportManifest writes the key layout manifest as indented JSON, suitable
+// for machine consumption by external tooling.
+func ExportManifest(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(Manifest())
+}
+
+// registerWellKnownSchemas records the prefixes owned by the kvstore
+// package itself. Other subsystems register their prefixes on their own.
+func registerWellKnownSchemas() {
+	for _, schema := range []PrefixSchema{
+		{
+			Prefix:   InitLockPath,
+			Pattern:  InitLockPath + "/<random>",
+			Encoding: "none",
+			Owner:    "kvstore",
+			Leased:   true,
+		},
+		{
+			Prefix:   HeartbeatPath,
+			Pattern:  HeartbeatPath,
+			Encoding: "json",
+			Owner:    "operator",
+		},
+		{
+			Prefix:   ClusterConfigPrefix,
+			Pattern:  ClusterConfigPrefix + "/<cluster>",
+			Encoding: "json",
+			Owner:    "clustermesh",
+		},
+		{
+			Prefix:   SyncedPrefix,
+			Pattern:  SyncedPrefix + "/<cluster>/<resource>",
+			Encoding: "none",
+			Owner:    "kvstore",
+			Leased:   true,
+		},
+	} {
+		if err := RegisterPrefixSchema(schema); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func init() {
+	registerWellKnownSchemas()
+}
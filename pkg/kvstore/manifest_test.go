@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrefixSchemaRegistry(t *testing.T) {
+	schema := PrefixSchema{
+		Prefix:   "cilium/state/test/v1",
+		Pattern:  "cilium/state/test/v1/<name>",
+		Encoding: "json",
+		Owner:    "test",
+		Leased:   true,
+	}
+	require.NoError(t, RegisterPrefixSchema(schema))
+	t.Cleanup(func() {
+		schemaRegistry.Lock()
+		delete(schemaRegistry.schemas, schema.Prefix)
+		schemaRegistry.Unlock()
+	})
+
+	// Duplicate and empty registrations are rejected.
+	assert.Error(t, RegisterPrefixSchema(schema))
+	assert.Error(t, RegisterPrefixSchema(PrefixSchema{}))
+
+	// The manifest contains the registered schema, sorted by prefix.
+	manifest := Manifest()
+	assert.Contains(t, manifest, schema)
+	assert.True(t, slicesIsSortedByPrefix(manifest))
+
+	// The exported manifest is machine-readable.
+	var sb strings.Builder
+	require.NoError(t, ExportManifest(&sb))
+	var decoded []PrefixSchema
+	require.NoError(t, json.Unmarshal([]byte(sb.String()), &decoded))
+	assert.Equal(t, manifest, decoded)
+}
+
+func slicesIsSortedByPrefix(manifest []PrefixSchema) bool {
+	for i := 1; i < len(manifest); i++ {
+		if manifest[i-1].Prefix > manifest[i].Prefix {
+			return false
+		}
+	}
+	return true
+}
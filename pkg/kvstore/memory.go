@@ -7,6 +7,8 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"iter"
+	"log/slog"
 	"strings"
 
 	"github.com/cilium/statedb"
@@ -14,8 +16,48 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/time"
 )
 
+// MemoryBackendName is the backend name of the in-memory kvstore. It holds
+// all keys in process memory, primarily for tests of kvstore consumers which
+// would otherwise require a real etcd cluster.
+const MemoryBackendName = "memory"
+
+// memoryModule registers the in-memory client as a first-class backend, so
+// that it can be selected through the regular --kvstore machinery.
+type memoryModule struct{}
+
+func (m *memoryModule) createInstance() backendModule {
+	return &memoryModule{}
+}
+
+func (m *memoryModule) setConfig(logger *slog.Logger, opts map[string]string) error {
+	// The in-memory backend has no configuration options.
+	return setOpts(logger, opts, backendOptions{})
+}
+
+func (m *memoryModule) newClient(ctx context.Context, logger *slog.Logger, opts ExtraOptions) (BackendOperations, chan error) {
+	errChan := make(chan error)
+	close(errChan)
+
+	clusterName := opts.ClusterName
+	if clusterName == "" {
+		clusterName = MemoryBackendName
+	}
+	return NewInMemoryClient(statedb.New(), clusterName), errChan
+}
+
+func init() {
+	registerBackend(MemoryBackendName, &memoryModule{})
+}
+
+// inMemoryLeaseID is the lease attached to keys written with lease=true. The
+// client owns a single session lease, mirroring the etcd lease manager: the
+// leased keys expire collectively when the client is closed.
+const inMemoryLeaseID int64 = 1
+
 func NewInMemoryClient(db *statedb.DB, clusterName string) Client {
 	table, err := statedb.NewTable(
 		"kvstore-"+clusterName,
@@ -28,15 +70,19 @@ func NewInMemoryClient(db *statedb.DB, clusterName string) Client {
 		panic(err)
 	}
 	return &inMemoryClient{
-		db:          db,
-		table:       table,
-		clusterName: clusterName,
+		db:                    db,
+		table:                 table,
+		clusterName:           clusterName,
+		locks:                 make(map[string]chan struct{}),
+		leaseExpiredObservers: make(map[string]func(key string)),
+		statusChanged:         make(chan struct{}),
 	}
 }
 
 type inMemoryObject struct {
-	key   string
-	value []byte
+	key     string
+	value   []byte
+	leaseID int64
 }
 
 var (
@@ -55,31 +101,97 @@ type inMemoryClient struct {
 	db          *statedb.DB
 	table       statedb.RWTable[inMemoryObject]
 	clusterName string
+
+	// locksMu protects locks, which contains a channel per held lock path,
+	// closed when the lock is released.
+	locksMu lock.Mutex
+	locks   map[string]chan struct{}
+
+	// observersMu protects leaseExpiredObservers, keyed by prefix. The
+	// observers are invoked when the client is closed, as that is when the
+	// session lease of the client expires.
+	observersMu           lock.Mutex
+	leaseExpiredObservers map[string]func(key string)
+
+	// statusChanged is never closed, as the status never changes.
+	statusChanged chan struct{}
+}
+
+// leaseID returns the lease to attach to a written key, mirroring the etcd
+// behavior of associating leased keys with the client session.
+func leaseID(lease bool) int64 {
+	if lease {
+		return inMemoryLeaseID
+	}
+	return 0
+}
+
+// holdsLock returns true if the given locker was handed out by this client
+// and has not been released, matching the lock validation the etcd backend
+// performs via transaction comparators.
+func (c *inMemoryClient) holdsLock(kvLock KVLocker) bool {
+	l, ok := kvLock.(*inMemoryLocker)
+	if !ok || l.client != c {
+		return false
+	}
+
+	c.locksMu.Lock()
+	defer c.locksMu.Unlock()
+	released, held := c.locks[l.path]
+	return held && released == l.released
 }
 
 func (c *inMemoryClient) IsEnabled() bool { return true }
 
-// Close implements BackendOperations.
+// Close implements BackendOperations. Closing the client expires its session
+// lease: all keys written with lease=true are deleted and the lease expired
+// observers are invoked for them.
 func (c *inMemoryClient) Close() {
+	var expired []string
+
+	wtxn := c.db.WriteTxn(c.table)
+	for obj := range c.table.All(wtxn) {
+		if obj.leaseID != 0 {
+			c.table.Delete(wtxn, obj)
+			expired = append(expired, obj.key)
+		}
+	}
+	wtxn.Commit()
+
+	c.observersMu.Lock()
+	defer c.observersMu.Unlock()
+	for _, key := range expired {
+		for prefix, fn := range c.leaseExpiredObservers {
+			if strings.HasPrefix(key, prefix) {
+				fn(key)
+			}
+		}
+	}
 }
 
-// CreateOnly implements BackendOperations.
+// CreateOnly implements BackendOperations. As with the etcd backend, an
+// already existing key is not an error, it is reported via the returned
+// boolean.
 func (c *inMemoryClient) CreateOnly(ctx context.Context, key string, value []byte, lease bool) (bool, error) {
 	wtxn := c.db.WriteTxn(c.table)
 	defer wtxn.Abort()
-	_, hadOld, _ := c.table.Insert(wtxn, inMemoryObject{
-		key:   key,
-		value: value,
-	})
-	if hadOld {
-		return false, fmt.Errorf("key %q existed", key)
+	if _, _, found := c.table.Get(wtxn, inMemoryKeyIndex.Query(key)); found {
+		return false, nil
 	}
+	c.table.Insert(wtxn, inMemoryObject{
+		key:     key,
+		value:   value,
+		leaseID: leaseID(lease),
+	})
 	wtxn.Commit()
 	return true, nil
 }
 
 // CreateOnlyIfLocked implements BackendOperations.
 func (c *inMemoryClient) CreateOnlyIfLocked(ctx context.Context, key string, value []byte, lease bool, lock KVLocker) (bool, error) {
+	if !c.holdsLock(lock) {
+		return false, ErrLockLeaseExpired
+	}
 	return c.CreateOnly(ctx, key, value, lease)
 }
 
@@ -97,6 +209,9 @@ func (c *inMemoryClient) Delete(ctx context.Context, key string) error {
 
 // DeleteIfLocked implements BackendOperations.
 func (c *inMemoryClient) DeleteIfLocked(ctx context.Context, key string, lock KVLocker) error {
+	if !c.holdsLock(lock) {
+		return ErrLockLeaseExpired
+	}
 	return c.Delete(ctx, key)
 }
 
@@ -110,6 +225,14 @@ func (c *inMemoryClient) DeletePrefix(ctx context.Context, path string) error {
 	return nil
 }
 
+// DeletePrefixIfLocked implements BackendOperations.
+func (c *inMemoryClient) DeletePrefixIfLocked(ctx context.Context, path string, lock KVLocker) error {
+	if !c.holdsLock(lock) {
+		return ErrLockLeaseExpired
+	}
+	return c.DeletePrefix(ctx, path)
+}
+
 // Get implements BackendOperations.
 func (c *inMemoryClient) Get(ctx context.Context, key string) ([]byte, error) {
 	obj, _, found := c.table.Get(c.db.ReadTxn(), inMemoryKeyIndex.Query(key))
@@ -121,11 +244,25 @@ func (c *inMemoryClient) Get(ctx context.Context, key string) ([]byte, error) {
 
 // GetIfLocked implements BackendOperations.
 func (c *inMemoryClient) GetIfLocked(ctx context.Context, key string, lock KVLocker) ([]byte, error) {
+	if !c.holdsLock(lock) {
+		return nil, ErrLockLeaseExpired
+	}
 	return c.Get(ctx, key)
 }
 
 // ListAndWatch implements BackendOperations.
 func (c *inMemoryClient) ListAndWatch(ctx context.Context, prefix string) EventChan {
+	return c.watch(ctx, func(key string) bool { return strings.HasPrefix(key, prefix) })
+}
+
+// WatchKey implements BackendOperations.
+func (c *inMemoryClient) WatchKey(ctx context.Context, key string) EventChan {
+	return c.watch(ctx, func(k string) bool { return k == key })
+}
+
+// watch emits an event for every change of a key matched by the given
+// function, preceded by the matching keys already present.
+func (c *inMemoryClient) watch(ctx context.Context, match func(key string) bool) EventChan {
 	wtxn := c.db.WriteTxn(c.table)
 	changeIter, err := c.table.Changes(wtxn)
 	wtxn.Commit()
@@ -137,12 +274,13 @@ func (c *inMemoryClient) ListAndWatch(ctx context.Context, prefix string) EventC
 	go func() {
 		defer close(events)
 		initDone := false
+		var seq uint64
 		exists := sets.New[string]()
 		for {
 			changes, watch := changeIter.Next(c.db.ReadTxn())
 			for change := range changes {
 				obj := change.Object
-				if !strings.HasPrefix(obj.key, prefix) {
+				if !match(obj.key) {
 					continue
 				}
 				var typ EventType
@@ -156,21 +294,29 @@ func (c *inMemoryClient) ListAndWatch(ctx context.Context, prefix string) EventC
 					typ = EventTypeCreate
 					exists.Insert(obj.key)
 				}
+				seq++
 				events <- KeyValueEvent{
 					Typ:   typ,
 					Key:   obj.key,
 					Value: obj.value,
+					Seq:   seq,
 				}
 			}
 
 			if !initDone {
-				events <- KeyValueEvent{Typ: EventTypeListDone}
+				seq++
+				events <- KeyValueEvent{Typ: EventTypeListDone, Seq: seq}
 				initDone = true
 			}
 
 			select {
 			case <-watch:
 			case <-ctx.Done():
+				seq++
+				select {
+				case events <- KeyValueEvent{Typ: EventTypeDrain, Value: []byte(context.Cause(ctx).Error()), Seq: seq}:
+				case <-time.After(drainTimeout):
+				}
 				return
 			}
 		}
@@ -185,24 +331,112 @@ func (c *inMemoryClient) ListPrefix(ctx context.Context, prefix string) (kvs Key
 		kvs[obj.key] = Value{
 			Data:        obj.value,
 			ModRevision: rev,
-			LeaseID:     0,
+			LeaseID:     obj.leaseID,
 		}
 	}
 	return
 }
 
+// ListPrefixFunc implements BackendOperations.
+func (c *inMemoryClient) ListPrefixFunc(ctx context.Context, prefix string, fn func(key string, value Value) bool) error {
+	for obj, rev := range c.table.Prefix(c.db.ReadTxn(), inMemoryKeyIndex.Query(prefix)) {
+		if !fn(obj.key, Value{
+			Data:        obj.value,
+			ModRevision: rev,
+			LeaseID:     obj.leaseID,
+		}) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// ListPrefixPaginated implements BackendOperations. The in-memory backend has
+// no limits on response size, hence the page size is ignored.
+func (c *inMemoryClient) ListPrefixPaginated(ctx context.Context, prefix string, pageSize int) (iter.Seq2[string, Value], <-chan error) {
+	errs := make(chan error, 1)
+	return func(yield func(string, Value) bool) {
+		defer close(errs)
+		for obj, rev := range c.table.Prefix(c.db.ReadTxn(), inMemoryKeyIndex.Query(prefix)) {
+			if !yield(obj.key, Value{
+				Data:        obj.value,
+				ModRevision: rev,
+				LeaseID:     obj.leaseID,
+			}) {
+				return
+			}
+		}
+	}, errs
+}
+
 // ListPrefixIfLocked implements BackendOperations.
 func (c *inMemoryClient) ListPrefixIfLocked(ctx context.Context, prefix string, lock KVLocker) (KeyValuePairs, error) {
+	if !c.holdsLock(lock) {
+		return nil, ErrLockLeaseExpired
+	}
 	return c.ListPrefix(ctx, prefix)
 }
 
 // LockPath implements BackendOperations.
 func (c *inMemoryClient) LockPath(ctx context.Context, path string) (KVLocker, error) {
-	panic("unimplemented")
+	for {
+		c.locksMu.Lock()
+		released, held := c.locks[path]
+		if !held {
+			released = make(chan struct{})
+			c.locks[path] = released
+			c.locksMu.Unlock()
+			return &inMemoryLocker{client: c, path: path, released: released}, nil
+		}
+		c.locksMu.Unlock()
+
+		select {
+		case <-released:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// inMemoryLocker implements KVLocker for the in-memory client. The released
+// channel identifies the lock acquisition, so that an unlocked and
+// reacquired lock invalidates lockers handed out earlier.
+type inMemoryLocker struct {
+	client   *inMemoryClient
+	path     string
+	released chan struct{}
 }
 
-// RegisterLeaseExpiredObserver implements BackendOperations.
+func (l *inMemoryLocker) Unlock(ctx context.Context) error {
+	l.client.locksMu.Lock()
+	defer l.client.locksMu.Unlock()
+
+	if released, held := l.client.locks[l.path]; held && released == l.released {
+		close(released)
+		delete(l.client.locks, l.path)
+	}
+	return nil
+}
+
+func (l *inMemoryLocker) Comparator() any { return nil }
+
+// RegisterLeaseExpiredObserver implements BackendOperations. The observers
+// are invoked when the client is closed, as that is the only point at which
+// the in-memory session lease expires.
 func (c *inMemoryClient) RegisterLeaseExpiredObserver(prefix string, fn func(key string)) {
+	c.observersMu.Lock()
+	defer c.observersMu.Unlock()
+
+	if fn == nil {
+		delete(c.leaseExpiredObservers, prefix)
+		return
+	}
+	c.leaseExpiredObservers[prefix] = fn
+}
+
+// RegisterLeaseKeepaliveFailureObserver implements BackendOperations. The
+// in-memory lease keepalives never fail, so the observer is never invoked.
+func (c *inMemoryClient) RegisterLeaseKeepaliveFailureObserver(prefix string, fn func(key string)) {
 }
 
 // Status implements BackendOperations.
@@ -210,17 +444,75 @@ func (c *inMemoryClient) Status() *models.Status {
 	return &models.Status{}
 }
 
+// StatusDetail implements BackendOperations.
+func (c *inMemoryClient) StatusDetail() *models.KVstoreStatus {
+	return &models.KVstoreStatus{HasQuorum: true}
+}
+
+// StatusChanged implements BackendOperations.
+func (c *inMemoryClient) StatusChanged() <-chan struct{} {
+	return c.statusChanged
+}
+
 // StatusCheckErrors implements BackendOperations.
 func (c *inMemoryClient) StatusCheckErrors() <-chan error {
 	return nil
 }
 
+// Txn implements BackendOperations.
+func (c *inMemoryClient) Txn(ctx context.Context, conditions []TxnCondition, thenOps, elseOps []TxnOp) (bool, error) {
+	wtxn := c.db.WriteTxn(c.table)
+	defer wtxn.Abort()
+
+	succeeded := true
+	for _, cnd := range conditions {
+		obj, _, found := c.table.Get(wtxn, inMemoryKeyIndex.Query(cnd.Key))
+		switch cnd.Typ {
+		case TxnKeyExists:
+			succeeded = succeeded && found
+		case TxnKeyAbsent:
+			succeeded = succeeded && !found
+		case TxnValueEquals:
+			succeeded = succeeded && found && bytes.Equal(obj.value, cnd.Value)
+		default:
+			return false, fmt.Errorf("unsupported transaction condition type %q", cnd.Typ)
+		}
+	}
+
+	ops := thenOps
+	if !succeeded {
+		ops = elseOps
+	}
+	for _, op := range ops {
+		switch op.Typ {
+		case TxnOpPut:
+			c.table.Insert(wtxn, inMemoryObject{key: op.Key, value: op.Value})
+		case TxnOpDelete:
+			c.table.Delete(wtxn, inMemoryObject{key: op.Key})
+		default:
+			return false, fmt.Errorf("unsupported transaction operation type %q", op.Typ)
+		}
+	}
+
+	wtxn.Commit()
+	return succeeded, nil
+}
+
+// UpdateBatch implements BackendOperations.
+func (c *inMemoryClient) UpdateBatch(ctx context.Context, pairs []KeyValue, lease bool) error {
+	wtxn := c.db.WriteTxn(c.table)
+	defer wtxn.Commit()
+	for _, pair := range pairs {
+		c.table.Insert(wtxn, inMemoryObject{pair.Key, pair.Value, leaseID(lease)})
+	}
+	return nil
+}
+
 // Update implements BackendOperations.
 func (c *inMemoryClient) Update(ctx context.Context, key string, value []byte, lease bool) error {
 	wtxn := c.db.WriteTxn(c.table)
 	defer wtxn.Commit()
-	c.table.Insert(wtxn, inMemoryObject{key, value})
-	wtxn.Commit()
+	c.table.Insert(wtxn, inMemoryObject{key, value, leaseID(lease)})
 	return nil
 }
 
@@ -229,32 +521,40 @@ func (c *inMemoryClient) UpdateIfDifferent(ctx context.Context, key string, valu
 	wtxn := c.db.WriteTxn(c.table)
 	defer wtxn.Abort()
 	obj, _, found := c.table.Get(wtxn, inMemoryKeyIndex.Query(key))
-	if found && bytes.Equal(obj.value, value) {
+	if found && bytes.Equal(obj.value, value) && obj.leaseID == leaseID(lease) {
 		return false, nil
 	}
-	c.table.Insert(wtxn, inMemoryObject{key, value})
+	c.table.Insert(wtxn, inMemoryObject{key, value, leaseID(lease)})
 	wtxn.Commit()
 	return true, nil
 }
 
 // UpdateIfDifferentIfLocked implements BackendOperations.
 func (c *inMemoryClient) UpdateIfDifferentIfLocked(ctx context.Context, key string, value []byte, lease bool, lock KVLocker) (bool, error) {
+	if !c.holdsLock(lock) {
+		return false, ErrLockLeaseExpired
+	}
 	return c.UpdateIfDifferent(ctx, key, value, lease)
 }
 
 // UpdateIfLocked implements BackendOperations.
 func (c *inMemoryClient) UpdateIfLocked(ctx context.Context, key string, value []byte, lease bool, lock KVLocker) error {
+	if !c.holdsLock(lock) {
+		return ErrLockLeaseExpired
+	}
 	return c.Update(ctx, key, value, lease)
 }
 
-// UserEnforceAbsence implements BackendOperations.
+// UserEnforceAbsence implements BackendOperations. The in-memory kvstore has
+// no user management, enforcing the absence of a user always succeeds.
 func (c *inMemoryClient) UserEnforceAbsence(ctx context.Context, name string) error {
-	panic("unimplemented")
+	return nil
 }
 
-// UserEnforcePresence implements BackendOperations.
+// UserEnforcePresence implements BackendOperations. The in-memory kvstore has
+// no user management, enforcing the presence of a user always succeeds.
 func (c *inMemoryClient) UserEnforcePresence(ctx context.Context, name string, roles []string) error {
-	panic("unimplemented")
+	return nil
 }
 
 var _ BackendOperations = &inMemoryClient{}
@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"testing"
+
+	"github.com/cilium/hive/hivetest"
+	"github.com/cilium/statedb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryBackendRegistered(t *testing.T) {
+	client, errCh := NewClient(t.Context(), hivetest.Logger(t), MemoryBackendName, nil, ExtraOptions{})
+	require.NotNil(t, client)
+	require.NoError(t, <-errCh)
+	defer client.Close()
+
+	require.NoError(t, client.Update(t.Context(), "cilium/test/key", []byte("value"), false))
+	value, err := client.Get(t.Context(), "cilium/test/key")
+	require.NoError(t, err)
+	require.Equal(t, []byte("value"), value)
+}
+
+func TestInMemoryListPrefixPaginated(t *testing.T) {
+	client := NewInMemoryClient(statedb.New(), "__local__")
+	ctx := t.Context()
+
+	require.NoError(t, client.Update(ctx, "cilium/a", []byte("1"), false))
+	require.NoError(t, client.Update(ctx, "cilium/b", []byte("2"), false))
+	require.NoError(t, client.Update(ctx, "other/c", []byte("3"), false))
+
+	pairs := KeyValuePairs{}
+	seq, errs := client.ListPrefixPaginated(ctx, "cilium/", 1)
+	for key, value := range seq {
+		pairs[key] = value
+	}
+	require.NoError(t, <-errs)
+	require.Len(t, pairs, 2)
+	assert.Equal(t, []byte("1"), pairs["cilium/a"].Data)
+	assert.Equal(t, []byte("2"), pairs["cilium/b"].Data)
+}
+
+func TestInMemoryCreateOnly(t *testing.T) {
+	client := NewInMemoryClient(statedb.New(), "__local__")
+	ctx := t.Context()
+
+	created, err := client.CreateOnly(ctx, "key", []byte("first"), false)
+	require.NoError(t, err)
+	require.True(t, created)
+
+	// An already existing key is not an error, matching the etcd backend.
+	created, err = client.CreateOnly(ctx, "key", []byte("second"), false)
+	require.NoError(t, err)
+	require.False(t, created)
+
+	value, err := client.Get(ctx, "key")
+	require.NoError(t, err)
+	require.Equal(t, []byte("first"), value)
+}
+
+func TestInMemoryLease(t *testing.T) {
+	client := NewInMemoryClient(statedb.New(), "__local__")
+	ctx := t.Context()
+
+	require.NoError(t, client.Update(ctx, "cilium/leased/key", []byte("value"), true))
+	require.NoError(t, client.Update(ctx, "cilium/unleased/key", []byte("value"), false))
+
+	// Leased keys carry the lease ID of the client session.
+	pairs, err := client.ListPrefix(ctx, "cilium/")
+	require.NoError(t, err)
+	assert.NotZero(t, pairs["cilium/leased/key"].LeaseID)
+	assert.Zero(t, pairs["cilium/unleased/key"].LeaseID)
+
+	var expired []string
+	client.RegisterLeaseExpiredObserver("cilium/leased/", func(key string) {
+		expired = append(expired, key)
+	})
+
+	// Closing the client expires the session lease: the leased keys are
+	// deleted and the observers invoked, the unleased keys survive.
+	client.Close()
+	assert.Equal(t, []string{"cilium/leased/key"}, expired)
+
+	value, err := client.Get(ctx, "cilium/leased/key")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	value, err = client.Get(ctx, "cilium/unleased/key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+}
+
+func TestInMemoryLockValidation(t *testing.T) {
+	client := NewInMemoryClient(statedb.New(), "__local__")
+	ctx := t.Context()
+
+	locker, err := client.LockPath(ctx, "cilium/locks/test")
+	require.NoError(t, err)
+
+	// Operations guarded by a held lock succeed.
+	require.NoError(t, client.UpdateIfLocked(ctx, "key", []byte("value"), false, locker))
+	_, err = client.GetIfLocked(ctx, "key", locker)
+	require.NoError(t, err)
+
+	// Once released, the locker no longer guards anything.
+	require.NoError(t, locker.Unlock(ctx))
+	assert.ErrorIs(t, client.UpdateIfLocked(ctx, "key", []byte("value"), false, locker), ErrLockLeaseExpired)
+	_, err = client.GetIfLocked(ctx, "key", locker)
+	assert.ErrorIs(t, err, ErrLockLeaseExpired)
+	_, err = client.ListPrefixIfLocked(ctx, "cilium/", locker)
+	assert.ErrorIs(t, err, ErrLockLeaseExpired)
+	assert.ErrorIs(t, client.DeleteIfLocked(ctx, "key", locker), ErrLockLeaseExpired)
+	assert.ErrorIs(t, client.DeletePrefixIfLocked(ctx, "cilium/", locker), ErrLockLeaseExpired)
+	_, err = client.CreateOnlyIfLocked(ctx, "key2", []byte("value"), false, locker)
+	assert.ErrorIs(t, err, ErrLockLeaseExpired)
+
+	// A stale locker does not release a lock reacquired by someone else.
+	locker2, err := client.LockPath(ctx, "cilium/locks/test")
+	require.NoError(t, err)
+	require.NoError(t, locker.Unlock(ctx))
+	require.NoError(t, client.UpdateIfLocked(ctx, "key", []byte("value"), false, locker2))
+	require.NoError(t, locker2.Unlock(ctx))
+}
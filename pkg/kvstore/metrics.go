@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/cilium/cilium/pkg/lock"
 	"github.com/cilium/cilium/pkg/metrics"
 	"github.com/cilium/cilium/pkg/time"
 )
@@ -15,8 +16,49 @@ const (
 	metricDelete = "delete"
 	metricRead   = "read"
 	metricSet    = "set"
+
+	// writeOutcomeWritten accounts for write requests issued to the kvstore,
+	// writeOutcomeFailed for the ones that returned an error, and
+	// writeOutcomeAvoided for the updates skipped because the value turned
+	// out to be unchanged.
+	writeOutcomeWritten = "written"
+	writeOutcomeFailed  = "failed"
+	writeOutcomeAvoided = "avoided"
+
+	// metricScopeOther is the bucket accounting for all keys that do not
+	// map to one of the registered metric scopes, so that arbitrary keys
+	// cannot grow the scope label cardinality without bound.
+	metricScopeOther = "other"
 )
 
+// metricScopes is the set of well-known per-prefix buckets used as the scope
+// label of kvstore metrics. It is seeded with the scopes of the standard
+// Cilium prefixes; consumers operating on additional prefixes can extend it
+// via RegisterMetricScope.
+var metricScopes = func() *lock.Map[string, struct{}] {
+	m := &lock.Map[string, struct{}]{}
+	for _, scope := range []string{
+		"identities/v1",
+		"ip/v1",
+		"nodes/v1",
+		"services/v1",
+		"serviceexports/v1",
+		GetScopeFromKey(HeartbeatPath),
+		GetScopeFromKey(InitLockPath),
+		GetScopeFromKey(ClusterConfigPrefix),
+	} {
+		m.Store(scope, struct{}{})
+	}
+	return m
+}()
+
+// RegisterMetricScope adds the given scope to the set of buckets exposed via
+// the scope label of kvstore metrics. Keys whose scope has not been
+// registered are accounted under "other".
+func RegisterMetricScope(scope string) {
+	metricScopes.Store(scope, struct{}{})
+}
+
 func GetScopeFromKey(key string) string {
 	s := strings.SplitN(key, "/", 5)
 	if len(s) < 4 {
@@ -28,11 +70,26 @@ func GetScopeFromKey(key string) string {
 	return fmt.Sprintf("%s/%s", s[2], s[3])
 }
 
+// bucketScope maps an already derived scope to itself if registered, or to
+// metricScopeOther otherwise.
+func bucketScope(scope string) string {
+	if _, ok := metricScopes.Load(scope); ok {
+		return scope
+	}
+	return metricScopeOther
+}
+
+// bucketScopeFromKey maps the given key to one of the registered metric
+// scopes, falling back to metricScopeOther.
+func bucketScopeFromKey(key string) string {
+	return bucketScope(GetScopeFromKey(key))
+}
+
 func increaseMetric(key, kind, action string, duration time.Duration, err error) {
 	if !metrics.KVStoreOperationsDuration.IsEnabled() {
 		return
 	}
-	namespace := GetScopeFromKey(key)
+	namespace := bucketScopeFromKey(key)
 	outcome := metrics.Error2Outcome(err)
 	metrics.KVStoreOperationsDuration.
 		WithLabelValues(namespace, kind, action, outcome).Observe(duration.Seconds())
@@ -42,7 +99,85 @@ func trackEventQueued(scope string, typ EventType, duration time.Duration) {
 	if !metrics.KVStoreEventsQueueDuration.IsEnabled() {
 		return
 	}
-	metrics.KVStoreEventsQueueDuration.WithLabelValues(scope, typ.String()).Observe(duration.Seconds())
+	metrics.KVStoreEventsQueueDuration.WithLabelValues(bucketScope(scope), typ.String()).Observe(duration.Seconds())
+}
+
+func recordEvent(scope string, typ EventType) {
+	if !metrics.KVStoreEvents.IsEnabled() {
+		return
+	}
+	metrics.KVStoreEvents.WithLabelValues(bucketScope(scope), typ.String()).Inc()
+}
+
+func recordRelist(scope string) {
+	if !metrics.KVStoreRelists.IsEnabled() {
+		return
+	}
+	metrics.KVStoreRelists.WithLabelValues(bucketScope(scope)).Inc()
+}
+
+// recordWatchRevisionLag expects an already bucketed scope, as callers
+// aggregate the lag of the watched prefixes per scope themselves.
+func recordWatchRevisionLag(scope string, lag int64) {
+	if !metrics.KVStoreWatchRevisionLag.IsEnabled() {
+		return
+	}
+	metrics.KVStoreWatchRevisionLag.WithLabelValues(scope).Set(float64(lag))
+}
+
+// recordPrefixKeyCount expects an already bucketed scope, as callers
+// aggregate the key counts of the watched prefixes per scope themselves.
+func recordPrefixKeyCount(scope string, count int64) {
+	if !metrics.KVStorePrefixKeys.IsEnabled() {
+		return
+	}
+	metrics.KVStorePrefixKeys.WithLabelValues(scope).Set(float64(count))
+}
+
+func recordInitialSyncDuration(prefix string, duration time.Duration) {
+	if !metrics.KVStoreInitialSyncDuration.IsEnabled() {
+		return
+	}
+	metrics.KVStoreInitialSyncDuration.WithLabelValues(bucketScopeFromKey(prefix)).Observe(duration.Seconds())
+}
+
+func recordWatcherQueueDuration(scope string, duration time.Duration) {
+	if !metrics.KVStoreWatcherQueueDuration.IsEnabled() {
+		return
+	}
+	metrics.KVStoreWatcherQueueDuration.WithLabelValues(bucketScope(scope)).Observe(duration.Seconds())
+}
+
+func recordWrite(key string, err error) {
+	if !metrics.KVStoreWrites.IsEnabled() {
+		return
+	}
+	outcome := writeOutcomeWritten
+	if err != nil {
+		outcome = writeOutcomeFailed
+	}
+	metrics.KVStoreWrites.WithLabelValues(bucketScopeFromKey(key), outcome).Inc()
+}
+
+func recordWriteAvoided(key string) {
+	if !metrics.KVStoreWrites.IsEnabled() {
+		return
+	}
+	metrics.KVStoreWrites.WithLabelValues(bucketScopeFromKey(key), writeOutcomeAvoided).Inc()
+}
+
+func recordOversizedRequest(key, action string) {
+	if !metrics.KVStoreOversizedRequests.IsEnabled() {
+		return
+	}
+	metrics.KVStoreOversizedRequests.WithLabelValues(bucketScopeFromKey(key), action).Inc()
+}
+
+func recordRateLimit(limit float64) {
+	if !metrics.KVStoreRateLimit.IsEnabled() {
+		return
+	}
+	metrics.KVStoreRateLimit.Set(limit)
 }
 
 func recordQuorumError(err string) {
@@ -51,3 +186,10 @@ func recordQuorumError(err string) {
 	}
 	metrics.KVStoreQuorumErrors.WithLabelValues(err).Inc()
 }
+
+func recordConnectivityStateTransition(endpoint, from, to string) {
+	if !metrics.KVStoreConnectivityStateTransitions.IsEnabled() {
+		return
+	}
+	metrics.KVStoreConnectivityStateTransitions.WithLabelValues(endpoint, from, to).Inc()
+}
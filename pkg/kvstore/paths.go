@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	cmtypes "github.com/cilium/cilium/pkg/clustermesh/types"
+)
+
+const (
+	// IPCachePrefix is the kvstore prefix under which the endpoint IP to
+	// identity mappings are shared.
+	IPCachePrefix = StatePrefix + "/ip/v1"
+
+	// NodesPrefix is the kvstore prefix under which the node information
+	// is shared.
+	NodesPrefix = StatePrefix + "/nodes/v1"
+
+	// IdentitiesPrefix is the kvstore prefix under which the security
+	// identities are allocated.
+	IdentitiesPrefix = StatePrefix + "/identities/v1"
+
+	// locksSuffix is the path element appended to a prefix to hold the
+	// distributed locks guarding it.
+	locksSuffix = "locks"
+)
+
+// JoinPath joins the given elements into a kvstore key path, validating that
+// no element is empty or contains leading, trailing or repeated slashes.
+// Differently from path.Join, malformed elements are rejected rather than
+// silently cleaned up, surfacing key construction bugs which would otherwise
+// manifest as subtle key mismatches between writers and watchers.
+func JoinPath(elems ...string) (string, error) {
+	if len(elems) == 0 {
+		return "", errors.New("no key path elements provided")
+	}
+
+	for _, elem := range elems {
+		switch {
+		case elem == "":
+			return "", errors.New("key path elements must not be empty")
+		case strings.HasPrefix(elem, "/"), strings.HasSuffix(elem, "/"), strings.Contains(elem, "//"):
+			return "", fmt.Errorf("key path element %q must not contain leading, trailing or repeated slashes", elem)
+		}
+	}
+
+	return strings.Join(elems, "/"), nil
+}
+
+// ClusterPath joins the given elements under prefix/<cluster name>,
+// additionally validating that the cluster name is legal.
+func ClusterPath(prefix string, clusterName string, elems ...string) (string, error) {
+	if err := cmtypes.ValidateClusterName(clusterName); err != nil {
+		return "", fmt.Errorf("invalid cluster name %q: %w", clusterName, err)
+	}
+
+	return JoinPath(append([]string{prefix, clusterName}, elems...)...)
+}
+
+// LocksPrefix returns the prefix under which the distributed locks guarding
+// the given prefix are created.
+func LocksPrefix(prefix string) string {
+	return prefix + "/" + locksSuffix
+}
@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJoinPath(t *testing.T) {
+	path, err := JoinPath(IPCachePrefix, "default", "10.0.0.1")
+	require.NoError(t, err)
+	require.Equal(t, "cilium/state/ip/v1/default/10.0.0.1", path)
+
+	// Elements may contain internal slashes, as long as they are well formed.
+	path, err = JoinPath(StatePrefix, "nodes/v1")
+	require.NoError(t, err)
+	require.Equal(t, NodesPrefix, path)
+
+	_, err = JoinPath()
+	require.Error(t, err)
+
+	_, err = JoinPath(NodesPrefix, "")
+	require.Error(t, err)
+
+	for _, elem := range []string{"/foo", "foo/", "foo//bar"} {
+		_, err = JoinPath(IdentitiesPrefix, elem)
+		require.Error(t, err, "element %q should have been rejected", elem)
+	}
+}
+
+func TestClusterPath(t *testing.T) {
+	path, err := ClusterPath(NodesPrefix, "foo", "node-1")
+	require.NoError(t, err)
+	require.Equal(t, "cilium/state/nodes/v1/foo/node-1", path)
+
+	for _, cluster := range []string{"", "Foo", "foo_bar", "-foo"} {
+		_, err = ClusterPath(NodesPrefix, cluster)
+		require.Error(t, err, "cluster name %q should have been rejected", cluster)
+	}
+}
+
+func TestLocksPrefix(t *testing.T) {
+	require.Equal(t, "cilium/state/identities/v1/locks", LocksPrefix(IdentitiesPrefix))
+}
@@ -0,0 +1,207 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"context"
+	"iter"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+)
+
+// WithReadCache wraps the given client serving Get and ListPrefix requests
+// for keys under the declared prefixes from an in-memory store, populated by
+// a background watch and invalidated by the received events. This cuts the
+// read traffic towards the kvstore for consumers repeatedly retrieving the
+// same keys (e.g., identities and ipcache entries).
+//
+// Cached reads are eventually consistent, as the watch events are applied
+// asynchronously, and the returned values carry no ModRevision or LeaseID
+// information. Consumers requiring linearizable reads should use the locked
+// variants (i.e., GetIfLocked and ListPrefixIfLocked), which always bypass
+// the cache, as do all reads performed before the initial listing of the
+// corresponding prefix has completed.
+func WithReadCache(logger *slog.Logger, client Client, prefixes ...string) Client {
+	ctx, cancel := context.WithCancel(context.Background())
+	rc := &readCacheClient{
+		Client:   client,
+		logger:   logger,
+		prefixes: prefixes,
+		cancel:   cancel,
+		entries:  make(map[string][]byte),
+		ready:    make(map[string]bool),
+	}
+
+	for _, prefix := range prefixes {
+		go rc.sync(ctx, prefix)
+	}
+	return rc
+}
+
+type readCacheClient struct {
+	Client
+
+	logger *slog.Logger
+
+	// prefixes is the list of key prefixes served from the cache.
+	prefixes []string
+
+	// cancel terminates the background watchers upon Close().
+	cancel context.CancelFunc
+
+	// mu protects the fields below.
+	mu lock.RWMutex
+
+	// entries maps the cached keys to their values.
+	entries map[string][]byte
+
+	// ready tracks the prefixes whose initial listing has completed, and
+	// which can hence be served from the cache.
+	ready map[string]bool
+}
+
+// sync populates the cache for the given prefix from the watch events.
+func (rc *readCacheClient) sync(ctx context.Context, prefix string) {
+	rc.logger.Info("Starting kvstore read cache", fieldPrefix, prefix)
+
+	for event := range rc.Client.ListAndWatch(ctx, prefix) {
+		switch event.Typ {
+		case EventTypeListDone:
+			rc.mu.Lock()
+			rc.ready[prefix] = true
+			rc.mu.Unlock()
+
+		case EventTypeCreate, EventTypeModify:
+			rc.mu.Lock()
+			rc.entries[event.Key] = event.Value
+			rc.mu.Unlock()
+
+		case EventTypeDelete:
+			rc.mu.Lock()
+			delete(rc.entries, event.Key)
+			rc.mu.Unlock()
+
+		case EventTypeDrain:
+			// The watcher is terminating: drop the cached entries, so that
+			// subsequent reads fall through to the backend.
+			rc.mu.Lock()
+			rc.ready[prefix] = false
+			for key := range rc.entries {
+				if strings.HasPrefix(key, prefix) {
+					delete(rc.entries, key)
+				}
+			}
+			rc.mu.Unlock()
+		}
+	}
+
+	rc.logger.Info("Stopped kvstore read cache",
+		fieldPrefix, prefix,
+		logfields.Error, context.Cause(ctx),
+	)
+}
+
+// cached returns whether keys matched by the given predicate can currently be
+// served from the cache.
+func (rc *readCacheClient) cached(matches func(prefix string) bool) bool {
+	for _, prefix := range rc.prefixes {
+		if matches(prefix) && rc.ready[prefix] {
+			return true
+		}
+	}
+	return false
+}
+
+func (rc *readCacheClient) Get(ctx context.Context, key string) ([]byte, error) {
+	rc.mu.RLock()
+	if rc.cached(func(prefix string) bool { return strings.HasPrefix(key, prefix) }) {
+		// Return a copy, so that the caller cannot corrupt the cached value.
+		value := slices.Clone(rc.entries[key])
+		rc.mu.RUnlock()
+		return value, nil
+	}
+	rc.mu.RUnlock()
+
+	return rc.Client.Get(ctx, key)
+}
+
+func (rc *readCacheClient) ListPrefix(ctx context.Context, prefix string) (KeyValuePairs, error) {
+	rc.mu.RLock()
+	if rc.cached(func(cached string) bool { return strings.HasPrefix(prefix, cached) }) {
+		pairs := make(KeyValuePairs)
+		for key, value := range rc.entries {
+			if strings.HasPrefix(key, prefix) {
+				pairs[key] = Value{Data: slices.Clone(value)}
+			}
+		}
+		rc.mu.RUnlock()
+		return pairs, nil
+	}
+	rc.mu.RUnlock()
+
+	return rc.Client.ListPrefix(ctx, prefix)
+}
+
+func (rc *readCacheClient) ListPrefixFunc(ctx context.Context, prefix string, fn func(key string, value Value) bool) error {
+	rc.mu.RLock()
+	cached := rc.cached(func(cached string) bool { return strings.HasPrefix(prefix, cached) })
+	rc.mu.RUnlock()
+
+	if !cached {
+		return rc.Client.ListPrefixFunc(ctx, prefix, fn)
+	}
+
+	// Iterate over a snapshot, so that the callback is not invoked with the
+	// lock held.
+	pairs, err := rc.ListPrefix(ctx, prefix)
+	if err != nil {
+		return err
+	}
+
+	for key, value := range pairs {
+		if !fn(key, value) {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (rc *readCacheClient) ListPrefixPaginated(ctx context.Context, prefix string, pageSize int) (iter.Seq2[string, Value], <-chan error) {
+	rc.mu.RLock()
+	cached := rc.cached(func(cached string) bool { return strings.HasPrefix(prefix, cached) })
+	rc.mu.RUnlock()
+
+	if !cached {
+		return rc.Client.ListPrefixPaginated(ctx, prefix, pageSize)
+	}
+
+	errs := make(chan error, 1)
+	return func(yield func(string, Value) bool) {
+		defer close(errs)
+
+		// Iterate over a snapshot, so that the caller does not run with the
+		// lock held.
+		pairs, err := rc.ListPrefix(ctx, prefix)
+		if err != nil {
+			errs <- err
+			return
+		}
+
+		for key, value := range pairs {
+			if !yield(key, value) {
+				return
+			}
+		}
+	}, errs
+}
+
+// Close terminates the background watchers and closes the wrapped client.
+func (rc *readCacheClient) Close() {
+	rc.cancel()
+	rc.Client.Close()
+}
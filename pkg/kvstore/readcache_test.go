@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cilium/hive/hivetest"
+	"github.com/cilium/statedb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCache(t *testing.T) {
+	ctx := context.Background()
+	backend := NewInMemoryClient(statedb.New(), "__local__")
+	require.NoError(t, backend.Update(ctx, "cilium/state/cached/v1/key1", []byte("value1"), false))
+
+	client := WithReadCache(hivetest.Logger(t), backend, "cilium/state/cached/v1")
+	defer client.Close()
+
+	// Wait for the initial listing to complete, after which reads are served
+	// from the cache.
+	require.EventuallyWithT(t, func(c *assert.CollectT) {
+		rc := client.(*readCacheClient)
+		rc.mu.RLock()
+		defer rc.mu.RUnlock()
+		require.True(c, rc.ready["cilium/state/cached/v1"])
+	}, time.Second, 10*time.Millisecond)
+
+	value, err := client.Get(ctx, "cilium/state/cached/v1/key1")
+	require.NoError(t, err, "Get should succeed")
+	require.Equal(t, []byte("value1"), value)
+
+	// Updates are reflected in the cache through the watch events.
+	require.NoError(t, client.Update(ctx, "cilium/state/cached/v1/key2", []byte("value2"), false))
+	require.EventuallyWithT(t, func(c *assert.CollectT) {
+		value, err := client.Get(ctx, "cilium/state/cached/v1/key2")
+		require.NoError(c, err)
+		require.Equal(c, []byte("value2"), value)
+	}, time.Second, 10*time.Millisecond)
+
+	pairs, err := client.ListPrefix(ctx, "cilium/state/cached/v1")
+	require.NoError(t, err, "ListPrefix should succeed")
+	require.Len(t, pairs, 2)
+	require.Equal(t, []byte("value1"), pairs["cilium/state/cached/v1/key1"].Data)
+
+	// Deletions invalidate the corresponding entries.
+	require.NoError(t, client.Delete(ctx, "cilium/state/cached/v1/key1"))
+	require.EventuallyWithT(t, func(c *assert.CollectT) {
+		value, err := client.Get(ctx, "cilium/state/cached/v1/key1")
+		require.NoError(c, err)
+		require.Empty(c, value)
+	}, time.Second, 10*time.Millisecond)
+
+	// Keys outside the cached prefixes fall through to the backend.
+	require.NoError(t, backend.Update(ctx, "cilium/state/other/v1/key", []byte("other"), false))
+	value, err = client.Get(ctx, "cilium/state/other/v1/key")
+	require.NoError(t, err, "Get should succeed")
+	require.Equal(t, []byte("other"), value)
+
+	// Mutating a returned value must not corrupt the cache.
+	value, err = client.Get(ctx, "cilium/state/cached/v1/key2")
+	require.NoError(t, err, "Get should succeed")
+	value[0] = 'X'
+	value, err = client.Get(ctx, "cilium/state/cached/v1/key2")
+	require.NoError(t, err, "Get should succeed")
+	require.Equal(t, []byte("value2"), value)
+}
@@ -0,0 +1,145 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// RestrictClient wraps the given client with a write allow-list: write
+// operations targeting a key outside the declared prefixes fail with an
+// error identifying the component. This is a client-side guard against
+// programming errors which would otherwise corrupt the keyspace of another
+// subsystem; it is not a security boundary, as read and watch operations
+// are not restricted.
+func RestrictClient(client Client, component string, writablePrefixes ...string) Client {
+	return &restrictedClient{
+		Client:           client,
+		component:        component,
+		writablePrefixes: writablePrefixes,
+	}
+}
+
+type restrictedClient struct {
+	Client
+
+	// component identifies the owner of the client in error messages.
+	component string
+
+	// writablePrefixes is the allow-list of key prefixes the component may
+	// write to. Keys outside these prefixes can be read but not modified.
+	writablePrefixes []string
+}
+
+// checkWrite returns an error if the key does not fall under any of the
+// writable prefixes.
+func (rc *restrictedClient) checkWrite(key string) error {
+	for _, prefix := range rc.writablePrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return nil
+		}
+	}
+	return fmt.Errorf("component %q is not allowed to write key %q (writable prefixes: %s)",
+		rc.component, key, strings.Join(rc.writablePrefixes, ", "))
+}
+
+func (rc *restrictedClient) LockPath(ctx context.Context, path string) (KVLocker, error) {
+	if err := rc.checkWrite(path); err != nil {
+		return nil, err
+	}
+	return rc.Client.LockPath(ctx, path)
+}
+
+func (rc *restrictedClient) Delete(ctx context.Context, key string) error {
+	if err := rc.checkWrite(key); err != nil {
+		return err
+	}
+	return rc.Client.Delete(ctx, key)
+}
+
+func (rc *restrictedClient) DeleteIfLocked(ctx context.Context, key string, lock KVLocker) error {
+	if err := rc.checkWrite(key); err != nil {
+		return err
+	}
+	return rc.Client.DeleteIfLocked(ctx, key, lock)
+}
+
+func (rc *restrictedClient) DeletePrefix(ctx context.Context, path string) error {
+	if err := rc.checkWrite(path); err != nil {
+		return err
+	}
+	return rc.Client.DeletePrefix(ctx, path)
+}
+
+func (rc *restrictedClient) DeletePrefixIfLocked(ctx context.Context, path string, lock KVLocker) error {
+	if err := rc.checkWrite(path); err != nil {
+		return err
+	}
+	return rc.Client.DeletePrefixIfLocked(ctx, path, lock)
+}
+
+func (rc *restrictedClient) Update(ctx context.Context, key string, value []byte, lease bool) error {
+	if err := rc.checkWrite(key); err != nil {
+		return err
+	}
+	return rc.Client.Update(ctx, key, value, lease)
+}
+
+func (rc *restrictedClient) UpdateIfLocked(ctx context.Context, key string, value []byte, lease bool, lock KVLocker) error {
+	if err := rc.checkWrite(key); err != nil {
+		return err
+	}
+	return rc.Client.UpdateIfLocked(ctx, key, value, lease, lock)
+}
+
+func (rc *restrictedClient) UpdateIfDifferent(ctx context.Context, key string, value []byte, lease bool) (bool, error) {
+	if err := rc.checkWrite(key); err != nil {
+		return false, err
+	}
+	return rc.Client.UpdateIfDifferent(ctx, key, value, lease)
+}
+
+func (rc *restrictedClient) UpdateIfDifferentIfLocked(ctx context.Context, key string, value []byte, lease bool, lock KVLocker) (bool, error) {
+	if err := rc.checkWrite(key); err != nil {
+		return false, err
+	}
+	return rc.Client.UpdateIfDifferentIfLocked(ctx, key, value, lease, lock)
+}
+
+func (rc *restrictedClient) UpdateBatch(ctx context.Context, pairs []KeyValue, lease bool) error {
+	for _, pair := range pairs {
+		if err := rc.checkWrite(pair.Key); err != nil {
+			return err
+		}
+	}
+	return rc.Client.UpdateBatch(ctx, pairs, lease)
+}
+
+func (rc *restrictedClient) CreateOnly(ctx context.Context, key string, value []byte, lease bool) (bool, error) {
+	if err := rc.checkWrite(key); err != nil {
+		return false, err
+	}
+	return rc.Client.CreateOnly(ctx, key, value, lease)
+}
+
+func (rc *restrictedClient) CreateOnlyIfLocked(ctx context.Context, key string, value []byte, lease bool, lock KVLocker) (bool, error) {
+	if err := rc.checkWrite(key); err != nil {
+		return false, err
+	}
+	return rc.Client.CreateOnlyIfLocked(ctx, key, value, lease, lock)
+}
+
+func (rc *restrictedClient) Txn(ctx context.Context, conditions []TxnCondition, thenOps, elseOps []TxnOp) (bool, error) {
+	// Both branches are checked upfront, as which one applies is only known
+	// once the transaction has been committed.
+	for _, op := range slices.Concat(thenOps, elseOps) {
+		if err := rc.checkWrite(op.Key); err != nil {
+			return false, err
+		}
+	}
+	return rc.Client.Txn(ctx, conditions, thenOps, elseOps)
+}
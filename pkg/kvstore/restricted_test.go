@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"testing"
+
+	"github.com/cilium/statedb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestrictClient(t *testing.T) {
+	db := statedb.New()
+	client := RestrictClient(NewInMemoryClient(db, "__local__"),
+		"test-component", "cilium/state/nodes/", "cilium/state/identities/")
+
+	ctx := t.Context()
+
+	// Writes inside the declared prefixes are passed through.
+	require.NoError(t, client.Update(ctx, "cilium/state/nodes/v1/foo", []byte("value"), false))
+	created, err := client.CreateOnly(ctx, "cilium/state/identities/v1/1", []byte("value"), false)
+	require.NoError(t, err)
+	assert.True(t, created)
+	require.NoError(t, client.Delete(ctx, "cilium/state/nodes/v1/foo"))
+
+	// Writes outside the prefixes are rejected before reaching the backend.
+	err = client.Update(ctx, "cilium/state/ip/v1/default/10.0.0.1", []byte("value"), false)
+	assert.ErrorContains(t, err, "test-component")
+	assert.ErrorContains(t, err, "not allowed to write")
+
+	_, err = client.CreateOnly(ctx, "cilium/state/ip/v1/default/10.0.0.1", []byte("value"), false)
+	assert.Error(t, err)
+	assert.Error(t, client.Delete(ctx, "cilium/state/ip/v1/default/10.0.0.1"))
+	assert.Error(t, client.DeletePrefix(ctx, "cilium/state/ip/"))
+	_, err = client.UpdateIfDifferent(ctx, "cilium/state/ip/v1/default/10.0.0.1", []byte("value"), false)
+	assert.Error(t, err)
+
+	// Reads are not restricted.
+	value, err := client.Get(ctx, "cilium/state/identities/v1/1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+	pairs, err := client.ListPrefix(ctx, "cilium/state/ip/")
+	require.NoError(t, err)
+	assert.Empty(t, pairs)
+}
@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+
+	"github.com/cilium/cilium/pkg/time"
+)
+
+// SelfTestPrefix is the dedicated prefix under which the client self-test
+// performs its round-trips. Keys created below it carry a random suffix, so
+// that concurrent self-tests do not interfere with each other.
+const SelfTestPrefix = "cilium/selftest"
+
+// selfTestWatchTimeout bounds how long the self-test waits for the watch
+// round-trip to report the previously written key.
+const selfTestWatchTimeout = 10 * time.Second
+
+// SelfTestStep is the outcome of a single self-test operation.
+type SelfTestStep struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// SelfTestReport is the structured result of a client self-test.
+type SelfTestReport struct {
+	Success bool           `json:"success"`
+	Steps   []SelfTestStep `json:"steps"`
+}
+
+// SelfTest performs write, read, watch and lock round-trips against a
+// dedicated test prefix, measuring the duration of each operation, and
+// finally removes the keys it created. All steps are attempted even if an
+// earlier one failed, so that the report shows which operations are broken,
+// which is useful when troubleshooting an installation.
+func SelfTest(ctx context.Context, client BackendOperations) SelfTestReport {
+	report := SelfTestReport{Success: true}
+
+	prefix := fmt.Sprintf("%s/%016x", SelfTestPrefix, rand.Uint64())
+	key := prefix + "/key"
+	value := []byte(strconv.FormatInt(time.Now().UnixNano(), 10))
+
+	step := func(name string, fn func() error) {
+		start := time.Now()
+		err := fn()
+		s := SelfTestStep{Name: name, Duration: time.Since(start)}
+		if err != nil {
+			s.Error = err.Error()
+			report.Success = false
+		}
+		report.Steps = append(report.Steps, s)
+	}
+
+	step("write", func() error {
+		return client.Update(ctx, key, value, false)
+	})
+
+	step("read", func() error {
+		v, err := client.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(v, value) {
+			return fmt.Errorf("read back unexpected value %q", v)
+		}
+		return nil
+	})
+
+	step("watch", func() error {
+		wctx, cancel := context.WithTimeout(ctx, selfTestWatchTimeout)
+		defer cancel()
+
+		// The initial listing reports the previously written key as created.
+		for event := range client.ListAndWatch(wctx, prefix) {
+			if event.Typ == EventTypeCreate && event.Key == key {
+				return nil
+			}
+		}
+		return fmt.Errorf("watch did not report key %q: %w", key, wctx.Err())
+	})
+
+	step("lock", func() error {
+		locker, err := client.LockPath(ctx, prefix+"/lock")
+		if err != nil {
+			return err
+		}
+		return locker.Unlock(ctx)
+	})
+
+	step("delete", func() error {
+		return client.DeletePrefix(ctx, prefix)
+	})
+
+	return report
+}
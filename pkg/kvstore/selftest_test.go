@@ -0,0 +1,32 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cilium/statedb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelfTest(t *testing.T) {
+	client := NewInMemoryClient(statedb.New(), "__local__")
+	defer client.Close()
+
+	report := SelfTest(context.Background(), client)
+	require.True(t, report.Success)
+
+	var names []string
+	for _, step := range report.Steps {
+		require.Emptyf(t, step.Error, "step %q failed", step.Name)
+		names = append(names, step.Name)
+	}
+	require.Equal(t, []string{"write", "read", "watch", "lock", "delete"}, names)
+
+	// The self-test cleans up after itself.
+	kvs, err := client.ListPrefix(context.Background(), SelfTestPrefix)
+	require.NoError(t, err)
+	require.Empty(t, kvs)
+}
@@ -175,6 +175,13 @@ func (rws *restartableWatchStore) Watch(ctx context.Context, backend WatchStoreB
 			continue
 		}
 
+		if event.Typ == kvstore.EventTypeDrain {
+			rws.log.Debug("Watcher drained",
+				logfields.Reason, string(event.Value),
+			)
+			continue
+		}
+
 		key := strings.TrimPrefix(event.Key, prefix)
 		rws.log.Debug(
 			"Received event from kvstore",
@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+// TxnOpType is the type of a mutation part of a transaction.
+type TxnOpType string
+
+const (
+	// TxnOpPut creates or updates a key.
+	TxnOpPut TxnOpType = "put"
+
+	// TxnOpDelete deletes a key.
+	TxnOpDelete TxnOpType = "delete"
+)
+
+// TxnOp describes a single mutation part of a transaction.
+type TxnOp struct {
+	// Typ is the type of the operation.
+	Typ TxnOpType
+
+	// Key is the key the operation applies to.
+	Key string
+
+	// Value is the value to associate with the key. Put operations only.
+	Value []byte
+
+	// Lease attaches the client lease to the key. Put operations only.
+	Lease bool
+}
+
+// TxnConditionType is the type of a guard evaluated as part of a transaction.
+type TxnConditionType string
+
+const (
+	// TxnKeyExists asserts that the key exists.
+	TxnKeyExists TxnConditionType = "exists"
+
+	// TxnKeyAbsent asserts that the key does not exist.
+	TxnKeyAbsent TxnConditionType = "absent"
+
+	// TxnValueEquals asserts that the key exists with the given value.
+	TxnValueEquals TxnConditionType = "value-equals"
+)
+
+// TxnCondition expresses a guard which must hold for a transaction to apply
+// its then branch.
+type TxnCondition struct {
+	// Typ is the type of the condition.
+	Typ TxnConditionType
+
+	// Key is the key the condition applies to.
+	Key string
+
+	// Value is the expected value. TxnValueEquals conditions only.
+	Value []byte
+}
@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"testing"
+
+	"github.com/cilium/statedb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryTxn(t *testing.T) {
+	db := statedb.New()
+	client := NewInMemoryClient(db, "__local__")
+
+	ctx := t.Context()
+	key1, key2, key3 := "txn/key1", "txn/key2", "txn/key3"
+
+	// Without conditions, the then branch is applied atomically.
+	succeeded, err := client.Txn(ctx, nil, []TxnOp{
+		{Typ: TxnOpPut, Key: key1, Value: []byte("val1")},
+		{Typ: TxnOpPut, Key: key2, Value: []byte("val2")},
+	}, nil)
+	require.NoError(t, err)
+	assert.True(t, succeeded)
+
+	value, err := client.Get(ctx, key1)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("val1"), value)
+
+	// A failing condition applies the else branch instead.
+	succeeded, err = client.Txn(ctx, []TxnCondition{
+		{Typ: TxnKeyAbsent, Key: key1},
+	}, []TxnOp{
+		{Typ: TxnOpPut, Key: key3, Value: []byte("then")},
+	}, []TxnOp{
+		{Typ: TxnOpPut, Key: key3, Value: []byte("else")},
+		{Typ: TxnOpDelete, Key: key2},
+	})
+	require.NoError(t, err)
+	assert.False(t, succeeded)
+
+	value, err = client.Get(ctx, key3)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("else"), value)
+	value, err = client.Get(ctx, key2)
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	// All conditions must hold for the then branch to be applied.
+	succeeded, err = client.Txn(ctx, []TxnCondition{
+		{Typ: TxnKeyExists, Key: key1},
+		{Typ: TxnValueEquals, Key: key3, Value: []byte("else")},
+	}, []TxnOp{
+		{Typ: TxnOpDelete, Key: key3},
+	}, nil)
+	require.NoError(t, err)
+	assert.True(t, succeeded)
+
+	value, err = client.Get(ctx, key3)
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	// Malformed conditions and operations are rejected.
+	_, err = client.Txn(ctx, []TxnCondition{{Typ: "bogus", Key: key1}}, nil, nil)
+	assert.Error(t, err)
+	_, err = client.Txn(ctx, nil, []TxnOp{{Typ: "bogus", Key: key1}}, nil)
+	assert.Error(t, err)
+}
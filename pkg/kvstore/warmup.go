@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/time"
+)
+
+// watchWarmupBackend is the subset of the kvstore client required by the
+// watch warm-up coordinator.
+type watchWarmupBackend interface {
+	ListAndWatch(ctx context.Context, prefix string) EventChan
+}
+
+// WatchWarmup coordinates the initial synchronization of the well-known
+// prefixes watched at startup. Consumers register the prefixes they intend
+// to watch before Start is invoked; Start then opens all watches
+// concurrently, and the events of each prefix are forwarded, unchanged, to
+// the channel handed out at registration time.
+//
+// The warm-up observes the first ListDone event of every prefix to record
+// how long the initial listing took, replacing ad-hoc tracking by each
+// consumer, and closes the channel returned by SyncedChannel once all
+// registered prefixes completed their initial listing. That channel can be
+// plugged into the BootstrapComplete option of the client, so that the
+// bootstrap rate limit is lifted as soon as the warm-up is over.
+type WatchWarmup struct {
+	logger  *slog.Logger
+	backend watchWarmupBackend
+
+	mu      lock.Mutex
+	started bool
+	watches map[string]chan KeyValueEvent
+	pending int
+	synced  chan struct{}
+}
+
+// NewWatchWarmup returns a warm-up coordinator. The backend through which the
+// prefixes are watched is provided at Start time, so that the coordinator can
+// be constructed, and its synced channel plugged into the client options,
+// before the client itself exists.
+func NewWatchWarmup(logger *slog.Logger) *WatchWarmup {
+	return &WatchWarmup{
+		logger:  logger,
+		watches: make(map[string]chan KeyValueEvent),
+		synced:  make(chan struct{}),
+	}
+}
+
+// Register adds the given prefix to the set of watches opened by Start, and
+// returns the channel on which its events will be delivered. It must not be
+// called once Start has been invoked, nor twice for the same prefix.
+func (w *WatchWarmup) Register(prefix string) EventChan {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.started {
+		logging.Panic(w.logger, "Cannot call Register after the watch warm-up has started")
+	}
+	if _, ok := w.watches[prefix]; ok {
+		logging.Panic(w.logger, "Prefix already registered in the watch warm-up", logfields.Prefix, prefix)
+	}
+
+	events := make(chan KeyValueEvent)
+	w.watches[prefix] = events
+	w.pending++
+	return events
+}
+
+// SyncedChannel returns the channel closed once all registered prefixes have
+// received their initial ListDone event.
+func (w *WatchWarmup) SyncedChannel() <-chan struct{} {
+	return w.synced
+}
+
+// Start opens the watches for all registered prefixes concurrently through
+// the given backend. The watches are stopped, and the consumer channels
+// closed, when the context is cancelled.
+func (w *WatchWarmup) Start(ctx context.Context, backend watchWarmupBackend) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.started {
+		logging.Panic(w.logger, "Cannot start the watch warm-up twice")
+	}
+	w.started = true
+	w.backend = backend
+
+	// An empty registration set is trivially synced.
+	if w.pending == 0 {
+		close(w.synced)
+	}
+
+	for prefix, events := range w.watches {
+		go w.watch(ctx, prefix, events)
+	}
+}
+
+func (w *WatchWarmup) watch(ctx context.Context, prefix string, out chan<- KeyValueEvent) {
+	defer close(out)
+
+	start := time.Now()
+	synced := false
+	for event := range w.backend.ListAndWatch(ctx, prefix) {
+		if !synced && event.Typ == EventTypeListDone {
+			synced = true
+			duration := time.Since(start)
+			recordInitialSyncDuration(prefix, duration)
+			w.logger.Info("Initial listing of kvstore prefix complete",
+				logfields.Prefix, prefix,
+				logfields.Duration, duration,
+			)
+			w.markSynced()
+		}
+
+		select {
+		case out <- event:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (w *WatchWarmup) markSynced() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.pending--
+	if w.pending == 0 {
+		close(w.synced)
+	}
+}
@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cilium/hive/hivetest"
+	"github.com/cilium/statedb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cilium/cilium/pkg/time"
+)
+
+func TestWatchWarmup(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := NewInMemoryClient(statedb.New(), "__local__")
+	defer client.Close()
+
+	require.NoError(t, client.Update(ctx, "cilium/state/nodes/v1/foo", []byte("n1"), false))
+
+	w := NewWatchWarmup(hivetest.Logger(t))
+	nodes := w.Register("cilium/state/nodes/v1")
+	services := w.Register("cilium/state/services/v1")
+
+	select {
+	case <-w.SyncedChannel():
+		t.Fatal("synced channel closed before the warm-up started")
+	default:
+	}
+
+	w.Start(ctx, client)
+
+	// The pre-existing key is delivered, followed by the ListDone event.
+	ev := <-nodes
+	require.Equal(t, EventTypeCreate, ev.Typ)
+	require.Equal(t, "cilium/state/nodes/v1/foo", ev.Key)
+	ev = <-nodes
+	require.Equal(t, EventTypeListDone, ev.Typ)
+
+	ev = <-services
+	require.Equal(t, EventTypeListDone, ev.Typ)
+
+	select {
+	case <-w.SyncedChannel():
+	case <-time.After(time.Second):
+		t.Fatal("synced channel not closed after all prefixes completed the initial listing")
+	}
+
+	// Events keep flowing after the warm-up completed.
+	require.NoError(t, client.Update(ctx, "cilium/state/services/v1/bar", []byte("s1"), false))
+	ev = <-services
+	require.Equal(t, EventTypeCreate, ev.Typ)
+	require.Equal(t, "cilium/state/services/v1/bar", ev.Key)
+
+	// Cancelling the context stops the watches and closes the channels.
+	cancel()
+	for range nodes {
+	}
+	for range services {
+	}
+}
+
+func TestWatchWarmupNoPrefixes(t *testing.T) {
+	client := NewInMemoryClient(statedb.New(), "__local__")
+	defer client.Close()
+
+	// A warm-up without registered prefixes is trivially synced.
+	w := NewWatchWarmup(hivetest.Logger(t))
+	w.Start(context.Background(), client)
+
+	select {
+	case <-w.SyncedChannel():
+	case <-time.After(time.Second):
+		t.Fatal("synced channel not closed without registered prefixes")
+	}
+}
@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"context"
+
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/time"
+)
+
+// watcherWeights assigns the relative share of limiter admissions granted to
+// each watched prefix scope. Scopes not listed have weight 1. The heartbeat
+// watcher is prioritized, as missing its updates eventually leads the client
+// to declare heartbeat failure.
+var watcherWeights = map[string]uint{
+	GetScopeFromKey(HeartbeatPath): 4,
+}
+
+func watcherWeight(scope string) uint {
+	if w, ok := watcherWeights[scope]; ok {
+		return w
+	}
+	return 1
+}
+
+// watchFairness grants the watchers sharing the client's rate limiter access
+// to it in weighted round-robin order, FIFO within each prefix scope. Without
+// it, a watcher aggressively relisting (e.g. looping on compaction errors)
+// can keep re-entering the limiter and crowd out the other watchers.
+type watchFairness struct {
+	mu lock.Mutex
+
+	// queues contains the waiters per scope, granted in FIFO order.
+	queues map[string][]*fairWaiter
+	// order is the round-robin order of the known scopes; idx points at the
+	// scope currently being served.
+	order []string
+	idx   int
+	// credits is the number of grants the current scope may still receive
+	// before the round moves on, replenished to the scope's weight.
+	credits map[string]uint
+	// busy indicates that a grant is outstanding, i.e. a watcher currently
+	// waits on, or holds, the rate limiter.
+	busy bool
+}
+
+type fairWaiter struct {
+	granted chan struct{}
+}
+
+func newWatchFairness() *watchFairness {
+	return &watchFairness{
+		queues:  make(map[string][]*fairWaiter),
+		credits: make(map[string]uint),
+	}
+}
+
+// wait blocks until the watcher of the given scope is granted its turn, or
+// the context is cancelled. Once granted, the caller must invoke done() after
+// its rate limiter interaction completed.
+func (f *watchFairness) wait(ctx context.Context, scope string) error {
+	w := &fairWaiter{granted: make(chan struct{})}
+	start := time.Now()
+
+	f.mu.Lock()
+	if _, known := f.queues[scope]; !known {
+		f.order = append(f.order, scope)
+		f.credits[scope] = watcherWeight(scope)
+	}
+	f.queues[scope] = append(f.queues[scope], w)
+	f.dispatchLocked()
+	f.mu.Unlock()
+
+	select {
+	case <-w.granted:
+		recordWatcherQueueDuration(scope, time.Since(start))
+		return nil
+	case <-ctx.Done():
+		f.abandon(scope, w)
+		return ctx.Err()
+	}
+}
+
+// done releases the outstanding grant, passing the turn to the next waiter.
+func (f *watchFairness) done() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.busy = false
+	f.dispatchLocked()
+}
+
+// abandon removes a waiter whose context was cancelled. If the waiter was
+// granted its turn concurrently, the grant is released instead.
+func (f *watchFairness) abandon(scope string, w *fairWaiter) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i, waiting := range f.queues[scope] {
+		if waiting == w {
+			f.queues[scope] = append(f.queues[scope][:i], f.queues[scope][i+1:]...)
+			return
+		}
+	}
+
+	// Not queued anymore: the waiter was already granted its turn.
+	f.busy = false
+	f.dispatchLocked()
+}
+
+func (f *watchFairness) dispatchLocked() {
+	if f.busy || len(f.order) == 0 {
+		return
+	}
+
+	// Visit each scope at most twice: once to consume its remaining credits,
+	// and once more after they have been replenished, so that the loop
+	// terminates when no waiters are queued.
+	for range 2 * len(f.order) {
+		scope := f.order[f.idx]
+		if len(f.queues[scope]) > 0 && f.credits[scope] > 0 {
+			w := f.queues[scope][0]
+			f.queues[scope] = f.queues[scope][1:]
+			f.credits[scope]--
+			f.busy = true
+			close(w.granted)
+			return
+		}
+
+		// The scope exhausted its credits or has no waiters: replenish and
+		// move on to the next one.
+		f.credits[scope] = watcherWeight(scope)
+		f.idx = (f.idx + 1) % len(f.order)
+	}
+}
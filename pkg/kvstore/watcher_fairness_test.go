@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package kvstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cilium/cilium/pkg/time"
+)
+
+func TestWatchFairness(t *testing.T) {
+	f := newWatchFairness()
+	ctx := context.Background()
+	hbScope := GetScopeFromKey(HeartbeatPath)
+
+	grants := make(chan string, 16)
+	enqueue := func(scope string) {
+		go func() {
+			if err := f.wait(ctx, scope); err == nil {
+				grants <- scope
+			}
+		}()
+	}
+
+	next := func() string {
+		t.Helper()
+		select {
+		case scope := <-grants:
+			return scope
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for a grant")
+			return ""
+		}
+	}
+
+	queued := func(scope string, n int) {
+		t.Helper()
+		require.Eventually(t, func() bool {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			return len(f.queues[scope]) == n
+		}, time.Second, time.Millisecond)
+	}
+
+	// The first waiter is granted immediately and holds the turn until done.
+	enqueue("noisy/v1")
+	require.Equal(t, "noisy/v1", next())
+
+	// Queue up an aggressive relister and the heartbeat watcher behind it.
+	enqueue("noisy/v1")
+	queued("noisy/v1", 1)
+	enqueue("noisy/v1")
+	queued("noisy/v1", 2)
+	enqueue(hbScope)
+	queued(hbScope, 1)
+
+	// Despite the noisy watchers having queued first, the round-robin moves
+	// on to the heartbeat scope once the noisy one spent its credit.
+	f.done()
+	require.Equal(t, hbScope, next())
+
+	// The remaining noisy waiters are then served in FIFO order.
+	f.done()
+	require.Equal(t, "noisy/v1", next())
+	f.done()
+	require.Equal(t, "noisy/v1", next())
+
+	// A queued waiter whose context is cancelled is abandoned without
+	// consuming a turn.
+	cancelled, cancel := context.WithCancel(ctx)
+	errs := make(chan error, 1)
+	go func() {
+		errs <- f.wait(cancelled, "noisy/v1")
+	}()
+	queued("noisy/v1", 1)
+	cancel()
+	require.ErrorIs(t, <-errs, context.Canceled)
+
+	f.done()
+
+	// The turnstile is idle again: a new waiter is granted immediately.
+	enqueue("other/v1")
+	require.Equal(t, "other/v1", next())
+	f.done()
+}
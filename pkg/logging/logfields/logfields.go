@@ -555,6 +555,9 @@ const (
 	// Resource is a resource
 	Resource = "resource"
 
+	// Generation is the generation or revision of a resource
+	Generation = "generation"
+
 	// ConflictingResource is a resource that conflicts with 'Resource'
 	ConflictingResource = "conflictingResource"
 
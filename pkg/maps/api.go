@@ -20,6 +20,8 @@ import (
 	"github.com/cilium/cilium/pkg/ebpf"
 	"github.com/cilium/cilium/pkg/logging/logfields"
 	"github.com/cilium/cilium/pkg/metrics"
+	"github.com/cilium/cilium/pkg/rate"
+	"github.com/cilium/cilium/pkg/time"
 )
 
 type eventsDumper interface {
@@ -131,6 +133,60 @@ func (h *getMapNameEventsHandler) Handle(params restapi.GetMapNameEventsParams)
 	})
 }
 
+// streamDumpPageInterval is the minimum interval between the pages emitted by
+// the streaming map dump API, bounding the rate at which entries are read
+// from the kernel and written to the consumer.
+const streamDumpPageInterval = 10 * time.Millisecond
+
+type getMapNameDumpHandler struct {
+	logger *slog.Logger
+}
+
+func (h *getMapNameDumpHandler) Handle(params restapi.GetMapNameDumpParams) middleware.Responder {
+	m := bpf.GetMap(h.logger, params.Name)
+	if m == nil {
+		return restapi.NewGetMapNameDumpNotFound()
+	}
+	if !m.SupportsStreamDump() {
+		return restapi.NewGetMapNameDumpNotImplemented()
+	}
+
+	opts := bpf.StreamDumpOptions{}
+	if params.PageSize != nil {
+		opts.PageSize = int(*params.PageSize)
+	}
+
+	return middleware.ResponderFunc(func(w http.ResponseWriter, _ runtime.Producer) {
+		flusher, err := getFlusher(w)
+		if err != nil {
+			h.logger.Error("BUG: could not get flusher from ResponseWriter", logfields.Error, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		opts.Limiter = rate.NewLimiter(streamDumpPageInterval, 1)
+		defer opts.Limiter.Stop()
+
+		// Errors encountered mid-stream cannot be reported to the client
+		// through the status code anymore, only logged and the response
+		// truncated.
+		count, err := m.StreamDump(params.HTTPRequest.Context(),
+			&flushWriter{f: flusher, w: w}, opts)
+		if err != nil {
+			h.logger.Error("api handler failed to stream map dump",
+				logfields.BPFMapName, params.Name,
+				logfields.Error, err,
+			)
+			return
+		}
+
+		h.logger.Debug("Streamed map dump",
+			logfields.BPFMapName, params.Name,
+			logfields.Entries, count,
+		)
+	})
+}
+
 type getMapNameHandler struct {
 	logger *slog.Logger
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/cilium/hive/cell"
 
 	daemonapi "github.com/cilium/cilium/api/v1/server/restapi/daemon"
+	"github.com/cilium/cilium/pkg/bpf"
 	"github.com/cilium/cilium/pkg/maps/act"
 	"github.com/cilium/cilium/pkg/maps/authmap"
 	"github.com/cilium/cilium/pkg/maps/bwmap"
@@ -32,6 +33,18 @@ var Cell = cell.Module(
 
 	cell.Provide(newMapApiHandler),
 
+	// Allows inspecting and adjusting the interval of the per-map error
+	// resolver controllers at runtime.
+	cell.Provide(mapSyncCommands),
+
+	// Exposes the error resolver backlog of all registered maps, so that
+	// stuck reconciliation (e.g. due to a full map) is alertable.
+	cell.Invoke(bpf.RegisterMapErrorBacklogCollector),
+
+	// Periodically reports the health of all registered maps, with a
+	// separate scope per map visible via "cilium-dbg status --all-health".
+	cell.Invoke(bpf.RegisterMapHealthJob),
+
 	// Provides the auth.Map which contains the authentication state between Cilium security identities.
 	authmap.Cell,
 
@@ -80,6 +93,7 @@ type mapApiHandlerOut struct {
 
 	GetMapHandler           daemonapi.GetMapHandler
 	GetMapNameHandler       daemonapi.GetMapNameHandler
+	GetMapNameDumpHandler   daemonapi.GetMapNameDumpHandler
 	GetMapNameEventsHandler daemonapi.GetMapNameEventsHandler
 }
 
@@ -87,6 +101,7 @@ func newMapApiHandler(logger *slog.Logger) mapApiHandlerOut {
 	return mapApiHandlerOut{
 		GetMapHandler:           &getMapHandler{},
 		GetMapNameHandler:       &getMapNameHandler{logger: logger},
+		GetMapNameDumpHandler:   &getMapNameDumpHandler{logger: logger},
 		GetMapNameEventsHandler: &getMapNameEventsHandler{logger: logger, mapGetter: &mapGetterImpl{logger: logger}},
 	}
 }
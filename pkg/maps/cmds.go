@@ -0,0 +1,215 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package maps
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/cilium/hive"
+	"github.com/cilium/hive/script"
+	"github.com/spf13/pflag"
+
+	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/option"
+	"github.com/cilium/cilium/pkg/time"
+)
+
+func mapSyncCommands(logger *slog.Logger) hive.ScriptCmdsOut {
+	return hive.NewScriptCmds(map[string]script.Cmd{
+		"bpf/map-sync-interval": mapSyncIntervalCommand(logger),
+		"bpf/map-event-buffer":  mapEventBufferCommand(),
+		"bpf/orphan-pins":       orphanPinsCommand(logger),
+		"bpf/map-access-audit":  mapAccessAuditCommand(),
+	})
+}
+
+// mapAccessAuditCommand implements the "bpf/map-access-audit" script command,
+// accessible via "cilium-dbg shell". It reports external processes holding
+// file descriptors to registered BPF maps, helping diagnose third-party
+// tooling inspecting or mutating Cilium maps.
+func mapAccessAuditCommand() script.Cmd {
+	return script.Command(
+		script.CmdUsage{
+			Summary: "List external processes holding file descriptors to registered BPF maps",
+			Args:    "",
+			Detail: []string{
+				"Scans /proc for processes other than the agent itself holding",
+				"file descriptors to any of the registered BPF maps, identified",
+				"by matching the map IDs reported in /proc/<pid>/fdinfo.",
+				"Processes which cannot be inspected with the agent's privileges",
+				"are silently skipped.",
+			},
+		},
+		func(s *script.State, args ...string) (script.WaitFunc, error) {
+			if len(args) != 0 {
+				return nil, script.ErrUsage
+			}
+
+			records, err := bpf.AuditMapAccess()
+			if err != nil {
+				return nil, err
+			}
+
+			return func(s *script.State) (stdout string, stderr string, err error) {
+				if len(records) == 0 {
+					return "no external map access detected\n", "", nil
+				}
+				var sb strings.Builder
+				for _, r := range records {
+					fmt.Fprintf(&sb, "pid=%d comm=%s map=%s id=%d\n", r.Pid, r.Comm, r.MapName, r.MapID)
+				}
+				return sb.String(), "", nil
+			}, nil
+		},
+	)
+}
+
+// orphanPinsCommand implements the "bpf/orphan-pins" script command,
+// accessible via "cilium-dbg shell". It allows operators to inspect, and
+// optionally clean up, map pins on bpffs which do not belong to any
+// registered map.
+func orphanPinsCommand(logger *slog.Logger) script.Cmd {
+	return script.Command(
+		script.CmdUsage{
+			Summary: "List or remove orphaned BPF map pins",
+			Args:    "",
+			Flags: func(fs *pflag.FlagSet) {
+				fs.Bool("remove", false, "Remove orphaned pins older than the grace period")
+				fs.Duration("grace-period", bpf.OrphanPinGracePeriod, "Minimum pin age required for removal")
+			},
+			Detail: []string{
+				"Scans the bpffs map pin directory for pinned maps not belonging",
+				"to any registered map, e.g. left behind by a crashed agent or by",
+				"a feature that has since been disabled. With --remove, orphaned",
+				"pins older than the grace period are unpinned.",
+			},
+		},
+		func(s *script.State, args ...string) (script.WaitFunc, error) {
+			if len(args) != 0 {
+				return nil, script.ErrUsage
+			}
+
+			remove, err := s.Flags.GetBool("remove")
+			if err != nil {
+				return nil, err
+			}
+			gracePeriod, err := s.Flags.GetDuration("grace-period")
+			if err != nil {
+				return nil, err
+			}
+
+			orphans, err := bpf.SweepOrphanMapPins(logger, gracePeriod, remove)
+			if err != nil {
+				return nil, err
+			}
+
+			return func(s *script.State) (stdout string, stderr string, err error) {
+				if len(orphans) == 0 {
+					return "no orphaned map pins\n", "", nil
+				}
+				var sb strings.Builder
+				for _, o := range orphans {
+					fmt.Fprintf(&sb, "%s type=%s age=%s", o.Path, o.Type, o.Age.Round(time.Second))
+					if o.Removed {
+						sb.WriteString(" (removed)")
+					}
+					sb.WriteByte('\n')
+				}
+				return sb.String(), "", nil
+			}, nil
+		},
+	)
+}
+
+// mapEventBufferCommand implements the "bpf/map-event-buffer" script command,
+// accessible via "cilium-dbg shell". It allows operators to enable event
+// buffering on specific maps during troubleshooting, and disable it
+// afterwards, without restarting the agent.
+func mapEventBufferCommand() script.Cmd {
+	return script.Command(
+		script.CmdUsage{
+			Summary: "Enable or disable the event buffer of BPF maps matching a pattern",
+			Args:    "pattern config",
+			Detail: []string{
+				"The pattern is a regular expression matched against the names of",
+				"the registered BPF maps. The config uses the same tuple format as",
+				"the bpf-map-event-buffers option, e.g. 'enabled_1000_1h' to buffer",
+				"up to 1000 events with a one hour TTL, or 'disabled' to turn the",
+				"buffering off again.",
+			},
+		},
+		func(s *script.State, args ...string) (script.WaitFunc, error) {
+			if len(args) != 2 {
+				return nil, script.ErrUsage
+			}
+
+			cfg, err := option.ParseEventBufferTupleString(args[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid config %q: %w", args[1], err)
+			}
+
+			names, err := bpf.ApplyEventBufferConfig(args[0], cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			return func(s *script.State) (stdout string, stderr string, err error) {
+				if len(names) == 0 {
+					return "no maps matched\n", "", nil
+				}
+				return strings.Join(names, "\n") + "\n", "", nil
+			}, nil
+		},
+	)
+}
+
+// mapSyncIntervalCommand implements the "bpf/map-sync-interval" script
+// command, accessible via "cilium-dbg shell". It allows operators to inspect
+// and adjust the interval of the per-map error resolver controllers at
+// runtime, e.g. to temporarily speed up reconciliation during incident
+// recovery without restarting the agent.
+func mapSyncIntervalCommand(logger *slog.Logger) script.Cmd {
+	return script.Command(
+		script.CmdUsage{
+			Summary: "Show or set the sync controller interval of a BPF map",
+			Args:    "map-name [interval]",
+			Detail: []string{
+				"Without an interval, shows the current error resolver controller",
+				"interval of the given map. With an interval (e.g. '500ms', '5s'),",
+				"overrides it; '0' resets the interval to the default.",
+				"",
+				"The new interval takes effect immediately if the controller is",
+				"currently scheduled due to outstanding errors.",
+			},
+		},
+		func(s *script.State, args ...string) (script.WaitFunc, error) {
+			if len(args) < 1 || len(args) > 2 {
+				return nil, script.ErrUsage
+			}
+
+			m := bpf.GetMap(logger, args[0])
+			if m == nil {
+				return nil, fmt.Errorf("map %q not found", args[0])
+			}
+
+			if len(args) == 2 {
+				interval, err := time.ParseDuration(args[1])
+				if err != nil {
+					return nil, fmt.Errorf("invalid interval %q: %w", args[1], err)
+				}
+				if interval < 0 {
+					return nil, fmt.Errorf("interval must not be negative")
+				}
+
+				m.SetErrorResolverInterval(interval)
+			}
+
+			return func(s *script.State) (stdout string, stderr string, err error) {
+				return fmt.Sprintf("%s: %s\n", args[0], m.ErrorResolverInterval()), "", nil
+			}, nil
+		},
+	)
+}
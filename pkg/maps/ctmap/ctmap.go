@@ -298,15 +298,24 @@ func OpenCTMap(m CtMap) (path string, err error) {
 }
 
 // newMap creates a new CT map of the specified type with the specified name.
+// withStreamDumper registers the stream dumper matching the tuple family of
+// the given CT map type, enabling the streaming dump API for the map.
+func withStreamDumper(t mapType, m *bpf.Map) *bpf.Map {
+	if t.isIPv4() {
+		return bpf.WithStreamDumper[tuple.TupleKey4, CtEntry](m)
+	}
+	return bpf.WithStreamDumper[tuple.TupleKey6, CtEntry](m)
+}
+
 func newMap(mapName string, m mapType) *Map {
 	result := &Map{
-		Map: *bpf.NewMap(mapName,
+		Map: *withStreamDumper(m, bpf.NewMap(mapName,
 			ebpf.LRUHash,
 			m.key(),
 			m.value(),
 			m.maxEntries(),
 			0,
-		),
+		)),
 		mapType: m,
 	}
 	return result
@@ -98,8 +98,8 @@ func NewMap(registry *metrics.Registry, name string, family IPFamily, entries in
 		mapValue = &NatEntry6{}
 	}
 
-	return &Map{
-		Map: *bpf.NewMap(
+	newMap := func() *bpf.Map {
+		return bpf.NewMap(
 			name,
 			ebpf.LRUHash,
 			mapKey,
@@ -108,7 +108,17 @@ func NewMap(registry *metrics.Registry, name string, family IPFamily, entries in
 			0,
 		).WithCache().
 			WithEvents(option.Config.GetEventBufferConfig(name)).
-			WithPressureMetric(registry),
+			WithPressureMetric(registry)
+	}
+
+	if family == IPv4 {
+		return &Map{
+			Map:    *bpf.WithStreamDumper[tuple.TupleKey4, NatEntry4](newMap()),
+			family: family,
+		}
+	}
+	return &Map{
+		Map:    *bpf.WithStreamDumper[tuple.TupleKey6, NatEntry6](newMap()),
 		family: family,
 	}
 }
@@ -444,7 +444,8 @@ func newPolicyMap(logger *slog.Logger, id uint16, maxEntries int, stats *StatsMa
 			&PolicyEntry{},
 			maxEntries,
 			flags,
-		).WithGroupName("endpoint_policy"),
+		).WithGroupName("endpoint_policy").
+			WithEvents(option.Config.GetEventBufferConfig(MapName)),
 		stats: stats,
 		epID:  id,
 	}, nil
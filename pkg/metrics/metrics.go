@@ -169,6 +169,12 @@ const (
 	// LabelBuildState is the state a build queue entry is in
 	LabelBuildState = "state"
 
+	// LabelFromState is the state a transition originated from
+	LabelFromState = "from_state"
+
+	// LabelToState is the state a transition led to
+	LabelToState = "to_state"
+
 	// LabelBuildQueueName is the name of the build queue
 	LabelBuildQueueName = "name"
 
@@ -467,6 +473,49 @@ var (
 	// KVStoreQuorumErrors records the number of kvstore quorum errors
 	KVStoreQuorumErrors = NoOpCounterVec
 
+	// KVStoreOversizedRequests records the number of kvstore write requests
+	// rejected client-side because the value exceeded the request size limit
+	KVStoreOversizedRequests = NoOpCounterVec
+
+	// KVStoreConnectivityStateTransitions records the number of gRPC
+	// connectivity state transitions of the kvstore client connection
+	KVStoreConnectivityStateTransitions = NoOpCounterVec
+
+	// KVStoreInitialSyncDuration records the duration of the initial listing
+	// of each watched kvstore prefix during startup
+	KVStoreInitialSyncDuration = NoOpObserverVec
+
+	// KVStoreWrites records the number of write requests issued to the
+	// kvstore per scope, including the writes avoided because the value
+	// turned out to be unchanged
+	KVStoreWrites = NoOpCounterVec
+
+	// KVStoreWatcherQueueDuration records the duration kvstore watchers
+	// waited for their turn on the shared rate limiter
+	KVStoreWatcherQueueDuration = NoOpObserverVec
+
+	// KVStoreRateLimit records the client-side kvstore rate limit currently
+	// applied, which may differ from the configured one when adaptive rate
+	// limiting is enabled
+	KVStoreRateLimit = NoOpGauge
+
+	// KVStoreEvents records the number of events received on watched
+	// kvstore prefixes
+	KVStoreEvents = NoOpCounterVec
+
+	// KVStoreWatchRevisionLag records the difference between the etcd
+	// cluster head revision and the revision last processed by the watcher
+	// of each prefix
+	KVStoreWatchRevisionLag = NoOpGaugeVec
+
+	// KVStoreRelists records the number of full list operations performed
+	// by kvstore watchers
+	KVStoreRelists = NoOpCounterVec
+
+	// KVStorePrefixKeys records the approximate number of live keys under
+	// each watched kvstore prefix, as tracked by the watch streams
+	KVStorePrefixKeys = NoOpGaugeVec
+
 	// FQDNGarbageCollectorCleanedTotal is the number of domains cleaned by the
 	// GC job.
 	FQDNGarbageCollectorCleanedTotal = NoOpCounter
@@ -618,81 +667,91 @@ var (
 )
 
 type LegacyMetrics struct {
-	BootstrapTimes                   metric.Vec[metric.Gauge]
-	APIInteractions                  metric.Vec[metric.Observer]
-	NodeHealthConnectivityStatus     metric.Vec[metric.Gauge]
-	NodeHealthConnectivityLatency    metric.Vec[metric.Observer]
-	Endpoint                         metric.GaugeFunc
-	EndpointMaxIfindex               metric.Gauge
-	EndpointRegenerationTotal        metric.Vec[metric.Counter]
-	EndpointStateCount               metric.Vec[metric.Gauge]
-	EndpointRegenerationTimeStats    metric.Vec[metric.Observer]
-	EndpointPropagationDelay         metric.Vec[metric.Observer]
-	Policy                           metric.Gauge
-	PolicyRevision                   metric.Gauge
-	PolicyChangeTotal                metric.Vec[metric.Counter]
-	PolicyEndpointStatus             metric.Vec[metric.Gauge]
-	PolicyImplementationDelay        metric.Vec[metric.Observer]
-	PolicyIncrementalUpdateDuration  metric.Vec[metric.Observer]
-	Identity                         metric.Vec[metric.Gauge]
-	IdentityLabelSources             metric.Vec[metric.Gauge]
-	EventTS                          metric.Vec[metric.Gauge]
-	EventLagK8s                      metric.Gauge
-	ProxyRedirects                   metric.Vec[metric.Gauge]
-	ProxyPolicyL7Total               metric.Vec[metric.Counter]
-	ProxyUpstreamTime                metric.Vec[metric.Observer]
-	ProxyDatapathUpdateTimeout       metric.Counter
-	ConntrackGCRuns                  metric.Vec[metric.Counter]
-	ConntrackGCKeyFallbacks          metric.Vec[metric.Counter]
-	ConntrackGCSize                  metric.Vec[metric.Gauge]
-	NatGCSize                        metric.Vec[metric.Gauge]
-	ConntrackGCDuration              metric.Vec[metric.Observer]
-	ConntrackInterval                metric.Vec[metric.Gauge]
-	ConntrackDumpResets              metric.Vec[metric.Counter]
-	SignalsHandled                   metric.Vec[metric.Counter]
-	ServicesEventsCount              metric.Vec[metric.Counter]
-	ServiceImplementationDelay       metric.Vec[metric.Observer]
-	ErrorsWarnings                   metric.Vec[metric.Counter]
-	ControllerRuns                   metric.Vec[metric.Counter]
-	ControllerRunsDuration           metric.Vec[metric.Observer]
-	SubprocessStart                  metric.Vec[metric.Counter]
-	KubernetesEventProcessed         metric.Vec[metric.Counter]
-	KubernetesEventReceived          metric.Vec[metric.Counter]
-	KubernetesAPIInteractions        metric.Vec[metric.Observer]
-	KubernetesAPIRateLimiterLatency  metric.Vec[metric.Observer]
-	KubernetesAPICallsTotal          metric.Vec[metric.Counter]
-	TerminatingEndpointsEvents       metric.Counter
-	IPAMEvent                        metric.Vec[metric.Counter]
-	IPAMCapacity                     metric.Vec[metric.Gauge]
-	KVStoreOperationsDuration        metric.Vec[metric.Observer]
-	KVStoreEventsQueueDuration       metric.Vec[metric.Observer]
-	KVStoreQuorumErrors              metric.Vec[metric.Counter]
-	FQDNGarbageCollectorCleanedTotal metric.Counter
-	FQDNActiveNames                  metric.Vec[metric.Gauge]
-	FQDNActiveIPs                    metric.Vec[metric.Gauge]
-	FQDNAliveZombieConnections       metric.Vec[metric.Gauge]
-	FQDNSelectors                    metric.Gauge
-	FQDNSemaphoreRejectedTotal       metric.Counter
-	IPCacheErrorsTotal               metric.Vec[metric.Counter]
-	IPCacheEventsTotal               metric.Vec[metric.Counter]
-	BPFSyscallDuration               metric.Vec[metric.Observer]
-	BPFMapOps                        metric.Vec[metric.Counter]
-	BPFMapCapacity                   metric.Vec[metric.Gauge]
-	VersionMetric                    metric.Vec[metric.Gauge]
-	APILimiterWaitHistoryDuration    metric.Vec[metric.Observer]
-	APILimiterWaitDuration           metric.Vec[metric.Gauge]
-	APILimiterProcessingDuration     metric.Vec[metric.Gauge]
-	APILimiterRequestsInFlight       metric.Vec[metric.Gauge]
-	APILimiterRateLimit              metric.Vec[metric.Gauge]
-	APILimiterAdjustmentFactor       metric.Vec[metric.Gauge]
-	APILimiterProcessedRequests      metric.Vec[metric.Counter]
-	WorkQueueDepth                   metric.Vec[metric.Gauge]
-	WorkQueueAddsTotal               metric.Vec[metric.Counter]
-	WorkQueueLatency                 metric.Vec[metric.Observer]
-	WorkQueueDuration                metric.Vec[metric.Observer]
-	WorkQueueUnfinishedWork          metric.Vec[metric.Gauge]
-	WorkQueueLongestRunningProcessor metric.Vec[metric.Gauge]
-	WorkQueueRetries                 metric.Vec[metric.Counter]
+	BootstrapTimes                      metric.Vec[metric.Gauge]
+	APIInteractions                     metric.Vec[metric.Observer]
+	NodeHealthConnectivityStatus        metric.Vec[metric.Gauge]
+	NodeHealthConnectivityLatency       metric.Vec[metric.Observer]
+	Endpoint                            metric.GaugeFunc
+	EndpointMaxIfindex                  metric.Gauge
+	EndpointRegenerationTotal           metric.Vec[metric.Counter]
+	EndpointStateCount                  metric.Vec[metric.Gauge]
+	EndpointRegenerationTimeStats       metric.Vec[metric.Observer]
+	EndpointPropagationDelay            metric.Vec[metric.Observer]
+	Policy                              metric.Gauge
+	PolicyRevision                      metric.Gauge
+	PolicyChangeTotal                   metric.Vec[metric.Counter]
+	PolicyEndpointStatus                metric.Vec[metric.Gauge]
+	PolicyImplementationDelay           metric.Vec[metric.Observer]
+	PolicyIncrementalUpdateDuration     metric.Vec[metric.Observer]
+	Identity                            metric.Vec[metric.Gauge]
+	IdentityLabelSources                metric.Vec[metric.Gauge]
+	EventTS                             metric.Vec[metric.Gauge]
+	EventLagK8s                         metric.Gauge
+	ProxyRedirects                      metric.Vec[metric.Gauge]
+	ProxyPolicyL7Total                  metric.Vec[metric.Counter]
+	ProxyUpstreamTime                   metric.Vec[metric.Observer]
+	ProxyDatapathUpdateTimeout          metric.Counter
+	ConntrackGCRuns                     metric.Vec[metric.Counter]
+	ConntrackGCKeyFallbacks             metric.Vec[metric.Counter]
+	ConntrackGCSize                     metric.Vec[metric.Gauge]
+	NatGCSize                           metric.Vec[metric.Gauge]
+	ConntrackGCDuration                 metric.Vec[metric.Observer]
+	ConntrackInterval                   metric.Vec[metric.Gauge]
+	ConntrackDumpResets                 metric.Vec[metric.Counter]
+	SignalsHandled                      metric.Vec[metric.Counter]
+	ServicesEventsCount                 metric.Vec[metric.Counter]
+	ServiceImplementationDelay          metric.Vec[metric.Observer]
+	ErrorsWarnings                      metric.Vec[metric.Counter]
+	ControllerRuns                      metric.Vec[metric.Counter]
+	ControllerRunsDuration              metric.Vec[metric.Observer]
+	SubprocessStart                     metric.Vec[metric.Counter]
+	KubernetesEventProcessed            metric.Vec[metric.Counter]
+	KubernetesEventReceived             metric.Vec[metric.Counter]
+	KubernetesAPIInteractions           metric.Vec[metric.Observer]
+	KubernetesAPIRateLimiterLatency     metric.Vec[metric.Observer]
+	KubernetesAPICallsTotal             metric.Vec[metric.Counter]
+	TerminatingEndpointsEvents          metric.Counter
+	IPAMEvent                           metric.Vec[metric.Counter]
+	IPAMCapacity                        metric.Vec[metric.Gauge]
+	KVStoreOperationsDuration           metric.Vec[metric.Observer]
+	KVStoreEventsQueueDuration          metric.Vec[metric.Observer]
+	KVStoreQuorumErrors                 metric.Vec[metric.Counter]
+	KVStoreOversizedRequests            metric.Vec[metric.Counter]
+	KVStoreConnectivityStateTransitions metric.Vec[metric.Counter]
+	KVStoreInitialSyncDuration          metric.Vec[metric.Observer]
+	KVStoreWrites                       metric.Vec[metric.Counter]
+	KVStoreWatcherQueueDuration         metric.Vec[metric.Observer]
+	KVStoreRateLimit                    metric.Gauge
+	KVStoreEvents                       metric.Vec[metric.Counter]
+	KVStoreWatchRevisionLag             metric.Vec[metric.Gauge]
+	KVStoreRelists                      metric.Vec[metric.Counter]
+	KVStorePrefixKeys                   metric.Vec[metric.Gauge]
+	FQDNGarbageCollectorCleanedTotal    metric.Counter
+	FQDNActiveNames                     metric.Vec[metric.Gauge]
+	FQDNActiveIPs                       metric.Vec[metric.Gauge]
+	FQDNAliveZombieConnections          metric.Vec[metric.Gauge]
+	FQDNSelectors                       metric.Gauge
+	FQDNSemaphoreRejectedTotal          metric.Counter
+	IPCacheErrorsTotal                  metric.Vec[metric.Counter]
+	IPCacheEventsTotal                  metric.Vec[metric.Counter]
+	BPFSyscallDuration                  metric.Vec[metric.Observer]
+	BPFMapOps                           metric.Vec[metric.Counter]
+	BPFMapCapacity                      metric.Vec[metric.Gauge]
+	VersionMetric                       metric.Vec[metric.Gauge]
+	APILimiterWaitHistoryDuration       metric.Vec[metric.Observer]
+	APILimiterWaitDuration              metric.Vec[metric.Gauge]
+	APILimiterProcessingDuration        metric.Vec[metric.Gauge]
+	APILimiterRequestsInFlight          metric.Vec[metric.Gauge]
+	APILimiterRateLimit                 metric.Vec[metric.Gauge]
+	APILimiterAdjustmentFactor          metric.Vec[metric.Gauge]
+	APILimiterProcessedRequests         metric.Vec[metric.Counter]
+	WorkQueueDepth                      metric.Vec[metric.Gauge]
+	WorkQueueAddsTotal                  metric.Vec[metric.Counter]
+	WorkQueueLatency                    metric.Vec[metric.Observer]
+	WorkQueueDuration                   metric.Vec[metric.Observer]
+	WorkQueueUnfinishedWork             metric.Vec[metric.Gauge]
+	WorkQueueLongestRunningProcessor    metric.Vec[metric.Gauge]
+	WorkQueueRetries                    metric.Vec[metric.Counter]
 }
 
 func NewLegacyMetrics() *LegacyMetrics {
@@ -1051,6 +1110,14 @@ func NewLegacyMetrics() *LegacyMetrics {
 			Help:       "Duration in seconds of kvstore operations",
 		}, []string{LabelScope, LabelKind, LabelAction, LabelOutcome}),
 
+		KVStoreWrites: metric.NewCounterVec(metric.CounterOpts{
+			ConfigName: Namespace + "_" + SubsystemKVStore + "_writes_total",
+			Namespace:  Namespace,
+			Subsystem:  SubsystemKVStore,
+			Name:       "writes_total",
+			Help:       "Number of kvstore write requests, including the ones avoided because the value was unchanged",
+		}, []string{LabelScope, LabelOutcome}),
+
 		KVStoreEventsQueueDuration: metric.NewHistogramVec(metric.HistogramOpts{
 			ConfigName: Namespace + "_" + SubsystemKVStore + "_events_queue_seconds",
 			Namespace:  Namespace,
@@ -1068,6 +1135,79 @@ func NewLegacyMetrics() *LegacyMetrics {
 			Help:       "Number of quorum errors",
 		}, []string{LabelError}),
 
+		KVStoreOversizedRequests: metric.NewCounterVec(metric.CounterOpts{
+			ConfigName: Namespace + "_" + SubsystemKVStore + "_oversized_requests_total",
+			Namespace:  Namespace,
+			Subsystem:  SubsystemKVStore,
+			Name:       "oversized_requests_total",
+			Help:       "Number of write requests rejected client-side because the value exceeded the request size limit",
+		}, []string{LabelScope, LabelAction}),
+
+		KVStoreConnectivityStateTransitions: metric.NewCounterVec(metric.CounterOpts{
+			ConfigName: Namespace + "_" + SubsystemKVStore + "_connectivity_state_transitions_total",
+			Namespace:  Namespace,
+			Subsystem:  SubsystemKVStore,
+			Name:       "connectivity_state_transitions_total",
+			Help:       "Number of gRPC connectivity state transitions of the kvstore client connection",
+		}, []string{LabelPeerEndpoint, LabelFromState, LabelToState}),
+
+		KVStoreInitialSyncDuration: metric.NewHistogramVec(metric.HistogramOpts{
+			ConfigName: Namespace + "_" + SubsystemKVStore + "_initial_sync_duration_seconds",
+			Namespace:  Namespace,
+			Subsystem:  SubsystemKVStore,
+			Name:       "initial_sync_duration_seconds",
+			Help:       "Duration in seconds of the initial listing of each watched kvstore prefix during startup",
+		}, []string{LabelScope}),
+
+		KVStoreWatcherQueueDuration: metric.NewHistogramVec(metric.HistogramOpts{
+			ConfigName: Namespace + "_" + SubsystemKVStore + "_watcher_queue_duration_seconds",
+			Namespace:  Namespace,
+			Subsystem:  SubsystemKVStore,
+			Name:       "watcher_queue_duration_seconds",
+			Help:       "Duration in seconds kvstore watchers waited for their turn on the shared rate limiter",
+			Buckets:    []float64{.002, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+		}, []string{LabelScope}),
+
+		KVStoreRateLimit: metric.NewGauge(metric.GaugeOpts{
+			ConfigName: Namespace + "_" + SubsystemKVStore + "_rate_limit_qps",
+			Namespace:  Namespace,
+			Subsystem:  SubsystemKVStore,
+			Name:       "rate_limit_qps",
+			Help:       "Client-side kvstore rate limit currently applied in operations per second",
+		}),
+
+		KVStoreEvents: metric.NewCounterVec(metric.CounterOpts{
+			ConfigName: Namespace + "_" + SubsystemKVStore + "_events_total",
+			Namespace:  Namespace,
+			Subsystem:  SubsystemKVStore,
+			Name:       "events_total",
+			Help:       "Number of events received on watched kvstore prefixes",
+		}, []string{LabelScope, LabelAction}),
+
+		KVStoreWatchRevisionLag: metric.NewGaugeVec(metric.GaugeOpts{
+			ConfigName: Namespace + "_" + SubsystemKVStore + "_watch_revision_lag",
+			Namespace:  Namespace,
+			Subsystem:  SubsystemKVStore,
+			Name:       "watch_revision_lag",
+			Help:       "Difference between the etcd cluster head revision and the revision last processed by the watcher of each prefix",
+		}, []string{LabelScope}),
+
+		KVStoreRelists: metric.NewCounterVec(metric.CounterOpts{
+			ConfigName: Namespace + "_" + SubsystemKVStore + "_relists_total",
+			Namespace:  Namespace,
+			Subsystem:  SubsystemKVStore,
+			Name:       "relists_total",
+			Help:       "Number of full list operations performed by kvstore watchers, including the initial list of each prefix",
+		}, []string{LabelScope}),
+
+		KVStorePrefixKeys: metric.NewGaugeVec(metric.GaugeOpts{
+			ConfigName: Namespace + "_" + SubsystemKVStore + "_prefix_keys",
+			Namespace:  Namespace,
+			Subsystem:  SubsystemKVStore,
+			Name:       "prefix_keys",
+			Help:       "Approximate number of live keys under each watched kvstore prefix, as tracked by the watch streams",
+		}, []string{LabelScope}),
+
 		IPCacheErrorsTotal: metric.NewCounterVec(metric.CounterOpts{
 			ConfigName: Namespace + "_" + SubsystemIPCache + "_errors_total",
 			Namespace:  Namespace,
@@ -1331,6 +1471,16 @@ func NewLegacyMetrics() *LegacyMetrics {
 	KVStoreOperationsDuration = lm.KVStoreOperationsDuration
 	KVStoreEventsQueueDuration = lm.KVStoreEventsQueueDuration
 	KVStoreQuorumErrors = lm.KVStoreQuorumErrors
+	KVStoreOversizedRequests = lm.KVStoreOversizedRequests
+	KVStoreConnectivityStateTransitions = lm.KVStoreConnectivityStateTransitions
+	KVStoreInitialSyncDuration = lm.KVStoreInitialSyncDuration
+	KVStoreWrites = lm.KVStoreWrites
+	KVStoreWatcherQueueDuration = lm.KVStoreWatcherQueueDuration
+	KVStoreRateLimit = lm.KVStoreRateLimit
+	KVStoreEvents = lm.KVStoreEvents
+	KVStoreWatchRevisionLag = lm.KVStoreWatchRevisionLag
+	KVStoreRelists = lm.KVStoreRelists
+	KVStorePrefixKeys = lm.KVStorePrefixKeys
 	FQDNGarbageCollectorCleanedTotal = lm.FQDNGarbageCollectorCleanedTotal
 	FQDNActiveNames = lm.FQDNActiveNames
 	FQDNActiveIPs = lm.FQDNActiveIPs
@@ -26,6 +26,11 @@ var Cell = cell.Module(
 
 	cell.Provide(newMonitorAgent),
 	cell.Config(defaultConfig),
+
+	// Exports the event buffers of selected BPF maps (e.g. policy maps,
+	// ipcache) as agent notifications on the monitor and Hubble streams.
+	cell.Config(defaultMapEventExportConfig),
+	cell.Invoke(registerMapEventExporter),
 )
 
 type AgentConfig struct {
@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package agent
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/cilium/hive/cell"
+	"github.com/spf13/pflag"
+
+	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/monitor/api"
+	"github.com/cilium/cilium/pkg/time"
+)
+
+// mapEventExportRetryInterval is the interval at which the exporter retries
+// subscribing to a map whose event buffer is not yet available, e.g. because
+// the map has not been opened yet.
+const mapEventExportRetryInterval = 5 * time.Second
+
+// MapEventExportConfig configures the export of BPF map events as agent
+// notifications.
+type MapEventExportConfig struct {
+	// MonitorExportMapEvents is the list of BPF map names whose event
+	// buffers are exported on the monitor and Hubble streams.
+	MonitorExportMapEvents []string
+}
+
+var defaultMapEventExportConfig = MapEventExportConfig{}
+
+func (def MapEventExportConfig) Flags(flags *pflag.FlagSet) {
+	flags.StringSlice("monitor-export-map-events", def.MonitorExportMapEvents,
+		"List of BPF map names (e.g. cilium_ipcache) whose events are exported as agent notifications on the monitor and Hubble streams. Requires event buffers to be enabled for those maps via bpf-map-event-buffers")
+}
+
+type mapEventExporterParams struct {
+	cell.In
+
+	Lifecycle cell.Lifecycle
+	Log       *slog.Logger
+	Config    MapEventExportConfig
+	Agent     Agent
+}
+
+// registerMapEventExporter subscribes to the event buffers of the configured
+// BPF maps and re-emits the received events as agent notifications, giving
+// flow-correlated visibility into datapath state changes.
+func registerMapEventExporter(params mapEventExporterParams) {
+	if len(params.Config.MonitorExportMapEvents) == 0 {
+		return
+	}
+
+	exporter := &mapEventExporter{
+		logger: params.Log,
+		agent:  params.Agent,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	params.Lifecycle.Append(cell.Hook{
+		OnStart: func(cell.HookContext) error {
+			for _, name := range params.Config.MonitorExportMapEvents {
+				exporter.wg.Add(1)
+				go func() {
+					defer exporter.wg.Done()
+					exporter.export(ctx, name)
+				}()
+			}
+			return nil
+		},
+		OnStop: func(cell.HookContext) error {
+			cancel()
+			exporter.wg.Wait()
+			return nil
+		},
+	})
+}
+
+type mapEventExporter struct {
+	logger *slog.Logger
+	agent  Agent
+	wg     sync.WaitGroup
+}
+
+// export forwards the events of the given map until the context is cancelled.
+// It retries both the initial subscription, as the map may not have been
+// opened yet, and subsequent ones, as the event buffer closes the handle of
+// consumers not keeping up with the event throughput.
+func (e *mapEventExporter) export(ctx context.Context, name string) {
+	scopedLog := e.logger.With(logfields.BPFMapName, name)
+
+	for {
+		handle, err := e.subscribe(ctx, name)
+		if err != nil {
+			// The context has been cancelled.
+			return
+		}
+
+		// Close the handle on cancellation to unblock the consumer.
+		stop := context.AfterFunc(ctx, handle.Close)
+
+		for event := range handle.C() {
+			msg := api.MapEventMessage(name, event.GetAction(), event.GetKey(),
+				event.GetValue(), event.GetDesiredAction().String(), event.GetLastError())
+			if err := e.agent.SendEvent(api.MessageTypeAgent, msg); err != nil {
+				scopedLog.Debug("Failed to emit map event notification",
+					logfields.Error, err,
+				)
+			}
+		}
+
+		stop()
+
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			scopedLog.Warn("Map event subscription closed, resubscribing")
+		}
+	}
+}
+
+// subscribe attaches to the event buffer of the given map, retrying until it
+// becomes available or the context is cancelled. The handle is closed upon
+// context cancellation.
+func (e *mapEventExporter) subscribe(ctx context.Context, name string) (*bpf.Handle, error) {
+	for {
+		if m := bpf.GetMap(e.logger, name); m != nil && m.IsEventsEnabled() {
+			handle, err := m.DumpAndSubscribe(nil, true)
+			if err == nil {
+				return handle, nil
+			}
+
+			e.logger.Warn("Failed to subscribe to map events, will retry",
+				logfields.Error, err,
+				logfields.BPFMapName, name,
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(mapEventExportRetryInterval):
+		}
+	}
+}
@@ -228,6 +228,7 @@ const (
 	AgentNotifyEndpointDeleted
 	AgentNotifyIPCacheUpserted
 	AgentNotifyIPCacheDeleted
+	AgentNotifyMapEvent
 )
 
 // AgentNotifications is a map of all supported agent notification types.
@@ -243,6 +244,7 @@ var AgentNotifications = map[AgentNotification]string{
 	AgentNotifyIPCacheUpserted:           "IPCache entry upserted",
 	AgentNotifyPolicyUpdated:             "Policy updated",
 	AgentNotifyPolicyDeleted:             "Policy deleted",
+	AgentNotifyMapEvent:                  "Datapath map updated",
 }
 
 func resolveAgentType(t AgentNotification) string {
@@ -413,6 +415,36 @@ func IPCacheDeletedMessage(cidr string, id uint32, oldID *uint32, hostIP net.IP,
 	}
 }
 
+// MapEventNotification structures a datapath map event notification
+type MapEventNotification struct {
+	Map           string `json:"map"`
+	Action        string `json:"action"`
+	Key           string `json:"key"`
+	Value         string `json:"value,omitempty"`
+	DesiredAction string `json:"desired_action,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// MapEventMessage constructs an agent notification message for datapath map events
+func MapEventMessage(mapName, action, key, value, desiredAction string, lastError error) AgentNotifyMessage {
+	notification := MapEventNotification{
+		Map:           mapName,
+		Action:        action,
+		Key:           key,
+		Value:         value,
+		DesiredAction: desiredAction,
+	}
+
+	if lastError != nil {
+		notification.Error = lastError.Error()
+	}
+
+	return AgentNotifyMessage{
+		Type:         AgentNotifyMapEvent,
+		Notification: notification,
+	}
+}
+
 // TimeNotification structures agent start notification
 type TimeNotification struct {
 	Time string `json:"time"`
@@ -133,6 +133,19 @@ func TestEndpointRegenMessage(t *testing.T) {
 	testEqualityEndpoint(repr.Text, `{"id":10,"labels":["unspec:key1=value1","unspec:key2=value2"]}`, t)
 }
 
+func TestMapEventMessage(t *testing.T) {
+	msg := MapEventMessage("cilium_ipcache", "update", "10.0.0.1/32", "identity=1234", "sync", nil)
+	repr, err := msg.ToJSON()
+	require.NoError(t, err)
+	require.Equal(t, AgentNotifyMapEvent, repr.Type)
+	require.JSONEq(t, `{"map":"cilium_ipcache","action":"update","key":"10.0.0.1/32","value":"identity=1234","desired_action":"sync"}`, repr.Text)
+
+	msg = MapEventMessage("cilium_ipcache", "delete", "10.0.0.1/32", "", "to-be-deleted", RegenError{})
+	repr, err = msg.ToJSON()
+	require.NoError(t, err)
+	require.JSONEq(t, `{"map":"cilium_ipcache","action":"delete","key":"10.0.0.1/32","desired_action":"to-be-deleted","error":"RegenError"}`, repr.Text)
+}
+
 func TestStartMessage(t *testing.T) {
 	now := time.Now()
 
@@ -105,9 +105,20 @@ func parseMountInfoFile(r io.Reader) ([]*MountInfo, error) {
 // GetMountInfo returns a slice of *MountInfo with information parsed from
 // /proc/self/mountinfo
 func GetMountInfo() ([]*MountInfo, error) {
-	fMounts, err := os.Open(mountInfoFilepath)
+	return getMountInfo(mountInfoFilepath)
+}
+
+// GetMountInfoFromPID returns a slice of *MountInfo with information parsed
+// from /proc/<pid>/mountinfo, i.e. the mounts as seen in the mount namespace
+// of the given process.
+func GetMountInfoFromPID(pid int) ([]*MountInfo, error) {
+	return getMountInfo(fmt.Sprintf("/proc/%d/mountinfo", pid))
+}
+
+func getMountInfo(path string) ([]*MountInfo, error) {
+	fMounts, err := os.Open(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open mount information at %s: %w", mountInfoFilepath, err)
+		return nil, fmt.Errorf("failed to open mount information at %s: %w", path, err)
 	}
 	defer fMounts.Close()
 
@@ -7,7 +7,6 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
-	"path"
 
 	"github.com/cilium/cilium/pkg/defaults"
 	"github.com/cilium/cilium/pkg/kvstore"
@@ -22,7 +21,9 @@ var (
 	//
 	// WARNING - STABLE API: Changing the structure or values of this will
 	// break backwards compatibility
-	NodeStorePrefix = path.Join(kvstore.BaseKeyPrefix, "state", "nodes", "v1")
+	//
+	// Deprecated: use kvstore.NodesPrefix instead.
+	NodeStorePrefix = kvstore.NodesPrefix
 
 	// KeyCreator creates a node for a shared store
 	KeyCreator = func() store.Key {
@@ -52,6 +52,13 @@ type LogConfig struct {
 	// +kubebuilder:validation:MaxLength=32
 	// +kubebuilder:validation:Pattern=`^\PC*$`
 	Value string `json:"value,omitempty"`
+
+	// Verdicts enables policy verdict logging in Hubble for traffic
+	// that matches this rule, without enabling global policy debug
+	// options.
+	//
+	// +kubebuilder:validation:Optional
+	Verdicts bool `json:"verdicts,omitempty"`
 }
 
 // Rule is a policy rule which must be applied to all endpoints which match the
@@ -227,6 +227,21 @@ func (n *EndpointSelector) SyncRequirementsWithLabelSelector() {
 	n.requirements = labelSelectorToRequirements(n.LabelSelector)
 }
 
+// CachedRequirements returns the compiled requirements cached within the
+// EndpointSelector, or nil if the selector has not been compiled yet or
+// failed validation.
+func (n EndpointSelector) CachedRequirements() *k8sLbls.Requirements {
+	return n.requirements
+}
+
+// SetCachedRequirements replaces the compiled requirements cached within the
+// EndpointSelector. It allows a single compiled instance to be shared between
+// all selectors with the same string representation; the caller must ensure
+// that the provided requirements are equivalent to the selector's own.
+func (n *EndpointSelector) SetCachedRequirements(reqs *k8sLbls.Requirements) {
+	n.requirements = reqs
+}
+
 // newReservedEndpointSelector returns a selector that matches on all
 // endpoints with the specified reserved label.
 func newReservedEndpointSelector(ID string) EndpointSelector {
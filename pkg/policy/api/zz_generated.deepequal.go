@@ -955,6 +955,10 @@ func (in *LogConfig) DeepEqual(other *LogConfig) bool {
 		return false
 	}
 
+	if in.Verdicts != other.Verdicts {
+		return false
+	}
+
 	return true
 }
 
@@ -32,6 +32,7 @@ var Cell = cell.Module(
 	cell.Provide(newIdentityUpdater),
 	cell.Config(defaultConfig),
 	metrics.Metric(newIdentityUpdaterMetrics),
+	metrics.Metric(newPolicyImporterMetrics),
 )
 
 type Config struct {
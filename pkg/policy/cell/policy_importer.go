@@ -13,6 +13,9 @@ import (
 	"github.com/cilium/hive/cell"
 	"github.com/cilium/hive/job"
 	"github.com/cilium/stream"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	cmtypes "github.com/cilium/cilium/pkg/clustermesh/types"
 	"github.com/cilium/cilium/pkg/container/set"
@@ -23,6 +26,7 @@ import (
 	"github.com/cilium/cilium/pkg/labels"
 	"github.com/cilium/cilium/pkg/logging/logfields"
 	"github.com/cilium/cilium/pkg/metrics"
+	"github.com/cilium/cilium/pkg/metrics/metric"
 	"github.com/cilium/cilium/pkg/monitor/agent"
 	monitorapi "github.com/cilium/cilium/pkg/monitor/api"
 	"github.com/cilium/cilium/pkg/policy"
@@ -46,6 +50,7 @@ type policyImporterParams struct {
 	EndpointManager endpointmanager.EndpointManager
 	IPCache         *ipcache.IPCache
 	MonitorAgent    agent.Agent
+	Metrics         *policyImporterMetrics
 }
 
 type policyImporter struct {
@@ -55,11 +60,19 @@ type policyImporter struct {
 	ipc          ipcacher
 	monitorAgent agent.Agent
 
+	// tracer emits spans around update batches, linked to the spans of the
+	// events that produced them. No-op unless a trace provider is configured.
+	tracer trace.Tracer
+
 	// prefixesByResources is the list of prefixes
 	// that belong to each resource. This is tracked separately
 	// so we can allocate and release prefixes as policy changes.
 	prefixesByResource map[ipcachetypes.ResourceID][]netip.Prefix
 
+	// metrics report the backpressure between the producers of policy
+	// updates and the importer. May be nil in tests.
+	metrics *policyImporterMetrics
+
 	q chan *policytypes.PolicyUpdate
 }
 
@@ -80,6 +93,8 @@ func newPolicyImporter(cfg policyImporterParams) PolicyImporter {
 		epm:          cfg.EndpointManager,
 		ipc:          cfg.IPCache,
 		monitorAgent: cfg.MonitorAgent,
+		metrics:      cfg.Metrics,
+		tracer:       otel.Tracer("policy-importer"),
 
 		q: make(chan *policytypes.PolicyUpdate, cfg.Config.PolicyQueueSize),
 
@@ -102,6 +117,10 @@ func newPolicyImporter(cfg policyImporterParams) PolicyImporter {
 const ResourceIDAnonymous = "policy/anonymous"
 
 func (i *policyImporter) UpdatePolicy(u *policytypes.PolicyUpdate) {
+	u.EnqueueTime = time.Now()
+	if i.metrics != nil {
+		i.metrics.PendingUpdates.Inc()
+	}
 	i.q <- u
 }
 
@@ -269,8 +288,29 @@ func (i *policyImporter) processUpdates(ctx context.Context, updates []*policyty
 		return nil
 	}
 
+	links := make([]trace.Link, 0, len(updates))
+	for _, upd := range updates {
+		if upd.TraceContext.IsValid() {
+			links = append(links, trace.Link{SpanContext: upd.TraceContext})
+		}
+	}
+	var span trace.Span
+	ctx, span = i.tracer.Start(ctx, "policy-importer/process-updates",
+		trace.WithLinks(links...),
+		trace.WithAttributes(attribute.Int("updates", len(updates))))
+	defer span.End()
+
 	i.log.Info("Processing policy updates", logfields.Count, len(updates))
 
+	// Record how long the updates spent queued before this batch started.
+	if i.metrics != nil {
+		for _, upd := range updates {
+			if !upd.EnqueueTime.IsZero() {
+				i.metrics.QueueWaitDuration.Observe(time.Since(upd.EnqueueTime).Seconds())
+			}
+		}
+	}
+
 	// First, allocate local identities for all prefixes referenced by policies.
 	//
 	// This must happen before the policies are applied to the endpoints. Doing
@@ -350,6 +390,14 @@ func (i *policyImporter) processUpdates(ctx context.Context, updates []*policyty
 			upd.DoneChan <- endRevision
 		}
 
+		// The update is committed to the repository, acknowledge it.
+		if i.metrics != nil {
+			i.metrics.PendingUpdates.Dec()
+			if !upd.EnqueueTime.IsZero() {
+				i.metrics.AckDuration.Observe(time.Since(upd.EnqueueTime).Seconds())
+			}
+		}
+
 		// Send a policy update notification
 		if i.monitorAgent != nil {
 			var msg monitorapi.AgentNotifyMessage
@@ -402,6 +450,42 @@ func (i *policyImporter) processUpdates(ctx context.Context, updates []*policyty
 	return nil
 }
 
+// policyImporterMetrics exposes the backpressure of the policy import
+// pipeline: how many updates are queued between the producers (e.g. the k8s
+// policy watcher) and the importer, and how long each update takes to be
+// picked up and acknowledged. These make it visible when policy programming
+// lags behind apiserver changes.
+type policyImporterMetrics struct {
+	PendingUpdates    metric.Gauge
+	QueueWaitDuration metric.Histogram
+	AckDuration       metric.Histogram
+}
+
+const importerSubsystem = "policy_importer"
+
+func newPolicyImporterMetrics() *policyImporterMetrics {
+	return &policyImporterMetrics{
+		PendingUpdates: metric.NewGauge(metric.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: importerSubsystem,
+			Name:      "pending_updates",
+			Help:      "Number of policy updates queued but not yet applied to the policy repository",
+		}),
+		QueueWaitDuration: metric.NewHistogram(metric.HistogramOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: importerSubsystem,
+			Name:      "queue_wait_duration_seconds",
+			Help:      "Time policy updates spent queued before the importer started processing them",
+		}),
+		AckDuration: metric.NewHistogram(metric.HistogramOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: importerSubsystem,
+			Name:      "ack_duration_seconds",
+			Help:      "Time from queueing a policy update until the importer acknowledged it as applied",
+		}),
+	}
+}
+
 // truncate takes an iterator and passes through at most maxLen values.
 func truncate[T any](xs iter.Seq[T], maxLen int) iter.Seq[T] {
 	pos := 0
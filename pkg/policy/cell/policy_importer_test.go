@@ -11,6 +11,7 @@ import (
 
 	"github.com/cilium/hive/hivetest"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sTypes "k8s.io/apimachinery/pkg/types"
 
@@ -101,10 +102,11 @@ func TestAddReplaceRemoveRule(t *testing.T) {
 	}
 
 	pi := &policyImporter{
-		log:  slog.Default(),
-		repo: policy.NewPolicyRepository(hivetest.Logger(t), ids, nil, nil, nil, testpolicy.NewPolicyMetricsNoop()),
-		epm:  epm,
-		ipc:  ipc,
+		log:    slog.Default(),
+		repo:   policy.NewPolicyRepository(hivetest.Logger(t), ids, nil, nil, nil, testpolicy.NewPolicyMetricsNoop()),
+		epm:    epm,
+		ipc:    ipc,
+		tracer: otel.Tracer("test"),
 
 		q: make(chan *policytypes.PolicyUpdate, 10),
 
@@ -504,8 +506,9 @@ func TestAddCiliumNetworkPolicyByLabels(t *testing.T) {
 			}
 
 			pi := &policyImporter{
-				log:  slog.Default(),
-				repo: args.repo,
+				log:    slog.Default(),
+				repo:   args.repo,
+				tracer: otel.Tracer("test"),
 			}
 
 			pi.processUpdates(context.Background(), []*policytypes.PolicyUpdate{{
@@ -518,3 +521,28 @@ func TestAddCiliumNetworkPolicyByLabels(t *testing.T) {
 		})
 	}
 }
+
+func TestImporterMetrics(t *testing.T) {
+	m := newPolicyImporterMetrics()
+	pi := &policyImporter{
+		log:     slog.Default(),
+		repo:    policy.NewPolicyRepository(hivetest.Logger(t), nil, nil, nil, nil, testpolicy.NewPolicyMetricsNoop()),
+		metrics: m,
+		tracer:  otel.Tracer("test"),
+
+		q: make(chan *policytypes.PolicyUpdate, 10),
+
+		prefixesByResource: map[ipcachetypes.ResourceID][]netip.Prefix{},
+	}
+	pi.repo.GetSelectorCache().SetLocalIdentityNotifier(testidentity.NewDummyIdentityNotifier())
+
+	upd := &policytypes.PolicyUpdate{
+		Resource: ipcachetypes.ResourceID("resourceid"),
+	}
+	pi.UpdatePolicy(upd)
+	require.Equal(t, 1.0, m.PendingUpdates.Get())
+	require.False(t, upd.EnqueueTime.IsZero())
+
+	pi.processUpdates(context.Background(), []*policytypes.PolicyUpdate{<-pi.q})
+	require.Equal(t, 0.0, m.PendingUpdates.Get())
+}
@@ -10,11 +10,15 @@ import (
 
 	"github.com/cilium/hive/cell"
 	"github.com/cilium/statedb"
+	"github.com/spf13/pflag"
+	"go.opentelemetry.io/otel"
 	"k8s.io/apimachinery/pkg/util/sets"
 
+	daemonk8s "github.com/cilium/cilium/daemon/k8s"
 	cmtypes "github.com/cilium/cilium/pkg/clustermesh/types"
 	"github.com/cilium/cilium/pkg/ipcache"
 	cilium_v2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+	"github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2/validator"
 	"github.com/cilium/cilium/pkg/k8s/client"
 	"github.com/cilium/cilium/pkg/k8s/resource"
 	slim_networking_v1 "github.com/cilium/cilium/pkg/k8s/slim/k8s/api/networking/v1"
@@ -22,6 +26,7 @@ import (
 	"github.com/cilium/cilium/pkg/k8s/types"
 	"github.com/cilium/cilium/pkg/labels"
 	"github.com/cilium/cilium/pkg/loadbalancer"
+	"github.com/cilium/cilium/pkg/metrics"
 	"github.com/cilium/cilium/pkg/option"
 	"github.com/cilium/cilium/pkg/policy"
 	"github.com/cilium/cilium/pkg/policy/api"
@@ -45,9 +50,40 @@ var Cell = cell.Module(
 	"policy-k8s-watcher",
 	"Watches K8s policy related objects",
 
-	cell.Invoke(startK8sPolicyWatcher),
+	metrics.Metric(MetricsProvider),
+
+	cell.Config(defaultConfig),
+	cell.Provide(newPolicyWatcher),
+	cell.Provide(dependencyGraphCommands),
+	cell.Invoke(func(*policyWatcher) {}),
 )
 
+// Config holds the configuration of the K8s policy watcher.
+type Config struct {
+	// PolicyStrictUnknownFields, when set, rejects CNPs and CCNPs containing
+	// fields unknown to this agent version (e.g. from a newer CRD version)
+	// with a status condition, instead of silently dropping those fields
+	// during conversion.
+	PolicyStrictUnknownFields bool `mapstructure:"policy-strict-unknown-fields"`
+
+	// PolicyCIDRGroupStatus, when set, reports the number of policies
+	// referencing each CiliumCIDRGroup and the outcome of CIDR resolution
+	// in the group's status. The counts are computed from node-local state
+	// and every agent writes to the same cluster-scoped object, hence this
+	// is off by default.
+	PolicyCIDRGroupStatus bool `mapstructure:"policy-cidr-group-status"`
+}
+
+var defaultConfig = Config{
+	PolicyStrictUnknownFields: false,
+	PolicyCIDRGroupStatus:     false,
+}
+
+func (def Config) Flags(flags *pflag.FlagSet) {
+	flags.Bool("policy-strict-unknown-fields", def.PolicyStrictUnknownFields, "Reject CiliumNetworkPolicies and CiliumClusterwideNetworkPolicies containing unknown fields instead of silently dropping them")
+	flags.Bool("policy-cidr-group-status", def.PolicyCIDRGroupStatus, "Report the number of referencing policies and the CIDR resolution outcome in the CiliumCIDRGroup status")
+}
+
 type PolicyManager interface {
 	PolicyAdd(rules api.Rules, opts *policy.AddOptions) (newRev uint64, err error)
 	PolicyDelete(labels labels.LabelArray, opts *policy.DeleteOptions) (newRev uint64, err error)
@@ -65,15 +101,17 @@ type PolicyWatcherParams struct {
 
 	ClientSet               client.Clientset
 	Config                  *option.DaemonConfig
+	WatcherConfig           Config
 	ClusterMeshPolicyConfig cmtypes.PolicyConfig
 	Logger                  *slog.Logger
 
 	K8sResourceSynced *synced.Resources
 	K8sAPIGroups      *synced.APIGroups
 
-	DB       *statedb.DB
-	Services statedb.Table[*loadbalancer.Service]
-	Backends statedb.Table[*loadbalancer.Backend]
+	DB         *statedb.DB
+	Services   statedb.Table[*loadbalancer.Service]
+	Backends   statedb.Table[*loadbalancer.Backend]
+	Namespaces statedb.Table[daemonk8s.Namespace] `optional:"true"`
 
 	IPCache        *ipcache.IPCache
 	PolicyImporter policycell.PolicyImporter
@@ -84,11 +122,12 @@ type PolicyWatcherParams struct {
 	NetworkPolicies                  resource.Resource[*slim_networking_v1.NetworkPolicy]
 
 	MetricsManager CNPMetrics
+	QueueMetrics   *PolicyQueueMetrics
 }
 
-func startK8sPolicyWatcher(params PolicyWatcherParams) {
+func newPolicyWatcher(params PolicyWatcherParams) (*policyWatcher, error) {
 	if !params.ClientSet.IsEnabled() {
-		return // skip watcher if K8s is not enabled
+		return nil, nil // skip watcher if K8s is not enabled
 	}
 
 	// We want to subscribe before the start hook is invoked in order to not miss
@@ -100,24 +139,42 @@ func startK8sPolicyWatcher(params PolicyWatcherParams) {
 		config:                           params.Config,
 		clusterMeshPolicyConfig:          params.ClusterMeshPolicyConfig,
 		policyImporter:                   params.PolicyImporter,
+		tracer:                           otel.Tracer("policy-k8s-watcher"),
 		k8sResourceSynced:                params.K8sResourceSynced,
 		k8sAPIGroups:                     params.K8sAPIGroups,
+		clientset:                        params.ClientSet,
 		db:                               params.DB,
 		services:                         params.Services,
 		backends:                         params.Backends,
+		namespaces:                       params.Namespaces,
 		ipCache:                          params.IPCache,
 		ciliumNetworkPolicies:            params.CiliumNetworkPolicies,
 		ciliumClusterwideNetworkPolicies: params.CiliumClusterwideNetworkPolicies,
 		ciliumCIDRGroups:                 params.CiliumCIDRGroups,
 		networkPolicies:                  params.NetworkPolicies,
 
-		cnpCache:       make(map[resource.Key]*types.SlimCNP),
-		cidrGroupCache: make(map[string]*cilium_v2.CiliumCIDRGroup),
-		cidrGroupCIDRs: make(map[string]sets.Set[netip.Prefix]),
+		cnpCache:          make(map[resource.Key]*types.SlimCNP),
+		compiledSelectors: newCompiledSelectorCache(),
+		cidrGroupCache:    make(map[string]*cilium_v2.CiliumCIDRGroup),
+		cidrGroupCIDRs:    make(map[string]sets.Set[netip.Prefix]),
 
 		toServicesPolicies: make(map[resource.Key]struct{}),
 		cnpByServiceID:     make(map[loadbalancer.ServiceName]map[resource.Key]struct{}),
+		backendPrefixCache: make(map[loadbalancer.ServiceName]backendPrefixes),
 		metricsManager:     params.MetricsManager,
+		queueMetrics:       params.QueueMetrics,
+
+		reportCIDRGroupStatus: params.WatcherConfig.PolicyCIDRGroupStatus,
+	}
+
+	if params.WatcherConfig.PolicyStrictUnknownFields {
+		npValidator, err := validator.NewNPValidator(params.Logger)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		p.npValidator = npValidator
+		p.rejectedPolicies = make(map[resource.Key]struct{})
 	}
 
 	// Service notifications are not used if CNPs/CCNPs are disabled.
@@ -163,4 +220,6 @@ func startK8sPolicyWatcher(params PolicyWatcherParams) {
 			return p.cidrGroupSynced.Load()
 		})
 	}
+
+	return p, nil
 }
@@ -4,15 +4,21 @@
 package k8s
 
 import (
+	"context"
+	"fmt"
 	"maps"
 	"net/netip"
+	"slices"
 
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	cmtypes "github.com/cilium/cilium/pkg/clustermesh/types"
 	"github.com/cilium/cilium/pkg/ipcache"
 	ipcacheTypes "github.com/cilium/cilium/pkg/ipcache/types"
 	cilium_v2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+	"github.com/cilium/cilium/pkg/k8s/types"
 	"github.com/cilium/cilium/pkg/k8s/utils"
 	"github.com/cilium/cilium/pkg/labels"
 	"github.com/cilium/cilium/pkg/logging/logfields"
@@ -24,6 +30,7 @@ import (
 // if this CIDRGroup is referenced by any policies,
 // applies it to the IPCache.
 func (p *policyWatcher) onUpsertCIDRGroup(
+	ctx context.Context,
 	cidrGroup *cilium_v2.CiliumCIDRGroup,
 	apiGroup string,
 ) {
@@ -34,6 +41,115 @@ func (p *policyWatcher) onUpsertCIDRGroup(
 
 	p.cidrGroupCache[name] = cidrGroup
 	p.applyCIDRGroup(name)
+	p.updateCIDRGroupStatus(ctx, name)
+}
+
+// updateCIDRGroupStatus updates the status of the named CIDRGroup with the
+// number of policies currently referencing it and a condition reflecting
+// whether all of its CIDRs were successfully resolved. The status is only
+// written if it differs from the one stored in the cache, so that the
+// resulting watch event does not cause another update. Reporting is gated
+// behind the policy-cidr-group-status option, as the reference counts are
+// node-local and every agent writes to the same cluster-scoped object.
+func (p *policyWatcher) updateCIDRGroupStatus(ctx context.Context, name string) {
+	if !p.reportCIDRGroupStatus || p.clientset == nil || !p.clientset.IsEnabled() {
+		return
+	}
+	cidrGroup, ok := p.cidrGroupCache[name]
+	if !ok {
+		return
+	}
+
+	var refs int32
+	for _, cnp := range p.cnpCache {
+		if cidrGroupRefs(cnp).Has(name) {
+			refs++
+		}
+	}
+
+	invalid := 0
+	for _, c := range cidrGroup.Spec.ExternalCIDRs {
+		if _, err := netip.ParsePrefix(string(c)); err != nil {
+			invalid++
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:               cilium_v2.CIDRGroupConditionResolved,
+		Status:             metav1.ConditionTrue,
+		Reason:             cilium_v2.CIDRGroupReasonResolved,
+		Message:            fmt.Sprintf("%d CIDRs resolved", len(cidrGroup.Spec.ExternalCIDRs)),
+		ObservedGeneration: cidrGroup.Generation,
+	}
+	if invalid > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = cilium_v2.CIDRGroupReasonInvalid
+		condition.Message = fmt.Sprintf("%d out of %d CIDRs are invalid", invalid, len(cidrGroup.Spec.ExternalCIDRs))
+	}
+
+	desired := cidrGroup.Status.DeepCopy()
+	desired.ReferencingPolicies = refs
+	meta.SetStatusCondition(&desired.Conditions, condition)
+
+	if desired.ReferencingPolicies == cidrGroup.Status.ReferencingPolicies &&
+		slices.Equal(desired.Conditions, cidrGroup.Status.Conditions) {
+		return
+	}
+
+	updated := cidrGroup.DeepCopy()
+	updated.Status = *desired
+	if _, err := p.clientset.CiliumV2().CiliumCIDRGroups().UpdateStatus(ctx, updated, metav1.UpdateOptions{}); err != nil {
+		p.log.Warn(
+			"Failed to update CiliumCIDRGroup status",
+			logfields.Error, err,
+			logfields.CIDRGroupRef, name,
+		)
+		return
+	}
+	p.cidrGroupCache[name] = updated
+}
+
+// cidrGroupRefs returns the names of the CIDRGroups referenced by the given
+// policy.
+func cidrGroupRefs(cnp *types.SlimCNP) sets.Set[string] {
+	refs := sets.New[string]()
+	specs := make(api.Rules, 0, len(cnp.Specs)+1)
+	if cnp.Spec != nil {
+		specs = append(specs, cnp.Spec)
+	}
+	specs = append(specs, cnp.Specs...)
+
+	for _, spec := range specs {
+		for _, rule := range spec.Ingress {
+			for _, c := range rule.FromCIDRSet {
+				if c.CIDRGroupRef != "" {
+					refs.Insert(string(c.CIDRGroupRef))
+				}
+			}
+		}
+		for _, rule := range spec.IngressDeny {
+			for _, c := range rule.FromCIDRSet {
+				if c.CIDRGroupRef != "" {
+					refs.Insert(string(c.CIDRGroupRef))
+				}
+			}
+		}
+		for _, rule := range spec.Egress {
+			for _, c := range rule.ToCIDRSet {
+				if c.CIDRGroupRef != "" {
+					refs.Insert(string(c.CIDRGroupRef))
+				}
+			}
+		}
+		for _, rule := range spec.EgressDeny {
+			for _, c := range rule.ToCIDRSet {
+				if c.CIDRGroupRef != "" {
+					refs.Insert(string(c.CIDRGroupRef))
+				}
+			}
+		}
+	}
+	return refs
 }
 
 // applyCIDRGroup inserts / removes prefixes in the ipcache
@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package k8s
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+
+	"github.com/cilium/hive/hivetest"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/cilium/cilium/pkg/ipcache"
+	cilium_v2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+	k8sFakeClient "github.com/cilium/cilium/pkg/k8s/client/testutils"
+	"github.com/cilium/cilium/pkg/k8s/resource"
+	k8sSynced "github.com/cilium/cilium/pkg/k8s/synced"
+	"github.com/cilium/cilium/pkg/k8s/types"
+	"github.com/cilium/cilium/pkg/loadbalancer"
+	"github.com/cilium/cilium/pkg/option"
+	"github.com/cilium/cilium/pkg/policy/api"
+	policytypes "github.com/cilium/cilium/pkg/policy/types"
+)
+
+type fakeIPCache struct{}
+
+func (f *fakeIPCache) UpsertMetadataBatch(updates ...ipcache.MU) (revision uint64) { return 0 }
+func (f *fakeIPCache) RemoveMetadataBatch(updates ...ipcache.MU) (revision uint64) { return 0 }
+
+func TestCIDRGroupStatus(t *testing.T) {
+	_, clientset := k8sFakeClient.NewFakeClientset(hivetest.Logger(t))
+
+	cidrGroup := &cilium_v2.CiliumCIDRGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-group",
+		},
+		Spec: cilium_v2.CiliumCIDRGroupSpec{
+			ExternalCIDRs: []api.CIDR{"1.0.0.0/24", "not-a-cidr"},
+		},
+	}
+	_, err := clientset.CiliumV2().CiliumCIDRGroups().Create(context.Background(), cidrGroup, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	p := &policyWatcher{
+		log:                hivetest.Logger(t),
+		config:             &option.DaemonConfig{},
+		clientset:          clientset,
+		k8sResourceSynced:  &k8sSynced.Resources{CacheStatus: make(k8sSynced.CacheStatus)},
+		k8sAPIGroups:       &k8sSynced.APIGroups{},
+		policyImporter:     &fakePolicyImporter{OnUpdatePolicy: func(upd *policytypes.PolicyUpdate) {}},
+		tracer:             otel.Tracer("test"),
+		cnpCache:           map[resource.Key]*types.SlimCNP{},
+		cidrGroupCache:     map[string]*cilium_v2.CiliumCIDRGroup{},
+		cidrGroupCIDRs:     map[string]sets.Set[netip.Prefix]{},
+		toServicesPolicies: map[resource.Key]struct{}{},
+		cnpByServiceID:     map[loadbalancer.ServiceName]map[resource.Key]struct{}{},
+		metricsManager:     NewCNPMetricsNoop(),
+		ipCache:            &fakeIPCache{},
+
+		reportCIDRGroupStatus: true,
+	}
+
+	p.onUpsertCIDRGroup(context.Background(), cidrGroup, k8sAPIGroupCiliumCIDRGroupV2)
+
+	group, err := clientset.CiliumV2().CiliumCIDRGroups().Get(context.Background(), "test-group", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(0), group.Status.ReferencingPolicies)
+	cond := meta.FindStatusCondition(group.Status.Conditions, cilium_v2.CIDRGroupConditionResolved)
+	if assert.NotNil(t, cond) {
+		assert.Equal(t, metav1.ConditionFalse, cond.Status)
+		assert.Equal(t, cilium_v2.CIDRGroupReasonInvalid, cond.Reason)
+	}
+
+	// Adding a policy referencing the group bumps the reference count.
+	cnp := &types.SlimCNP{
+		CiliumNetworkPolicy: &cilium_v2.CiliumNetworkPolicy{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "cilium.io/v2",
+				Kind:       "CiliumNetworkPolicy",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cnp-cidr-group",
+				Namespace: "test",
+			},
+			Spec: &api.Rule{
+				EndpointSelector: api.NewESFromLabels(),
+				Ingress: []api.IngressRule{
+					{
+						IngressCommonRule: api.IngressCommonRule{
+							FromCIDRSet: []api.CIDRRule{
+								{CIDRGroupRef: "test-group"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	cnpKey := resource.NewKey(cnp)
+	assert.NoError(t, p.onUpsert(cnp, cnpKey, k8sAPIGroupCiliumNetworkPolicyV2, resourceIDForCiliumNetworkPolicy(cnpKey, cnp), nil))
+
+	group, err = clientset.CiliumV2().CiliumCIDRGroups().Get(context.Background(), "test-group", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), group.Status.ReferencingPolicies)
+
+	// Deleting the policy drops the count back to zero.
+	p.onDelete(cnp, cnpKey, k8sAPIGroupCiliumNetworkPolicyV2, resourceIDForCiliumNetworkPolicy(cnpKey, cnp), nil)
+
+	group, err = clientset.CiliumV2().CiliumCIDRGroups().Get(context.Background(), "test-group", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(0), group.Status.ReferencingPolicies)
+}
@@ -7,6 +7,9 @@ import (
 	"context"
 	"fmt"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
 	cmtypes "github.com/cilium/cilium/pkg/clustermesh/types"
 	ipcacheTypes "github.com/cilium/cilium/pkg/ipcache/types"
 	"github.com/cilium/cilium/pkg/k8s/resource"
@@ -28,6 +31,13 @@ func (p *policyWatcher) onUpsert(
 ) error {
 	initialRecvTime := time.Now()
 
+	ctx, span := p.tracer.Start(context.Background(), "policy-watcher/cnp-upsert",
+		trace.WithAttributes(
+			attribute.String("resource", string(resourceID)),
+			attribute.String("api-group", apiGroup),
+		))
+	defer span.End()
+
 	defer func() {
 		p.k8sResourceSynced.SetEventTimestamp(apiGroup)
 	}()
@@ -52,6 +62,14 @@ func (p *policyWatcher) onUpsert(
 		)
 	}
 
+	// In strict mode, reject policies whose stored form contains fields
+	// unknown to this agent version before translating them any further.
+	if p.npValidator != nil {
+		if err := p.enforceStrictFields(ctx, key); err != nil {
+			return err
+		}
+	}
+
 	if cnp.RequiresDerivative() {
 		return nil
 	}
@@ -70,7 +88,23 @@ func (p *policyWatcher) onUpsert(
 		}
 	}
 
-	return p.resolveCiliumNetworkPolicyRefs(cnp, key, initialRecvTime, resourceID, dc)
+	// The set of policies referencing a CIDRGroup may change with this
+	// update, refresh the status of the groups referenced before or after.
+	cidrGroups := cidrGroupRefs(cnp)
+	if ok {
+		cidrGroups = cidrGroups.Union(cidrGroupRefs(oldCNP))
+	}
+
+	err := p.resolveCiliumNetworkPolicyRefs(ctx, cnp, key, initialRecvTime, resourceID, dc)
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	for name := range cidrGroups {
+		p.updateCIDRGroupStatus(ctx, name)
+	}
+
+	return err
 }
 
 func (p *policyWatcher) onDelete(
@@ -80,9 +114,21 @@ func (p *policyWatcher) onDelete(
 	resourceID ipcacheTypes.ResourceID,
 	dc chan uint64,
 ) {
-	p.deleteCiliumNetworkPolicyV2(cnp, resourceID, dc)
+	ctx, span := p.tracer.Start(context.Background(), "policy-watcher/cnp-delete",
+		trace.WithAttributes(
+			attribute.String("resource", string(resourceID)),
+			attribute.String("api-group", apiGroup),
+		))
+	defer span.End()
+
+	p.deleteCiliumNetworkPolicyV2(ctx, cnp, resourceID, dc)
 
 	delete(p.cnpCache, key)
+	p.compiledSelectors.detach(key)
+
+	for name := range cidrGroupRefs(cnp) {
+		p.updateCIDRGroupStatus(ctx, name)
+	}
 
 	// Clear ToServices index
 	for svcID := range p.cnpByServiceID {
@@ -99,6 +145,7 @@ func (p *policyWatcher) onDelete(
 // If the CNP was successfully imported, the raw (i.e. untranslated) CNP/CCNP
 // is also added to p.cnpCache.
 func (p *policyWatcher) resolveCiliumNetworkPolicyRefs(
+	ctx context.Context,
 	cnp *types.SlimCNP,
 	key resource.Key,
 	initialRecvTime time.Time,
@@ -115,7 +162,7 @@ func (p *policyWatcher) resolveCiliumNetworkPolicyRefs(
 		p.resolveToServices(key, translatedCNP)
 	}
 
-	err := p.upsertCiliumNetworkPolicyV2(translatedCNP, initialRecvTime, resourceID, dc)
+	err := p.upsertCiliumNetworkPolicyV2(ctx, translatedCNP, key, initialRecvTime, resourceID, dc)
 	if err == nil {
 		p.cnpCache[key] = cnp
 	}
@@ -123,7 +170,7 @@ func (p *policyWatcher) resolveCiliumNetworkPolicyRefs(
 	return err
 }
 
-func (p *policyWatcher) upsertCiliumNetworkPolicyV2(cnp *types.SlimCNP, initialRecvTime time.Time, resourceID ipcacheTypes.ResourceID, dc chan uint64) error {
+func (p *policyWatcher) upsertCiliumNetworkPolicyV2(ctx context.Context, cnp *types.SlimCNP, key resource.Key, initialRecvTime time.Time, resourceID ipcacheTypes.ResourceID, dc chan uint64) error {
 	scopedLog := p.log.With(
 		logfields.CiliumNetworkPolicyName, cnp.ObjectMeta.Name,
 		logfields.K8sAPIVersion, cnp.TypeMeta.APIVersion,
@@ -148,6 +195,15 @@ func (p *policyWatcher) upsertCiliumNetworkPolicyV2(cnp *types.SlimCNP, initialR
 		)
 		return fmt.Errorf("failed to parse CiliumNetworkPolicy %s/%s: %w", cnp.ObjectMeta.Namespace, cnp.ObjectMeta.Name, err)
 	}
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("rules", len(rules)))
+
+	// Attach stable per-rule UIDs so that downstream consumers can correlate
+	// the rules across policy generations.
+	attachRuleUIDs(cnp.ObjectMeta.UID, rules)
+
+	// Share the compiled selectors with identical ones from other policies.
+	p.compiledSelectors.attach(key, rules)
+
 	if dc != nil {
 		if cnp.ObjectMeta.Namespace == "" {
 			p.ccnpSyncPending.Add(1)
@@ -161,6 +217,7 @@ func (p *policyWatcher) upsertCiliumNetworkPolicyV2(cnp *types.SlimCNP, initialR
 		ProcessingStartTime: initialRecvTime,
 		Resource:            resourceID,
 		DoneChan:            dc,
+		TraceContext:        trace.SpanContextFromContext(ctx),
 	})
 	scopedLog.Info(
 		"Imported CiliumNetworkPolicy",
@@ -168,7 +225,7 @@ func (p *policyWatcher) upsertCiliumNetworkPolicyV2(cnp *types.SlimCNP, initialR
 	return nil
 }
 
-func (p *policyWatcher) deleteCiliumNetworkPolicyV2(cnp *types.SlimCNP, resourceID ipcacheTypes.ResourceID, dc chan uint64) {
+func (p *policyWatcher) deleteCiliumNetworkPolicyV2(ctx context.Context, cnp *types.SlimCNP, resourceID ipcacheTypes.ResourceID, dc chan uint64) {
 	p.log.Debug("Deleting CiliumNetworkPolicy",
 		logfields.CiliumNetworkPolicyName, cnp.ObjectMeta.Name,
 		logfields.K8sAPIVersion, cnp.TypeMeta.APIVersion,
@@ -189,9 +246,10 @@ func (p *policyWatcher) deleteCiliumNetworkPolicyV2(cnp *types.SlimCNP, resource
 		}
 	}
 	p.policyImporter.UpdatePolicy(&policytypes.PolicyUpdate{
-		Source:   source.CustomResource,
-		Resource: resourceID,
-		DoneChan: dc,
+		Source:       source.CustomResource,
+		Resource:     resourceID,
+		DoneChan:     dc,
+		TraceContext: trace.SpanContextFromContext(ctx),
 	})
 	p.log.Info("Deleted CiliumNetworkPolicy",
 		logfields.CiliumNetworkPolicyName, cnp.ObjectMeta.Name,
@@ -0,0 +1,138 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package k8s
+
+import (
+	"github.com/cilium/cilium/pkg/k8s/resource"
+	k8sLbls "github.com/cilium/cilium/pkg/k8s/slim/k8s/apis/labels"
+	"github.com/cilium/cilium/pkg/policy/api"
+)
+
+// compiledSelectorCache shares the compiled form of identical label selectors
+// across policies. Parsing a CNP compiles every EndpointSelector into a set
+// of label requirements; in clusters where thousands of policies use the same
+// selectors, deduplicating the compiled objects saves both the memory for the
+// duplicates and the CPU spent recompiling them on the first Matches() call.
+//
+// The cache is only accessed from the policy watcher's event handlers, which
+// are serialized, so it requires no locking. A nil cache is a no-op, used by
+// tests that exercise the watcher without selector sharing.
+type compiledSelectorCache struct {
+	// selectors maps the canonical string representation of a label
+	// selector to its shared compiled form.
+	selectors map[string]*compiledSelector
+
+	// byPolicy records the selectors each policy holds a reference to.
+	byPolicy map[resource.Key][]string
+}
+
+type compiledSelector struct {
+	requirements *k8sLbls.Requirements
+	refCount     int
+}
+
+func newCompiledSelectorCache() *compiledSelectorCache {
+	return &compiledSelectorCache{
+		selectors: make(map[string]*compiledSelector),
+		byPolicy:  make(map[resource.Key][]string),
+	}
+}
+
+// attach points every selector in the given rules at the shared compiled
+// requirements, adopting the selector's own compiled form on first use, and
+// records a reference for each one against the policy. References previously
+// held by the policy are released first, so attach may be called again with
+// the policy's updated rules.
+func (c *compiledSelectorCache) attach(key resource.Key, rules api.Rules) {
+	if c == nil {
+		return
+	}
+	c.detach(key)
+	var held []string
+	for _, r := range rules {
+		forEachSelector(r, func(es *api.EndpointSelector) {
+			if s, ok := c.intern(es); ok {
+				held = append(held, s)
+			}
+		})
+	}
+	if len(held) > 0 {
+		c.byPolicy[key] = held
+	}
+}
+
+// detach releases the selector references held by the policy, dropping
+// compiled selectors no longer referenced by any policy.
+func (c *compiledSelectorCache) detach(key resource.Key) {
+	if c == nil {
+		return
+	}
+	for _, s := range c.byPolicy[key] {
+		if entry, ok := c.selectors[s]; ok {
+			entry.refCount--
+			if entry.refCount <= 0 {
+				delete(c.selectors, s)
+			}
+		}
+	}
+	delete(c.byPolicy, key)
+}
+
+// intern replaces the compiled requirements of the selector with the shared
+// instance. Selectors which have not been compiled, or whose compilation
+// failed, are left untouched.
+func (c *compiledSelectorCache) intern(es *api.EndpointSelector) (string, bool) {
+	s := es.CachedString()
+	if s == "" {
+		return "", false
+	}
+	entry, ok := c.selectors[s]
+	if !ok {
+		reqs := es.CachedRequirements()
+		if reqs == nil {
+			return "", false
+		}
+		entry = &compiledSelector{requirements: reqs}
+		c.selectors[s] = entry
+	}
+	entry.refCount++
+	es.SetCachedRequirements(entry.requirements)
+	return s, true
+}
+
+// forEachSelector invokes f for every EndpointSelector of the rule.
+func forEachSelector(r *api.Rule, f func(*api.EndpointSelector)) {
+	if !r.EndpointSelector.IsZero() {
+		f(&r.EndpointSelector)
+	}
+	if !r.NodeSelector.IsZero() {
+		f(&r.NodeSelector)
+	}
+	for i := range r.Ingress {
+		forEachSelectorSlice(r.Ingress[i].FromEndpoints, f)
+		forEachSelectorSlice(r.Ingress[i].FromRequires, f)
+		forEachSelectorSlice(r.Ingress[i].FromNodes, f)
+	}
+	for i := range r.IngressDeny {
+		forEachSelectorSlice(r.IngressDeny[i].FromEndpoints, f)
+		forEachSelectorSlice(r.IngressDeny[i].FromRequires, f)
+		forEachSelectorSlice(r.IngressDeny[i].FromNodes, f)
+	}
+	for i := range r.Egress {
+		forEachSelectorSlice(r.Egress[i].ToEndpoints, f)
+		forEachSelectorSlice(r.Egress[i].ToRequires, f)
+		forEachSelectorSlice(r.Egress[i].ToNodes, f)
+	}
+	for i := range r.EgressDeny {
+		forEachSelectorSlice(r.EgressDeny[i].ToEndpoints, f)
+		forEachSelectorSlice(r.EgressDeny[i].ToRequires, f)
+		forEachSelectorSlice(r.EgressDeny[i].ToNodes, f)
+	}
+}
+
+func forEachSelectorSlice(sel []api.EndpointSelector, f func(*api.EndpointSelector)) {
+	for i := range sel {
+		f(&sel[i])
+	}
+}
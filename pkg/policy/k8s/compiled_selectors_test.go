@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cilium/cilium/pkg/k8s/resource"
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/policy/api"
+)
+
+func TestCompiledSelectorCache(t *testing.T) {
+	newRules := func() api.Rules {
+		return api.Rules{
+			{
+				EndpointSelector: api.NewESFromLabels(labels.NewLabel("app", "foo", labels.LabelSourceK8s)),
+				Ingress: []api.IngressRule{
+					{
+						IngressCommonRule: api.IngressCommonRule{
+							FromEndpoints: []api.EndpointSelector{
+								api.NewESFromLabels(labels.NewLabel("role", "backend", labels.LabelSourceK8s)),
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	c := newCompiledSelectorCache()
+
+	keyA := resource.Key{Namespace: "test", Name: "a"}
+	keyB := resource.Key{Namespace: "test", Name: "b"}
+	rulesA, rulesB := newRules(), newRules()
+
+	c.attach(keyA, rulesA)
+	c.attach(keyB, rulesB)
+
+	// Both policies use identical selectors, so the compiled objects must be
+	// shared.
+	assert.Len(t, c.selectors, 2)
+	assert.Same(t, rulesA[0].EndpointSelector.CachedRequirements(), rulesB[0].EndpointSelector.CachedRequirements())
+	assert.Same(t,
+		rulesA[0].Ingress[0].FromEndpoints[0].CachedRequirements(),
+		rulesB[0].Ingress[0].FromEndpoints[0].CachedRequirements(),
+	)
+	for _, entry := range c.selectors {
+		assert.Equal(t, 2, entry.refCount)
+	}
+
+	// Re-attaching a policy must not leak references.
+	c.attach(keyA, newRules())
+	for _, entry := range c.selectors {
+		assert.Equal(t, 2, entry.refCount)
+	}
+
+	// Detaching one policy keeps the selectors alive for the other.
+	c.detach(keyA)
+	assert.Len(t, c.selectors, 2)
+	for _, entry := range c.selectors {
+		assert.Equal(t, 1, entry.refCount)
+	}
+
+	// Dropping the last reference removes the compiled selectors.
+	c.detach(keyB)
+	assert.Empty(t, c.selectors)
+	assert.Empty(t, c.byPolicy)
+
+	// A nil cache is a no-op.
+	var nilCache *compiledSelectorCache
+	nilCache.attach(keyA, newRules())
+	nilCache.detach(keyA)
+}
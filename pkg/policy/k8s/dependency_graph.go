@@ -0,0 +1,213 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package k8s
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/cilium/hive"
+	"github.com/cilium/hive/script"
+	"github.com/spf13/pflag"
+
+	"github.com/cilium/cilium/pkg/k8s/resource"
+	"github.com/cilium/cilium/pkg/policy/api"
+)
+
+// PolicyDependencyGraph describes the dependencies of the imported
+// CiliumNetworkPolicies and CiliumClusterwideNetworkPolicies on other
+// Kubernetes objects: the services matched by their ToServices rules and the
+// CiliumCIDRGroups referenced from their CIDR sets. It can be used to tell
+// which policies would be affected by a change to a given service or group.
+type PolicyDependencyGraph struct {
+	Policies []PolicyDependencies `json:"policies"`
+}
+
+// PolicyDependencies lists the objects a single policy depends on.
+type PolicyDependencies struct {
+	// Kind is either "CiliumNetworkPolicy" or
+	// "CiliumClusterwideNetworkPolicy".
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+
+	// ServiceSelectors are the label selectors of the ToServices rules of
+	// the policy, which determine the matched services as they come and go.
+	ServiceSelectors []string `json:"serviceSelectors,omitempty"`
+
+	// Services are the services (as "namespace/name") currently matched by
+	// the ToServices rules of the policy.
+	Services []string `json:"services,omitempty"`
+
+	// CIDRGroups are the names of the CiliumCIDRGroups referenced by the
+	// policy.
+	CIDRGroups []string `json:"cidrGroups,omitempty"`
+}
+
+// dependencyGraph builds the dependency graph from the current watcher state.
+func (p *policyWatcher) dependencyGraph() *PolicyDependencyGraph {
+	p.handlerMu.Lock()
+	defer p.handlerMu.Unlock()
+
+	// Invert cnpByServiceID to obtain the services matched by each policy.
+	services := make(map[resource.Key][]string)
+	for svcID, keys := range p.cnpByServiceID {
+		for key := range keys {
+			services[key] = append(services[key], svcID.String())
+		}
+	}
+
+	graph := &PolicyDependencyGraph{
+		Policies: make([]PolicyDependencies, 0, len(p.cnpCache)),
+	}
+	for key, cnp := range p.cnpCache {
+		kind := "CiliumNetworkPolicy"
+		if key.Namespace == "" {
+			kind = "CiliumClusterwideNetworkPolicy"
+		}
+
+		deps := PolicyDependencies{
+			Kind:       kind,
+			Namespace:  key.Namespace,
+			Name:       key.Name,
+			Services:   slices.Sorted(slices.Values(services[key])),
+			CIDRGroups: slices.Sorted(slices.Values(cidrGroupRefs(cnp).UnsortedList())),
+		}
+
+		specs := make(api.Rules, 0, len(cnp.Specs)+1)
+		if cnp.Spec != nil {
+			specs = append(specs, cnp.Spec)
+		}
+		specs = append(specs, cnp.Specs...)
+		for _, spec := range specs {
+			for _, egress := range spec.Egress {
+				for _, toService := range egress.ToServices {
+					if sel := toService.K8sServiceSelector; sel != nil {
+						deps.ServiceSelectors = append(deps.ServiceSelectors,
+							api.EndpointSelector(sel.Selector).CachedString())
+					}
+				}
+			}
+		}
+
+		graph.Policies = append(graph.Policies, deps)
+	}
+
+	slices.SortFunc(graph.Policies, func(a, b PolicyDependencies) int {
+		return cmp.Or(
+			cmp.Compare(a.Kind, b.Kind),
+			cmp.Compare(a.Namespace, b.Namespace),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+	return graph
+}
+
+// Filter removes the policies which do not depend on the given service
+// ("namespace/name") or CIDR group name.
+func (g *PolicyDependencyGraph) Filter(dependency string) {
+	g.Policies = slices.DeleteFunc(g.Policies, func(deps PolicyDependencies) bool {
+		return !slices.Contains(deps.Services, dependency) &&
+			!slices.Contains(deps.CIDRGroups, dependency)
+	})
+}
+
+// WriteDOT renders the graph in the DOT format understood by Graphviz.
+func (g *PolicyDependencyGraph) WriteDOT(w io.Writer) error {
+	if _, err := fmt.Fprintln(w, "digraph \"policy-dependencies\" {"); err != nil {
+		return err
+	}
+	for _, deps := range g.Policies {
+		policy := deps.Kind + "/" + deps.Name
+		if deps.Namespace != "" {
+			policy = deps.Kind + "/" + deps.Namespace + "/" + deps.Name
+		}
+		if _, err := fmt.Fprintf(w, "  %q;\n", policy); err != nil {
+			return err
+		}
+		for _, svc := range deps.Services {
+			if _, err := fmt.Fprintf(w, "  %q -> %q;\n", policy, "Service/"+svc); err != nil {
+				return err
+			}
+		}
+		for _, group := range deps.CIDRGroups {
+			if _, err := fmt.Fprintf(w, "  %q -> %q;\n", policy, "CiliumCIDRGroup/"+group); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
+
+// dependencyGraphCommands exposes the dependency graph as a script command.
+// The watcher is nil when Kubernetes support is disabled, in which case no
+// command is registered.
+func dependencyGraphCommands(p *policyWatcher) hive.ScriptCmdsOut {
+	if p == nil {
+		return hive.NewScriptCmds(nil)
+	}
+	return hive.NewScriptCmds(map[string]script.Cmd{
+		"policy/dependency-graph": dependencyGraphCommand(p),
+	})
+}
+
+func dependencyGraphCommand(p *policyWatcher) script.Cmd {
+	return script.Command(
+		script.CmdUsage{
+			Summary: "Show the dependencies of the imported policies on services and CIDR groups",
+			Args:    "[service-namespace/name|cidr-group-name]",
+			Flags: func(fs *pflag.FlagSet) {
+				fs.String("format", "json", "Output format: json or dot")
+				fs.StringP("output", "o", "", "File to write output to")
+			},
+			Detail: []string{
+				"Prints the graph of policies and the services and CIDR groups",
+				"they reference. If a service (as namespace/name) or CIDR group",
+				"name is given, only the policies depending on it are shown.",
+			},
+		},
+		func(s *script.State, args ...string) (script.WaitFunc, error) {
+			format, err := s.Flags.GetString("format")
+			if err != nil {
+				return nil, err
+			}
+
+			file, err := s.Flags.GetString("output")
+			if err != nil {
+				return nil, err
+			}
+
+			w := s.LogWriter()
+			if file != "" {
+				fd, err := os.Create(s.Path(file))
+				if err != nil {
+					return nil, err
+				}
+				defer fd.Close()
+				w = fd
+			}
+
+			graph := p.dependencyGraph()
+			if len(args) > 0 {
+				graph.Filter(args[0])
+			}
+
+			switch format {
+			case "json":
+				enc := json.NewEncoder(w)
+				enc.SetIndent("", "  ")
+				return nil, enc.Encode(graph)
+			case "dot":
+				return nil, graph.WriteDOT(w)
+			default:
+				return nil, fmt.Errorf("unknown format %q", format)
+			}
+		},
+	)
+}
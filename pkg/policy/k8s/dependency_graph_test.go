@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package k8s
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cilium_v2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+	"github.com/cilium/cilium/pkg/k8s/resource"
+	"github.com/cilium/cilium/pkg/k8s/types"
+	"github.com/cilium/cilium/pkg/loadbalancer"
+	"github.com/cilium/cilium/pkg/policy/api"
+)
+
+func TestDependencyGraph(t *testing.T) {
+	toServicesCNP := &types.SlimCNP{
+		CiliumNetworkPolicy: &cilium_v2.CiliumNetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "to-services",
+				Namespace: "test",
+			},
+			Spec: &api.Rule{
+				EndpointSelector: api.NewESFromLabels(),
+				Egress: []api.EgressRule{
+					{
+						EgressCommonRule: api.EgressCommonRule{
+							ToServices: []api.Service{
+								{
+									K8sService: &api.K8sServiceNamespace{
+										ServiceName: "foo-svc",
+										Namespace:   "foo-ns",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	toServicesKey := resource.NewKey(toServicesCNP)
+
+	cidrGroupCCNP := &types.SlimCNP{
+		CiliumNetworkPolicy: &cilium_v2.CiliumNetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "from-cidr-group",
+			},
+			Spec: &api.Rule{
+				EndpointSelector: api.NewESFromLabels(),
+				Ingress: []api.IngressRule{
+					{
+						IngressCommonRule: api.IngressCommonRule{
+							FromCIDRSet: []api.CIDRRule{
+								{CIDRGroupRef: "test-group"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	cidrGroupKey := resource.NewKey(cidrGroupCCNP)
+
+	p := &policyWatcher{
+		cnpCache: map[resource.Key]*types.SlimCNP{
+			toServicesKey: toServicesCNP,
+			cidrGroupKey:  cidrGroupCCNP,
+		},
+		cnpByServiceID: map[loadbalancer.ServiceName]map[resource.Key]struct{}{
+			loadbalancer.NewServiceName("foo-ns", "foo-svc"): {
+				toServicesKey: {},
+			},
+		},
+	}
+
+	graph := p.dependencyGraph()
+	assert.Equal(t, []PolicyDependencies{
+		{
+			Kind:       "CiliumClusterwideNetworkPolicy",
+			Name:       "from-cidr-group",
+			CIDRGroups: []string{"test-group"},
+		},
+		{
+			Kind:      "CiliumNetworkPolicy",
+			Namespace: "test",
+			Name:      "to-services",
+			Services:  []string{"foo-ns/foo-svc"},
+		},
+	}, graph.Policies)
+
+	var dot strings.Builder
+	assert.NoError(t, graph.WriteDOT(&dot))
+	assert.Contains(t, dot.String(),
+		`"CiliumNetworkPolicy/test/to-services" -> "Service/foo-ns/foo-svc";`)
+	assert.Contains(t, dot.String(),
+		`"CiliumClusterwideNetworkPolicy/from-cidr-group" -> "CiliumCIDRGroup/test-group";`)
+
+	// Filtering by a dependency keeps only the policies referencing it.
+	graph.Filter("test-group")
+	assert.Len(t, graph.Policies, 1)
+	assert.Equal(t, "from-cidr-group", graph.Policies[0].Name)
+
+	graph = p.dependencyGraph()
+	graph.Filter("foo-ns/foo-svc")
+	assert.Len(t, graph.Policies, 1)
+	assert.Equal(t, "to-services", graph.Policies[0].Name)
+}
@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package k8s
+
+import (
+	"testing"
+
+	fuzz "github.com/AdaLogics/go-fuzz-headers"
+	"github.com/cilium/hive/hivetest"
+	"go.opentelemetry.io/otel"
+
+	cilium_v2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+	"github.com/cilium/cilium/pkg/k8s/resource"
+	k8sSynced "github.com/cilium/cilium/pkg/k8s/synced"
+	"github.com/cilium/cilium/pkg/k8s/types"
+	"github.com/cilium/cilium/pkg/loadbalancer"
+	"github.com/cilium/cilium/pkg/option"
+	"github.com/cilium/cilium/pkg/policy/api"
+	policytypes "github.com/cilium/cilium/pkg/policy/types"
+)
+
+// FuzzCNPTranslation feeds generated CNPs through the watcher's upsert path
+// and checks the translation invariants: no panics, the input CNP is not
+// mutated by parsing (GH-33432: nil vs. empty slices must be preserved), and
+// the derived rules are deterministic.
+func FuzzCNPTranslation(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		ff := fuzz.NewConsumer(data)
+		cnp := &cilium_v2.CiliumNetworkPolicy{}
+		if err := ff.GenerateStruct(cnp); err != nil {
+			return
+		}
+		cnp.ObjectMeta.Name = "fuzz-cnp"
+		cnp.ObjectMeta.Namespace = "fuzz-ns"
+
+		slim := &types.SlimCNP{CiliumNetworkPolicy: cnp}
+		orig := slim.DeepCopy()
+
+		rules1 := translateCNPForFuzzing(t, slim)
+		if !slim.DeepEqual(orig) {
+			t.Fatal("watcher translation mutated the input CNP")
+		}
+		rules2 := translateCNPForFuzzing(t, slim)
+		if !rules1.DeepEqual(&rules2) {
+			t.Fatal("watcher translation is not deterministic")
+		}
+	})
+}
+
+// translateCNPForFuzzing runs the CNP through a fresh policy watcher and
+// returns the rules handed to the policy importer, or nil if the CNP was
+// rejected.
+func translateCNPForFuzzing(t *testing.T, cnp *types.SlimCNP) api.Rules {
+	var rules api.Rules
+	importer := &fakePolicyImporter{
+		OnUpdatePolicy: func(upd *policytypes.PolicyUpdate) {
+			rules = upd.Rules
+		},
+	}
+	servicesFixture := newServicesFixture(t)
+	p := &policyWatcher{
+		log:                hivetest.Logger(t),
+		config:             &option.DaemonConfig{EnableIPv4: true, EnableIPv6: true},
+		k8sResourceSynced:  &k8sSynced.Resources{CacheStatus: make(k8sSynced.CacheStatus)},
+		k8sAPIGroups:       &k8sSynced.APIGroups{},
+		db:                 servicesFixture.db,
+		services:           servicesFixture.services,
+		backends:           servicesFixture.backends,
+		policyImporter:     importer,
+		tracer:             otel.Tracer("test"),
+		cnpCache:           map[resource.Key]*types.SlimCNP{},
+		toServicesPolicies: map[resource.Key]struct{}{},
+		cnpByServiceID:     map[loadbalancer.ServiceName]map[resource.Key]struct{}{},
+		metricsManager:     NewCNPMetricsNoop(),
+	}
+	key := resource.NewKey(cnp)
+	_ = p.onUpsert(cnp, key, k8sAPIGroupCiliumNetworkPolicyV2, resourceIDForCiliumNetworkPolicy(key, cnp), nil)
+	return rules
+}
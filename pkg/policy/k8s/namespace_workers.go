@@ -0,0 +1,226 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package k8s
+
+import (
+	"sync"
+
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/metrics"
+	"github.com/cilium/cilium/pkg/metrics/metric"
+)
+
+// PolicyQueueMetrics exposes the length of the per-namespace policy event
+// queues, so that a namespace flooding the watcher with updates is visible.
+type PolicyQueueMetrics struct {
+	PolicyEventQueueLength metric.Vec[metric.Gauge]
+}
+
+func MetricsProvider() *PolicyQueueMetrics {
+	return &PolicyQueueMetrics{
+		PolicyEventQueueLength: metric.NewGaugeVec(metric.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: metrics.SubsystemK8s,
+			Name:      "policy_event_queue_length",
+			Help:      "Number of Kubernetes policy events queued per namespace",
+		}, []string{"namespace"}),
+	}
+}
+
+// defaultPolicyWorkers is the number of workers serving the per-namespace
+// policy event queues.
+const defaultPolicyWorkers = 4
+
+// namespaceWorkerPool fans policy events out to per-namespace FIFO queues
+// which are served round-robin by a fixed number of workers. This way a flood
+// of policy updates in one namespace cannot delay events of unrelated
+// namespaces. Events of the same namespace are processed in order, one at a
+// time; events of different namespaces may be processed concurrently, so the
+// enqueued tasks must synchronize access to any shared state themselves.
+type namespaceWorkerPool struct {
+	metrics *PolicyQueueMetrics
+
+	mu     lock.Mutex
+	queues map[string][]poolTask
+	// ready contains the namespaces with pending tasks which are not
+	// currently served by a worker, in round-robin order.
+	ready []string
+	// pending is the number of enqueued but not yet completed tasks,
+	// tracked for the barriers below.
+	pending int
+	// nextSeq is the sequence number assigned to the next enqueued task,
+	// so that barriers can tell tasks enqueued before them apart from
+	// later ones.
+	nextSeq uint64
+	// barriers contains the callbacks registered via Barrier, each invoked
+	// once the tasks enqueued before it have completed.
+	barriers []*poolBarrier
+
+	wake   chan struct{}
+	done   chan struct{}
+	wg     sync.WaitGroup
+	closed bool
+}
+
+type poolTask struct {
+	run func()
+	seq uint64
+}
+
+type poolBarrier struct {
+	// threshold is the pool sequence number at registration time: only the
+	// completion of tasks enqueued before it counts towards the barrier.
+	threshold uint64
+	remaining int
+	callback  func()
+}
+
+// newNamespaceWorkerPool starts a worker pool with the given number of
+// workers. 'metrics' may be nil, in which case no queue lengths are reported.
+func newNamespaceWorkerPool(workers int, metrics *PolicyQueueMetrics) *namespaceWorkerPool {
+	w := &namespaceWorkerPool{
+		metrics: metrics,
+		queues:  map[string][]poolTask{},
+		wake:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	for range workers {
+		w.wg.Add(1)
+		go w.worker()
+	}
+	return w
+}
+
+// Enqueue adds a task to the queue of the given namespace. Cluster-scoped
+// events should use the empty namespace. Tasks enqueued after Close are
+// dropped.
+func (w *namespaceWorkerPool) Enqueue(namespace string, task func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return
+	}
+
+	queue, active := w.queues[namespace]
+	w.queues[namespace] = append(queue, poolTask{run: task, seq: w.nextSeq})
+	w.nextSeq++
+	w.pending++
+	if !active {
+		w.ready = append(w.ready, namespace)
+	}
+	w.updateQueueLength(namespace)
+
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Barrier registers a callback which is invoked, from a worker, once all
+// tasks enqueued before the barrier have completed. If the pool is idle the
+// callback is invoked synchronously.
+func (w *namespaceWorkerPool) Barrier(callback func()) {
+	w.mu.Lock()
+	if w.pending == 0 {
+		w.mu.Unlock()
+		callback()
+		return
+	}
+	w.barriers = append(w.barriers, &poolBarrier{threshold: w.nextSeq, remaining: w.pending, callback: callback})
+	w.mu.Unlock()
+}
+
+// Close stops the workers and waits for the tasks already picked up to
+// complete. Remaining queued tasks are dropped.
+func (w *namespaceWorkerPool) Close() {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return
+	}
+	w.closed = true
+	w.mu.Unlock()
+
+	close(w.done)
+	w.wg.Wait()
+}
+
+func (w *namespaceWorkerPool) worker() {
+	defer w.wg.Done()
+	for {
+		task, namespace, ok := w.next()
+		for ok {
+			task.run()
+			w.complete(namespace, task.seq)
+			task, namespace, ok = w.next()
+		}
+
+		select {
+		case <-w.done:
+			return
+		case <-w.wake:
+		}
+	}
+}
+
+// next pops the first task of the next ready namespace, marking the
+// namespace as actively served so that a single namespace is only ever
+// processed by one worker at a time.
+func (w *namespaceWorkerPool) next() (poolTask, string, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.ready) == 0 {
+		return poolTask{}, "", false
+	}
+
+	namespace := w.ready[0]
+	w.ready = w.ready[1:]
+	queue := w.queues[namespace]
+	task := queue[0]
+	// Leave the empty queue in the map to mark the namespace as active.
+	w.queues[namespace] = queue[1:]
+	w.updateQueueLength(namespace)
+	return task, namespace, true
+}
+
+func (w *namespaceWorkerPool) complete(namespace string, seq uint64) {
+	var callbacks []func()
+
+	w.mu.Lock()
+	if len(w.queues[namespace]) > 0 {
+		w.ready = append(w.ready, namespace)
+		select {
+		case w.wake <- struct{}{}:
+		default:
+		}
+	} else {
+		delete(w.queues, namespace)
+	}
+
+	w.pending--
+	remaining := w.barriers[:0]
+	for _, barrier := range w.barriers {
+		// Tasks enqueued after the barrier do not count towards it.
+		if seq < barrier.threshold {
+			barrier.remaining--
+		}
+		if barrier.remaining == 0 {
+			callbacks = append(callbacks, barrier.callback)
+		} else {
+			remaining = append(remaining, barrier)
+		}
+	}
+	w.barriers = remaining
+	w.mu.Unlock()
+
+	for _, callback := range callbacks {
+		callback()
+	}
+}
+
+func (w *namespaceWorkerPool) updateQueueLength(namespace string) {
+	if w.metrics != nil {
+		w.metrics.PolicyEventQueueLength.WithLabelValues(namespace).Set(float64(len(w.queues[namespace])))
+	}
+}
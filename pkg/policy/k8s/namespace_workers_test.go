@@ -0,0 +1,147 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package k8s
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespaceWorkerPoolOrdering(t *testing.T) {
+	w := newNamespaceWorkerPool(4, MetricsProvider())
+	defer w.Close()
+
+	const perNamespace = 100
+	var mu sync.Mutex
+	processed := map[string][]int{}
+
+	var wg sync.WaitGroup
+	for _, ns := range []string{"foo", "bar", ""} {
+		for i := range perNamespace {
+			wg.Add(1)
+			w.Enqueue(ns, func() {
+				defer wg.Done()
+				mu.Lock()
+				processed[ns] = append(processed[ns], i)
+				mu.Unlock()
+			})
+		}
+	}
+	wg.Wait()
+
+	// Events of the same namespace are processed in order.
+	for ns, order := range processed {
+		require.Len(t, order, perNamespace, "namespace %q", ns)
+		for i, v := range order {
+			require.Equal(t, i, v, "namespace %q", ns)
+		}
+	}
+}
+
+func TestNamespaceWorkerPoolFairness(t *testing.T) {
+	w := newNamespaceWorkerPool(1, nil)
+	defer w.Close()
+
+	// Block the single worker so that the queues below build up.
+	block := make(chan struct{})
+	started := make(chan struct{})
+	w.Enqueue("flood", func() {
+		close(started)
+		<-block
+	})
+	<-started
+
+	var mu sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	wg.Add(51)
+	for range 50 {
+		w.Enqueue("flood", func() {
+			defer wg.Done()
+			mu.Lock()
+			order = append(order, "flood")
+			mu.Unlock()
+		})
+	}
+	w.Enqueue("other", func() {
+		defer wg.Done()
+		mu.Lock()
+		order = append(order, "other")
+		mu.Unlock()
+	})
+
+	close(block)
+	wg.Wait()
+
+	// The other namespace must not wait for the flooded queue to drain:
+	// round-robin scheduling lets it run after at most one more flood event.
+	otherIndex := slices.Index(order, "other")
+	require.NotEqual(t, -1, otherIndex)
+	assert.LessOrEqual(t, otherIndex, 1, "other namespace was starved: %v", order)
+}
+
+func TestNamespaceWorkerPoolBarrier(t *testing.T) {
+	w := newNamespaceWorkerPool(2, nil)
+	defer w.Close()
+
+	// An idle pool invokes the barrier synchronously.
+	invoked := false
+	w.Barrier(func() { invoked = true })
+	assert.True(t, invoked)
+
+	block := make(chan struct{})
+	var completed int
+	var mu sync.Mutex
+	for range 10 {
+		w.Enqueue("ns", func() {
+			<-block
+			mu.Lock()
+			completed++
+			mu.Unlock()
+		})
+	}
+
+	barrier := make(chan int, 1)
+	w.Barrier(func() {
+		mu.Lock()
+		defer mu.Unlock()
+		barrier <- completed
+	})
+
+	select {
+	case <-barrier:
+		t.Fatal("barrier fired before the enqueued tasks completed")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	// Tasks enqueued after the barrier do not count towards it: their
+	// completion must not fire the barrier while the pre-barrier tasks are
+	// still blocked.
+	var after sync.WaitGroup
+	for range 10 {
+		after.Add(1)
+		w.Enqueue("other", func() { after.Done() })
+	}
+	after.Wait()
+
+	select {
+	case <-barrier:
+		t.Fatal("barrier fired on the completion of tasks enqueued after it")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	close(block)
+	select {
+	case n := <-barrier:
+		// The barrier only fires once all tasks enqueued before it are done.
+		assert.Equal(t, 10, n)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for barrier")
+	}
+}
@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package k8s
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+
+	k8sConst "github.com/cilium/cilium/pkg/k8s/apis/cilium.io"
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/policy/api"
+)
+
+// attachRuleUIDs labels each rule with a UID derived from the rule content
+// and the UID of the resource the rule was parsed from. The UID is stable
+// across re-imports of the policy as long as the rule itself is unchanged,
+// allowing downstream accounting (policy map entries, metrics, flow
+// annotations) to correlate rules across policy generations.
+func attachRuleUIDs(uid k8sTypes.UID, rules api.Rules) {
+	for _, rule := range rules {
+		rule.Labels = append(rule.Labels,
+			labels.NewLabel(k8sConst.PolicyLabelRuleUID, ruleUID(uid, rule), labels.LabelSourceK8s),
+		).Sort()
+	}
+}
+
+// ruleUID hashes the normalized content of the rule together with the
+// resource UID. The rule labels and any content generated by reference
+// resolution (e.g. the ToCIDRSet entries inlined for ToServices rules) are
+// excluded from the hash, as they may differ between imports of an otherwise
+// unchanged rule.
+func ruleUID(uid k8sTypes.UID, r *api.Rule) string {
+	normalized := r.DeepCopy()
+	normalized.Labels = nil
+	for i := range normalized.Egress {
+		egress := &normalized.Egress[i]
+		egress.ToCIDRSet = slices.DeleteFunc(egress.ToCIDRSet, func(c api.CIDRRule) bool {
+			return c.Generated
+		})
+		egress.ToEndpoints = slices.DeleteFunc(egress.ToEndpoints, func(es api.EndpointSelector) bool {
+			return es.Generated
+		})
+	}
+
+	h := sha256.New()
+	h.Write([]byte(uid))
+	// api.Rule consists of marshalable types only, the error is always nil.
+	content, _ := json.Marshal(normalized)
+	h.Write(content)
+	return hex.EncodeToString(h.Sum(nil))
+}
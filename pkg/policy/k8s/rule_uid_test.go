@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+
+	k8sConst "github.com/cilium/cilium/pkg/k8s/apis/cilium.io"
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/policy/api"
+)
+
+func TestRuleUID(t *testing.T) {
+	uid := k8sTypes.UID("11bba160-ddca-11e8-b697-0800273b04ff")
+
+	newRule := func() *api.Rule {
+		return &api.Rule{
+			EndpointSelector: api.NewESFromLabels(),
+			Egress: []api.EgressRule{
+				{
+					EgressCommonRule: api.EgressCommonRule{
+						ToServices: []api.Service{
+							{
+								K8sService: &api.K8sServiceNamespace{
+									ServiceName: "foo-svc",
+									Namespace:   "foo-ns",
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	base := ruleUID(uid, newRule())
+
+	// The UID is stable across identical rules.
+	require.Equal(t, base, ruleUID(uid, newRule()))
+
+	// Rule labels and generated content do not influence the UID, so a rule
+	// keeps its UID when its references are re-resolved.
+	translated := newRule()
+	translated.Labels = labels.ParseLabelArray("k8s:io.cilium.k8s.policy.name=foo")
+	translated.Egress[0].ToCIDRSet = api.CIDRRuleSlice{
+		{Cidr: "10.1.1.1/32", Generated: true},
+	}
+	translated.Egress[0].ToEndpoints = []api.EndpointSelector{
+		{LabelSelector: api.NewESFromLabels().LabelSelector, Generated: true},
+	}
+	require.Equal(t, base, ruleUID(uid, translated))
+
+	// Changes to the rule content yield a different UID.
+	changed := newRule()
+	changed.Egress[0].ToServices[0].K8sService.ServiceName = "bar-svc"
+	require.NotEqual(t, base, ruleUID(uid, changed))
+
+	// User-provided (non-generated) entries are part of the rule content.
+	userCIDR := newRule()
+	userCIDR.Egress[0].ToCIDRSet = api.CIDRRuleSlice{{Cidr: "10.1.1.1/32"}}
+	require.NotEqual(t, base, ruleUID(uid, userCIDR))
+
+	// The same rule in a different resource yields a different UID.
+	require.NotEqual(t, base, ruleUID(k8sTypes.UID("other"), newRule()))
+}
+
+func TestAttachRuleUIDs(t *testing.T) {
+	uid := k8sTypes.UID("11bba160-ddca-11e8-b697-0800273b04ff")
+	rule := &api.Rule{
+		EndpointSelector: api.NewESFromLabels(),
+		Labels:           labels.ParseLabelArray("k8s:io.cilium.k8s.policy.name=foo"),
+	}
+
+	attachRuleUIDs(uid, api.Rules{rule})
+
+	require.Len(t, rule.Labels, 2)
+	var uidLabel labels.Label
+	for _, lbl := range rule.Labels {
+		if lbl.Key == k8sConst.PolicyLabelRuleUID {
+			uidLabel = lbl
+		}
+	}
+	require.Equal(t, labels.LabelSourceK8s, uidLabel.Source)
+	require.Equal(t, ruleUID(uid, rule), uidLabel.Value)
+
+	// Re-attaching after a no-op update yields the same label.
+	updated := &api.Rule{
+		EndpointSelector: api.NewESFromLabels(),
+		Labels:           labels.ParseLabelArray("k8s:io.cilium.k8s.policy.name=foo"),
+	}
+	attachRuleUIDs(uid, api.Rules{updated})
+	require.Equal(t, rule.Labels, updated.Labels)
+}
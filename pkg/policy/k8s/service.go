@@ -40,11 +40,15 @@ import (
 
 // serviceEvent captures the relevant description of a change to a service.
 // This is used to find matching CNPs and trigger recomputation if needed.
+// Only the fields the ToServices translation depends on (labels, selector,
+// ports and the associated backends) are captured, so that changes to
+// unrelated service fields do not cause policies to be re-imported.
 type serviceEvent struct {
 	deleted          bool
 	name             loadbalancer.ServiceName
 	labels           labels.Labels
 	selector         map[string]string
+	ports            map[string]uint16
 	backendRevisions []statedb.Revision
 	previous         *serviceEvent
 }
@@ -54,7 +58,8 @@ func (s serviceEvent) Equal(other serviceEvent) bool {
 		s.name.Equal(other.name) &&
 		s.labels.Equals(other.labels) &&
 		slices.Equal(s.backendRevisions, other.backendRevisions) &&
-		maps.Equal(s.selector, other.selector)
+		maps.Equal(s.selector, other.selector) &&
+		maps.Equal(s.ports, other.ports)
 }
 
 func (s serviceEvent) getLabels() labels.Labels { return s.labels }
@@ -126,6 +131,7 @@ func serviceEventStream(db *statedb.DB, services statedb.Table[*loadbalancer.Ser
 						newEvent.name = svc.Name
 						newEvent.labels = svc.Labels
 						newEvent.selector = svc.Selector
+						newEvent.ports = svc.PortNames
 						for _, rev := range backends.List(txn, loadbalancer.BackendByServiceName(name)) {
 							newEvent.backendRevisions = append(newEvent.backendRevisions, rev)
 						}
@@ -165,6 +171,8 @@ func serviceEventStream(db *statedb.DB, services statedb.Table[*loadbalancer.Ser
 // onServiceEvent processes a ServiceNotification and (if necessary)
 // recalculates all policies affected by this change.
 func (p *policyWatcher) onServiceEvent(event serviceEvent) {
+	// The service or its backends have changed, drop the cached resolution.
+	p.invalidateBackendPrefixes(event.name)
 	err := p.updateToServicesPolicies(event)
 	if err != nil {
 		p.log.Warn(
@@ -226,7 +234,7 @@ func (p *policyWatcher) updateToServicesPolicies(ev serviceEvent) error {
 
 		resourceID := resourceIDForCiliumNetworkPolicy(key, cnp)
 
-		errs = append(errs, p.resolveCiliumNetworkPolicyRefs(cnp, key, initialRecvTime, resourceID, nil))
+		errs = append(errs, p.resolveCiliumNetworkPolicyRefs(context.Background(), cnp, key, initialRecvTime, resourceID, nil))
 	}
 	return errors.Join(errs...)
 }
@@ -237,7 +245,8 @@ func (p *policyWatcher) resolveToServices(key resource.Key, cnp *types.SlimCNP)
 	txn := p.db.ReadTxn()
 
 	for svc := range p.services.All(txn) {
-		svcEndpoints := newServiceEndpoints(svc, txn, p.backends)
+		svcEndpoints := newServiceEndpoints(svc, txn, p.backends, p.config.EnableIPv4, p.config.EnableIPv6)
+		svcEndpoints.backendPrefixes = p.cachingBackendPrefixes(svc.Name, svcEndpoints.backendPrefixes)
 
 		// This extracts the selected service endpoints from the rule
 		// and translates it to a ToCIDRSet
@@ -258,6 +267,52 @@ func (p *policyWatcher) resolveToServices(key resource.Key, cnp *types.SlimCNP)
 
 type backendPrefixes = []api.CIDR
 
+// cachingBackendPrefixes wraps the backend prefix computation of a service
+// with the resolution cache: on a cache hit the cached prefixes are returned,
+// on a miss 'compute' is invoked and its result stored. This avoids
+// re-listing the backends of unchanged services every time a policy with a
+// ToServices rule is re-resolved.
+func (p *policyWatcher) cachingBackendPrefixes(svcID loadbalancer.ServiceName, compute func() backendPrefixes) func() backendPrefixes {
+	return func() backendPrefixes {
+		p.backendPrefixMu.Lock()
+		prefixes, ok := p.backendPrefixCache[svcID]
+		p.backendPrefixMu.Unlock()
+		if ok {
+			return prefixes
+		}
+
+		prefixes = compute()
+		p.backendPrefixMu.Lock()
+		if p.backendPrefixCache != nil {
+			p.backendPrefixCache[svcID] = prefixes
+		}
+		p.backendPrefixMu.Unlock()
+		return prefixes
+	}
+}
+
+// invalidateBackendPrefixes drops the cached backend prefixes of the given
+// service, forcing the next resolution to re-list its backends.
+func (p *policyWatcher) invalidateBackendPrefixes(svcID loadbalancer.ServiceName) {
+	p.backendPrefixMu.Lock()
+	delete(p.backendPrefixCache, svcID)
+	p.backendPrefixMu.Unlock()
+}
+
+// ToServicesBackends returns a snapshot of the cached ToServices backend
+// resolutions, keyed by service name. Only services which have been resolved
+// for a ToServices rule since their last change are included.
+func (p *policyWatcher) ToServicesBackends() map[loadbalancer.ServiceName][]api.CIDR {
+	p.backendPrefixMu.Lock()
+	defer p.backendPrefixMu.Unlock()
+
+	backends := make(map[loadbalancer.ServiceName][]api.CIDR, len(p.backendPrefixCache))
+	for svcID, prefixes := range p.backendPrefixCache {
+		backends[svcID] = slices.Clone(prefixes)
+	}
+	return backends
+}
+
 // cnpMatchesService returns true if the cnp contains a ToServices rule which
 // matches the provided service svcID/svc
 func (p *policyWatcher) cnpMatchesService(cnp *types.SlimCNP, ev serviceEvent) bool {
@@ -399,14 +454,20 @@ func (s serviceEndpoints) getNamespace() string     { return s.svc.Name.Namespac
 
 var _ serviceDetailer = serviceEndpoints{}
 
-// newServiceEndpoints returns an initialized serviceEndpoints struct
-func newServiceEndpoints(svc *loadbalancer.Service, txn statedb.ReadTxn, backends statedb.Table[*loadbalancer.Backend]) serviceEndpoints {
+// newServiceEndpoints returns an initialized serviceEndpoints struct.
+// Backend addresses of a disabled address family are filtered out, so that
+// dual-stack services only generate CIDRs which are enforceable with the
+// current enable-ipv4/enable-ipv6 configuration.
+func newServiceEndpoints(svc *loadbalancer.Service, txn statedb.ReadTxn, backends statedb.Table[*loadbalancer.Backend], enableIPv4, enableIPv6 bool) serviceEndpoints {
 	return serviceEndpoints{
 		svc: svc,
 		backendPrefixes: sync.OnceValue(func() backendPrefixes {
 			prefixes := backendPrefixes{}
 			for be := range backends.List(txn, loadbalancer.BackendByServiceName(svc.Name)) {
-				addr := be.Address.AddrCluster.Addr()
+				addr := be.Address.AddrCluster.Addr().Unmap()
+				if (addr.Is4() && !enableIPv4) || (addr.Is6() && !enableIPv6) {
+					continue
+				}
 				prefixes = append(prefixes, api.CIDR(netip.PrefixFrom(addr, addr.BitLen()).String()))
 			}
 			return prefixes
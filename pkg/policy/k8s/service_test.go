@@ -15,6 +15,7 @@ import (
 	"github.com/cilium/stream"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	cmtypes "github.com/cilium/cilium/pkg/clustermesh/types"
@@ -29,6 +30,7 @@ import (
 	"github.com/cilium/cilium/pkg/option"
 	"github.com/cilium/cilium/pkg/policy/api"
 	policytypes "github.com/cilium/cilium/pkg/policy/types"
+	"github.com/cilium/cilium/pkg/time"
 )
 
 type fakePolicyImporter struct {
@@ -239,13 +241,14 @@ func TestPolicyWatcher_updateToServicesPolicies(t *testing.T) {
 
 	p := &policyWatcher{
 		log:                hivetest.Logger(t),
-		config:             &option.DaemonConfig{},
+		config:             &option.DaemonConfig{EnableIPv4: true, EnableIPv6: true},
 		k8sResourceSynced:  &k8sSynced.Resources{CacheStatus: make(k8sSynced.CacheStatus)},
 		k8sAPIGroups:       &k8sSynced.APIGroups{},
 		db:                 servicesFixture.db,
 		services:           servicesFixture.services,
 		backends:           servicesFixture.backends,
 		policyImporter:     policyImporter,
+		tracer:             otel.Tracer("test"),
 		cnpCache:           map[resource.Key]*types.SlimCNP{},
 		toServicesPolicies: map[resource.Key]struct{}{},
 		cnpByServiceID:     map[loadbalancer.ServiceName]map[resource.Key]struct{}{},
@@ -516,10 +519,11 @@ func TestPolicyWatcher_updateToServicesPoliciesTransformToEndpoint(t *testing.T)
 
 	p := &policyWatcher{
 		log:                hivetest.Logger(t),
-		config:             &option.DaemonConfig{},
+		config:             &option.DaemonConfig{EnableIPv4: true, EnableIPv6: true},
 		k8sResourceSynced:  &k8sSynced.Resources{CacheStatus: make(k8sSynced.CacheStatus)},
 		k8sAPIGroups:       &k8sSynced.APIGroups{},
 		policyImporter:     policyImporter,
+		tracer:             otel.Tracer("test"),
 		db:                 servicesFixture.db,
 		services:           servicesFixture.services,
 		backends:           servicesFixture.backends,
@@ -1097,3 +1101,359 @@ func TestServiceEventStream(t *testing.T) {
 		}
 	}
 }
+
+func TestServiceEventStreamRelevantFields(t *testing.T) {
+	servicesFixture := newServicesFixture(t)
+	serviceEvents := stream.ToChannel(
+		t.Context(),
+		serviceEventStream(servicesFixture.db, servicesFixture.services, servicesFixture.backends),
+	)
+
+	svc := loadbalancer.NewServiceName("test", "svc1")
+	upsert := func(service *loadbalancer.Service) {
+		wtxn := servicesFixture.db.WriteTxn(servicesFixture.services)
+		servicesFixture.services.Insert(wtxn, service)
+		wtxn.Commit()
+	}
+
+	upsert(&loadbalancer.Service{
+		Name:      svc,
+		PortNames: map[string]uint16{"http": 80},
+	})
+	ev := <-serviceEvents
+	require.Equal(t, map[string]uint16{"http": 80}, ev.ports)
+
+	// Changing fields which do not influence the ToServices translation
+	// does not emit an event.
+	upsert(&loadbalancer.Service{
+		Name:            svc,
+		PortNames:       map[string]uint16{"http": 80},
+		Annotations:     map[string]string{"unrelated": "change"},
+		SessionAffinity: true,
+	})
+	time.Sleep(100 * time.Millisecond)
+	select {
+	case ev := <-serviceEvents:
+		t.Fatalf("unexpected event for unrelated service change: %+v", ev)
+	default:
+	}
+
+	// Changing the service ports does emit an event.
+	upsert(&loadbalancer.Service{
+		Name:      svc,
+		PortNames: map[string]uint16{"http": 8080},
+	})
+	ev = <-serviceEvents
+	require.Equal(t, map[string]uint16{"http": 8080}, ev.ports)
+	require.NotNil(t, ev.previous)
+	require.Equal(t, map[string]uint16{"http": 80}, ev.previous.ports)
+}
+
+func TestPolicyWatcher_serviceUpdateCounts(t *testing.T) {
+	policyAdd := make(chan api.Rules, 2)
+	policyImporter := &fakePolicyImporter{
+		OnUpdatePolicy: func(upd *policytypes.PolicyUpdate) {
+			policyAdd <- upd.Rules
+		},
+	}
+
+	newCNPForService := func(name, svcName, svcNamespace string) *types.SlimCNP {
+		return &types.SlimCNP{
+			CiliumNetworkPolicy: &cilium_v2.CiliumNetworkPolicy{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "cilium.io/v2",
+					Kind:       "CiliumNetworkPolicy",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "test",
+				},
+				Spec: &api.Rule{
+					EndpointSelector: api.NewESFromLabels(),
+					Egress: []api.EgressRule{
+						{
+							EgressCommonRule: api.EgressCommonRule{
+								ToServices: []api.Service{
+									{
+										K8sService: &api.K8sServiceNamespace{
+											ServiceName: svcName,
+											Namespace:   svcNamespace,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	fooCNP := newCNPForService("select-foo", "foo-svc", "foo-ns")
+	fooKey := resource.NewKey(fooCNP)
+	barCNP := newCNPForService("select-bar", "bar-svc", "bar-ns")
+	barKey := resource.NewKey(barCNP)
+
+	fooSvcID := loadbalancer.NewServiceName("foo-ns", "foo-svc")
+	fooEps := []cmtypes.AddrCluster{cmtypes.MustParseAddrCluster("10.1.1.1")}
+	barSvcID := loadbalancer.NewServiceName("bar-ns", "bar-svc")
+	barEps := []cmtypes.AddrCluster{cmtypes.MustParseAddrCluster("192.168.1.1")}
+
+	servicesFixture := newServicesFixture(t)
+
+	p := &policyWatcher{
+		log:                hivetest.Logger(t),
+		config:             &option.DaemonConfig{EnableIPv4: true, EnableIPv6: true},
+		k8sResourceSynced:  &k8sSynced.Resources{CacheStatus: make(k8sSynced.CacheStatus)},
+		k8sAPIGroups:       &k8sSynced.APIGroups{},
+		db:                 servicesFixture.db,
+		services:           servicesFixture.services,
+		backends:           servicesFixture.backends,
+		policyImporter:     policyImporter,
+		tracer:             otel.Tracer("test"),
+		cnpCache:           map[resource.Key]*types.SlimCNP{},
+		toServicesPolicies: map[resource.Key]struct{}{},
+		cnpByServiceID:     map[loadbalancer.ServiceName]map[resource.Key]struct{}{},
+		metricsManager:     NewCNPMetricsNoop(),
+	}
+
+	err := p.onUpsert(fooCNP, fooKey, k8sAPIGroupCiliumNetworkPolicyV2, resourceIDForCiliumNetworkPolicy(fooKey, fooCNP), nil)
+	assert.NoError(t, err)
+	<-policyAdd
+	err = p.onUpsert(barCNP, barKey, k8sAPIGroupCiliumNetworkPolicyV2, resourceIDForCiliumNetworkPolicy(barKey, barCNP), nil)
+	assert.NoError(t, err)
+	<-policyAdd
+
+	fooEv := servicesFixture.upsertService(fooSvcID, nil, nil, fooEps, nil)
+	assert.NoError(t, p.updateToServicesPolicies(fooEv))
+	<-policyAdd
+	barEv := servicesFixture.upsertService(barSvcID, nil, nil, barEps, nil)
+	assert.NoError(t, p.updateToServicesPolicies(barEv))
+	<-policyAdd
+
+	assert.Equal(t, map[loadbalancer.ServiceName]map[resource.Key]struct{}{
+		fooSvcID: {fooKey: {}},
+		barSvcID: {barKey: {}},
+	}, p.cnpByServiceID)
+
+	// A port-only change of foo-svc re-translates only the policy selecting
+	// foo-svc, not all ToServices policies.
+	portEv := fooEv
+	portEv.ports = map[string]uint16{"http": 8080}
+	portEv.previous = &fooEv
+	assert.NoError(t, p.updateToServicesPolicies(portEv))
+
+	rules := <-policyAdd
+	assert.Len(t, rules, 1)
+	assert.Contains(t, rules[0].Labels, labels.NewLabel("io.cilium.k8s.policy.name", fooCNP.Name, "k8s"))
+	select {
+	case rules := <-policyAdd:
+		t.Fatalf("unexpected policy update for unaffected policy: %+v", rules)
+	default:
+	}
+}
+
+func TestPolicyWatcher_updateToServicesPoliciesDualStack(t *testing.T) {
+	policyAdd := make(chan api.Rules, 1)
+	policyImporter := &fakePolicyImporter{
+		OnUpdatePolicy: func(upd *policytypes.PolicyUpdate) {
+			policyAdd <- upd.Rules
+		},
+	}
+
+	cnp := &types.SlimCNP{
+		CiliumNetworkPolicy: &cilium_v2.CiliumNetworkPolicy{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "cilium.io/v2",
+				Kind:       "CiliumNetworkPolicy",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "svc-dual-stack",
+				Namespace: "test",
+			},
+			Spec: &api.Rule{
+				EndpointSelector: api.NewESFromLabels(),
+				Egress: []api.EgressRule{
+					{
+						EgressCommonRule: api.EgressCommonRule{
+							ToServices: []api.Service{
+								{
+									K8sService: &api.K8sServiceNamespace{
+										ServiceName: "foo-svc",
+										Namespace:   "foo-ns",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	cnpKey := resource.NewKey(cnp)
+	cnpResourceID := resourceIDForCiliumNetworkPolicy(cnpKey, cnp)
+
+	epAddrV4 := cmtypes.MustParseAddrCluster("10.1.1.1")
+	epAddrV6 := cmtypes.MustParseAddrCluster("fd00::1")
+	svcID := loadbalancer.NewServiceName("foo-ns", "foo-svc")
+	eps := []cmtypes.AddrCluster{epAddrV4, epAddrV6}
+
+	tests := []struct {
+		name       string
+		enableIPv4 bool
+		enableIPv6 bool
+		expected   api.CIDRRuleSlice
+	}{
+		{
+			name:       "dual-stack",
+			enableIPv4: true,
+			enableIPv6: true,
+			expected: api.CIDRRuleSlice{
+				addrToCIDRRule(epAddrV4.Addr()),
+				addrToCIDRRule(epAddrV6.Addr()),
+			},
+		},
+		{
+			name:       "ipv4 only",
+			enableIPv4: true,
+			expected: api.CIDRRuleSlice{
+				addrToCIDRRule(epAddrV4.Addr()),
+			},
+		},
+		{
+			name:       "ipv6 only",
+			enableIPv6: true,
+			expected: api.CIDRRuleSlice{
+				addrToCIDRRule(epAddrV6.Addr()),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			servicesFixture := newServicesFixture(t)
+			p := &policyWatcher{
+				log:                hivetest.Logger(t),
+				config:             &option.DaemonConfig{EnableIPv4: tt.enableIPv4, EnableIPv6: tt.enableIPv6},
+				k8sResourceSynced:  &k8sSynced.Resources{CacheStatus: make(k8sSynced.CacheStatus)},
+				k8sAPIGroups:       &k8sSynced.APIGroups{},
+				db:                 servicesFixture.db,
+				services:           servicesFixture.services,
+				backends:           servicesFixture.backends,
+				policyImporter:     policyImporter,
+				tracer:             otel.Tracer("test"),
+				cnpCache:           map[resource.Key]*types.SlimCNP{},
+				toServicesPolicies: map[resource.Key]struct{}{},
+				cnpByServiceID:     map[loadbalancer.ServiceName]map[resource.Key]struct{}{},
+				metricsManager:     NewCNPMetricsNoop(),
+			}
+
+			ev := servicesFixture.upsertService(svcID, nil, nil, eps, nil)
+			err := p.onUpsert(cnp, cnpKey, k8sAPIGroupCiliumNetworkPolicyV2, cnpResourceID, nil)
+			assert.NoError(t, err)
+			rules := <-policyAdd
+			assert.Len(t, rules, 1)
+			assert.Len(t, rules[0].Egress, 1)
+			assert.Equal(t, tt.expected, sortCIDRSet(rules[0].Egress[0].ToCIDRSet))
+
+			// Re-resolving on a service event yields the same translation.
+			err = p.updateToServicesPolicies(ev)
+			assert.NoError(t, err)
+			rules = <-policyAdd
+			assert.Len(t, rules, 1)
+			assert.Equal(t, tt.expected, sortCIDRSet(rules[0].Egress[0].ToCIDRSet))
+		})
+	}
+}
+
+func TestPolicyWatcher_backendPrefixCache(t *testing.T) {
+	policyAdd := make(chan api.Rules, 1)
+	policyImporter := &fakePolicyImporter{
+		OnUpdatePolicy: func(upd *policytypes.PolicyUpdate) {
+			policyAdd <- upd.Rules
+		},
+	}
+
+	cnp := &types.SlimCNP{
+		CiliumNetworkPolicy: &cilium_v2.CiliumNetworkPolicy{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "cilium.io/v2",
+				Kind:       "CiliumNetworkPolicy",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "svc-cache",
+				Namespace: "test",
+			},
+			Spec: &api.Rule{
+				EndpointSelector: api.NewESFromLabels(),
+				Egress: []api.EgressRule{
+					{
+						EgressCommonRule: api.EgressCommonRule{
+							ToServices: []api.Service{
+								{
+									K8sService: &api.K8sServiceNamespace{
+										ServiceName: "foo-svc",
+										Namespace:   "foo-ns",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	cnpKey := resource.NewKey(cnp)
+	cnpResourceID := resourceIDForCiliumNetworkPolicy(cnpKey, cnp)
+
+	epAddr := cmtypes.MustParseAddrCluster("10.1.1.1")
+	otherEpAddr := cmtypes.MustParseAddrCluster("10.1.1.2")
+	svcID := loadbalancer.NewServiceName("foo-ns", "foo-svc")
+
+	servicesFixture := newServicesFixture(t)
+	p := &policyWatcher{
+		log:                hivetest.Logger(t),
+		config:             &option.DaemonConfig{EnableIPv4: true, EnableIPv6: true},
+		k8sResourceSynced:  &k8sSynced.Resources{CacheStatus: make(k8sSynced.CacheStatus)},
+		k8sAPIGroups:       &k8sSynced.APIGroups{},
+		db:                 servicesFixture.db,
+		services:           servicesFixture.services,
+		backends:           servicesFixture.backends,
+		policyImporter:     policyImporter,
+		tracer:             otel.Tracer("test"),
+		cnpCache:           map[resource.Key]*types.SlimCNP{},
+		toServicesPolicies: map[resource.Key]struct{}{},
+		cnpByServiceID:     map[loadbalancer.ServiceName]map[resource.Key]struct{}{},
+		backendPrefixCache: map[loadbalancer.ServiceName]backendPrefixes{},
+		metricsManager:     NewCNPMetricsNoop(),
+	}
+
+	// Resolving the policy populates the cache for the selected service.
+	ev := servicesFixture.upsertService(svcID, nil, nil, []cmtypes.AddrCluster{epAddr}, nil)
+	err := p.onUpsert(cnp, cnpKey, k8sAPIGroupCiliumNetworkPolicyV2, cnpResourceID, nil)
+	assert.NoError(t, err)
+	<-policyAdd
+	assert.Equal(t, map[loadbalancer.ServiceName][]api.CIDR{
+		svcID: {api.CIDR("10.1.1.1/32")},
+	}, p.ToServicesBackends())
+
+	// A service event invalidates the cached entry and the subsequent
+	// re-resolution picks up the new backends.
+	ev = servicesFixture.upsertService(svcID, nil, nil, []cmtypes.AddrCluster{otherEpAddr}, &ev)
+	p.onServiceEvent(ev)
+	rules := <-policyAdd
+	assert.Len(t, rules, 1)
+	assert.Equal(t, api.CIDRRuleSlice{addrToCIDRRule(otherEpAddr.Addr())},
+		sortCIDRSet(rules[0].Egress[0].ToCIDRSet))
+	assert.Equal(t, map[loadbalancer.ServiceName][]api.CIDR{
+		svcID: {api.CIDR("10.1.1.2/32")},
+	}, p.ToServicesBackends())
+
+	// Deleting the policy does not resolve the service again: its entry is
+	// dropped on the next event and stays absent.
+	p.onDelete(cnp, cnpKey, k8sAPIGroupCiliumNetworkPolicyV2, cnpResourceID, nil)
+	<-policyAdd
+	ev = servicesFixture.upsertService(svcID, nil, nil, []cmtypes.AddrCluster{epAddr}, &ev)
+	p.onServiceEvent(ev)
+	assert.Empty(t, p.ToServicesBackends())
+}
@@ -0,0 +1,182 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	cilium_v2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+	"github.com/cilium/cilium/pkg/k8s/resource"
+	slimv1 "github.com/cilium/cilium/pkg/k8s/slim/k8s/apis/meta/v1"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+)
+
+const (
+	// policyReasonUnknownFields is the reason set on the Valid condition when
+	// a policy is rejected by strict field validation.
+	policyReasonUnknownFields = "UnknownFields"
+
+	// policyReasonValid is the reason set on the Valid condition when a
+	// previously rejected policy passes strict field validation again.
+	policyReasonValid = "Valid"
+)
+
+// enforceStrictFields validates the policy as it is stored in the API server
+// against the CRD schema shipped with this agent, rejecting it if it contains
+// fields unknown to this agent version. The typed informer feeding the watcher
+// has already dropped any such field, so the object is re-fetched in its
+// unstructured form, which is the only place where they are still visible.
+//
+// The outcome is reflected in the policy's Valid status condition, so that
+// operators can tell a rejected policy apart from one whose unknown fields
+// were silently ignored.
+func (p *policyWatcher) enforceStrictFields(ctx context.Context, key resource.Key) error {
+	var (
+		err      error
+		validate func(*unstructured.Unstructured) error
+	)
+
+	u := &unstructured.Unstructured{}
+	req := p.clientset.CiliumV2().RESTClient().Get()
+	if key.Namespace != "" {
+		err = req.Resource(cilium_v2.CNPPluralName).Namespace(key.Namespace).Name(key.Name).Do(ctx).Into(u)
+		validate = p.npValidator.ValidateCNP
+	} else {
+		err = req.Resource(cilium_v2.CCNPPluralName).Name(key.Name).Do(ctx).Into(u)
+		validate = p.npValidator.ValidateCCNP
+	}
+	if err != nil {
+		// The policy was deleted in the meantime, the pending delete event
+		// will clean up.
+		if k8sErrors.IsNotFound(err) {
+			return nil
+		}
+		// Fail open on any other fetch error (e.g. missing RBAC to re-fetch
+		// the policy): rejecting the policy would block its import even
+		// though it has already passed the typed decoding, which is worse
+		// than skipping the strict validation.
+		p.log.Warn(
+			"Failed to fetch policy for strict field validation, skipping it",
+			logfields.Error, err,
+			logfields.CiliumNetworkPolicyName, key.Name,
+			logfields.K8sNamespace, key.Namespace,
+		)
+		return nil
+	}
+
+	validationErr := validate(u)
+	p.setStrictValidationCondition(ctx, key, validationErr)
+	if validationErr != nil {
+		p.log.Warn(
+			"Rejecting policy with unknown fields",
+			logfields.Error, validationErr,
+			logfields.CiliumNetworkPolicyName, key.Name,
+			logfields.K8sNamespace, key.Namespace,
+		)
+		return fmt.Errorf("strict validation of %s failed: %w", key, validationErr)
+	}
+	return nil
+}
+
+// setStrictValidationCondition records the outcome of strict field validation
+// in the policy's Valid status condition. To avoid churning the condition
+// also managed by the operator's validator, a successful validation is only
+// written back if this watcher rejected the policy before.
+func (p *policyWatcher) setStrictValidationCondition(ctx context.Context, key resource.Key, validationErr error) {
+	if validationErr == nil {
+		if _, rejected := p.rejectedPolicies[key]; !rejected {
+			return
+		}
+		delete(p.rejectedPolicies, key)
+	} else {
+		p.rejectedPolicies[key] = struct{}{}
+	}
+
+	var err error
+	if key.Namespace != "" {
+		var cnp *cilium_v2.CiliumNetworkPolicy
+		cnp, err = p.clientset.CiliumV2().CiliumNetworkPolicies(key.Namespace).Get(ctx, key.Name, metav1.GetOptions{})
+		if err == nil {
+			conditions := strictValidationConditions(cnp.Status.Conditions, validationErr)
+			if conditions == nil {
+				return
+			}
+			cnp.Status.Conditions = conditions
+			// Using the UpdateStatus subresource will prevent the generation
+			// from being bumped.
+			_, err = p.clientset.CiliumV2().CiliumNetworkPolicies(key.Namespace).UpdateStatus(ctx, cnp, metav1.UpdateOptions{})
+		}
+	} else {
+		var ccnp *cilium_v2.CiliumClusterwideNetworkPolicy
+		ccnp, err = p.clientset.CiliumV2().CiliumClusterwideNetworkPolicies().Get(ctx, key.Name, metav1.GetOptions{})
+		if err == nil {
+			conditions := strictValidationConditions(ccnp.Status.Conditions, validationErr)
+			if conditions == nil {
+				return
+			}
+			ccnp.Status.Conditions = conditions
+			_, err = p.clientset.CiliumV2().CiliumClusterwideNetworkPolicies().UpdateStatus(ctx, ccnp, metav1.UpdateOptions{})
+		}
+	}
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		p.log.Warn(
+			"Failed to update policy status condition",
+			logfields.Error, err,
+			logfields.CiliumNetworkPolicyName, key.Name,
+			logfields.K8sNamespace, key.Namespace,
+		)
+	}
+}
+
+// strictValidationConditions returns the conditions updated with the outcome
+// of strict field validation, setting the transition time as necessary, or
+// nil if no update is needed.
+func strictValidationConditions(conditions []cilium_v2.NetworkPolicyCondition, validationErr error) []cilium_v2.NetworkPolicyCondition {
+	newCond := cilium_v2.NetworkPolicyCondition{
+		Type:               cilium_v2.PolicyConditionValid,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: slimv1.Now(),
+		Reason:             policyReasonValid,
+		Message:            "strict field validation succeeded",
+	}
+	if validationErr != nil {
+		newCond.Status = corev1.ConditionFalse
+		newCond.Reason = policyReasonUnknownFields
+		newCond.Message = validationErr.Error()
+	}
+
+	// Look for the condition type already existing.
+	foundIdx := -1
+	for i, cond := range conditions {
+		if cond.Type == cilium_v2.PolicyConditionValid {
+			foundIdx = i
+			// If nothing important changed, short-circuit.
+			if cond.Status == newCond.Status && cond.Reason == newCond.Reason && cond.Message == newCond.Message {
+				return nil
+			}
+			break
+		}
+	}
+
+	out := make([]cilium_v2.NetworkPolicyCondition, len(conditions), len(conditions)+1)
+	copy(out, conditions)
+
+	if foundIdx >= 0 {
+		// If the status did not change (just the message), don't bump the
+		// LastTransitionTime.
+		if out[foundIdx].Status == newCond.Status {
+			newCond.LastTransitionTime = out[foundIdx].LastTransitionTime
+		}
+		out[foundIdx] = newCond
+	} else {
+		out = append(out, newCond)
+	}
+	return out
+}
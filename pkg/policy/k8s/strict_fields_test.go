@@ -0,0 +1,48 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package k8s
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	cilium_v2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+)
+
+func TestStrictValidationConditions(t *testing.T) {
+	validationErr := errors.New("unknown fields found, extra:[spec.futureField]")
+
+	// A rejected policy without conditions gets a new False condition.
+	conds := strictValidationConditions(nil, validationErr)
+	require.Len(t, conds, 1)
+	require.Equal(t, cilium_v2.PolicyConditionValid, conds[0].Type)
+	require.Equal(t, corev1.ConditionFalse, conds[0].Status)
+	require.Equal(t, policyReasonUnknownFields, conds[0].Reason)
+	require.Equal(t, validationErr.Error(), conds[0].Message)
+
+	// Re-validating the same error is a no-op.
+	require.Nil(t, strictValidationConditions(conds, validationErr))
+
+	// A different error updates the message, but keeps the transition time.
+	updated := strictValidationConditions(conds, errors.New("unknown fields found, extra:[spec.otherField]"))
+	require.Len(t, updated, 1)
+	require.Equal(t, conds[0].LastTransitionTime, updated[0].LastTransitionTime)
+	require.NotEqual(t, conds[0].Message, updated[0].Message)
+
+	// A successful validation flips the condition back to True.
+	valid := strictValidationConditions(conds, nil)
+	require.Len(t, valid, 1)
+	require.Equal(t, corev1.ConditionTrue, valid[0].Status)
+	require.Equal(t, policyReasonValid, valid[0].Reason)
+
+	// Unrelated conditions are preserved.
+	existing := append([]cilium_v2.NetworkPolicyCondition{{Type: "Other"}}, valid...)
+	conds = strictValidationConditions(existing, validationErr)
+	require.Len(t, conds, 2)
+	require.Equal(t, cilium_v2.PolicyConditionType("Other"), conds[0].Type)
+	require.Equal(t, corev1.ConditionFalse, conds[1].Status)
+}
@@ -13,17 +13,25 @@ import (
 
 	"github.com/cilium/statedb"
 	"github.com/cilium/stream"
+	"go.opentelemetry.io/otel/trace"
 
+	daemonk8s "github.com/cilium/cilium/daemon/k8s"
 	cmtypes "github.com/cilium/cilium/pkg/clustermesh/types"
 	ipcacheTypes "github.com/cilium/cilium/pkg/ipcache/types"
+	ciliumk8s "github.com/cilium/cilium/pkg/k8s"
 	cilium_v2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+	"github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2/validator"
+	"github.com/cilium/cilium/pkg/k8s/client"
 	"github.com/cilium/cilium/pkg/k8s/resource"
 	slim_networking_v1 "github.com/cilium/cilium/pkg/k8s/slim/k8s/api/networking/v1"
 	k8sSynced "github.com/cilium/cilium/pkg/k8s/synced"
 	"github.com/cilium/cilium/pkg/k8s/types"
 	"github.com/cilium/cilium/pkg/loadbalancer"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging/logfields"
 	"github.com/cilium/cilium/pkg/option"
 	policycell "github.com/cilium/cilium/pkg/policy/cell"
+	"github.com/cilium/cilium/pkg/time"
 )
 
 type policyWatcher struct {
@@ -34,12 +42,33 @@ type policyWatcher struct {
 	k8sResourceSynced *k8sSynced.Resources
 	k8sAPIGroups      *k8sSynced.APIGroups
 
+	// clientset is used to update the status of CiliumCIDRGroups. It may be
+	// nil in tests, in which case status updates are skipped.
+	clientset client.Clientset
+
 	policyImporter policycell.PolicyImporter
 
+	// tracer emits spans around the per-resource event handlers. Without a
+	// configured trace provider this is a no-op tracer.
+	tracer trace.Tracer
+
 	db       *statedb.DB
 	services statedb.Table[*loadbalancer.Service]
 	backends statedb.Table[*loadbalancer.Backend]
 
+	// namespaces is used as a barrier when importing CNPs: policies in a
+	// newly created namespace are imported only once the namespace's labels
+	// are known. It may be nil in tests, in which case no barrier applies.
+	namespaces statedb.Table[daemonk8s.Namespace]
+
+	// workers fans the policy events out to per-namespace queues, so that a
+	// flood of updates in one namespace does not delay unrelated namespaces.
+	workers      *namespaceWorkerPool
+	queueMetrics *PolicyQueueMetrics
+	// handlerMu serializes the event handlers invoked from the worker pool,
+	// which on its own only guarantees ordering per namespace.
+	handlerMu lock.Mutex
+
 	serviceEvents stream.Observable[serviceEvent]
 
 	ipCache ipc
@@ -66,6 +95,27 @@ type policyWatcher struct {
 
 	cidrGroupCache map[string]*cilium_v2.CiliumCIDRGroup
 
+	// compiledSelectors shares the compiled form of identical label
+	// selectors across the imported policies. It may be nil in tests,
+	// in which case selectors are not shared.
+	compiledSelectors *compiledSelectorCache
+
+	// npValidator validates policies against the CRD schema shipped with
+	// this agent before importing them. It is only set when strict field
+	// validation is enabled; when nil, unknown fields are silently dropped
+	// by the typed informer as before.
+	npValidator *validator.NPValidator
+
+	// rejectedPolicies is the set of policies currently rejected by strict
+	// field validation, tracked so that their Valid condition can be reset
+	// once they validate again.
+	rejectedPolicies map[resource.Key]struct{}
+
+	// reportCIDRGroupStatus enables reporting the number of referencing
+	// policies and the CIDR resolution outcome in the CiliumCIDRGroup
+	// status.
+	reportCIDRGroupStatus bool
+
 	// cidrGroupCIDRs is the set of CIDRs upserted in to the ipcache
 	// for a given cidrgroup
 	cidrGroupCIDRs map[string]sets.Set[netip.Prefix]
@@ -74,6 +124,13 @@ type policyWatcher struct {
 	toServicesPolicies map[resource.Key]struct{}
 	cnpByServiceID     map[loadbalancer.ServiceName]map[resource.Key]struct{}
 
+	// backendPrefixMu protects backendPrefixCache, which caches the backend
+	// prefixes resolved for ToServices rules per service. Entries are
+	// invalidated by service events, so that re-resolving a policy does not
+	// re-list the backends of services which have not changed.
+	backendPrefixMu    lock.Mutex
+	backendPrefixCache map[loadbalancer.ServiceName]backendPrefixes
+
 	metricsManager CNPMetrics
 }
 
@@ -150,6 +207,9 @@ func (p *policyWatcher) watchResources(ctx context.Context) {
 			serviceEvents = stream.ToChannel(ctx, p.serviceEvents)
 		}
 
+		p.workers = newNamespaceWorkerPool(defaultPolicyWorkers, p.queueMetrics)
+		defer p.workers.Close()
+
 		for {
 			select {
 			case event, ok := <-knpEvents:
@@ -159,23 +219,33 @@ func (p *policyWatcher) watchResources(ctx context.Context) {
 				}
 
 				if event.Kind == resource.Sync {
-					knpDone <- 0
+					// Signal the sync only once all the events received
+					// before it have been processed by the worker pool.
+					done := knpDone
+					p.workers.Barrier(func() {
+						done <- 0
+						event.Done(nil)
+					})
 					knpDone = nil // stop tracking pending work
-					event.Done(nil)
 					continue
 				}
 
-				var err error
-				switch event.Kind {
-				case resource.Upsert:
-					err = p.addK8sNetworkPolicyV1(
-						event.Object, k8sAPIGroupNetworkingV1Core, knpDone,
-						cmtypes.LocalClusterNameForPolicies(p.clusterMeshPolicyConfig, p.config.ClusterName),
-					)
-				case resource.Delete:
-					err = p.deleteK8sNetworkPolicyV1(event.Object, k8sAPIGroupNetworkingV1Core, knpDone)
-				}
-				event.Done(err)
+				done := knpDone
+				p.workers.Enqueue(event.Object.Namespace, func() {
+					var err error
+					p.handlerMu.Lock()
+					switch event.Kind {
+					case resource.Upsert:
+						err = p.addK8sNetworkPolicyV1(
+							event.Object, k8sAPIGroupNetworkingV1Core, done,
+							cmtypes.LocalClusterNameForPolicies(p.clusterMeshPolicyConfig, p.config.ClusterName),
+						)
+					case resource.Delete:
+						err = p.deleteK8sNetworkPolicyV1(event.Object, k8sAPIGroupNetworkingV1Core, done)
+					}
+					p.handlerMu.Unlock()
+					event.Done(err)
+				})
 			case event, ok := <-cnpEvents:
 				if !ok {
 					cnpEvents = nil
@@ -183,35 +253,47 @@ func (p *policyWatcher) watchResources(ctx context.Context) {
 				}
 
 				if event.Kind == resource.Sync {
-					cnpDone <- 0
+					done := cnpDone
+					p.workers.Barrier(func() {
+						done <- 0
+						event.Done(nil)
+					})
 					cnpDone = nil
-					event.Done(nil)
 					continue
 				}
 
-				slimCNP := &types.SlimCNP{
-					CiliumNetworkPolicy: &cilium_v2.CiliumNetworkPolicy{
-						TypeMeta:   event.Object.TypeMeta,
-						ObjectMeta: event.Object.ObjectMeta,
-						Spec:       event.Object.Spec,
-						Specs:      event.Object.Specs,
-					},
-				}
+				slimCNP := ciliumk8s.SlimCNPFrom(event.Object)
 
 				resourceID := ipcacheTypes.NewResourceID(
 					ipcacheTypes.ResourceKindCNP,
 					slimCNP.ObjectMeta.Namespace,
 					slimCNP.ObjectMeta.Name,
 				)
-				var err error
-				switch event.Kind {
-				case resource.Upsert:
-					err = p.onUpsert(slimCNP, event.Key, k8sAPIGroupCiliumNetworkPolicyV2, resourceID, cnpDone)
-				case resource.Delete:
-					p.onDelete(slimCNP, event.Key, k8sAPIGroupCiliumNetworkPolicyV2, resourceID, cnpDone)
-				}
-				reportCNPChangeMetrics(err)
-				event.Done(err)
+				done := cnpDone
+				p.workers.Enqueue(slimCNP.ObjectMeta.Namespace, func() {
+					if event.Kind == resource.Upsert {
+						// Block this namespace's queue until the namespace
+						// labels are known, so that the rules are not
+						// computed against incomplete namespace labels.
+						p.waitForNamespace(ctx, slimCNP.ObjectMeta.Namespace)
+					}
+					var err error
+					p.handlerMu.Lock()
+					switch event.Kind {
+					case resource.Upsert:
+						err = p.onUpsert(slimCNP, event.Key, k8sAPIGroupCiliumNetworkPolicyV2, resourceID, done)
+					case resource.Delete:
+						p.onDelete(slimCNP, event.Key, k8sAPIGroupCiliumNetworkPolicyV2, resourceID, done)
+					}
+					// Return the conversion wrapper to the pool unless the
+					// handler retained it in the policy cache.
+					if p.cnpCache[event.Key] != slimCNP {
+						ciliumk8s.ReleaseSlimCNP(slimCNP)
+					}
+					p.handlerMu.Unlock()
+					reportCNPChangeMetrics(err)
+					event.Done(err)
+				})
 			case event, ok := <-ccnpEvents:
 				if !ok {
 					ccnpEvents = nil
@@ -219,35 +301,40 @@ func (p *policyWatcher) watchResources(ctx context.Context) {
 				}
 
 				if event.Kind == resource.Sync {
-					ccnpDone <- 0
+					done := ccnpDone
+					p.workers.Barrier(func() {
+						done <- 0
+						event.Done(nil)
+					})
 					ccnpDone = nil
-					event.Done(nil)
 					continue
 				}
 
-				slimCNP := &types.SlimCNP{
-					CiliumNetworkPolicy: &cilium_v2.CiliumNetworkPolicy{
-						TypeMeta:   event.Object.TypeMeta,
-						ObjectMeta: event.Object.ObjectMeta,
-						Spec:       event.Object.Spec,
-						Specs:      event.Object.Specs,
-					},
-				}
+				slimCNP := ciliumk8s.SlimCNPFromCCNP(event.Object)
 
 				resourceID := ipcacheTypes.NewResourceID(
 					ipcacheTypes.ResourceKindCCNP,
 					slimCNP.ObjectMeta.Namespace,
 					slimCNP.ObjectMeta.Name,
 				)
-				var err error
-				switch event.Kind {
-				case resource.Upsert:
-					err = p.onUpsert(slimCNP, event.Key, k8sAPIGroupCiliumClusterwideNetworkPolicyV2, resourceID, ccnpDone)
-				case resource.Delete:
-					p.onDelete(slimCNP, event.Key, k8sAPIGroupCiliumClusterwideNetworkPolicyV2, resourceID, ccnpDone)
-				}
-				reportCNPChangeMetrics(err)
-				event.Done(err)
+				// CCNPs are cluster-scoped, so they share the empty namespace queue.
+				done := ccnpDone
+				p.workers.Enqueue(slimCNP.ObjectMeta.Namespace, func() {
+					var err error
+					p.handlerMu.Lock()
+					switch event.Kind {
+					case resource.Upsert:
+						err = p.onUpsert(slimCNP, event.Key, k8sAPIGroupCiliumClusterwideNetworkPolicyV2, resourceID, done)
+					case resource.Delete:
+						p.onDelete(slimCNP, event.Key, k8sAPIGroupCiliumClusterwideNetworkPolicyV2, resourceID, done)
+					}
+					if p.cnpCache[event.Key] != slimCNP {
+						ciliumk8s.ReleaseSlimCNP(slimCNP)
+					}
+					p.handlerMu.Unlock()
+					reportCNPChangeMetrics(err)
+					event.Done(err)
+				})
 			case event, ok := <-cidrGroupEvents:
 				if !ok {
 					cidrGroupEvents = nil
@@ -260,12 +347,14 @@ func (p *policyWatcher) watchResources(ctx context.Context) {
 					continue
 				}
 
+				p.handlerMu.Lock()
 				switch event.Kind {
 				case resource.Upsert:
-					p.onUpsertCIDRGroup(event.Object, k8sAPIGroupCiliumCIDRGroupV2)
+					p.onUpsertCIDRGroup(ctx, event.Object, k8sAPIGroupCiliumCIDRGroupV2)
 				case resource.Delete:
 					p.onDeleteCIDRGroup(event.Object.Name, k8sAPIGroupCiliumCIDRGroupV2)
 				}
+				p.handlerMu.Unlock()
 				event.Done(nil)
 
 			case event, ok := <-serviceEvents:
@@ -273,7 +362,9 @@ func (p *policyWatcher) watchResources(ctx context.Context) {
 					serviceEvents = nil
 					break
 				}
+				p.handlerMu.Lock()
 				p.onServiceEvent(event)
+				p.handlerMu.Unlock()
 			}
 
 			if knpEvents == nil && cnpEvents == nil && ccnpEvents == nil && cidrGroupEvents == nil && serviceEvents == nil {
@@ -283,6 +374,40 @@ func (p *policyWatcher) watchResources(ctx context.Context) {
 	}()
 }
 
+// namespaceWaitTimeout bounds the time a namespace's policy queue is blocked
+// waiting for the namespace to appear in the namespace table, e.g. when a
+// policy event outlives its already deleted namespace.
+const namespaceWaitTimeout = 30 * time.Second
+
+// waitForNamespace blocks until the given namespace is present in the
+// namespace table, i.e. until its identity-relevant labels are known. It
+// returns immediately for cluster-scoped policies, and gives up once the
+// context is cancelled or namespaceWaitTimeout has elapsed.
+func (p *policyWatcher) waitForNamespace(ctx context.Context, namespace string) {
+	if p.namespaces == nil || namespace == "" {
+		return
+	}
+
+	limit := time.After(namespaceWaitTimeout)
+	for {
+		_, _, watch, found := p.namespaces.GetWatch(p.db.ReadTxn(), daemonk8s.NamespaceByName(namespace))
+		if found {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-limit:
+			p.log.Warn(
+				"Timed out waiting for namespace labels, importing policies with potentially incomplete namespace labels",
+				logfields.K8sNamespace, namespace,
+			)
+			return
+		case <-watch:
+		}
+	}
+}
+
 type CNPMetrics interface {
 	AddCNP(cec *cilium_v2.CiliumNetworkPolicy)
 	DelCNP(cec *cilium_v2.CiliumNetworkPolicy)
@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cilium/hive/hivetest"
+	"github.com/cilium/statedb"
+	"github.com/stretchr/testify/require"
+
+	daemonk8s "github.com/cilium/cilium/daemon/k8s"
+	"github.com/cilium/cilium/pkg/time"
+)
+
+// waitTimeout is a generous timeout to avoid flakes in noisy CI environments.
+const waitTimeout = 10 * time.Second
+
+func TestWaitForNamespace(t *testing.T) {
+	db := statedb.New()
+	namespaces, err := daemonk8s.NewNamespaceTable(db)
+	require.NoError(t, err)
+
+	p := &policyWatcher{
+		log:        hivetest.Logger(t),
+		db:         db,
+		namespaces: namespaces,
+	}
+
+	// Cluster-scoped policies and known namespaces pass immediately.
+	p.waitForNamespace(t.Context(), "")
+
+	txn := db.WriteTxn(namespaces)
+	_, _, err = namespaces.Insert(txn, daemonk8s.Namespace{Name: "existing"})
+	require.NoError(t, err)
+	txn.Commit()
+	p.waitForNamespace(t.Context(), "existing")
+
+	// An unknown namespace blocks the caller until it is created.
+	released := make(chan struct{})
+	go func() {
+		p.waitForNamespace(t.Context(), "pending")
+		close(released)
+	}()
+	select {
+	case <-released:
+		t.Fatal("waitForNamespace returned before the namespace was created")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	txn = db.WriteTxn(namespaces)
+	_, _, err = namespaces.Insert(txn, daemonk8s.Namespace{Name: "pending"})
+	require.NoError(t, err)
+	txn.Commit()
+
+	select {
+	case <-released:
+	case <-time.After(waitTimeout):
+		t.Fatal("waitForNamespace did not return after the namespace was created")
+	}
+
+	// Context cancellation lifts the barrier.
+	ctx, cancel := context.WithCancel(context.Background())
+	released = make(chan struct{})
+	go func() {
+		p.waitForNamespace(ctx, "never")
+		close(released)
+	}()
+	cancel()
+	select {
+	case <-released:
+	case <-time.After(waitTimeout):
+		t.Fatal("waitForNamespace did not return after context cancellation")
+	}
+}
@@ -22,6 +22,9 @@ type stringList string
 type RuleMeta struct {
 	labels labels.LabelArrayListString // from LabelArrayList.String()
 	log    stringList
+	// verdicts is true if any of the owning rules requested policy verdict
+	// logging for the traffic it matches.
+	verdicts bool
 }
 
 func (rm RuleMeta) LabelArray() labels.LabelArrayList {
@@ -89,19 +92,26 @@ func (rm RuleMeta) Log() []string {
 	return rm.log.List()
 }
 
+// VerdictLogging returns true if any of the owning rules requested policy
+// verdict logging.
+func (rm RuleMeta) VerdictLogging() bool {
+	return rm.verdicts
+}
+
 func newRuleOrigin(rm RuleMeta) ruleOrigin {
 	return ruleOrigin(unique.Make(rm))
 }
 
-func makeRuleOrigin(lbls labels.LabelArrayList, logs []string) ruleOrigin {
+func makeRuleOrigin(lbls labels.LabelArrayList, logs []string, verdicts bool) ruleOrigin {
 	return newRuleOrigin(RuleMeta{
-		labels: lbls.ArrayListString(),
-		log:    newStringList(logs...),
+		labels:   lbls.ArrayListString(),
+		log:      newStringList(logs...),
+		verdicts: verdicts,
 	})
 }
 
-func makeSingleRuleOrigin(lbls labels.LabelArray, log string) ruleOrigin {
-	return makeRuleOrigin(labels.LabelArrayList{lbls}, []string{log})
+func makeSingleRuleOrigin(lbls labels.LabelArray, log string, verdicts bool) ruleOrigin {
+	return makeRuleOrigin(labels.LabelArrayList{lbls}, []string{log}, verdicts)
 }
 
 // Merge combines two rule origins.
@@ -120,8 +130,9 @@ func (ro ruleOrigin) Merge(other ruleOrigin) ruleOrigin {
 	}
 
 	new := RuleMeta{
-		labels: labels.MergeSortedLabelArrayListStrings(ro.LabelsString(), other.LabelsString()),
-		log:    mergeStringList(ro.Value().log, other.Value().log),
+		labels:   labels.MergeSortedLabelArrayListStrings(ro.LabelsString(), other.LabelsString()),
+		log:      mergeStringList(ro.Value().log, other.Value().log),
+		verdicts: ro.Value().verdicts || other.Value().verdicts,
 	}
 
 	return ruleOrigin(unique.Make(new))
@@ -135,7 +146,7 @@ type testOrigin map[CachedSelector]labels.LabelArrayList
 func OriginForTest(m testOrigin) map[CachedSelector]ruleOrigin {
 	res := make(map[CachedSelector]ruleOrigin, len(m))
 	for cs, lbls := range m {
-		res[cs] = makeRuleOrigin(lbls, nil)
+		res[cs] = makeRuleOrigin(lbls, nil, false)
 	}
 	return res
 }
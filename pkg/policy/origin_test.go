@@ -15,15 +15,21 @@ func TestRuleOrigin(t *testing.T) {
 	lbls1 := labels.NewLabelsFromSortedList("k8s:a=1;k8s:b=1").LabelArray()
 	lbls2 := labels.NewLabelsFromSortedList("k8s:a=2;k8s:b=2").LabelArray()
 
-	ro := makeSingleRuleOrigin(lbls1, "log1")
+	ro := makeSingleRuleOrigin(lbls1, "log1", false)
 	require.ElementsMatch(t, labels.LabelArrayList{lbls1}, ro.Value().LabelArray())
 	require.ElementsMatch(t, []string{"log1"}, ro.Value().log.List())
 
-	ro = ro.Merge(makeSingleRuleOrigin(lbls2, "log2"))
+	ro = ro.Merge(makeSingleRuleOrigin(lbls2, "log2", false))
 	require.ElementsMatch(t, labels.LabelArrayList{lbls1, lbls2}, ro.Value().LabelArray())
 	require.ElementsMatch(t, []string{"log1", "log2"}, ro.Value().log.List())
 
-	ro = ro.Merge(makeSingleRuleOrigin(lbls2, "log2"))
+	ro = ro.Merge(makeSingleRuleOrigin(lbls2, "log2", false))
 	require.ElementsMatch(t, labels.LabelArrayList{lbls1, lbls2}, ro.Value().LabelArray())
 	require.ElementsMatch(t, []string{"log1", "log2"}, ro.Value().log.List())
+	require.False(t, ro.Value().VerdictLogging())
+
+	// Verdict logging sticks when any merged rule requests it.
+	ro = ro.Merge(makeSingleRuleOrigin(lbls2, "log2", true))
+	require.True(t, ro.Value().VerdictLogging())
+	require.ElementsMatch(t, []string{"log1", "log2"}, ro.Value().log.List())
 }
@@ -14,6 +14,10 @@ type PolicyCorrelationInfo struct {
 	// no entry here. Duplicate strings are coalesced.
 	Log []string
 
+	// LogVerdicts is true if any of the matched rules requested policy verdict
+	// logging via Spec.Log.Verdicts.
+	LogVerdicts bool
+
 	// Revision is the policy revision.
 	Revision uint64
 }
@@ -6,6 +6,8 @@ package types
 import (
 	"time"
 
+	"go.opentelemetry.io/otel/trace"
+
 	ipcacheTypes "github.com/cilium/cilium/pkg/ipcache/types"
 	"github.com/cilium/cilium/pkg/labels"
 	policyapi "github.com/cilium/cilium/pkg/policy/api"
@@ -37,8 +39,19 @@ type PolicyUpdate struct {
 	// policy was received from the API server.
 	ProcessingStartTime time.Time
 
+	// EnqueueTime is the time the update was handed to the PolicyImporter.
+	// It is set by the importer itself and used to measure how long updates
+	// spend queued before being applied.
+	EnqueueTime time.Time
+
 	// DoneChan, if not nil, will have a single value emitted: the revision of the
 	// policy repository when the update has been processed.
 	// Thus must be a buffered channel!
 	DoneChan chan<- uint64
+
+	// TraceContext, if valid, is the span context of the event that produced
+	// this update. Spans emitted while applying the update are linked to it,
+	// so that end-to-end latency from resource change to datapath programming
+	// can be traced.
+	TraceContext trace.SpanContext
 }
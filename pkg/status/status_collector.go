@@ -673,6 +673,10 @@ func (d *statusCollector) getProbes() []Probe {
 
 				if kvstore, ok := status.Data.(*models.Status); ok {
 					d.statusResponse.Kvstore = kvstore
+					d.statusResponse.KvstoreDetail = nil
+					if d.statusParams.KVStoreClient.IsEnabled() {
+						d.statusResponse.KvstoreDetail = d.statusParams.KVStoreClient.StatusDetail()
+					}
 				}
 			},
 		},